@@ -107,34 +107,59 @@ type AudioSource struct {
 	player        *audio.Player
 	panStream     *StereoPanStream
 	isFading      bool
+	paused        bool
 	fadeDuration  float64
 	currentVolume float64
 	targetVolume  float64
 	fadeType      AudioFadeType
 	trackID       TrackID
+	// gain scales the volume by distance for spatial sources.
+	gain    float64
+	spatial *audioSpatial
+	bus     *audioBus
 }
+
+// applyVolume sends the current volume, scaled by the spatial gain and the
+// bus volume, to the player.
+func (self *AudioSource) applyVolume() {
+	self.player.SetVolume(self.currentVolume * self.gain * self.bus.effectiveVolume())
+}
+
 type TrackData struct {
 	ext     string
 	content []byte
+	tags    map[string]string
+	info    *TrackInfo
+	mode    TrackMode
+	pcm     []byte
 }
 
 // AudioManager manages all game audio, including music and sound effects.
 type AudioManager struct {
 	audioContext   *audio.Context
-	trackList      map[TrackID]TrackData
+	trackList      map[TrackID]*TrackData
 	players        map[PlaybackID]*AudioSource
 	stackingTracks map[TrackID]*StackingArray
 	nextPlaybackID PlaybackID
+	pausedAll      []PlaybackID
+	allPaused      bool
+	decodeCache    *audioDecodeCache
+	listener       audioListener
+	master         *audioBus
+	buses          map[string]*audioBus
 }
 
 // NewAudioManager initializes and returns a new AudioManager.
 func NewAudioManager(sampleRate int) *AudioManager {
 	return &AudioManager{
 		audioContext:   audio.NewContext(sampleRate),
-		trackList:      make(map[TrackID]TrackData),
+		trackList:      make(map[TrackID]*TrackData),
 		players:        make(map[PlaybackID]*AudioSource),
 		nextPlaybackID: 0,
 		stackingTracks: make(map[TrackID]*StackingArray),
+		decodeCache:    newAudioDecodeCache(DefaultAudioDecodeBudget),
+		master:         &audioBus{volume: 1},
+		buses:          make(map[string]*audioBus),
 	}
 }
 
@@ -166,9 +191,7 @@ func (self *AudioManager) Load(path string) (TrackID, error) {
 	if len(b) == 0 {
 		return -1, fmt.Errorf("failed to read audio file: %s", path)
 	}
-	id := TrackID(len(self.trackList))
-	self.trackList[id] = TrackData{content: b, ext: GetFileExtension(path)}
-	return id, nil
+	return self.addTrack(b, path), nil
 }
 
 // LoadEmbedded loads embedded audio data and stores its bytes.
@@ -177,9 +200,7 @@ func (self *AudioManager) LoadEmbedded(path string) (TrackID, error) {
 	if len(b) == 0 {
 		return -1, fmt.Errorf("failed to read embedded audio file: %s", path)
 	}
-	id := TrackID(len(self.trackList))
-	self.trackList[id] = TrackData{content: b, ext: GetFileExtension(path)}
-	return id, nil
+	return self.addTrack(b, path), nil
 }
 
 // LoadFromAssetPacker loads audio from an asset pack and stores its bytes.
@@ -188,9 +209,18 @@ func (self *AudioManager) LoadFromAssetPacker(path string) (TrackID, error) {
 	if len(b) == 0 {
 		return -1, fmt.Errorf("failed to read bundled audio file: %s", path)
 	}
+	return self.addTrack(b, path), nil
+}
+
+// addTrack registers loaded audio bytes under a new track ID.
+func (self *AudioManager) addTrack(content []byte, path string) TrackID {
 	id := TrackID(len(self.trackList))
-	self.trackList[id] = TrackData{content: b, ext: GetFileExtension(path)}
-	return id, nil
+	self.trackList[id] = &TrackData{
+		content: content,
+		ext:     GetFileExtension(path),
+		tags:    make(map[string]string),
+	}
+	return id
 }
 
 // prepareAudioSource prepares a new audio source from stored bytes.
@@ -199,6 +229,17 @@ func (self *AudioManager) prepareAudioSource(trackID TrackID, pan float64, loop
 		return nil, fmt.Errorf("invalid track ID: %d", trackID)
 	}
 	trackData := self.trackList[trackID]
+	if trackData.mode == TrackMemory {
+		pcm, err := self.decodedPCM(trackID)
+		if err != nil {
+			return nil, err
+		}
+		var stream io.ReadSeeker = bytes.NewReader(pcm)
+		if loop {
+			stream = audio.NewInfiniteLoop(stream, int64(len(pcm)))
+		}
+		return self.newAudioSource(trackID, NewStereoPanStream(stream), pan)
+	}
 	reader, err := self.fromBytes(trackData.content, trackData.ext)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode audio from bytes: %w", err)
@@ -231,6 +272,11 @@ func (self *AudioManager) prepareAudioSource(trackID TrackID, pan float64, loop
 	} else {
 		panStream = NewStereoPanStream(stream)
 	}
+	return self.newAudioSource(trackID, panStream, pan)
+}
+
+// newAudioSource wraps a panned stream in a player.
+func (self *AudioManager) newAudioSource(trackID TrackID, panStream *StereoPanStream, pan float64) (*AudioSource, error) {
 	if pan != 0 {
 		panStream.SetPan(pan)
 	}
@@ -242,18 +288,24 @@ func (self *AudioManager) prepareAudioSource(trackID TrackID, pan float64, loop
 		player:    player,
 		panStream: panStream,
 		trackID:   trackID,
+		gain:      1,
 	}, nil
 }
 
 // createAudioSource creates and initializes an audio source, starting playback.
-func (self *AudioManager) createAudioSource(trackID TrackID, pan float64, loop bool, defaultVolume float64, fadeDuration float64, fadeType AudioFadeType) (*AudioSource, error) {
+func (self *AudioManager) createAudioSource(trackID TrackID, pan float64, loop bool, defaultVolume float64, fadeDuration float64, fadeType AudioFadeType, spatial *audioSpatial, bus *audioBus) (*AudioSource, error) {
 	source, err := self.prepareAudioSource(trackID, pan, loop)
 	if err != nil {
 		return nil, err
 	}
+	source.bus = bus
+	if spatial != nil {
+		source.spatial = spatial
+		self.spatialize(source)
+	}
 	if fadeDuration <= 0 {
 		source.currentVolume = defaultVolume
-		source.player.SetVolume(defaultVolume)
+		source.applyVolume()
 		source.player.Play()
 		return source, nil
 	}
@@ -261,21 +313,21 @@ func (self *AudioManager) createAudioSource(trackID TrackID, pan float64, loop b
 	targetVolume := defaultVolume
 	if fadeType == AudioFadeIn {
 		startVolume = 0
-		source.player.SetVolume(0)
 	} else {
 		targetVolume = 0
 	}
+	source.currentVolume = startVolume
+	source.applyVolume()
 	source.player.Play()
 	source.isFading = true
 	source.fadeDuration = fadeDuration
-	source.currentVolume = startVolume
 	source.targetVolume = targetVolume
 	source.fadeType = fadeType
 	return source, nil
 }
 
 // internalPlay handles playback logic, including stacking and fading.
-func (self *AudioManager) internalPlay(trackID TrackID, pan float64, loop bool, defaultVolume float64, fadeDuration float64, fadeType AudioFadeType, stackConfig *StackingConfig) (PlaybackID, error) {
+func (self *AudioManager) internalPlay(trackID TrackID, pan float64, loop bool, defaultVolume float64, fadeDuration float64, fadeType AudioFadeType, stackConfig *StackingConfig, spatial *audioSpatial, bus string) (PlaybackID, error) {
 	stacking := stackConfig != nil && stackConfig.Enabled && stackConfig.MaxStack > 1
 	if !stacking {
 		self.StopByTrackID(trackID)
@@ -300,7 +352,7 @@ func (self *AudioManager) internalPlay(trackID TrackID, pan float64, loop bool,
 			stackArray.playbackIDs = active[1:]
 		}
 	}
-	source, err := self.createAudioSource(trackID, pan, loop, defaultVolume, fadeDuration, fadeType)
+	source, err := self.createAudioSource(trackID, pan, loop, defaultVolume, fadeDuration, fadeType, spatial, self.bus(bus))
 	if err != nil {
 		return -1, err
 	}
@@ -310,27 +362,33 @@ func (self *AudioManager) internalPlay(trackID TrackID, pan float64, loop bool,
 	if stacking {
 		self.stackingTracks[trackID].playbackIDs = append(self.stackingTracks[trackID].playbackIDs, playbackID)
 	}
+	if source.bus.paused {
+		source.player.Pause()
+		source.paused = true
+		source.bus.pausedIDs = append(source.bus.pausedIDs, playbackID)
+	}
 	return playbackID, nil
 }
 
-// PlaySound plays a one-shot sound effect.
-func (self *AudioManager) PlaySound(trackID TrackID, pan float64, stackConfig *StackingConfig) (PlaybackID, error) {
-	return self.internalPlay(trackID, pan, false, 1.0, 0, AudioFadeIn, stackConfig)
+// PlaySound plays a one-shot sound effect on the given bus, AudioBusSFX by
+// default.
+func (self *AudioManager) PlaySound(trackID TrackID, pan float64, stackConfig *StackingConfig, bus ...string) (PlaybackID, error) {
+	return self.internalPlay(trackID, pan, false, 1.0, 0, AudioFadeIn, stackConfig, nil, busOr(bus, AudioBusSFX))
 }
 
-// PlayMusic plays a music track.
-func (self *AudioManager) PlayMusic(trackID TrackID, loop bool) (PlaybackID, error) {
-	return self.internalPlay(trackID, 0, loop, 0.5, 0, AudioFadeIn, nil)
+// PlayMusic plays a music track on the given bus, AudioBusMusic by default.
+func (self *AudioManager) PlayMusic(trackID TrackID, loop bool, bus ...string) (PlaybackID, error) {
+	return self.internalPlay(trackID, 0, loop, 0.5, 0, AudioFadeIn, nil, nil, busOr(bus, AudioBusMusic))
 }
 
 // FadeSound plays a sound effect with a fade effect.
-func (self *AudioManager) FadeSound(trackID TrackID, pan, fadeDuration float64, fadeType AudioFadeType, stackConfig *StackingConfig) (PlaybackID, error) {
-	return self.internalPlay(trackID, pan, false, 1.0, fadeDuration, fadeType, stackConfig)
+func (self *AudioManager) FadeSound(trackID TrackID, pan, fadeDuration float64, fadeType AudioFadeType, stackConfig *StackingConfig, bus ...string) (PlaybackID, error) {
+	return self.internalPlay(trackID, pan, false, 1.0, fadeDuration, fadeType, stackConfig, nil, busOr(bus, AudioBusSFX))
 }
 
 // FadeMusic plays a music track with a fade effect.
-func (self *AudioManager) FadeMusic(trackID TrackID, loop bool, fadeDuration float64, fadeType AudioFadeType) (PlaybackID, error) {
-	return self.internalPlay(trackID, 0, loop, 1.0, fadeDuration, fadeType, nil)
+func (self *AudioManager) FadeMusic(trackID TrackID, loop bool, fadeDuration float64, fadeType AudioFadeType, bus ...string) (PlaybackID, error) {
+	return self.internalPlay(trackID, 0, loop, 1.0, fadeDuration, fadeType, nil, nil, busOr(bus, AudioBusMusic))
 }
 
 // Stop stops and removes a single playing audio source.
@@ -377,6 +435,7 @@ func (self *AudioManager) Pause(id PlaybackID) error {
 		return fmt.Errorf("invalid playback ID: %d", id)
 	}
 	source.player.Pause()
+	source.paused = true
 	return nil
 }
 
@@ -387,9 +446,71 @@ func (self *AudioManager) Resume(id PlaybackID) error {
 		return fmt.Errorf("invalid playback ID: %d", id)
 	}
 	source.player.Play()
+	source.paused = false
 	return nil
 }
 
+// PauseAll pauses every playing source and remembers which ones were
+// playing, so ResumeAll restores exactly that set. Sources that were already
+// paused stay paused after ResumeAll.
+func (self *AudioManager) PauseAll() {
+	if self.allPaused {
+		return
+	}
+	self.allPaused = true
+	self.pausedAll = self.pausePlaying(self.pausedAll[:0])
+}
+
+// ResumeAll resumes the sources paused by PauseAll, skipping any that were
+// stopped in the meantime.
+func (self *AudioManager) ResumeAll() {
+	if !self.allPaused {
+		return
+	}
+	self.allPaused = false
+	self.resumePaused(self.pausedAll)
+	self.pausedAll = self.pausedAll[:0]
+}
+
+// pausePlaying pauses every playing source and appends their IDs to dst.
+func (self *AudioManager) pausePlaying(dst []PlaybackID) []PlaybackID {
+	for id, source := range self.players {
+		if source.paused || !source.player.IsPlaying() {
+			continue
+		}
+		source.player.Pause()
+		source.paused = true
+		dst = append(dst, id)
+	}
+	return dst
+}
+
+// resumePaused resumes the sources in ids that still exist. Sources on a
+// paused bus, or paused again by a PauseAll in effect, are handed to that
+// pause so they resume with it.
+func (self *AudioManager) resumePaused(ids []PlaybackID) {
+	for _, id := range ids {
+		source, ok := self.players[id]
+		if !ok {
+			continue
+		}
+		if self.allPaused {
+			self.pausedAll = append(self.pausedAll, id)
+			continue
+		}
+		if source.bus.paused {
+			source.bus.pausedIDs = append(source.bus.pausedIDs, id)
+			continue
+		}
+		_ = self.Resume(id)
+	}
+}
+
+// IsAllPaused reports whether PauseAll is in effect.
+func (self *AudioManager) IsAllPaused() bool {
+	return self.allPaused
+}
+
 // IsPlaying checks if a specific playback instance is currently playing.
 func (self *AudioManager) IsPlaying(id PlaybackID) bool {
 	source, ok := self.players[id]
@@ -399,6 +520,12 @@ func (self *AudioManager) IsPlaying(id PlaybackID) bool {
 	return source.player.IsPlaying()
 }
 
+// IsPaused reports whether a playback instance exists and is paused.
+func (self *AudioManager) IsPaused(id PlaybackID) bool {
+	source, ok := self.players[id]
+	return ok && source.paused
+}
+
 // IsTrackPlaying checks if any instance of a given track ID is currently playing.
 func (self *AudioManager) IsTrackPlaying(trackID TrackID) bool {
 	for _, source := range self.players {
@@ -423,7 +550,7 @@ func (self *AudioManager) SetVolume(id PlaybackID, volume float64) error {
 		return fmt.Errorf("invalid playback ID: %d", id)
 	}
 	source.currentVolume = volume
-	source.player.SetVolume(volume)
+	source.applyVolume()
 	return nil
 }
 
@@ -440,6 +567,12 @@ func (self *AudioManager) SetPan(id PlaybackID, pan float64) error {
 // Update handles audio state updates and cleanup.
 func (self *AudioManager) Update(dt float64) {
 	for id, source := range self.players {
+		if source.paused {
+			continue
+		}
+		if source.spatial != nil {
+			self.spatialize(source)
+		}
 		if source.isFading {
 			volumeChange := (1.0 / source.fadeDuration) * dt
 			if source.fadeType == AudioFadeOut {
@@ -452,7 +585,7 @@ func (self *AudioManager) Update(dt float64) {
 				source.currentVolume = source.targetVolume
 				source.isFading = false
 			}
-			source.player.SetVolume(source.currentVolume)
+			source.applyVolume()
 		}
 		if !source.player.IsPlaying() && !source.isFading {
 			if err := self.Stop(id); err != nil {
@@ -0,0 +1,138 @@
+package katsu2d
+
+// Default audio bus names. Buses are created on first use, so games may use
+// any other names as well.
+const (
+	AudioBusMusic = "music"
+	AudioBusSFX   = "sfx"
+	AudioBusUI    = "ui"
+)
+
+// audioBus groups sources for volume, mute and pause control. Every bus
+// feeds the master bus.
+type audioBus struct {
+	parent    *audioBus
+	volume    float64
+	muted     bool
+	paused    bool
+	pausedIDs []PlaybackID
+}
+
+// effectiveVolume multiplies the bus volume by its parents'.
+func (self *audioBus) effectiveVolume() float64 {
+	if self == nil {
+		return 1
+	}
+	if self.muted {
+		return 0
+	}
+	return self.volume * self.parent.effectiveVolume()
+}
+
+func busOr(bus []string, fallback string) string {
+	if len(bus) > 0 && bus[0] != "" {
+		return bus[0]
+	}
+	return fallback
+}
+
+// bus returns the named bus, creating it at full volume.
+func (self *AudioManager) bus(name string) *audioBus {
+	bus, ok := self.buses[name]
+	if !ok {
+		bus = &audioBus{parent: self.master, volume: 1}
+		self.buses[name] = bus
+	}
+	return bus
+}
+
+// SetMasterVolume scales every bus.
+func (self *AudioManager) SetMasterVolume(volume float64) {
+	self.master.volume = volume
+	self.applyVolumes()
+}
+
+// MasterVolume returns the master volume.
+func (self *AudioManager) MasterVolume() float64 {
+	return self.master.volume
+}
+
+// SetMasterMuted silences or restores all audio.
+func (self *AudioManager) SetMasterMuted(muted bool) {
+	self.master.muted = muted
+	self.applyVolumes()
+}
+
+// SetBusVolume sets the volume of a bus, e.g. from a settings slider.
+func (self *AudioManager) SetBusVolume(name string, volume float64) {
+	self.bus(name).volume = volume
+	self.applyVolumes()
+}
+
+// BusVolume returns the volume of a bus.
+func (self *AudioManager) BusVolume(name string) float64 {
+	return self.bus(name).volume
+}
+
+// SetBusMuted mutes or unmutes a bus without changing its volume.
+func (self *AudioManager) SetBusMuted(name string, muted bool) {
+	self.bus(name).muted = muted
+	self.applyVolumes()
+}
+
+// IsBusMuted reports whether a bus is muted.
+func (self *AudioManager) IsBusMuted(name string) bool {
+	return self.bus(name).muted
+}
+
+// PauseBus pauses the playing sources of a bus. Sounds started on a paused
+// bus begin paused.
+func (self *AudioManager) PauseBus(name string) {
+	bus := self.bus(name)
+	if bus.paused {
+		return
+	}
+	bus.paused = true
+	bus.pausedIDs = bus.pausedIDs[:0]
+	for id, source := range self.players {
+		if source.bus != bus || source.paused || !source.player.IsPlaying() {
+			continue
+		}
+		source.player.Pause()
+		source.paused = true
+		bus.pausedIDs = append(bus.pausedIDs, id)
+	}
+}
+
+// ResumeBus resumes the sources paused by PauseBus. While PauseAll is in
+// effect they are handed over to ResumeAll instead.
+func (self *AudioManager) ResumeBus(name string) {
+	bus := self.bus(name)
+	if !bus.paused {
+		return
+	}
+	bus.paused = false
+	for _, id := range bus.pausedIDs {
+		if _, ok := self.players[id]; !ok {
+			continue
+		}
+		if self.allPaused {
+			self.pausedAll = append(self.pausedAll, id)
+			continue
+		}
+		_ = self.Resume(id)
+	}
+	bus.pausedIDs = bus.pausedIDs[:0]
+}
+
+// IsBusPaused reports whether a bus is paused.
+func (self *AudioManager) IsBusPaused(name string) bool {
+	return self.bus(name).paused
+}
+
+// applyVolumes pushes bus changes to every source.
+func (self *AudioManager) applyVolumes() {
+	for _, source := range self.players {
+		source.applyVolume()
+	}
+}
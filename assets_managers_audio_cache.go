@@ -0,0 +1,157 @@
+package katsu2d
+
+import (
+	"container/list"
+	"fmt"
+	"io"
+)
+
+// DefaultAudioDecodeBudget is the default memory budget, in bytes, for
+// tracks kept decoded in memory.
+const DefaultAudioDecodeBudget = 64 << 20
+
+// TrackMode selects how a track is decoded for playback.
+type TrackMode int
+
+const (
+	// TrackStream decodes the compressed bytes on every play. It suits long
+	// music that is played rarely.
+	TrackStream TrackMode = iota
+	// TrackMemory decodes the track to PCM once and plays from memory. It
+	// suits sounds played often. Decoded tracks share a memory budget and
+	// the least recently played ones are evicted first. Tracks that still
+	// have a player are pinned: they stay decoded and count against the
+	// budget until their players are stopped.
+	TrackMemory
+)
+
+// audioDecodeCache tracks decoded PCM sizes in least recently used order.
+type audioDecodeCache struct {
+	budget  int64
+	used    int64
+	order   *list.List
+	entries map[TrackID]*list.Element
+}
+
+type audioCacheEntry struct {
+	id   TrackID
+	size int64
+}
+
+func newAudioDecodeCache(budget int64) *audioDecodeCache {
+	return &audioDecodeCache{
+		budget:  budget,
+		order:   list.New(),
+		entries: make(map[TrackID]*list.Element),
+	}
+}
+
+// touch marks a cached track as most recently used.
+func (self *audioDecodeCache) touch(id TrackID) {
+	if el, ok := self.entries[id]; ok {
+		self.order.MoveToFront(el)
+	}
+}
+
+// add records a decoded track and returns the tracks evicted to fit it.
+func (self *audioDecodeCache) add(id TrackID, size int64, pinned func(TrackID) bool) []TrackID {
+	self.remove(id)
+	self.entries[id] = self.order.PushFront(audioCacheEntry{id: id, size: size})
+	self.used += size
+	return self.evict(pinned)
+}
+
+func (self *audioDecodeCache) remove(id TrackID) {
+	if el, ok := self.entries[id]; ok {
+		self.used -= el.Value.(audioCacheEntry).size
+		self.order.Remove(el)
+		delete(self.entries, id)
+	}
+}
+
+// evict drops least recently used tracks until the budget is met, always
+// keeping the most recent one and the pinned ones.
+func (self *audioDecodeCache) evict(pinned func(TrackID) bool) []TrackID {
+	var evicted []TrackID
+	for el := self.order.Back(); el != nil && el != self.order.Front() && self.used > self.budget; {
+		prev := el.Prev()
+		if entry := el.Value.(audioCacheEntry); !pinned(entry.id) {
+			self.remove(entry.id)
+			evicted = append(evicted, entry.id)
+		}
+		el = prev
+	}
+	return evicted
+}
+
+// SetTrackMode selects whether a track streams from its compressed bytes or
+// plays from a decoded in-memory copy.
+func (self *AudioManager) SetTrackMode(trackID TrackID, mode TrackMode) error {
+	track, ok := self.trackList[trackID]
+	if !ok {
+		return fmt.Errorf("invalid track ID: %d", trackID)
+	}
+	track.mode = mode
+	if mode == TrackStream {
+		self.decodeCache.remove(trackID)
+		track.pcm = nil
+	}
+	return nil
+}
+
+// Preload switches a track to TrackMemory and decodes it now, e.g. during
+// a loading screen, instead of on its first play.
+func (self *AudioManager) Preload(trackID TrackID) error {
+	if err := self.SetTrackMode(trackID, TrackMemory); err != nil {
+		return err
+	}
+	_, err := self.decodedPCM(trackID)
+	return err
+}
+
+// SetDecodeBudget sets the memory budget, in bytes, shared by TrackMemory
+// tracks, evicting decoded tracks that no longer fit.
+func (self *AudioManager) SetDecodeBudget(bytes int64) {
+	self.decodeCache.budget = bytes
+	for _, id := range self.decodeCache.evict(self.trackInUse) {
+		self.trackList[id].pcm = nil
+	}
+}
+
+// DecodedBytes returns the memory currently used by decoded tracks.
+func (self *AudioManager) DecodedBytes() int64 {
+	return self.decodeCache.used
+}
+
+// decodedPCM returns the decoded samples of a track, decoding and caching
+// them when needed.
+func (self *AudioManager) decodedPCM(trackID TrackID) ([]byte, error) {
+	track := self.trackList[trackID]
+	if track.pcm != nil {
+		self.decodeCache.touch(trackID)
+		return track.pcm, nil
+	}
+	reader, err := self.fromBytes(track.content, track.ext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode audio from bytes: %w", err)
+	}
+	pcm, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode audio: %w", err)
+	}
+	track.pcm = pcm
+	for _, id := range self.decodeCache.add(trackID, int64(len(pcm)), self.trackInUse) {
+		self.trackList[id].pcm = nil
+	}
+	return pcm, nil
+}
+
+// trackInUse reports whether a player still reads the track's samples.
+func (self *AudioManager) trackInUse(trackID TrackID) bool {
+	for _, source := range self.players {
+		if source.trackID == trackID {
+			return true
+		}
+	}
+	return false
+}
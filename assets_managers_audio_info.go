@@ -0,0 +1,115 @@
+package katsu2d
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"maps"
+)
+
+// TrackInfo describes a loaded audio track.
+type TrackInfo struct {
+	Format string
+	// Duration is the length of the track in seconds.
+	Duration   float64
+	SampleRate int
+	// Channels is the channel count of the source file. Playback is always
+	// stereo.
+	Channels int
+	// Tags holds user data attached with SetTrackTag.
+	Tags map[string]string
+}
+
+// TrackInfo returns the metadata of a loaded track. The track is probed on
+// the first call and the result cached.
+func (self *AudioManager) TrackInfo(trackID TrackID) (TrackInfo, error) {
+	track, ok := self.trackList[trackID]
+	if !ok {
+		return TrackInfo{}, fmt.Errorf("invalid track ID: %d", trackID)
+	}
+	if track.info == nil {
+		info, err := self.probeTrack(track)
+		if err != nil {
+			return TrackInfo{}, err
+		}
+		track.info = &info
+	}
+	info := *track.info
+	info.Tags = maps.Clone(track.tags)
+	return info, nil
+}
+
+// SetTrackTag attaches a user data value to a track, e.g. a title or the
+// subtitle file to sync with it.
+func (self *AudioManager) SetTrackTag(trackID TrackID, key, value string) error {
+	track, ok := self.trackList[trackID]
+	if !ok {
+		return fmt.Errorf("invalid track ID: %d", trackID)
+	}
+	track.tags[key] = value
+	return nil
+}
+
+// TrackTag returns a user data value attached to a track.
+func (self *AudioManager) TrackTag(trackID TrackID, key string) (string, bool) {
+	track, ok := self.trackList[trackID]
+	if !ok {
+		return "", false
+	}
+	value, ok := track.tags[key]
+	return value, ok
+}
+
+// probeTrack decodes the track header to measure its length.
+func (self *AudioManager) probeTrack(track *TrackData) (TrackInfo, error) {
+	reader, err := self.fromBytes(track.content, track.ext)
+	if err != nil {
+		return TrackInfo{}, fmt.Errorf("failed to decode audio from bytes: %w", err)
+	}
+	info := TrackInfo{Format: track.ext, Channels: probeChannels(track.content, track.ext)}
+	if stream, ok := reader.(interface {
+		Length() int64
+		SampleRate() int
+	}); ok {
+		info.SampleRate = stream.SampleRate()
+		if info.SampleRate > 0 {
+			// Decoded streams are 32-bit float stereo: 8 bytes per frame.
+			info.Duration = float64(stream.Length()) / float64(8*info.SampleRate)
+		}
+	}
+	return info, nil
+}
+
+// probeChannels reads the channel count from the file header, defaulting to
+// stereo when it cannot be determined.
+func probeChannels(content []byte, ext string) int {
+	switch ext {
+	case "wav":
+		for pos := 12; pos+8 <= len(content); {
+			size := int(binary.LittleEndian.Uint32(content[pos+4:]))
+			if string(content[pos:pos+4]) == "fmt " && pos+12 <= len(content) {
+				return int(binary.LittleEndian.Uint16(content[pos+10:]))
+			}
+			pos += 8 + size + size%2
+		}
+	case "ogg":
+		if i := bytes.Index(content[:min(len(content), 256)], []byte("\x01vorbis")); i >= 0 && i+11 < len(content) {
+			return int(content[i+11])
+		}
+	case "mp3":
+		pos := 0
+		if len(content) >= 10 && string(content[:3]) == "ID3" {
+			size := int(content[6])<<21 | int(content[7])<<14 | int(content[8])<<7 | int(content[9])
+			pos = 10 + size
+		}
+		for ; pos+3 < len(content); pos++ {
+			if content[pos] == 0xff && content[pos+1]&0xe0 == 0xe0 {
+				if content[pos+3]>>6 == 3 {
+					return 1
+				}
+				return 2
+			}
+		}
+	}
+	return 2
+}
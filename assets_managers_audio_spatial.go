@@ -0,0 +1,91 @@
+package katsu2d
+
+import (
+	"fmt"
+	"math"
+)
+
+// AudioRolloff controls how a positional sound fades and pans with its
+// distance to the listener. Sounds play at full volume within MinDistance,
+// fall silent at MaxDistance and are fully panned PanWidth units to the side.
+type AudioRolloff struct {
+	MinDistance float64
+	MaxDistance float64
+	PanWidth    float64
+}
+
+// DefaultAudioRolloff suits a screen a few hundred pixels wide.
+var DefaultAudioRolloff = AudioRolloff{MinDistance: 64, MaxDistance: 640, PanWidth: 320}
+
+// Attenuate returns the gain and pan of a sound offset by delta from the
+// listener.
+func (self AudioRolloff) Attenuate(delta Vector) (gain, pan float64) {
+	dist := delta.Length()
+	gain = 1.0
+	if dist > self.MinDistance {
+		span := self.MaxDistance - self.MinDistance
+		if span <= 0 {
+			gain = 0
+		} else {
+			gain = Clamp(1-(dist-self.MinDistance)/span, 0, 1)
+		}
+	}
+	if self.PanWidth > 0 {
+		pan = Clamp(delta.X/self.PanWidth, -1, 1)
+	}
+	return gain * gain, pan
+}
+
+// audioListener is where spatial sounds are heard from.
+type audioListener struct {
+	position Vector
+	rotation float64
+}
+
+// audioSpatial is the world position of a spatial source.
+type audioSpatial struct {
+	position Vector
+	rolloff  AudioRolloff
+}
+
+// SetListener places the listener for spatial sounds, usually at the camera.
+// Pan follows the listener's rotation.
+func (self *AudioManager) SetListener(position Vector, rotation float64) {
+	self.listener = audioListener{position: position, rotation: rotation}
+}
+
+// Listener returns the listener position and rotation.
+func (self *AudioManager) Listener() (Vector, float64) {
+	return self.listener.position, self.listener.rotation
+}
+
+// PlaySpatial plays a sound at a world position; its volume and pan follow
+// the listener until it stops. It plays on AudioBusSFX unless a bus is given.
+func (self *AudioManager) PlaySpatial(trackID TrackID, position Vector, rolloff AudioRolloff, loop bool, stackConfig *StackingConfig, bus ...string) (PlaybackID, error) {
+	spatial := &audioSpatial{position: position, rolloff: rolloff}
+	return self.internalPlay(trackID, 0, loop, 1.0, 0, AudioFadeIn, stackConfig, spatial, busOr(bus, AudioBusSFX))
+}
+
+// SetSpatialPosition moves a spatial sound.
+func (self *AudioManager) SetSpatialPosition(id PlaybackID, position Vector) error {
+	source, ok := self.players[id]
+	if !ok || source.spatial == nil {
+		return fmt.Errorf("invalid spatial playback ID: %d", id)
+	}
+	source.spatial.position = position
+	return nil
+}
+
+// spatialize updates the gain and pan of a spatial source.
+func (self *AudioManager) spatialize(source *AudioSource) {
+	delta := source.spatial.position.Sub(self.listener.position)
+	if self.listener.rotation != 0 {
+		delta = delta.Rotate(-self.listener.rotation)
+	}
+	gain, pan := source.spatial.rolloff.Attenuate(delta)
+	source.panStream.SetPan(pan)
+	if math.Abs(gain-source.gain) > 1e-4 {
+		source.gain = gain
+		source.applyVolume()
+	}
+}
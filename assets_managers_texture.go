@@ -29,6 +29,16 @@ type TextureManager struct {
 	atlasHeight int
 	// --- Configuration ---
 	useAtlas bool
+	// --- Derived textures ---
+	derived      map[derivedTextureKey]int
+	derivedInset map[int]Point
+	// memory is the estimated GPU size of the textures or atlas pages.
+	memory int64
+}
+
+type derivedTextureKey struct {
+	id     int
+	effect string
 }
 
 // TextureManagerOption is a functional option for configuring a TextureManager.
@@ -69,7 +79,7 @@ func NewTextureManager(opts ...TextureManagerOption) *TextureManager {
 	white.Fill(color.White)
 	// Initialize the appropriate storage and add the white texture.
 	if tm.useAtlas {
-		tm.atlases = append(tm.atlases, atlas.New(tm.atlasWidth, tm.atlasHeight, nil))
+		tm.addAtlas(tm.atlasWidth, tm.atlasHeight)
 		// The Add method will handle adding the white texture to the atlas.
 	} else {
 		tm.textures = append(tm.textures, white)
@@ -133,6 +143,20 @@ func (tm *TextureManager) Get(id int) *ebiten.Image {
 	return tm.textures[id]
 }
 
+// Page returns the image that draws of texture id sample from: its atlas
+// page when atlases are on, or the texture itself. Draws sharing a page can
+// share a draw call, unlike the images returned by Get, which are new
+// sub-images on every call in atlas mode.
+func (tm *TextureManager) Page(id int) *ebiten.Image {
+	if tm.useAtlas {
+		if id < 0 || id >= len(tm.images) {
+			id = 0
+		}
+		return tm.images[id].Atlas().Image()
+	}
+	return tm.Get(id)
+}
+
 // AtlasSize returns the default dimensions of the atlases created by the manager.
 // Returns 0, 0 if the atlas system is not in use.
 func (tm *TextureManager) AtlasSize() (int, int) {
@@ -148,6 +172,7 @@ func (tm *TextureManager) Add(img *ebiten.Image) int {
 	if !tm.useAtlas {
 		id := len(tm.textures)
 		tm.textures = append(tm.textures, img)
+		tm.memory += imageBytes(img)
 		return id
 	}
 	imgWidth := img.Bounds().Dx()
@@ -156,9 +181,7 @@ func (tm *TextureManager) Add(img *ebiten.Image) int {
 	// Handle images that are larger than the standard atlas size.
 	if imgWidth > tm.atlasWidth || imgHeight > tm.atlasHeight {
 		// Create a new atlas specifically for this large image.
-		newAtlas := atlas.New(imgWidth, imgHeight, nil)
-		tm.atlases = append(tm.atlases, newAtlas)
-		atlasImg = newAtlas.NewImage(imgWidth, imgHeight)
+		atlasImg = tm.addAtlas(imgWidth, imgHeight).NewImage(imgWidth, imgHeight)
 	} else {
 		// For regular-sized images, try to fit them into an existing atlas.
 		for _, a := range tm.atlases {
@@ -169,9 +192,7 @@ func (tm *TextureManager) Add(img *ebiten.Image) int {
 		}
 		// If no space was found, create a new atlas with the default size.
 		if atlasImg == nil {
-			newAtlas := atlas.New(tm.atlasWidth, tm.atlasHeight, nil)
-			tm.atlases = append(tm.atlases, newAtlas)
-			atlasImg = newAtlas.NewImage(imgWidth, imgHeight)
+			atlasImg = tm.addAtlas(tm.atlasWidth, tm.atlasHeight).NewImage(imgWidth, imgHeight)
 		}
 	}
 	// If atlasImg is still nil, it means we couldn't allocate space even in a
@@ -186,3 +207,62 @@ func (tm *TextureManager) Add(img *ebiten.Image) int {
 	tm.images = append(tm.images, atlasImg)
 	return id
 }
+
+// addAtlas creates an atlas page of the given size.
+func (tm *TextureManager) addAtlas(width, height int) *atlas.Atlas {
+	a := atlas.New(width, height, nil)
+	tm.atlases = append(tm.atlases, a)
+	tm.memory += int64(width) * int64(height) * bytesPerPixel
+	return a
+}
+
+// MemoryUsage returns the estimated GPU memory of the managed textures, or
+// of the atlas pages when atlases are on.
+func (tm *TextureManager) MemoryUsage() int64 {
+	return tm.memory
+}
+
+func imageBytes(img *ebiten.Image) int64 {
+	size := img.Bounds().Size()
+	return int64(size.X) * int64(size.Y) * bytesPerPixel
+}
+
+// Derive returns the ID of a texture generated from id by effect, creating
+// and caching it on first use. Call it at load time to avoid hitches. An
+// unknown id returns the default texture 0.
+func (tm *TextureManager) Derive(id int, effect ImageEffect) int {
+	if !tm.has(id) {
+		return 0
+	}
+	key := derivedTextureKey{id: id, effect: effectKey(effect)}
+	if derivedID, ok := tm.derived[key]; ok {
+		return derivedID
+	}
+	if tm.derived == nil {
+		tm.derived = make(map[derivedTextureKey]int)
+		tm.derivedInset = make(map[int]Point)
+	}
+	img, inset := effect.Apply(tm.Get(id))
+	derivedID := tm.Add(img)
+	if tm.useAtlas {
+		// The atlas keeps its own copy.
+		img.Deallocate()
+	}
+	tm.derived[key] = derivedID
+	tm.derivedInset[derivedID] = inset
+	return derivedID
+}
+
+// has reports whether id names a loaded texture.
+func (tm *TextureManager) has(id int) bool {
+	if tm.useAtlas {
+		return id >= 0 && id < len(tm.images) && tm.images[id] != nil
+	}
+	return id >= 0 && id < len(tm.textures) && tm.textures[id] != nil
+}
+
+// DerivedInset returns where the source texture's top-left corner lies inside
+// a derived texture, for aligning it with the original sprite.
+func (tm *TextureManager) DerivedInset(id int) Point {
+	return tm.derivedInset[id]
+}
@@ -16,6 +16,26 @@ type BatchRenderer struct {
 	currentImage *ebiten.Image
 	vertices     []ebiten.Vertex
 	indices      []uint16
+	view         Matrix
+	hasView      bool
+	screenSpace  bool
+	mirror       *Mirror
+	mirrorBuf    []ebiten.Vertex
+	stats        RenderStats
+	lastStats    RenderStats
+	scratch      []ebiten.Vertex
+	arena        *FrameArena
+	camera       *Camera
+}
+
+// RenderStats counts the work submitted to the GPU during a frame.
+type RenderStats struct {
+	DrawCalls int
+	Vertices  int
+	Triangles int
+	// FlushesSaved is the number of batch flushes avoided by systems that
+	// reorder draws by texture.
+	FlushesSaved int
 }
 
 // NewBatchRenderer creates a new batch renderer.
@@ -26,17 +46,89 @@ func NewBatchRenderer() *BatchRenderer {
 	}
 }
 
+// Camera returns the camera of the viewport being drawn, or nil.
+func (self *BatchRenderer) Camera() *Camera {
+	return self.camera
+}
+
 // GetScreen returns the current screen image being rendered to.
 func (self *BatchRenderer) GetScreen() *ebiten.Image {
 	return self.screen
 }
 
+// Scratch returns a reusable buffer of n vertices for building a mesh before
+// passing it to AddCustomMeshes, which copies it. The engine's renderer
+// takes it from the frame arena, so it stays valid until the end of the
+// frame; other renderers share one buffer, valid until the next call.
+func (self *BatchRenderer) Scratch(n int) []ebiten.Vertex {
+	if self.arena != nil {
+		return arenaBuffer[ebiten.Vertex](self.arena, n)[:n]
+	}
+	if cap(self.scratch) < n {
+		self.scratch = make([]ebiten.Vertex, n)
+	}
+	return self.scratch[:n]
+}
+
 // Begin prepares the renderer for a new frame.
 func (self *BatchRenderer) Begin(screen *ebiten.Image) {
 	self.screen = screen
 	self.vertices = self.vertices[:0]
 	self.indices = self.indices[:0]
 	self.currentImage = nil
+	self.screenSpace = false
+}
+
+// SetView transforms everything drawn afterwards, e.g. by a camera matrix.
+func (self *BatchRenderer) SetView(view Matrix) {
+	self.Flush()
+	self.view = view
+	self.hasView = true
+}
+
+// ResetView stops applying the view transform.
+func (self *BatchRenderer) ResetView() {
+	self.Flush()
+	self.view = Matrix{}
+	self.hasView = false
+}
+
+// SetScreenSpace suspends the view transform while on, so following draws
+// use screen coordinates. The view itself is kept.
+func (self *BatchRenderer) SetScreenSpace(on bool) {
+	if on == self.screenSpace {
+		return
+	}
+	self.Flush()
+	self.screenSpace = on
+}
+
+// ScreenSpace reports whether the view transform is suspended.
+func (self *BatchRenderer) ScreenSpace() bool {
+	return self.screenSpace
+}
+
+// View returns the active view transform, if any.
+func (self *BatchRenderer) View() (Matrix, bool) {
+	return self.view, self.hasView
+}
+
+// Stats returns the counters of the last completed frame.
+func (self *BatchRenderer) Stats() RenderStats {
+	return self.lastStats
+}
+
+// EndFrame publishes the counters gathered since the previous call and
+// starts counting a new frame.
+func (self *BatchRenderer) EndFrame() {
+	self.Flush()
+	self.lastStats = self.stats
+	self.stats = RenderStats{}
+}
+
+// AddFlushSavings records batch flushes avoided by reordering draws.
+func (self *BatchRenderer) AddFlushSavings(n int) {
+	self.stats.FlushesSaved += n
 }
 
 // Flush draws the current batch.
@@ -44,7 +136,19 @@ func (self *BatchRenderer) Flush() {
 	if len(self.vertices) == 0 {
 		return
 	}
+	if self.mirror != nil && !self.screenSpace {
+		self.drawMirrored()
+	}
+	if self.hasView && !self.screenSpace {
+		for i := range self.vertices {
+			x, y := self.view.Apply(float64(self.vertices[i].DstX), float64(self.vertices[i].DstY))
+			self.vertices[i].DstX, self.vertices[i].DstY = float32(x), float32(y)
+		}
+	}
 	self.screen.DrawTriangles(self.vertices, self.indices, self.currentImage, nil)
+	self.stats.DrawCalls++
+	self.stats.Vertices += len(self.vertices)
+	self.stats.Triangles += len(self.indices) / 3
 	self.vertices = self.vertices[:0]
 	self.indices = self.indices[:0]
 	self.currentImage = nil
@@ -71,6 +175,51 @@ func (self *BatchRenderer) AddCustomMeshes(verts []ebiten.Vertex, inds []uint16,
 	}
 }
 
+// DrawMesh flushes the batch and draws verts in a single call with the given
+// blend mode. Unlike AddCustomMeshes it takes 32-bit indices, so the mesh is
+// not limited to 65534 vertices, and verts is not modified.
+func (self *BatchRenderer) DrawMesh(verts []ebiten.Vertex, inds []uint32, img *ebiten.Image, blend ebiten.Blend) {
+	if len(verts) == 0 || len(inds) == 0 {
+		return
+	}
+	self.Flush()
+	if self.hasView && !self.screenSpace {
+		view := self.Scratch(len(verts))
+		copy(view, verts)
+		for i := range view {
+			x, y := self.view.Apply(float64(view[i].DstX), float64(view[i].DstY))
+			view[i].DstX, view[i].DstY = float32(x), float32(y)
+		}
+		verts = view
+	}
+	self.screen.DrawTriangles32(verts, inds, img, &ebiten.DrawTrianglesOptions{Blend: blend})
+	self.stats.DrawCalls++
+	self.stats.Vertices += len(verts)
+	self.stats.Triangles += len(inds) / 3
+}
+
+// DrawShader flushes the batch and draws verts with shader, the view
+// applied. verts is not modified.
+func (self *BatchRenderer) DrawShader(verts []ebiten.Vertex, inds []uint16, shader *ebiten.Shader, opts *ebiten.DrawTrianglesShaderOptions) {
+	if len(verts) == 0 || len(inds) == 0 {
+		return
+	}
+	self.Flush()
+	if self.hasView && !self.screenSpace {
+		view := self.Scratch(len(verts))
+		copy(view, verts)
+		for i := range view {
+			x, y := self.view.Apply(float64(view[i].DstX), float64(view[i].DstY))
+			view[i].DstX, view[i].DstY = float32(x), float32(y)
+		}
+		verts = view
+	}
+	self.screen.DrawTrianglesShader(verts, inds, shader, opts)
+	self.stats.DrawCalls++
+	self.stats.Vertices += len(verts)
+	self.stats.Triangles += len(inds) / 3
+}
+
 // AddQuad draws a quad (sprite) with specified source rectangle and destination size.
 func (self *BatchRenderer) AddQuad(
 	pos, offset, origin, scale Vector, rotation float64, // transform parameters
@@ -78,16 +227,19 @@ func (self *BatchRenderer) AddQuad(
 	srcMinX, srcMinY, srcMaxX, srcMaxY float32, // source rectangle
 	// destination size
 	destW, destH float64) {
-	totalEstimation := len(self.vertices) + 4
-	if totalEstimation >= maxVertices {
-		self.Flush()
-	}
-	if img != self.currentImage && self.currentImage != nil {
-		self.Flush()
-	}
+	quad := QuadVertices(pos, offset, origin, scale, rotation, clr,
+		srcMinX, srcMinY, srcMaxX, srcMaxY, destW, destH)
+	self.addQuadVertices(quad, img)
+}
+
+// QuadVertices computes the four corner vertices that AddQuad would submit.
+func QuadVertices(
+	pos, offset, origin, scale Vector, rotation float64,
+	clr color.RGBA,
+	srcMinX, srcMinY, srcMaxX, srcMaxY float32,
+	destW, destH float64) [4]ebiten.Vertex {
 	pos = pos.Sub(offset).Sub(origin)
 
-	self.currentImage = img
 	srcProjMinX := pos.X
 	srcProjMinY := pos.Y
 	srcProjMaxX := srcProjMinX + destW*scale.X
@@ -105,17 +257,58 @@ func (self *BatchRenderer) AddQuad(
 		p2 = p2.RotateAround(srcOffset, rotation)
 		p3 = p3.RotateAround(srcOffset, rotation)
 	}
+	return quadFromCorners(p0, p1, p2, p3, clr, srcMinX, srcMinY, srcMaxX, srcMaxY)
+}
+
+// QuadVerticesMatrix computes the four corner vertices that AddQuadMatrix would submit.
+func QuadVerticesMatrix(
+	matrix Matrix,
+	clr color.RGBA,
+	srcMinX, srcMinY, srcMaxX, srcMaxY float32,
+	destW, destH float64) [4]ebiten.Vertex {
+	x0, y0 := matrix.Apply(0, 0)
+	x1, y1 := matrix.Apply(destW, 0)
+	x2, y2 := matrix.Apply(destW, destH)
+	x3, y3 := matrix.Apply(0, destH)
+	return quadFromCorners(V(x0, y0), V(x1, y1), V(x2, y2), V(x3, y3), clr,
+		srcMinX, srcMinY, srcMaxX, srcMaxY)
+}
+
+func quadFromCorners(
+	p0, p1, p2, p3 Vector,
+	clr color.RGBA,
+	srcMinX, srcMinY, srcMaxX, srcMaxY float32) [4]ebiten.Vertex {
 	cr, cg, cb, ca := float32(clr.R)/255, float32(clr.G)/255, float32(clr.B)/255, float32(clr.A)/255
+	return [4]ebiten.Vertex{
+		{DstX: AdjustDestinationPixel(float32(p0.X)), DstY: AdjustDestinationPixel(float32(p0.Y)), SrcX: srcMinX, SrcY: srcMinY, ColorR: cr, ColorG: cg, ColorB: cb, ColorA: ca},
+		{DstX: AdjustDestinationPixel(float32(p1.X)), DstY: AdjustDestinationPixel(float32(p1.Y)), SrcX: srcMaxX, SrcY: srcMinY, ColorR: cr, ColorG: cg, ColorB: cb, ColorA: ca},
+		{DstX: AdjustDestinationPixel(float32(p2.X)), DstY: AdjustDestinationPixel(float32(p2.Y)), SrcX: srcMaxX, SrcY: srcMaxY, ColorR: cr, ColorG: cg, ColorB: cb, ColorA: ca},
+		{DstX: AdjustDestinationPixel(float32(p3.X)), DstY: AdjustDestinationPixel(float32(p3.Y)), SrcX: srcMinX, SrcY: srcMaxY, ColorR: cr, ColorG: cg, ColorB: cb, ColorA: ca},
+	}
+}
+
+// addQuadVertices appends a precomputed quad to the batch.
+func (self *BatchRenderer) addQuadVertices(quad [4]ebiten.Vertex, img *ebiten.Image) {
+	totalEstimation := len(self.vertices) + 4
+	if totalEstimation >= maxVertices {
+		self.Flush()
+	}
+	if img != self.currentImage && self.currentImage != nil {
+		self.Flush()
+	}
+	self.currentImage = img
 	vertIndex := len(self.vertices)
-	self.vertices = append(self.vertices,
-		ebiten.Vertex{DstX: AdjustDestinationPixel(float32(p0.X)), DstY: AdjustDestinationPixel(float32(p0.Y)), SrcX: srcMinX, SrcY: srcMinY, ColorR: cr, ColorG: cg, ColorB: cb, ColorA: ca},
-		ebiten.Vertex{DstX: AdjustDestinationPixel(float32(p1.X)), DstY: AdjustDestinationPixel(float32(p1.Y)), SrcX: srcMaxX, SrcY: srcMinY, ColorR: cr, ColorG: cg, ColorB: cb, ColorA: ca},
-		ebiten.Vertex{DstX: AdjustDestinationPixel(float32(p2.X)), DstY: AdjustDestinationPixel(float32(p2.Y)), SrcX: srcMaxX, SrcY: srcMaxY, ColorR: cr, ColorG: cg, ColorB: cb, ColorA: ca},
-		ebiten.Vertex{DstX: AdjustDestinationPixel(float32(p3.X)), DstY: AdjustDestinationPixel(float32(p3.Y)), SrcX: srcMinX, SrcY: srcMaxY, ColorR: cr, ColorG: cg, ColorB: cb, ColorA: ca},
-	)
+	self.vertices = append(self.vertices, quad[:]...)
 	self.indices = append(self.indices, uint16(vertIndex), uint16(vertIndex+1), uint16(vertIndex+2), uint16(vertIndex), uint16(vertIndex+2), uint16(vertIndex+3))
 }
 
+// AddQuads submits a run of precomputed quads sharing the same image.
+func (self *BatchRenderer) AddQuads(quads []ebiten.Vertex, img *ebiten.Image) {
+	for start := 0; start+4 <= len(quads); start += 4 {
+		self.addQuadVertices([4]ebiten.Vertex(quads[start:start+4]), img)
+	}
+}
+
 // AddTriangleStrip draws a triangle strip.
 func (self *BatchRenderer) AddTriangleStrip(verts []ebiten.Vertex, img *ebiten.Image) {
 	totalEstimation := len(self.vertices) + len(verts)
@@ -143,3 +336,14 @@ func (self *BatchRenderer) AddTriangleStrip(verts []ebiten.Vertex, img *ebiten.I
 		}
 	}
 }
+
+// AddQuadMatrix draws a quad (sprite) whose local rectangle of size destW x destH
+// is transformed by the given matrix. This supports skew and arbitrary pivots.
+func (self *BatchRenderer) AddQuadMatrix(
+	matrix Matrix,
+	img *ebiten.Image, clr color.RGBA,
+	srcMinX, srcMinY, srcMaxX, srcMaxY float32,
+	destW, destH float64) {
+	quad := QuadVerticesMatrix(matrix, clr, srcMinX, srcMinY, srcMaxX, srcMaxY, destW, destH)
+	self.addQuadVertices(quad, img)
+}
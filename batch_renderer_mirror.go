@@ -0,0 +1,98 @@
+package katsu2d
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// MirrorMode selects which reflected copies the renderer draws.
+type MirrorMode int
+
+const (
+	// MirrorHorizontal reflects across the vertical line x = Axis.X.
+	MirrorHorizontal MirrorMode = 1 << iota
+	// MirrorVertical reflects across the horizontal line y = Axis.Y.
+	MirrorVertical
+	// MirrorQuad draws all three reflections for four-way symmetry.
+	MirrorQuad = MirrorHorizontal | MirrorVertical
+)
+
+// Mirror draws reflected copies of everything submitted while it is set,
+// in world space before the view transform. Copies draw beneath the
+// originals; screen space draws are not mirrored.
+type Mirror struct {
+	Mode MirrorMode
+	Axis Vector
+	// Alpha scales the opacity of the copies. Zero is treated as one.
+	Alpha float64
+	// FadeDistance, when positive, fades copies out linearly with their
+	// distance from the axis, e.g. for reflection floors.
+	FadeDistance float64
+}
+
+// NewReflectionFloor creates a mirror reflecting the scene below the line
+// y, fading out over fade pixels.
+func NewReflectionFloor(y, alpha, fade float64) *Mirror {
+	return &Mirror{Mode: MirrorVertical, Axis: V(0, y), Alpha: alpha, FadeDistance: fade}
+}
+
+// SetMirror draws reflected copies of following draws, or stops when m is
+// nil.
+func (self *BatchRenderer) SetMirror(m *Mirror) {
+	self.Flush()
+	self.mirror = m
+}
+
+// Mirror returns the active mirror, if any.
+func (self *BatchRenderer) Mirror() *Mirror {
+	return self.mirror
+}
+
+// drawMirrored draws the reflected copies of the current batch.
+func (self *BatchRenderer) drawMirrored() {
+	m := self.mirror
+	alpha := m.Alpha
+	if alpha == 0 {
+		alpha = 1
+	}
+	for _, mode := range [3]MirrorMode{MirrorHorizontal, MirrorVertical, MirrorQuad} {
+		if m.Mode&mode != mode {
+			continue
+		}
+		if cap(self.mirrorBuf) < len(self.vertices) {
+			self.mirrorBuf = make([]ebiten.Vertex, len(self.vertices))
+		}
+		verts := self.mirrorBuf[:len(self.vertices)]
+		copy(verts, self.vertices)
+		for i := range verts {
+			v := &verts[i]
+			dist := 0.0
+			if mode&MirrorHorizontal != 0 {
+				v.DstX = float32(2*m.Axis.X) - v.DstX
+				dist = max(dist, math.Abs(float64(v.DstX)-m.Axis.X))
+			}
+			if mode&MirrorVertical != 0 {
+				v.DstY = float32(2*m.Axis.Y) - v.DstY
+				dist = max(dist, math.Abs(float64(v.DstY)-m.Axis.Y))
+			}
+			a := alpha
+			if m.FadeDistance > 0 {
+				a *= Clamp(1-dist/m.FadeDistance, 0, 1)
+			}
+			// Vertex colors are premultiplied, so all channels scale.
+			v.ColorR *= float32(a)
+			v.ColorG *= float32(a)
+			v.ColorB *= float32(a)
+			v.ColorA *= float32(a)
+			if self.hasView && !self.screenSpace {
+				x, y := self.view.Apply(float64(v.DstX), float64(v.DstY))
+				v.DstX, v.DstY = float32(x), float32(y)
+			}
+		}
+		self.screen.DrawTriangles(verts, self.indices, self.currentImage, nil)
+		self.stats.DrawCalls++
+		self.stats.Vertices += len(verts)
+		self.stats.Triangles += len(self.indices) / 3
+	}
+}
@@ -0,0 +1,426 @@
+package katsu2d
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/edwinsyarief/teishoku"
+)
+
+// Blackboard is a key-value store for shared game state such as gold, flags
+// and quest progress. Values are ints, floats, bools or strings.
+type Blackboard struct {
+	values    map[string]any
+	listeners map[string][]func(BlackboardChangedEvent)
+	world     *teishoku.World
+}
+
+// NewBlackboard creates an empty blackboard.
+func NewBlackboard() *Blackboard {
+	return &Blackboard{
+		values:    make(map[string]any),
+		listeners: make(map[string][]func(BlackboardChangedEvent)),
+	}
+}
+
+// GetVars returns the world's blackboard, creating it on first use. Changes
+// made through it are also published on the world's event bus.
+func GetVars(w *teishoku.World) *Blackboard {
+	return EnsureResource(w, func() *Blackboard {
+		bb := NewBlackboard()
+		bb.world = w
+		return bb
+	})
+}
+
+// Set stores a value and notifies listeners if it changed. Values that
+// cannot be compared, such as slices and maps, always notify.
+func (self *Blackboard) Set(key string, value any) {
+	old, existed := self.values[key]
+	if existed && sameBlackboardValue(old, value) {
+		return
+	}
+	self.values[key] = value
+	self.notify(BlackboardChangedEvent{Key: key, Old: old, New: value})
+}
+
+// sameBlackboardValue reports whether a and b are equal, treating values
+// of non-comparable types as different instead of panicking.
+func sameBlackboardValue(a, b any) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	ta := reflect.TypeOf(a)
+	return ta == reflect.TypeOf(b) && ta.Comparable() && a == b
+}
+
+// SetInt stores an int value.
+func (self *Blackboard) SetInt(key string, value int) {
+	self.Set(key, value)
+}
+
+// SetFloat stores a float64 value.
+func (self *Blackboard) SetFloat(key string, value float64) {
+	self.Set(key, value)
+}
+
+// SetBool stores a bool value.
+func (self *Blackboard) SetBool(key string, value bool) {
+	self.Set(key, value)
+}
+
+// SetString stores a string value.
+func (self *Blackboard) SetString(key string, value string) {
+	self.Set(key, value)
+}
+
+// AddInt adds delta to an int value and returns the result.
+func (self *Blackboard) AddInt(key string, delta int) int {
+	v := self.GetInt(key) + delta
+	self.SetInt(key, v)
+	return v
+}
+
+// AddFloat adds delta to a float value and returns the result.
+func (self *Blackboard) AddFloat(key string, delta float64) float64 {
+	v := self.GetFloat(key) + delta
+	self.SetFloat(key, v)
+	return v
+}
+
+// Get returns the raw value stored under key.
+func (self *Blackboard) Get(key string) (any, bool) {
+	v, ok := self.values[key]
+	return v, ok
+}
+
+// GetInt returns the value as an int, converting floats, or 0.
+func (self *Blackboard) GetInt(key string) int {
+	switch v := self.values[key].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	}
+	return 0
+}
+
+// GetFloat returns the value as a float64, converting ints, or 0.
+func (self *Blackboard) GetFloat(key string) float64 {
+	switch v := self.values[key].(type) {
+	case int:
+		return float64(v)
+	case float64:
+		return v
+	}
+	return 0
+}
+
+// GetBool returns the value as a bool, or false.
+func (self *Blackboard) GetBool(key string) bool {
+	v, _ := self.values[key].(bool)
+	return v
+}
+
+// GetString returns the value as a string, or "".
+func (self *Blackboard) GetString(key string) string {
+	v, _ := self.values[key].(string)
+	return v
+}
+
+// Has reports whether key is set.
+func (self *Blackboard) Has(key string) bool {
+	_, ok := self.values[key]
+	return ok
+}
+
+// Delete removes key and notifies listeners with a nil new value.
+func (self *Blackboard) Delete(key string) {
+	old, ok := self.values[key]
+	if !ok {
+		return
+	}
+	delete(self.values, key)
+	self.notify(BlackboardChangedEvent{Key: key, Old: old})
+}
+
+// Keys returns all keys in sorted order.
+func (self *Blackboard) Keys() []string {
+	return slices.Sorted(maps.Keys(self.values))
+}
+
+// OnChange registers fn to be called whenever key changes.
+// An empty key listens to every change.
+func (self *Blackboard) OnChange(key string, fn func(BlackboardChangedEvent)) {
+	self.listeners[key] = append(self.listeners[key], fn)
+}
+
+func (self *Blackboard) notify(ev BlackboardChangedEvent) {
+	for _, fn := range self.listeners[ev.Key] {
+		fn(ev)
+	}
+	for _, fn := range self.listeners[""] {
+		fn(ev)
+	}
+	publishTo(self.world, ev)
+}
+
+// Snapshot returns a copy of all values, suitable for saving.
+func (self *Blackboard) Snapshot() map[string]any {
+	return maps.Clone(self.values)
+}
+
+// Restore replaces all values with the snapshot without notifying listeners.
+func (self *Blackboard) Restore(values map[string]any) {
+	self.values = make(map[string]any, len(values))
+	for k, v := range values {
+		self.values[k] = normalizeBlackboardValue(v)
+	}
+}
+
+// normalizeBlackboardValue folds the sized integer and float types some
+// decoders produce back into int or float64. A float64 stays a float64,
+// even when whole, so values keep the type they were set with.
+func normalizeBlackboardValue(v any) any {
+	switch n := v.(type) {
+	case int32:
+		return int(n)
+	case int64:
+		return int(n)
+	case uint32:
+		return int(n)
+	case uint64:
+		return int(n)
+	case float32:
+		return float64(n)
+	}
+	return v
+}
+
+// Evaluate parses and evaluates a condition against the blackboard, e.g.
+// `gold >= 10 && !door_open || name == "ruby"`. Bare keys are truthy when
+// they hold true, a non-zero number or a non-empty string.
+func (self *Blackboard) Evaluate(expr string) (bool, error) {
+	p := &conditionParser{bb: self, tokens: tokenizeCondition(expr)}
+	result, err := p.parseOr()
+	if err != nil {
+		return false, err
+	}
+	if p.pos < len(p.tokens) {
+		return false, fmt.Errorf("unexpected %q in condition %q", p.tokens[p.pos], expr)
+	}
+	return result, nil
+}
+
+// MustEvaluate is like Evaluate but treats errors as false.
+func (self *Blackboard) MustEvaluate(expr string) bool {
+	ok, _ := self.Evaluate(expr)
+	return ok
+}
+
+func tokenizeCondition(expr string) []string {
+	var tokens []string
+	for i := 0; i < len(expr); {
+		c := rune(expr[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '"' || c == '\'':
+			j := i + 1
+			for j < len(expr) && rune(expr[j]) != c {
+				j++
+			}
+			tokens = append(tokens, expr[i:min(j+1, len(expr))])
+			i = j + 1
+		case strings.ContainsRune("()", c):
+			tokens = append(tokens, string(c))
+			i++
+		case strings.ContainsRune("&|=!<>", c):
+			j := i + 1
+			if j < len(expr) && strings.ContainsRune("&|=", rune(expr[j])) {
+				j++
+			}
+			tokens = append(tokens, expr[i:j])
+			i = j
+		default:
+			j := i
+			for j < len(expr) && !unicode.IsSpace(rune(expr[j])) && !strings.ContainsRune("()&|=!<>\"'", rune(expr[j])) {
+				j++
+			}
+			tokens = append(tokens, expr[i:j])
+			i = j
+		}
+	}
+	return tokens
+}
+
+type conditionParser struct {
+	bb     *Blackboard
+	tokens []string
+	pos    int
+}
+
+func (self *conditionParser) peek() string {
+	if self.pos < len(self.tokens) {
+		return self.tokens[self.pos]
+	}
+	return ""
+}
+
+func (self *conditionParser) next() string {
+	tok := self.peek()
+	self.pos++
+	return tok
+}
+
+func (self *conditionParser) parseOr() (bool, error) {
+	left, err := self.parseAnd()
+	if err != nil {
+		return false, err
+	}
+	for self.peek() == "||" {
+		self.next()
+		right, err := self.parseAnd()
+		if err != nil {
+			return false, err
+		}
+		left = left || right
+	}
+	return left, nil
+}
+
+func (self *conditionParser) parseAnd() (bool, error) {
+	left, err := self.parseUnary()
+	if err != nil {
+		return false, err
+	}
+	for self.peek() == "&&" {
+		self.next()
+		right, err := self.parseUnary()
+		if err != nil {
+			return false, err
+		}
+		left = left && right
+	}
+	return left, nil
+}
+
+func (self *conditionParser) parseUnary() (bool, error) {
+	switch self.peek() {
+	case "!":
+		self.next()
+		v, err := self.parseUnary()
+		return !v, err
+	case "(":
+		self.next()
+		v, err := self.parseOr()
+		if err != nil {
+			return false, err
+		}
+		if self.next() != ")" {
+			return false, fmt.Errorf("missing closing parenthesis")
+		}
+		return v, nil
+	case "":
+		return false, fmt.Errorf("unexpected end of condition")
+	}
+	left := self.operand(self.next())
+	switch op := self.peek(); op {
+	case "==", "!=", "<", "<=", ">", ">=":
+		self.next()
+		if self.peek() == "" {
+			return false, fmt.Errorf("missing operand after %q", op)
+		}
+		return compareConditionValues(left, self.operand(self.next()), op), nil
+	}
+	return truthy(left), nil
+}
+
+// operand resolves a literal or a blackboard key.
+func (self *conditionParser) operand(tok string) any {
+	if len(tok) >= 2 && (tok[0] == '"' || tok[0] == '\'') {
+		return strings.Trim(tok, "\"'")
+	}
+	switch tok {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	if n, err := strconv.ParseFloat(tok, 64); err == nil {
+		return n
+	}
+	v, _ := self.bb.Get(tok)
+	return v
+}
+
+func truthy(v any) bool {
+	switch t := v.(type) {
+	case bool:
+		return t
+	case int:
+		return t != 0
+	case float64:
+		return t != 0
+	case string:
+		return t != ""
+	}
+	return false
+}
+
+func compareConditionValues(a, b any, op string) bool {
+	af, aNum := toConditionNumber(a)
+	bf, bNum := toConditionNumber(b)
+	if aNum && bNum {
+		switch op {
+		case "==":
+			return af == bf
+		case "!=":
+			return af != bf
+		case "<":
+			return af < bf
+		case "<=":
+			return af <= bf
+		case ">":
+			return af > bf
+		case ">=":
+			return af >= bf
+		}
+	}
+	switch op {
+	case "==":
+		return sameBlackboardValue(a, b)
+	case "!=":
+		return !sameBlackboardValue(a, b)
+	}
+	as, aStr := a.(string)
+	bs, bStr := b.(string)
+	if aStr && bStr {
+		switch op {
+		case "<":
+			return as < bs
+		case "<=":
+			return as <= bs
+		case ">":
+			return as > bs
+		case ">=":
+			return as >= bs
+		}
+	}
+	return false
+}
+
+func toConditionNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
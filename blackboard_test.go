@@ -0,0 +1,87 @@
+package katsu2d
+
+import "testing"
+
+// TestBlackboardEvaluate verifies comparisons, logic operators and
+// grouping in blackboard conditions.
+func TestBlackboardEvaluate(t *testing.T) {
+	bb := NewBlackboard()
+	bb.SetInt("gold", 12)
+	bb.SetFloat("speed", 1.5)
+	bb.SetBool("door_open", false)
+	bb.SetString("name", "ruby")
+
+	cases := map[string]bool{
+		"gold >= 10":                    true,
+		"gold < 10":                     false,
+		"gold == 12 && speed > 1":       true,
+		"!door_open":                    true,
+		"door_open || name == \"ruby\"": true,
+		"gold >= 10 && !door_open || name == 'x'": true,
+		"gold > 20 && (door_open || speed > 1)":   false,
+		"(gold > 20 || speed > 1) && !door_open":  true,
+		"name != 'ruby'":                          false,
+		"missing":                                 false,
+		"!missing":                                true,
+	}
+	for expr, want := range cases {
+		got, err := bb.Evaluate(expr)
+		if err != nil {
+			t.Errorf("Evaluate(%q) returned error: %v", expr, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("Evaluate(%q) = %v, want %v", expr, got, want)
+		}
+	}
+}
+
+// TestBlackboardEvaluateErrors verifies that malformed conditions fail.
+func TestBlackboardEvaluateErrors(t *testing.T) {
+	bb := NewBlackboard()
+	for _, expr := range []string{"", "gold >=", "(gold > 1", "gold > 1 )"} {
+		if _, err := bb.Evaluate(expr); err == nil {
+			t.Errorf("Evaluate(%q) returned no error", expr)
+		}
+	}
+}
+
+// TestBlackboardEvaluateNonComparable verifies that comparing slices does
+// not panic.
+func TestBlackboardEvaluateNonComparable(t *testing.T) {
+	bb := NewBlackboard()
+	bb.Set("list", []int{1, 2})
+	if bb.MustEvaluate("list == list") {
+		t.Error("non-comparable values compared equal")
+	}
+	if !bb.MustEvaluate("list != list") {
+		t.Error("non-comparable values compared not different")
+	}
+}
+
+// TestBlackboardRestoreKeepsTypes verifies that values keep their types
+// across Snapshot and Restore, and that sized decoder types are folded.
+func TestBlackboardRestoreKeepsTypes(t *testing.T) {
+	bb := NewBlackboard()
+	bb.SetFloat("speed", 2)
+	bb.SetInt("gold", 3)
+
+	restored := NewBlackboard()
+	snapshot := bb.Snapshot()
+	snapshot["level"] = int64(4)
+	snapshot["scale"] = float32(0.5)
+	restored.Restore(snapshot)
+
+	if v, _ := restored.Get("speed"); v != 2.0 {
+		t.Errorf("speed = %#v, want float64 2", v)
+	}
+	if v, _ := restored.Get("gold"); v != 3 {
+		t.Errorf("gold = %#v, want int 3", v)
+	}
+	if v, _ := restored.Get("level"); v != 4 {
+		t.Errorf("level = %#v, want int 4", v)
+	}
+	if v, _ := restored.Get("scale"); v != 0.5 {
+		t.Errorf("scale = %#v, want float64 0.5", v)
+	}
+}
@@ -0,0 +1,319 @@
+package katsu2d
+
+import (
+	"image"
+	"math"
+
+	"github.com/edwinsyarief/teishoku"
+)
+
+// Camera maps world space to a viewport. Position is the world point shown
+// at the viewport center.
+type Camera struct {
+	Position Vector
+	Zoom     float64
+	Rotation float64
+	// Width and Height are the viewport size in virtual pixels.
+	Width, Height int
+	// Viewport is the part of the screen the camera draws into, as
+	// fractions from 0 to 1; empty covers the whole screen. Screen
+	// coordinates of the camera are relative to its viewport.
+	Viewport Rectangle
+
+	// Target is followed by CameraSystem while Following is set.
+	Target    teishoku.Entity
+	Following bool
+	// FollowSpeed smooths following; 0 snaps to the target every frame.
+	FollowSpeed  float64
+	FollowOffset Vector
+
+	// LookAheadDistance shifts the focus up to this far ahead of the target
+	// so more of where it is heading is visible; 0 disables look-ahead.
+	LookAheadDistance float64
+	// LookAheadTime is how many seconds of the target's velocity to lead by.
+	LookAheadTime float64
+	// LookAheadSpeed smooths changes of the look-ahead; 0 snaps.
+	LookAheadSpeed float64
+	// Facing, when non-zero, points the look-ahead along this direction at
+	// the full LookAheadDistance instead of following the velocity.
+	Facing Vector
+
+	// Bounds confines the viewport to level regions. The region holding the
+	// follow target, or the camera when not following, is used.
+	Bounds []CameraRegion
+	// BoundsBlendTime blends from one region's confinement to the next over
+	// this many seconds when the target crosses between regions.
+	BoundsBlendTime float64
+
+	// PixelPerfect snaps the rendered position to whole virtual pixels so
+	// pixel art never shimmers.
+	PixelPerfect bool
+	// SubpixelScroll shifts the upscaled image by the snapped-away fraction
+	// to keep scrolling smooth in PixelPerfect mode.
+	SubpixelScroll bool
+	// IntegerZoom renders Zoom rounded to a whole number of virtual pixels
+	// per world unit, or its reciprocal below 1, in PixelPerfect mode.
+	IntegerZoom bool
+	// VirtualWidth and VirtualHeight are the resolution of the pixel art
+	// grid when it is coarser than the viewport, e.g. 320x180 art in a
+	// 1280x720 viewport. PixelPerfect then snaps to whole art pixels; 0
+	// uses the viewport size.
+	VirtualWidth, VirtualHeight int
+
+	// Shake tunes the trauma shake started with AddTrauma and Kick; the zero
+	// value uses ShakeMedium.
+	Shake ShakePreset
+
+	lookAhead      Vector
+	lastTarget     teishoku.Entity
+	lastTargetPos  Vector
+	targetTracked  bool
+	targetVelocity Vector
+
+	bounded            bool
+	freePosition       Vector
+	boundedPosition    Vector
+	region, prevRegion int
+	regionBlend        float64
+
+	shake     cameraShake
+	zoomPunch float64
+
+	// previous is the position before the last fixed step; alpha blends it
+	// with Position when drawing.
+	previous    Vector
+	interpolate bool
+	alpha       float64
+}
+
+// NewCamera creates a camera for a viewport of the given size.
+func NewCamera(width, height int) *Camera {
+	return &Camera{Zoom: 1, Width: width, Height: height}
+}
+
+// SetCamera installs cam as the world's main camera.
+func SetCamera(w *teishoku.World, cam *Camera) {
+	if current := GetCamera(w); current != nil {
+		*current = *cam
+		return
+	}
+	SetResource(w, cam)
+}
+
+// GetCamera returns the world's main camera, or nil if none is set.
+func GetCamera(w *teishoku.World) *Camera {
+	return GetResource[Camera](w)
+}
+
+// cameraFilter caches the CameraComponent filter of a world for Cameras.
+type cameraFilter struct {
+	filter *teishoku.Filter[CameraComponent]
+}
+
+// Cameras appends the cameras of w to dst in drawing order: the main camera
+// first, then those of CameraComponents by Order.
+func Cameras(w *teishoku.World, dst []*Camera) []*Camera {
+	cf := EnsureResource(w, func() *cameraFilter {
+		return &cameraFilter{filter: teishoku.NewFilter[CameraComponent](w)}
+	})
+	return appendCameras(w, cf.filter, dst)
+}
+
+// appendCameras is Cameras with the CameraComponent filter of w.
+func appendCameras(w *teishoku.World, filter *teishoku.Filter[CameraComponent], dst []*Camera) []*Camera {
+	if cam := GetCamera(w); cam != nil {
+		dst = append(dst, cam)
+	}
+	start := len(dst)
+	var ordersBuf [8]int
+	orders := ordersBuf[:0]
+	filter.Reset()
+	for filter.Next() {
+		c := filter.Get()
+		if c.Camera == nil {
+			continue
+		}
+		// Insertion sort by order, stable for equal orders.
+		i := len(orders)
+		dst, orders = append(dst, nil), append(orders, 0)
+		for ; i > 0 && orders[i-1] > c.Order; i-- {
+			dst[start+i], orders[i] = dst[start+i-1], orders[i-1]
+		}
+		dst[start+i], orders[i] = c.Camera, c.Order
+	}
+	return dst
+}
+
+// ViewportRect returns the viewport in pixels of a width x height screen.
+func (self *Camera) ViewportRect(width, height int) image.Rectangle {
+	if self.Viewport.IsEmpty() {
+		return image.Rect(0, 0, width, height)
+	}
+	return image.Rect(
+		int(math.Round(self.Viewport.Min.X*float64(width))), int(math.Round(self.Viewport.Min.Y*float64(height))),
+		int(math.Round(self.Viewport.Max.X*float64(width))), int(math.Round(self.Viewport.Max.Y*float64(height))),
+	)
+}
+
+// Follow makes the camera track e.
+func (self *Camera) Follow(e teishoku.Entity) {
+	self.Target = e
+	self.Following = true
+}
+
+// StopFollowing stops tracking the current target.
+func (self *Camera) StopFollowing() {
+	self.Following = false
+}
+
+// LookAhead returns the current look-ahead offset added to the focus point.
+func (self *Camera) LookAhead() Vector {
+	return self.lookAhead
+}
+
+// TargetVelocity returns the velocity of the follow target measured by the
+// CameraSystem.
+func (self *Camera) TargetVelocity() Vector {
+	return self.targetVelocity
+}
+
+// updateLookAhead measures the target's velocity and moves the look-ahead
+// towards where it is facing or heading.
+func (self *Camera) updateLookAhead(target Vector, dt float64) {
+	if self.targetTracked && dt > 0 {
+		self.targetVelocity = target.Sub(self.lastTargetPos).DivF(dt)
+	}
+	self.lastTargetPos, self.targetTracked = target, true
+	if self.LookAheadDistance <= 0 {
+		self.lookAhead = Vector{}
+		return
+	}
+	var goal Vector
+	if !self.Facing.IsZero() {
+		goal = self.Facing.Normalize().ScaleF(self.LookAheadDistance)
+	} else {
+		goal = self.targetVelocity.ScaleF(self.LookAheadTime).ClampLength(self.LookAheadDistance)
+	}
+	if self.LookAheadSpeed <= 0 {
+		self.lookAhead = goal
+		return
+	}
+	self.lookAhead = self.lookAhead.Add(goal.Sub(self.lookAhead).ScaleF(1 - math.Exp(-self.LookAheadSpeed*dt)))
+}
+
+func (self *Camera) zoom() float64 {
+	if self.Zoom <= 0 {
+		return 1 + self.zoomPunch
+	}
+	return self.Zoom * (1 + self.zoomPunch)
+}
+
+// pixelGrid returns the size of one virtual pixel in viewport pixels.
+func (self *Camera) pixelGrid() Vector {
+	grid := V(1, 1)
+	if self.VirtualWidth > 0 && self.Width > 0 {
+		grid.X = float64(self.Width) / float64(self.VirtualWidth)
+	}
+	if self.VirtualHeight > 0 && self.Height > 0 {
+		grid.Y = float64(self.Height) / float64(self.VirtualHeight)
+	}
+	return grid
+}
+
+// RenderZoom returns the zoom used for rendering, snapped to a whole number
+// of virtual pixels per world unit with IntegerZoom.
+func (self *Camera) RenderZoom() float64 {
+	z := self.zoom()
+	if !self.PixelPerfect || !self.IntegerZoom {
+		return z
+	}
+	grid := self.pixelGrid().X
+	if texel := z / grid; texel < 1 {
+		return grid / math.Round(1/texel)
+	}
+	return math.Round(z/grid) * grid
+}
+
+// ResetInterpolation stops interpolating from the previous position until
+// the next fixed step, e.g. after a cut.
+func (self *Camera) ResetInterpolation() {
+	self.interpolate = false
+}
+
+// interpolated returns the position blended from the one before the last
+// fixed step, or Position without a fixed timestep.
+func (self *Camera) interpolated() Vector {
+	if !self.interpolate {
+		return self.Position
+	}
+	return self.previous.Lerp(self.Position, self.alpha)
+}
+
+// viewPosition returns the interpolated position shifted by the camera
+// shake.
+func (self *Camera) viewPosition() Vector {
+	return self.interpolated().Add(self.shake.offset.DivF(self.RenderZoom()))
+}
+
+// RenderPosition returns the position used for rendering, including the
+// camera shake. In PixelPerfect mode it is snapped so the final translation
+// lands on whole virtual pixels.
+func (self *Camera) RenderPosition() Vector {
+	pos := self.viewPosition()
+	if !self.PixelPerfect {
+		return pos
+	}
+	z, grid := self.RenderZoom(), self.pixelGrid()
+	half := V(float64(self.Width)/2, float64(self.Height)/2)
+	return V(
+		(half.X-math.Round((half.X-pos.X*z)/grid.X)*grid.X)/z,
+		(half.Y-math.Round((half.Y-pos.Y*z)/grid.Y)*grid.Y)/z,
+	)
+}
+
+// Subpixel returns the offset in viewport pixels removed by snapping.
+func (self *Camera) Subpixel() Vector {
+	if !self.PixelPerfect || !self.SubpixelScroll {
+		return Vector{}
+	}
+	return self.viewPosition().Sub(self.RenderPosition()).ScaleF(self.RenderZoom())
+}
+
+// Matrix returns the world-to-viewport transform.
+func (self *Camera) Matrix() Matrix {
+	pos, z := self.RenderPosition(), self.RenderZoom()
+	var m Matrix
+	m.Translate(-pos.X, -pos.Y)
+	m.Rotate(-(self.Rotation + self.shake.rotation))
+	m.Scale(z, z)
+	m.Translate(float64(self.Width)/2, float64(self.Height)/2)
+	return m
+}
+
+// WorldToScreen converts a world position to viewport coordinates.
+func (self *Camera) WorldToScreen(p Vector) Vector {
+	m := self.Matrix()
+	return V(m.Apply(p.X, p.Y))
+}
+
+// ScreenToWorld converts viewport coordinates to a world position.
+func (self *Camera) ScreenToWorld(p Vector) Vector {
+	m := self.Matrix()
+	m.Invert()
+	return V(m.Apply(p.X, p.Y))
+}
+
+// VisibleBounds returns the world-space rectangle covering the viewport.
+func (self *Camera) VisibleBounds() Rectangle {
+	w, h := float64(self.Width), float64(self.Height)
+	corners := [4]Vector{
+		self.ScreenToWorld(V(0, 0)), self.ScreenToWorld(V(w, 0)),
+		self.ScreenToWorld(V(w, h)), self.ScreenToWorld(V(0, h)),
+	}
+	bounds := Rectangle{Min: corners[0], Max: corners[0]}
+	for _, c := range corners[1:] {
+		bounds.Min = V(min(bounds.Min.X, c.X), min(bounds.Min.Y, c.Y))
+		bounds.Max = V(max(bounds.Max.X, c.X), max(bounds.Max.Y, c.Y))
+	}
+	return bounds
+}
@@ -0,0 +1,134 @@
+package katsu2d
+
+import "math"
+
+// confineIterations bounds the corner pushes used to fit the viewport into
+// a polygon region.
+const confineIterations = 8
+
+// CameraRegion is an area the camera viewport is confined to. It is the
+// polygon when Polygon has at least three points, otherwise Rect.
+type CameraRegion struct {
+	Rect    Rectangle
+	Polygon []Vector
+}
+
+// RectRegion creates a rectangular camera region.
+func RectRegion(rect Rectangle) CameraRegion {
+	return CameraRegion{Rect: rect}
+}
+
+// PolygonRegion creates a polygonal camera region from its outline.
+func PolygonRegion(points ...Vector) CameraRegion {
+	return CameraRegion{Polygon: points}
+}
+
+func (self CameraRegion) isPolygon() bool {
+	return len(self.Polygon) >= 3
+}
+
+// Contains reports whether p lies inside the region.
+func (self CameraRegion) Contains(p Vector) bool {
+	if self.isPolygon() {
+		return PointInPolygon(p, self.Polygon)
+	}
+	return self.Rect.Contains(p)
+}
+
+// distanceTo returns how far p lies outside the region, 0 when inside.
+func (self CameraRegion) distanceTo(p Vector) float64 {
+	if self.Contains(p) {
+		return 0
+	}
+	return p.DistanceTo(self.closestPoint(p))
+}
+
+func (self CameraRegion) closestPoint(p Vector) Vector {
+	if self.isPolygon() {
+		hit, _ := ClosestPointOnPolyline(self.Polygon, true, p)
+		return hit.Point
+	}
+	return V(Clamp(p.X, self.Rect.Min.X, self.Rect.Max.X), Clamp(p.Y, self.Rect.Min.Y, self.Rect.Max.Y))
+}
+
+// Confine moves a viewport centered on center with the given half size so
+// it stays inside the region. A rectangle too small for the viewport centers
+// it; polygons keep the viewport corners inside, so a reflex vertex may
+// still poke into the view.
+func (self CameraRegion) Confine(center, half Vector) Vector {
+	if !self.isPolygon() {
+		return V(confineAxis(center.X, half.X, self.Rect.Min.X, self.Rect.Max.X),
+			confineAxis(center.Y, half.Y, self.Rect.Min.Y, self.Rect.Max.Y))
+	}
+	if !self.Contains(center) {
+		center = self.closestPoint(center)
+	}
+	for range confineIterations {
+		var push Vector
+		for _, corner := range [4]Vector{
+			center.Add(V(-half.X, -half.Y)), center.Add(V(half.X, -half.Y)),
+			center.Add(V(half.X, half.Y)), center.Add(V(-half.X, half.Y)),
+		} {
+			if self.Contains(corner) {
+				continue
+			}
+			d := self.closestPoint(corner).Sub(corner)
+			if math.Abs(d.X) > math.Abs(push.X) {
+				push.X = d.X
+			}
+			if math.Abs(d.Y) > math.Abs(push.Y) {
+				push.Y = d.Y
+			}
+		}
+		if push.IsZero() {
+			break
+		}
+		center = center.Add(push)
+	}
+	return center
+}
+
+func confineAxis(v, half, lo, hi float64) float64 {
+	if hi-lo <= 2*half {
+		return (lo + hi) / 2
+	}
+	return Clamp(v, lo+half, hi-half)
+}
+
+// regionAt picks the region for focus: the current one while it still
+// contains focus, else the first containing it, else the nearest.
+func (self *Camera) regionAt(focus Vector) int {
+	if self.region < len(self.Bounds) && self.Bounds[self.region].Contains(focus) {
+		return self.region
+	}
+	best, bestDist := 0, math.Inf(1)
+	for i, r := range self.Bounds {
+		if d := r.distanceTo(focus); d < bestDist {
+			best, bestDist = i, d
+			if d == 0 {
+				break
+			}
+		}
+	}
+	return best
+}
+
+// confine returns Position confined to the region holding focus, blending
+// from the previous region over BoundsBlendTime after a switch.
+func (self *Camera) confine(focus Vector, dt float64) Vector {
+	region := self.regionAt(focus)
+	if !self.bounded {
+		self.region, self.prevRegion, self.regionBlend = region, region, 1
+	} else if region != self.region {
+		self.prevRegion, self.region, self.regionBlend = self.region, region, 0
+	}
+	half := V(float64(self.Width)/2, float64(self.Height)/2).DivF(self.RenderZoom())
+	pos := self.Bounds[self.region].Confine(self.Position, half)
+	if self.regionBlend >= 1 || self.BoundsBlendTime <= 0 || self.prevRegion >= len(self.Bounds) {
+		self.regionBlend = 1
+		return pos
+	}
+	self.regionBlend = math.Min(1, self.regionBlend+dt/self.BoundsBlendTime)
+	t := self.regionBlend * self.regionBlend * (3 - 2*self.regionBlend)
+	return self.Bounds[self.prevRegion].Confine(self.Position, half).Lerp(pos, t)
+}
@@ -0,0 +1,106 @@
+package katsu2d
+
+import (
+	"math"
+
+	"github.com/edwinsyarief/katsu2d/opensimplex"
+)
+
+// ShakePreset tunes the trauma shake of a Camera. The shake strength is
+// trauma raised to Exponent, so small hits barely move the view while big
+// ones rattle it.
+type ShakePreset struct {
+	// MaxOffset and MaxRotation are reached at full trauma, in viewport
+	// pixels and radians.
+	MaxOffset   float64
+	MaxRotation float64
+	// Frequency is how fast the noise is sampled; higher shakes faster.
+	Frequency float64
+	// Decay is the trauma lost per second.
+	Decay    float64
+	Exponent float64
+	// KickDecay is how fast, per second, a Kick springs back.
+	KickDecay float64
+}
+
+// Shake presets, from a light bump to a heavy explosion.
+var (
+	ShakeLight  = ShakePreset{MaxOffset: 4, MaxRotation: 0.01, Frequency: 12, Decay: 1.5, Exponent: 2, KickDecay: 12}
+	ShakeMedium = ShakePreset{MaxOffset: 10, MaxRotation: 0.03, Frequency: 18, Decay: 1.2, Exponent: 2, KickDecay: 10}
+	ShakeHeavy  = ShakePreset{MaxOffset: 24, MaxRotation: 0.08, Frequency: 24, Decay: 0.8, Exponent: 2, KickDecay: 8}
+)
+
+// cameraShake is the trauma state of a Camera, advanced by the CameraSystem.
+type cameraShake struct {
+	trauma   float64
+	time     float64
+	kick     Vector
+	offset   Vector
+	rotation float64
+	noise    opensimplex.Noise
+}
+
+// AddTrauma adds to the camera's trauma, clamped to 1, which decays over
+// time. Each hit adds a little, so repeated impacts build up the shake.
+func (self *Camera) AddTrauma(amount float64) {
+	self.shake.trauma = Clamp(self.shake.trauma+amount, 0, 1)
+}
+
+// ShakeWith switches to preset and adds trauma.
+func (self *Camera) ShakeWith(preset ShakePreset, trauma float64) {
+	self.Shake = preset
+	self.AddTrauma(trauma)
+}
+
+// Trauma returns the current trauma from 0 to 1.
+func (self *Camera) Trauma() float64 {
+	return self.shake.trauma
+}
+
+// Kick pushes the view along direction by strength viewport pixels, then
+// springs it back, e.g. away from a gunshot or towards an impact.
+func (self *Camera) Kick(direction Vector, strength float64) {
+	self.shake.kick = self.shake.kick.Add(direction.Normalize().ScaleF(strength))
+}
+
+// ShakeOffset returns the current shake and kick offset in viewport pixels.
+func (self *Camera) ShakeOffset() Vector {
+	return self.shake.offset
+}
+
+// ShakeRotation returns the current shake rotation in radians.
+func (self *Camera) ShakeRotation() float64 {
+	return self.shake.rotation
+}
+
+// StopShake clears the trauma and any kick at once.
+func (self *Camera) StopShake() {
+	noise := self.shake.noise
+	self.shake = cameraShake{noise: noise}
+}
+
+func (self *Camera) updateShake(dt float64) {
+	preset := self.Shake
+	if preset == (ShakePreset{}) {
+		preset = ShakeMedium
+	}
+	s := &self.shake
+	s.trauma = max(s.trauma-preset.Decay*dt, 0)
+	s.kick = s.kick.ScaleF(math.Exp(-preset.KickDecay * dt))
+	if s.kick.Length() < 0.01 {
+		s.kick = Vector{}
+	}
+	if s.trauma == 0 {
+		s.offset, s.rotation = s.kick, 0
+		return
+	}
+	if s.noise == nil {
+		s.noise = opensimplex.New(Random().PositiveInt64())
+	}
+	s.time += dt
+	strength := math.Pow(s.trauma, preset.Exponent)
+	t := s.time * preset.Frequency
+	// Each channel samples its own row of the noise so they move apart.
+	s.offset = V(s.noise.Eval2(t, 0), s.noise.Eval2(t, 100)).ScaleF(preset.MaxOffset * strength).Add(s.kick)
+	s.rotation = s.noise.Eval2(t, 200) * preset.MaxRotation * strength
+}
@@ -5,10 +5,12 @@ import (
 )
 
 type canvas struct {
-	buffer                  *ebiten.Image
-	renderers               []func(*ebiten.Image)
-	topLeft, scale          Vector
-	width, height           int
+	buffer         *ebiten.Image
+	renderers      []func(*ebiten.Image)
+	topLeft, scale Vector
+	width, height  int
+	// output is the layout size the canvas was last fitted to.
+	output                  Vector
 	stretched, pixelPerfect bool
 	filter                  scalingFilter
 }
@@ -19,7 +21,7 @@ func newCanvas(width, height int, stretched, pixelPerfect bool) *canvas {
 		height:       height,
 		stretched:    stretched,
 		pixelPerfect: pixelPerfect,
-		buffer:       ebiten.NewImage(width, height),
+		buffer:       newRenderTarget(width, height, "canvas"),
 		renderers:    make([]func(*ebiten.Image), 0),
 	}
 	result.SetFilter(_AASamplingSoft)
@@ -41,6 +43,7 @@ func (self *canvas) GetScale() Vector {
 	return self.scale
 }
 func (self *canvas) Resize(width, height int) {
+	self.output = V(float64(width), float64(height))
 	scale := V(
 		float64(width)/float64(self.width),
 		float64(height)/float64(self.height),
@@ -70,10 +73,29 @@ func (self *canvas) ScaleCursorPosition() Vector {
 	)
 }
 func (self *canvas) Draw(source, destination *ebiten.Image) {
-	p0 := self.topLeft
-	p1 := p0.Add(V(self.scale.X*float64(self.width), 0))
-	p2 := p0.Add(V(self.scale.X*float64(self.width), self.scale.Y*float64(self.height)))
-	p3 := p0.Add(V(0, self.scale.Y*float64(self.height)))
+	b := source.Bounds()
+	self.DrawRegion(source, destination,
+		V(float64(b.Min.X), float64(b.Min.Y)), V(float64(b.Max.X), float64(b.Max.Y)))
+}
+
+// DrawRegion draws the source rectangle srcMin-srcMax, which may have
+// fractional coordinates for subpixel scrolling. The layout is fitted to
+// the output size and then scaled to destination, so a destination drawn
+// at a reduced render scale gets a proportionally smaller canvas.
+func (self *canvas) DrawRegion(source, destination *ebiten.Image, srcMin, srcMax Vector) {
+	b := destination.Bounds()
+	fit := V2(1)
+	if self.output.X > 0 && self.output.Y > 0 {
+		fit = V(float64(b.Dx())/self.output.X, float64(b.Dy())/self.output.Y)
+	}
+	origin := V(float64(b.Min.X), float64(b.Min.Y))
+	place := func(p Vector) Vector {
+		return V(p.X*fit.X, p.Y*fit.Y).Add(origin)
+	}
+	p0 := place(self.topLeft)
+	p1 := place(self.topLeft.Add(V(self.scale.X*float64(self.width), 0)))
+	p2 := place(self.topLeft.Add(V(self.scale.X*float64(self.width), self.scale.Y*float64(self.height))))
+	p3 := place(self.topLeft.Add(V(0, self.scale.Y*float64(self.height))))
 	shaderVertices[0].DstX = float32(p0.X)
 	shaderVertices[0].DstY = float32(p0.Y)
 	shaderVertices[1].DstX = float32(p1.X)
@@ -82,13 +104,12 @@ func (self *canvas) Draw(source, destination *ebiten.Image) {
 	shaderVertices[2].DstY = float32(p2.Y)
 	shaderVertices[3].DstX = float32(p3.X)
 	shaderVertices[3].DstY = float32(p3.Y)
-	sourceBounds := source.Bounds()
-	shaderVertices[0].SrcX = float32(sourceBounds.Min.X)
-	shaderVertices[0].SrcY = float32(sourceBounds.Min.Y)
-	shaderVertices[1].SrcX = float32(sourceBounds.Max.X)
+	shaderVertices[0].SrcX = float32(srcMin.X)
+	shaderVertices[0].SrcY = float32(srcMin.Y)
+	shaderVertices[1].SrcX = float32(srcMax.X)
 	shaderVertices[1].SrcY = shaderVertices[0].SrcY
 	shaderVertices[2].SrcX = shaderVertices[1].SrcX
-	shaderVertices[2].SrcY = float32(sourceBounds.Max.Y)
+	shaderVertices[2].SrcY = float32(srcMax.Y)
 	shaderVertices[3].SrcX = shaderVertices[0].SrcX
 	shaderVertices[3].SrcY = shaderVertices[2].SrcY
 	shaderOpts.Images[0] = source
@@ -0,0 +1,123 @@
+package katsu2d
+
+import (
+	"fmt"
+	"image/color"
+	"slices"
+
+	"github.com/edwinsyarief/teishoku"
+)
+
+// CollisionLayer is the layer of a ColliderComponent, set up in the
+// world's CollisionMatrix. Layer 0 is DefaultCollisionLayer.
+type CollisionLayer uint8
+
+// DefaultCollisionLayer holds colliders without a layer.
+const DefaultCollisionLayer CollisionLayer = 0
+
+// maxCollisionLayers is the number of layers a matrix can hold.
+const maxCollisionLayers = 32
+
+// CollisionMatrix names collision layers and decides which pairs of layers
+// collide. By default every layer collides with every other. The
+// CollisionSystem and its queries skip pairs the matrix disables before
+// any narrowphase test.
+type CollisionMatrix struct {
+	names  []string
+	colors []color.RGBA
+	masks  [maxCollisionLayers]uint32
+}
+
+// NewCollisionMatrix creates a matrix with only DefaultCollisionLayer.
+func NewCollisionMatrix() *CollisionMatrix {
+	m := &CollisionMatrix{}
+	for i := range m.masks {
+		m.masks[i] = ^uint32(0)
+	}
+	m.names = []string{"default"}
+	m.colors = []color.RGBA{{255, 255, 255, 255}}
+	return m
+}
+
+// GetCollisionMatrix returns the world's collision matrix, creating it on
+// first use.
+func GetCollisionMatrix(w *teishoku.World) *CollisionMatrix {
+	return EnsureResource(w, NewCollisionMatrix)
+}
+
+// AddLayer adds a layer colliding with every layer and drawn in col by
+// debug views. Adding an existing name returns its layer.
+func (self *CollisionMatrix) AddLayer(name string, col color.RGBA) CollisionLayer {
+	if layer, ok := self.Layer(name); ok {
+		self.colors[layer] = col
+		return layer
+	}
+	if len(self.names) == maxCollisionLayers {
+		panic(fmt.Sprintf("katsu2d: more than %d collision layers", maxCollisionLayers))
+	}
+	self.names = append(self.names, name)
+	self.colors = append(self.colors, col)
+	return CollisionLayer(len(self.names) - 1)
+}
+
+// Layer returns the layer called name.
+func (self *CollisionMatrix) Layer(name string) (CollisionLayer, bool) {
+	i := slices.Index(self.names, name)
+	return CollisionLayer(max(i, 0)), i >= 0
+}
+
+// Layers returns the layer names, indexed by layer.
+func (self *CollisionMatrix) Layers() []string {
+	return self.names
+}
+
+// Name returns the name of layer, or "" if it was never added.
+func (self *CollisionMatrix) Name(layer CollisionLayer) string {
+	if int(layer) < len(self.names) {
+		return self.names[layer]
+	}
+	return ""
+}
+
+// Color returns the debug color of layer.
+func (self *CollisionMatrix) Color(layer CollisionLayer) color.RGBA {
+	if int(layer) < len(self.colors) {
+		return self.colors[layer]
+	}
+	return color.RGBA{255, 255, 255, 255}
+}
+
+// SetCollides enables or disables collisions between layers a and b in both
+// directions.
+func (self *CollisionMatrix) SetCollides(a, b CollisionLayer, collides bool) {
+	if collides {
+		self.masks[a%maxCollisionLayers] |= 1 << (b % maxCollisionLayers)
+		self.masks[b%maxCollisionLayers] |= 1 << (a % maxCollisionLayers)
+		return
+	}
+	self.masks[a%maxCollisionLayers] &^= 1 << (b % maxCollisionLayers)
+	self.masks[b%maxCollisionLayers] &^= 1 << (a % maxCollisionLayers)
+}
+
+// SetCollidesByName is SetCollides with layer names, adding missing layers.
+func (self *CollisionMatrix) SetCollidesByName(a, b string, collides bool) {
+	la, ok := self.Layer(a)
+	if !ok {
+		la = self.AddLayer(a, color.RGBA{255, 255, 255, 255})
+	}
+	lb, ok := self.Layer(b)
+	if !ok {
+		lb = self.AddLayer(b, color.RGBA{255, 255, 255, 255})
+	}
+	self.SetCollides(la, lb, collides)
+}
+
+// Collides reports whether layers a and b collide.
+func (self *CollisionMatrix) Collides(a, b CollisionLayer) bool {
+	return self.masks[a%maxCollisionLayers]&(1<<(b%maxCollisionLayers)) != 0
+}
+
+// Mask returns the bit set of layers that layer collides with.
+func (self *CollisionMatrix) Mask(layer CollisionLayer) uint32 {
+	return self.masks[layer%maxCollisionLayers]
+}
@@ -0,0 +1,119 @@
+package katsu2d
+
+import (
+	_ "embed"
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+//go:embed internal_assets/shaders/lut.kage
+var _lutShaderSource []byte
+
+var lutShader *ebiten.Shader
+
+// ColorGrading applies a strip LUT to the rendered scene. A LUT of size N is
+// an N*N x N image holding N slices of red (x) by green (y), one per blue level.
+type ColorGrading struct {
+	// Strength mixes between the original (0) and graded (1) colors.
+	Strength float64
+
+	lut, next *ebiten.Image
+	size      int
+	blend     float64
+	duration  float64
+	elapsed   float64
+	vertices  [4]ebiten.Vertex
+	options   ebiten.DrawTrianglesShaderOptions
+}
+
+// NewColorGrading creates a grading step using lut at full strength.
+func NewColorGrading(lut *ebiten.Image) (*ColorGrading, error) {
+	size, err := lutSize(lut)
+	if err != nil {
+		return nil, err
+	}
+	return &ColorGrading{Strength: 1, lut: lut, size: size}, nil
+}
+
+func lutSize(lut *ebiten.Image) (int, error) {
+	if lut == nil {
+		return 0, fmt.Errorf("katsu2d: nil LUT")
+	}
+	w, h := lut.Bounds().Dx(), lut.Bounds().Dy()
+	if h < 2 || w != h*h {
+		return 0, fmt.Errorf("katsu2d: LUT must be N*N x N pixels, got %dx%d", w, h)
+	}
+	return h, nil
+}
+
+// SetLUT swaps the LUT immediately, cancelling any transition.
+func (self *ColorGrading) SetLUT(lut *ebiten.Image) error {
+	size, err := lutSize(lut)
+	if err != nil {
+		return err
+	}
+	self.lut, self.next, self.size, self.blend = lut, nil, size, 0
+	return nil
+}
+
+// TransitionTo blends from the current LUT to lut over duration seconds.
+// Both LUTs must have the same size.
+func (self *ColorGrading) TransitionTo(lut *ebiten.Image, duration float64) error {
+	size, err := lutSize(lut)
+	if err != nil {
+		return err
+	}
+	if size != self.size {
+		return fmt.Errorf("katsu2d: LUT size %d does not match current size %d", size, self.size)
+	}
+	if duration <= 0 {
+		return self.SetLUT(lut)
+	}
+	self.next, self.duration, self.elapsed, self.blend = lut, duration, 0, 0
+	return nil
+}
+
+// IsTransitioning reports whether a LUT transition is in progress.
+func (self *ColorGrading) IsTransitioning() bool {
+	return self.next != nil
+}
+
+// Update advances an active transition.
+func (self *ColorGrading) Update(dt float64) {
+	if self.next == nil {
+		return
+	}
+	self.elapsed += dt
+	self.blend = Clamp(self.elapsed/self.duration, 0, 1)
+	if self.blend >= 1 {
+		self.lut, self.next, self.blend = self.next, nil, 0
+	}
+}
+
+// Apply draws src graded onto dst. Both images must be the same size.
+func (self *ColorGrading) Apply(dst, src *ebiten.Image) {
+	if lutShader == nil {
+		shader, err := ebiten.NewShader(_lutShaderSource)
+		if err != nil {
+			panic("Failed to compile LUT shader: " + err.Error())
+		}
+		lutShader = shader
+	}
+	b := src.Bounds()
+	x0, y0, x1, y1 := float32(b.Min.X), float32(b.Min.Y), float32(b.Max.X), float32(b.Max.Y)
+	self.vertices = [4]ebiten.Vertex{
+		{DstX: 0, DstY: 0, SrcX: x0, SrcY: y0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: x1 - x0, DstY: 0, SrcX: x1, SrcY: y0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: x1 - x0, DstY: y1 - y0, SrcX: x1, SrcY: y1, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: 0, DstY: y1 - y0, SrcX: x0, SrcY: y1, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+	}
+	if self.options.Uniforms == nil {
+		self.options.Uniforms = make(map[string]any, 3)
+	}
+	self.options.Uniforms["LutSize"] = float32(self.size)
+	self.options.Uniforms["Blend"] = float32(self.blend)
+	self.options.Uniforms["Strength"] = float32(Clamp(self.Strength, 0, 1))
+	self.options.Images = [4]*ebiten.Image{src, self.lut, self.next}
+	dst.DrawTrianglesShader(self.vertices[:], []uint16{0, 1, 2, 0, 2, 3}, lutShader, &self.options)
+}
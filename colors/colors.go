@@ -0,0 +1,224 @@
+// Package colors provides color parsing, color space conversions,
+// interpolation and palettes shared by the renderer and mesh builders.
+package colors
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Hex parses "#rgb", "#rgba", "#rrggbb" or "#rrggbbaa" (the leading '#' is
+// optional) into a color.
+func Hex(s string) (color.RGBA, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(s) == 3 || len(s) == 4 {
+		expanded := make([]byte, 0, len(s)*2)
+		for i := 0; i < len(s); i++ {
+			expanded = append(expanded, s[i], s[i])
+		}
+		s = string(expanded)
+	}
+	if len(s) != 6 && len(s) != 8 {
+		return color.RGBA{}, fmt.Errorf("colors: invalid hex color %q", s)
+	}
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("colors: invalid hex color %q: %w", s, err)
+	}
+	if len(s) == 6 {
+		v = v<<8 | 0xff
+	}
+	return color.RGBA{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}, nil
+}
+
+// MustHex is like Hex but panics on malformed input.
+func MustHex(s string) color.RGBA {
+	c, err := Hex(s)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// ToHex formats a color as "#rrggbb", or "#rrggbbaa" when not opaque.
+func ToHex(c color.RGBA) string {
+	if c.A == 0xff {
+		return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	}
+	return fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A)
+}
+
+// FromHSV builds a color from hue in degrees and saturation, value and alpha
+// in 0..1.
+func FromHSV(h, s, v, a float64) color.RGBA {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+	c := v * s
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	r, g, b := hueSector(h, c, x)
+	m := v - c
+	return fromFloats(r+m, g+m, b+m, a)
+}
+
+// ToHSV returns hue in degrees and saturation, value and alpha in 0..1.
+func ToHSV(c color.RGBA) (h, s, v, a float64) {
+	r, g, b, a := toFloats(c)
+	maxC, minC := max(r, g, b), min(r, g, b)
+	d := maxC - minC
+	if maxC > 0 {
+		s = d / maxC
+	}
+	return hue(r, g, b, maxC, d), s, maxC, a
+}
+
+// FromHSL builds a color from hue in degrees and saturation, lightness and
+// alpha in 0..1.
+func FromHSL(h, s, l, a float64) color.RGBA {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+	c := (1 - math.Abs(2*l-1)) * s
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	r, g, b := hueSector(h, c, x)
+	m := l - c/2
+	return fromFloats(r+m, g+m, b+m, a)
+}
+
+// ToHSL returns hue in degrees and saturation, lightness and alpha in 0..1.
+func ToHSL(c color.RGBA) (h, s, l, a float64) {
+	r, g, b, a := toFloats(c)
+	maxC, minC := max(r, g, b), min(r, g, b)
+	d := maxC - minC
+	l = (maxC + minC) / 2
+	if d > 0 {
+		s = d / (1 - math.Abs(2*l-1))
+	}
+	return hue(r, g, b, maxC, d), s, l, a
+}
+
+// Lerp interpolates two colors with premultiplied alpha, so fading towards a
+// transparent color does not darken the result.
+func Lerp(c1, c2 color.RGBA, t float64) color.RGBA {
+	t = clamp01(t)
+	r1, g1, b1, a1 := toFloats(c1)
+	if a1 > 0 {
+		r1, g1, b1 = r1/a1, g1/a1, b1/a1
+	}
+	r2, g2, b2, a2 := toFloats(c2)
+	if a2 > 0 {
+		r2, g2, b2 = r2/a2, g2/a2, b2/a2
+	}
+	a := a1 + t*(a2-a1)
+	r := (r1*a1 + t*(r2*a2-r1*a1)) / a
+	g := (g1*a1 + t*(g2*a2-g1*a1)) / a
+	b := (b1*a1 + t*(b2*a2-b1*a1)) / a
+	return fromFloats(r, g, b, a)
+}
+
+// LerpGamma interpolates two colors in linear light, which avoids the dark
+// band a plain sRGB blend produces between saturated colors.
+func LerpGamma(c1, c2 color.RGBA, t float64) color.RGBA {
+	t = clamp01(t)
+	r1, g1, b1, a1 := toFloats(c1)
+	r2, g2, b2, a2 := toFloats(c2)
+	mix := func(x, y float64) float64 {
+		return ToSRGB(ToLinear(x) + t*(ToLinear(y)-ToLinear(x)))
+	}
+	return fromFloats(mix(r1, r2), mix(g1, g2), mix(b1, b2), a1+t*(a2-a1))
+}
+
+// Bilinear blends four corner colors (top-left, top-right, bottom-right,
+// bottom-left) at normalized coordinates u, v.
+func Bilinear(corners [4]color.RGBA, u, v float64) color.RGBA {
+	u, v = clamp01(u), clamp01(v)
+	w00, w10, w01, w11 := (1-u)*(1-v), u*(1-v), (1-u)*v, u*v
+	tl, tr, br, bl := corners[0], corners[1], corners[2], corners[3]
+	mix := func(a, b, c, d uint8) uint8 {
+		return uint8(float64(a)*w00 + float64(b)*w10 + float64(c)*w01 + float64(d)*w11)
+	}
+	return color.RGBA{
+		R: mix(tl.R, tr.R, bl.R, br.R),
+		G: mix(tl.G, tr.G, bl.G, br.G),
+		B: mix(tl.B, tr.B, bl.B, br.B),
+		A: mix(tl.A, tr.A, bl.A, br.A),
+	}
+}
+
+// ToLinear converts an sRGB channel in 0..1 to linear light.
+func ToLinear(v float64) float64 {
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+// ToSRGB converts a linear light channel in 0..1 to sRGB.
+func ToSRGB(v float64) float64 {
+	if v <= 0.0031308 {
+		return v * 12.92
+	}
+	return 1.055*math.Pow(v, 1/2.4) - 0.055
+}
+
+func hueSector(h, c, x float64) (r, g, b float64) {
+	switch {
+	case h < 60:
+		return c, x, 0
+	case h < 120:
+		return x, c, 0
+	case h < 180:
+		return 0, c, x
+	case h < 240:
+		return 0, x, c
+	case h < 300:
+		return x, 0, c
+	default:
+		return c, 0, x
+	}
+}
+
+func hue(r, g, b, maxC, d float64) float64 {
+	if d == 0 {
+		return 0
+	}
+	var h float64
+	switch maxC {
+	case r:
+		h = math.Mod((g-b)/d, 6)
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+	return h
+}
+
+func toFloats(c color.RGBA) (r, g, b, a float64) {
+	return float64(c.R) / 255, float64(c.G) / 255, float64(c.B) / 255, float64(c.A) / 255
+}
+
+func fromFloats(r, g, b, a float64) color.RGBA {
+	return color.RGBA{
+		R: uint8(math.Round(clamp01(r) * 255)),
+		G: uint8(math.Round(clamp01(g) * 255)),
+		B: uint8(math.Round(clamp01(b) * 255)),
+		A: uint8(math.Round(clamp01(a) * 255)),
+	}
+}
+
+func clamp01(v float64) float64 {
+	if math.IsNaN(v) {
+		return 0
+	}
+	return min(max(v, 0), 1)
+}
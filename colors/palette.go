@@ -0,0 +1,118 @@
+package colors
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Palette is an ordered list of colors.
+type Palette []color.RGBA
+
+// ParsePalette reads one hex color per line. Blank lines and lines starting
+// with ';', '#' followed by a space, or "//" are ignored, which accepts the
+// common .hex palette format.
+func ParsePalette(r io.Reader) (Palette, error) {
+	var p Palette
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, ";") || strings.HasPrefix(text, "//") || strings.HasPrefix(text, "# ") {
+			continue
+		}
+		c, err := Hex(text)
+		if err != nil {
+			return nil, fmt.Errorf("colors: palette line %d: %w", line, err)
+		}
+		p = append(p, c)
+	}
+	return p, scanner.Err()
+}
+
+// PaletteFromHex builds a palette from hex strings, panicking on bad input.
+func PaletteFromHex(hexes ...string) Palette {
+	p := make(Palette, len(hexes))
+	for i, h := range hexes {
+		p[i] = MustHex(h)
+	}
+	return p
+}
+
+// Color returns the color at index i, wrapping around the palette.
+func (self Palette) Color(i int) color.RGBA {
+	if len(self) == 0 {
+		return color.RGBA{}
+	}
+	i %= len(self)
+	if i < 0 {
+		i += len(self)
+	}
+	return self[i]
+}
+
+// At samples the palette as an evenly spaced gradient at t (0..1).
+func (self Palette) At(t float64) color.RGBA {
+	switch len(self) {
+	case 0:
+		return color.RGBA{}
+	case 1:
+		return self[0]
+	}
+	pos := clamp01(t) * float64(len(self)-1)
+	i := min(int(pos), len(self)-2)
+	return Lerp(self[i], self[i+1], pos-float64(i))
+}
+
+// Nearest returns the palette entry closest to c, e.g. to quantize colors.
+func (self Palette) Nearest(c color.RGBA) color.RGBA {
+	best, bestDist := color.RGBA{}, -1
+	for _, p := range self {
+		dr, dg, db := int(p.R)-int(c.R), int(p.G)-int(c.G), int(p.B)-int(c.B)
+		d := dr*dr + dg*dg + db*db
+		if bestDist < 0 || d < bestDist {
+			best, bestDist = p, d
+		}
+	}
+	return best
+}
+
+var (
+	palettesMu sync.RWMutex
+	palettes   = map[string]Palette{
+		"pico8": PaletteFromHex(
+			"#000000", "#1d2b53", "#7e2553", "#008751", "#ab5236", "#5f574f", "#c2c3c7", "#fff1e8",
+			"#ff004d", "#ffa300", "#ffec27", "#00e436", "#29adff", "#83769c", "#ff77a8", "#ffccaa"),
+		"gameboy":   PaletteFromHex("#0f380f", "#306230", "#8bac0f", "#9bbc0f"),
+		"grayscale": PaletteFromHex("#000000", "#555555", "#aaaaaa", "#ffffff"),
+	}
+)
+
+// RegisterPalette stores a palette under name, replacing any previous one.
+func RegisterPalette(name string, p Palette) {
+	palettesMu.Lock()
+	defer palettesMu.Unlock()
+	palettes[name] = p
+}
+
+// LoadPalette parses a palette from r and registers it under name.
+func LoadPalette(name string, r io.Reader) (Palette, error) {
+	p, err := ParsePalette(r)
+	if err != nil {
+		return nil, err
+	}
+	RegisterPalette(name, p)
+	return p, nil
+}
+
+// GetPalette returns the palette registered under name.
+func GetPalette(name string) (Palette, bool) {
+	palettesMu.RLock()
+	defer palettesMu.RUnlock()
+	p, ok := palettes[name]
+	return p, ok
+}
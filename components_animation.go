@@ -16,4 +16,18 @@ type AnimationComponent struct {
 	Mode      AnimMode
 	Direction bool
 	Active    bool
+	// Events names the events published as an AnimationFrameEvent when the
+	// animation enters a frame, keyed by frame index.
+	Events map[int][]string
+}
+
+// AddFrameEvent publishes the event name whenever the animation enters one
+// of frames, e.g. "footstep" on frames 3 and 7.
+func (self *AnimationComponent) AddFrameEvent(name string, frames ...int) {
+	if self.Events == nil {
+		self.Events = make(map[int][]string)
+	}
+	for _, frame := range frames {
+		self.Events[frame] = append(self.Events[frame], name)
+	}
 }
@@ -0,0 +1,39 @@
+package katsu2d
+
+// AudioEmitterComponent plays a track at the entity's position. While
+// Active, SpatialAudioSystem keeps it playing, restarting looped tracks;
+// one-shots clear Active once they finish.
+type AudioEmitterComponent struct {
+	Track   TrackID
+	Loop    bool
+	Active  bool
+	Rolloff AudioRolloff
+	// Bus is the mixing bus, AudioBusSFX when empty.
+	Bus string
+	// MaxInstances limits how many emitters of the same track play at once;
+	// the oldest is cut off beyond it. Values below 2 count as 2.
+	MaxInstances int
+	playback     PlaybackID
+	playing      bool
+}
+
+// NewAudioEmitterComponent creates an active emitter with the default
+// rolloff.
+func NewAudioEmitterComponent(track TrackID, loop bool) AudioEmitterComponent {
+	return AudioEmitterComponent{
+		Track:        track,
+		Loop:         loop,
+		Active:       true,
+		Rolloff:      DefaultAudioRolloff,
+		MaxInstances: 8,
+	}
+}
+
+// Playback returns the emitter's playback ID and whether it is playing.
+func (self *AudioEmitterComponent) Playback() (PlaybackID, bool) {
+	return self.playback, self.playing
+}
+
+// AudioListenerComponent marks the entity spatial sounds are heard from.
+// Without one, the camera is the listener.
+type AudioListenerComponent struct{}
@@ -0,0 +1,18 @@
+package katsu2d
+
+// CameraComponent adds a camera drawing the world into its own viewport,
+// e.g. one per player for split-screen. The CameraSystem updates it like the
+// main camera, and the scene is drawn once per camera.
+type CameraComponent struct {
+	Camera *Camera
+	// Order sorts the viewports; higher orders are drawn on top, e.g. a
+	// minimap over the player views.
+	Order int
+}
+
+// NewCameraComponent creates a camera component drawing cam into viewport,
+// given as fractions of the screen.
+func NewCameraComponent(cam *Camera, viewport Rectangle) CameraComponent {
+	cam.Viewport = viewport
+	return CameraComponent{Camera: cam}
+}
@@ -0,0 +1,49 @@
+package katsu2d
+
+// ColliderShape selects the geometry of a ColliderComponent.
+type ColliderShape uint8
+
+const (
+	ColliderAABB ColliderShape = iota
+	ColliderCircle
+	ColliderCapsule
+)
+
+// ColliderComponent gives an entity a collision shape centered on its
+// position plus Offset. Boxes stay axis-aligned; capsule endpoints A and B
+// rotate with the transform.
+type ColliderComponent struct {
+	Shape         ColliderShape
+	Offset        Vector
+	Width, Height float64
+	Radius        float64
+	A, B          Vector
+	Disabled      bool
+	// Layer decides what the collider hits through the CollisionMatrix.
+	Layer CollisionLayer
+	// Sensor colliders publish collision events but are never pushed apart
+	// from others, e.g. for pickups and triggers.
+	Sensor bool
+	// OneWay, when non-zero, is the direction the collider's solid side
+	// faces: others only collide when on that side and not moving out
+	// through it, e.g. V(0, -1) for a jump-through platform.
+	OneWay Vector
+	// Material names the surface in the world's PhysicsMaterials.
+	Material string
+}
+
+// NewBoxCollider creates an axis-aligned box collider.
+func NewBoxCollider(width, height float64) ColliderComponent {
+	return ColliderComponent{Shape: ColliderAABB, Width: width, Height: height}
+}
+
+// NewCircleCollider creates a circle collider.
+func NewCircleCollider(radius float64) ColliderComponent {
+	return ColliderComponent{Shape: ColliderCircle, Radius: radius}
+}
+
+// NewCapsuleCollider creates a capsule collider from segment endpoints
+// relative to the entity position.
+func NewCapsuleCollider(a, b Vector, radius float64) ColliderComponent {
+	return ColliderComponent{Shape: ColliderCapsule, A: a, B: b, Radius: radius}
+}
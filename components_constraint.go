@@ -0,0 +1,26 @@
+package katsu2d
+
+import "github.com/edwinsyarief/teishoku"
+
+// OrbitComponent moves an entity in a circle around Center.
+type OrbitComponent struct {
+	Center       teishoku.Entity
+	Radius       float64
+	AngularSpeed float64
+	Angle        float64
+}
+
+// LookAtComponent rotates an entity to face Target. TurnSpeed limits the
+// rotation in radians per second; 0 turns instantly.
+type LookAtComponent struct {
+	Target         teishoku.Entity
+	RotationOffset float64
+	TurnSpeed      float64
+}
+
+// DistanceConstraintComponent keeps an entity between Min and Max distance
+// from Target. A Max of 0 leaves the distance unbounded.
+type DistanceConstraintComponent struct {
+	Target   teishoku.Entity
+	Min, Max float64
+}
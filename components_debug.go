@@ -0,0 +1,7 @@
+package katsu2d
+
+// DebugLabelComponent names an entity in the DebugOverlaySystem, which shows
+// the name above it while debug mode is on.
+type DebugLabelComponent struct {
+	Name string
+}
@@ -0,0 +1,18 @@
+package katsu2d
+
+// ElevationComponent places an entity on a discrete cliff level. Height lifts
+// the sprite above its ground position, e.g. while jumping, without moving
+// its shadow or collision footprint.
+type ElevationComponent struct {
+	Level  int
+	Height float64
+}
+
+// RampComponent connects two elevation levels. Entities crossing Region
+// along Direction move from level From to level To, and back when crossing
+// the other way.
+type RampComponent struct {
+	Region    Rectangle
+	From, To  int
+	Direction Vector
+}
@@ -1,5 +1,7 @@
 package katsu2d
 
+import "slices"
+
 // Action represents a game action (e.g., "move_up", "jump").
 type Action string
 
@@ -11,6 +13,25 @@ const (
 	InputTypeMouse    InputType = 1
 	InputTypeGamepad  InputType = 2
 	InputTypeAnalog   InputType = 3 // New type for analog sticks
+	// InputTypeMouseMotion is the cursor movement since the last frame,
+	// with a MouseAxis code.
+	InputTypeMouseMotion InputType = 4
+	// InputTypeTouch is a touch gesture, with a TouchGesture code.
+	InputTypeTouch InputType = 5
+	// InputTypeVirtualButton is an on-screen button of the
+	// VirtualControlsSystem, with a VirtualButton code.
+	InputTypeVirtualButton InputType = 6
+	// InputTypeVirtualAxis is an on-screen joystick axis of the
+	// VirtualControlsSystem, with a VirtualStickAxis code.
+	InputTypeVirtualAxis InputType = 7
+)
+
+// MouseAxis selects a direction of cursor movement for axis bindings.
+type MouseAxis int
+
+const (
+	MouseAxisX MouseAxis = iota
+	MouseAxisY
 )
 
 // InputCode represents a specific key, button, or axis on a device.
@@ -36,4 +57,115 @@ type InputComponent struct {
 	// Mouse wheel state, stored separately as it's not a binary button state
 	MouseWheelX float64
 	MouseWheelY float64
+
+	// Axes binds analog actions, read with GetAxis.
+	Axes map[Action][]AxisBinding
+	// AxisDeadZone applies to stick and trigger bindings without their own
+	// DeadZone.
+	AxisDeadZone float64
+
+	axisValues map[Action]float64
+	// activeCodes holds, per action, the inputs that drove it this frame.
+	activeCodes map[Action][]InputCode
+}
+
+// Consume clears an action's state for the rest of the frame, together with
+// every other action driven by the same physical inputs, so e.g. a click
+// handled by the UI does not also reach gameplay.
+func (self *InputComponent) Consume(action Action) {
+	codes := self.activeCodes[action]
+	self.clearAction(action)
+	for other, otherCodes := range self.activeCodes {
+		for _, code := range otherCodes {
+			if slices.Contains(codes, code) {
+				self.clearAction(other)
+				break
+			}
+		}
+	}
+}
+
+// GetAxis returns the value of an analog action: -1 to 1 for sticks and
+// virtual axes, 0 to 1 for triggers, and pixels times Scale for mouse
+// motion.
+func (self *InputComponent) GetAxis(action Action) float64 {
+	return self.axisValues[action]
+}
+
+// GetAxis2D combines two axis actions into a vector clamped to unit length,
+// so diagonal keyboard movement is no faster than straight movement.
+func (self *InputComponent) GetAxis2D(x, y Action) Vector {
+	return V(self.GetAxis(x), self.GetAxis(y)).ClampLength(1)
+}
+
+func (self *InputComponent) clearAction(action Action) {
+	delete(self.axisValues, action)
+	delete(self.JustPressed, action)
+	delete(self.Pressed, action)
+	delete(self.JustReleased, action)
+	delete(self.activeCodes, action)
+}
+
+// AxisBinding drives an analog action from a stick axis, a trigger, mouse
+// motion or a key, or from a pair of keys or buttons as a virtual axis.
+type AxisBinding struct {
+	// Positive pushes the axis towards +1.
+	Positive InputCode
+	// Negative pushes the axis towards -1 when Virtual is set.
+	Negative InputCode
+	Virtual  bool
+	// Scale multiplies the value, e.g. -1 to invert or a mouse
+	// sensitivity. Zero counts as 1.
+	Scale float64
+	// DeadZone ignores stick and trigger values below it and rescales the
+	// rest to start at 0. Zero uses the component's AxisDeadZone.
+	DeadZone float64
+}
+
+// NewAxisBinding binds an analog input: an ebiten.StandardGamepadAxis, a
+// trigger as ebiten.StandardGamepadButton, a MouseAxis, or a key or button.
+func NewAxisBinding(input any) AxisBinding {
+	return AxisBinding{Positive: toInputCode(input)}
+}
+
+// NewVirtualAxis binds a pair of keys or buttons as an axis from -1 to 1.
+func NewVirtualAxis(negative, positive any) AxisBinding {
+	return AxisBinding{
+		Positive: toInputCode(positive),
+		Negative: toInputCode(negative),
+		Virtual:  true,
+	}
+}
+
+// ActionMap is a named set of bindings, such as "gameplay", "menu" or
+// "vehicle", that can be pushed onto an InputSystem.
+type ActionMap struct {
+	Name     string
+	Bindings map[Action][]KeyConfig
+	Axes     map[Action][]AxisBinding
+	// Blocking stops every map below this one, including the components' own
+	// bindings, from receiving input while it is active.
+	Blocking bool
+}
+
+// NewActionMap creates an empty action map.
+func NewActionMap(name string) *ActionMap {
+	return &ActionMap{
+		Name:     name,
+		Bindings: make(map[Action][]KeyConfig),
+		Axes:     make(map[Action][]AxisBinding),
+	}
+}
+
+// Bind adds a binding for an action; key and modifiers are Ebitengine keys,
+// mouse buttons or gamepad buttons.
+func (self *ActionMap) Bind(action Action, key any, modifiers ...any) *ActionMap {
+	self.Bindings[action] = append(self.Bindings[action], NewKeyConfig(key, modifiers...))
+	return self
+}
+
+// BindAxis adds an axis binding for an action.
+func (self *ActionMap) BindAxis(action Action, binding AxisBinding) *ActionMap {
+	self.Axes[action] = append(self.Axes[action], binding)
+	return self
 }
@@ -0,0 +1,144 @@
+package katsu2d
+
+import (
+	"math"
+	"slices"
+)
+
+// JuiceKind selects what a JuiceEffect modifies.
+type JuiceKind int
+
+const (
+	// JuiceSquash widens X and shortens Y by Amount, swinging into a
+	// stretch as it settles. A negative Amount stretches first.
+	JuiceSquash JuiceKind = iota
+	// JuiceHop lifts the entity Amount pixels, Cycles times.
+	JuiceHop
+	// JuicePunch scales both axes by Amount, pulsing as it settles.
+	JuicePunch
+	// JuiceWobble rocks the rotation by Amount radians, Cycles times.
+	JuiceWobble
+)
+
+// JuiceEffect is one procedural effect of a JuiceComponent. Its strength
+// fades from Amount to zero over Duration along EaseType while oscillating
+// Cycles times.
+type JuiceEffect struct {
+	Kind     JuiceKind
+	Amount   float64
+	Duration float64
+	Cycles   float64
+	EaseType EaseType
+	Time     float64
+}
+
+// value returns the effect's current signed strength.
+func (self JuiceEffect) value() float64 {
+	progress := min(self.Time/self.Duration, 1)
+	fade := 1 - EaseTypes[float64](self.EaseType)(min(self.Time, self.Duration), 0, 1, self.Duration)
+	phase := 2 * math.Pi * self.Cycles * progress
+	switch self.Kind {
+	case JuiceHop:
+		return self.Amount * fade * math.Abs(math.Sin(phase/2))
+	case JuiceWobble:
+		return self.Amount * fade * math.Sin(phase)
+	default:
+		return self.Amount * fade * math.Cos(phase)
+	}
+}
+
+// JuiceComponent layers short procedural effects over an entity's
+// transform when it is drawn, leaving the TransformComponent untouched.
+// The TweenSystem advances the effects and sums them into Scale (added to
+// 1 on each axis), Rotation and Offset. Squash and punch scale around the
+// transform's origin, so put it at the feet for landings.
+type JuiceComponent struct {
+	Effects  []JuiceEffect
+	Scale    Vector
+	Rotation float64
+	Offset   Vector
+}
+
+// Add starts effect, replacing a running effect of the same kind.
+func (self *JuiceComponent) Add(effect JuiceEffect) {
+	effect.Time = 0
+	if effect.Duration <= 0 {
+		return
+	}
+	if i := slices.IndexFunc(self.Effects, func(e JuiceEffect) bool { return e.Kind == effect.Kind }); i >= 0 {
+		self.Effects[i] = effect
+		return
+	}
+	self.Effects = append(self.Effects, effect)
+}
+
+// Squash squashes the entity by amount, e.g. 0.3 on landing, springing
+// back over duration seconds.
+func (self *JuiceComponent) Squash(amount, duration float64) {
+	self.Add(JuiceEffect{Kind: JuiceSquash, Amount: amount, Duration: duration, Cycles: 1.5, EaseType: QuadOut})
+}
+
+// Hop bounces the entity hops times, the first height pixels high, over
+// duration seconds.
+func (self *JuiceComponent) Hop(height, duration float64, hops int) {
+	self.Add(JuiceEffect{Kind: JuiceHop, Amount: height, Duration: duration, Cycles: float64(max(hops, 1)), EaseType: Linear})
+}
+
+// Punch scales the entity up by amount, e.g. 0.2 on a hit, pulsing back
+// over duration seconds.
+func (self *JuiceComponent) Punch(amount, duration float64) {
+	self.Add(JuiceEffect{Kind: JuicePunch, Amount: amount, Duration: duration, Cycles: 2, EaseType: QuadOut})
+}
+
+// Wobble rocks the entity up to angle radians, cycles times over duration
+// seconds.
+func (self *JuiceComponent) Wobble(angle, duration float64, cycles int) {
+	self.Add(JuiceEffect{Kind: JuiceWobble, Amount: angle, Duration: duration, Cycles: float64(max(cycles, 1)), EaseType: Linear})
+}
+
+// Stop ends all effects.
+func (self *JuiceComponent) Stop() {
+	self.Effects = self.Effects[:0]
+	self.Scale, self.Rotation, self.Offset = Vector{}, 0, Vector{}
+}
+
+// Active reports whether any effect is running.
+func (self *JuiceComponent) Active() bool {
+	return len(self.Effects) > 0
+}
+
+// advance moves the effects on by dt, drops finished ones and sums the
+// modifiers.
+func (self *JuiceComponent) advance(dt float64) {
+	self.Scale, self.Rotation, self.Offset = Vector{}, 0, Vector{}
+	self.Effects = slices.DeleteFunc(self.Effects, func(e JuiceEffect) bool { return e.Time >= e.Duration })
+	for i := range self.Effects {
+		effect := &self.Effects[i]
+		effect.Time += dt
+		v := effect.value()
+		switch effect.Kind {
+		case JuiceSquash:
+			self.Scale = self.Scale.Add(V(v, -v))
+		case JuicePunch:
+			self.Scale = self.Scale.Add(V(v, v))
+		case JuiceHop:
+			self.Offset.Y -= v
+		case JuiceWobble:
+			self.Rotation += v
+		}
+	}
+}
+
+// apply layers the modifiers over tr.
+func (self *JuiceComponent) apply(tr *Transform) {
+	if self.Scale != (Vector{}) {
+		scale := tr.Scale()
+		tr.SetScale(V(scale.X*(1+self.Scale.X), scale.Y*(1+self.Scale.Y)))
+	}
+	if self.Rotation != 0 {
+		tr.SetRotation(tr.Rotation() + self.Rotation)
+	}
+	if self.Offset != (Vector{}) {
+		tr.SetPosition(tr.Position().Add(self.Offset))
+	}
+}
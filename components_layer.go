@@ -0,0 +1,31 @@
+package katsu2d
+
+import "github.com/edwinsyarief/teishoku"
+
+// RenderSpace selects whether an entity is drawn through the camera.
+type RenderSpace int
+
+const (
+	// WorldSpace entities are transformed by the active camera view.
+	WorldSpace RenderSpace = iota
+	// ScreenSpace entities ignore the camera, e.g. HUD elements placed in
+	// screen pixels.
+	ScreenSpace
+)
+
+// LayerComponent sets the render space of a sprite, text, shape or line
+// entity. Entities without one are drawn in world space.
+type LayerComponent struct {
+	Space RenderSpace
+}
+
+// NewLayerComponent creates a layer component drawn in space.
+func NewLayerComponent(space RenderSpace) LayerComponent {
+	return LayerComponent{Space: space}
+}
+
+// entityRenderSpace switches rdr to the render space of e.
+func entityRenderSpace(w *teishoku.World, e teishoku.Entity, rdr *BatchRenderer) {
+	l := teishoku.GetComponent[LayerComponent](w, e)
+	rdr.SetScreenSpace(l != nil && l.Space == ScreenSpace)
+}
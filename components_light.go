@@ -0,0 +1,31 @@
+package katsu2d
+
+import "image/color"
+
+// StaticLightComponent is a light that never moves. It is baked into a
+// Lightmap instead of being lit every frame.
+type StaticLightComponent struct {
+	Color     color.RGBA
+	Radius    float64
+	Intensity float64
+}
+
+// PointLightComponent is a dynamic light shading sprites that have a
+// normal map. Height is how far above the sprite plane it sits, in pixels;
+// lower lights graze the surface and bring out more relief.
+type PointLightComponent struct {
+	Color     color.RGBA
+	Radius    float64
+	Intensity float64
+	Height    float64
+}
+
+// NewPointLightComponent creates a point light of the given color and radius.
+func NewPointLightComponent(col color.RGBA, radius float64) PointLightComponent {
+	return PointLightComponent{Color: col, Radius: radius, Intensity: 1, Height: 32}
+}
+
+// NewStaticLightComponent creates a static light of the given color and radius.
+func NewStaticLightComponent(col color.RGBA, radius float64) StaticLightComponent {
+	return StaticLightComponent{Color: col, Radius: radius, Intensity: 1}
+}
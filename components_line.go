@@ -0,0 +1,7 @@
+package katsu2d
+
+// LineComponent attaches a Line to an entity. The line's points are in local
+// space and are positioned by the entity's TransformComponent.
+type LineComponent struct {
+	Line *Line
+}
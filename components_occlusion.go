@@ -0,0 +1,10 @@
+package katsu2d
+
+// OcclusionGroupComponent places an entity in a baked occlusion group, such
+// as a map chunk or building. The sprite, ordered sprite, static sprite,
+// text and shape systems skip every entity of a group the OcclusionSet
+// reports as hidden from the camera's region. Particles are not culled by
+// group.
+type OcclusionGroupComponent struct {
+	Group int
+}
@@ -1,5 +1,42 @@
 package katsu2d
 
+import "github.com/edwinsyarief/teishoku"
+
+// OrderProvider computes an entity's sort index each frame.
+type OrderProvider func(w *teishoku.World, e teishoku.Entity) float64
+
+// OrderableComponent sorts sprites sharing a Z and elevation by Index. A
+// static Index is set once; with Provider or TrackY set, OrderableSystem
+// recomputes it every frame so moving entities keep sorting correctly.
 type OrderableComponent struct {
 	Index float64
+	// Provider, when set, supplies the index each frame.
+	Provider OrderProvider
+	// TrackY makes the index follow the entity's transform Y plus YOffset.
+	// It is ignored when Provider is set.
+	TrackY  bool
+	YOffset float64
+}
+
+// NewOrderableComponent creates an orderable whose index comes from
+// provider. A nil provider gives a static index set via SetIndex.
+func NewOrderableComponent(provider OrderProvider) OrderableComponent {
+	return OrderableComponent{Provider: provider}
+}
+
+// NewYSortComponent creates an orderable that tracks the entity's Y
+// position, offset by yOffset (e.g. to sort by the feet of a sprite).
+func NewYSortComponent(yOffset float64) OrderableComponent {
+	return OrderableComponent{TrackY: true, YOffset: yOffset}
+}
+
+// SetIndex sets a static index.
+func (self OrderableComponent) SetIndex(index float64) OrderableComponent {
+	self.Index = index
+	return self
+}
+
+// Dynamic reports whether the index is recomputed each frame.
+func (self *OrderableComponent) Dynamic() bool {
+	return self.Provider != nil || self.TrackY
 }
@@ -0,0 +1,112 @@
+package katsu2d
+
+// PathCurve selects how a path interpolates between its points.
+type PathCurve int
+
+const (
+	// PathLinear connects the points with straight segments.
+	PathLinear PathCurve = iota
+	// PathCatmullRom passes a smooth spline through every point.
+	PathCatmullRom
+	// PathBezier treats the points as a chain of cubic Bezier curves:
+	// anchor, control, control, anchor, control, control, anchor, ...
+	PathBezier
+)
+
+// PathLoopMode controls what a follower does when it reaches the end of a path.
+type PathLoopMode int
+
+const (
+	PathOnce PathLoopMode = iota
+	PathLoop
+	PathPingPong
+)
+
+// PathComponent describes a curve in world space.
+type PathComponent struct {
+	Points    []Point
+	Curve     PathCurve
+	Closed    bool
+	Precision int // Samples per curve segment, defaults to 16
+	samples   []Vector
+	length    float64
+	built     bool
+}
+
+// Invalidate forces the sampled path to be rebuilt, e.g. after editing Points.
+func (self *PathComponent) Invalidate() {
+	self.built = false
+}
+
+// Polyline returns the path sampled into straight segments.
+func (self *PathComponent) Polyline() []Vector {
+	if !self.built {
+		self.build()
+	}
+	return self.samples
+}
+
+// Length returns the length of the sampled path.
+func (self *PathComponent) Length() float64 {
+	if !self.built {
+		self.build()
+	}
+	return self.length
+}
+
+func (self *PathComponent) build() {
+	precision := self.Precision
+	if precision <= 0 {
+		precision = 16
+	}
+	points := make([]Vector, len(self.Points))
+	for i, p := range self.Points {
+		points[i] = Vector(p)
+	}
+	switch self.Curve {
+	case PathCatmullRom:
+		if self.Closed && len(points) > 2 {
+			points = append(points, points[0])
+		}
+		self.samples = catmullRomPath(points, precision)
+	case PathBezier:
+		self.samples = bezierPath(points, precision)
+	default:
+		self.samples = points
+	}
+	closed := self.Closed && self.Curve == PathLinear
+	self.length = PolylineLength(self.samples, closed)
+	self.built = true
+}
+
+// bezierPath samples a chain of cubic Bezier curves.
+func bezierPath(points []Vector, precision int) []Vector {
+	if len(points) < 4 {
+		return points
+	}
+	samples := []Vector{points[0]}
+	for i := 0; i+3 < len(points); i += 3 {
+		p0, p1, p2, p3 := points[i], points[i+1], points[i+2], points[i+3]
+		for j := 1; j <= precision; j++ {
+			t := float64(j) / float64(precision)
+			u := 1 - t
+			samples = append(samples, p0.ScaleF(u*u*u).
+				Add(p1.ScaleF(3*u*u*t)).
+				Add(p2.ScaleF(3*u*t*t)).
+				Add(p3.ScaleF(t*t*t)))
+		}
+	}
+	return samples
+}
+
+// PathFollowerComponent moves an entity along the PathComponent on the same entity.
+type PathFollowerComponent struct {
+	Speed           float64      // Travel speed in units per second
+	EaseType        EaseType     // Speed profile over one traversal of the path
+	Mode            PathLoopMode // Behaviour at the end of the path
+	OrientToTangent bool         // Rotate the entity to face along the path
+	RotationOffset  float64      // Added to the tangent angle when orienting
+	Elapsed         float64      // Time spent on the current traversal cycle
+	Distance        float64      // Current distance along the path
+	Active          bool
+}
@@ -0,0 +1,5 @@
+package katsu2d
+
+// QuestActorComponent marks an entity, usually the player, whose position
+// completes ObjectiveReachArea objectives.
+type QuestActorComponent struct{}
@@ -0,0 +1,99 @@
+package katsu2d
+
+import "github.com/edwinsyarief/teishoku"
+
+// RigidBodyComponent moves an entity by its velocity. Impulses are scaled
+// by mass, and velocity decays by GroundFriction or AirFriction per second
+// depending on Grounded, which the game's ground checks set, or the
+// CollisionSystem when it resolves contacts.
+type RigidBodyComponent struct {
+	Velocity Vector
+	// Mass scales impulses; 0 is treated as 1.
+	Mass           float64
+	Gravity        Vector
+	GroundFriction float64
+	AirFriction    float64
+	Grounded       bool
+	// ControlLock is the seconds left during which character controllers
+	// should ignore input, e.g. while knocked back. See ControlLocked.
+	ControlLock float64
+}
+
+// NewRigidBodyComponent creates a body of the given mass with ground
+// friction strong enough to stop a knockback within a fraction of a second.
+func NewRigidBodyComponent(mass float64) RigidBodyComponent {
+	return RigidBodyComponent{Mass: mass, GroundFriction: 8, AirFriction: 1}
+}
+
+// ApplyImpulse changes the velocity by impulse divided by mass.
+func (self *RigidBodyComponent) ApplyImpulse(impulse Vector) {
+	mass := self.Mass
+	if mass <= 0 {
+		mass = 1
+	}
+	self.Velocity = self.Velocity.Add(impulse.DivF(mass))
+}
+
+// inverseMass returns 1/Mass, with 0 treated as 1.
+func (self *RigidBodyComponent) inverseMass() float64 {
+	if self.Mass <= 0 {
+		return 1
+	}
+	return 1 / self.Mass
+}
+
+// LockControl keeps ControlLock at least seconds.
+func (self *RigidBodyComponent) LockControl(seconds float64) {
+	self.ControlLock = max(self.ControlLock, seconds)
+}
+
+// ApplyImpulse pushes e's RigidBodyComponent by impulse. It does nothing
+// for entities without one.
+func ApplyImpulse(w *teishoku.World, e teishoku.Entity, impulse Vector) {
+	if rb := teishoku.GetComponent[RigidBodyComponent](w, e); rb != nil {
+		rb.ApplyImpulse(impulse)
+	}
+}
+
+// Knockback describes combat pushback.
+type Knockback struct {
+	// Strength is the impulse; a body of mass 1 leaves at this speed.
+	Strength float64
+	// Lift is added upwards (negative Y) as a fraction of Strength, so
+	// grounded targets pop into the air in side-view games.
+	Lift float64
+	// Stun locks the target's controls for this many seconds.
+	Stun float64
+	// Reset drops the target's velocity first, so knockbacks don't stack
+	// with running speed.
+	Reset bool
+}
+
+// ApplyKnockback pushes e away from source, locking its controls for the
+// knockback's Stun time.
+func ApplyKnockback(w *teishoku.World, e teishoku.Entity, source Vector, kb Knockback) {
+	rb, t := teishoku.GetComponent2[RigidBodyComponent, TransformComponent](w, e)
+	if rb == nil || t == nil {
+		return
+	}
+	dir := Vector(t.Position).Sub(source).Normalize()
+	if dir == (Vector{}) {
+		dir = V(1, 0)
+	}
+	if kb.Reset {
+		rb.Velocity = Vector{}
+	}
+	rb.ApplyImpulse(dir.ScaleF(kb.Strength).Add(V(0, -kb.Lift*kb.Strength)))
+	if kb.Lift > 0 {
+		rb.Grounded = false
+	}
+	rb.LockControl(kb.Stun)
+}
+
+// ControlLocked reports whether e's controls are locked by a knockback.
+// The InputSystem gives locked entities no input; controllers reading input
+// elsewhere should check it themselves.
+func ControlLocked(w *teishoku.World, e teishoku.Entity) bool {
+	rb := teishoku.GetComponent[RigidBodyComponent](w, e)
+	return rb != nil && rb.ControlLock > 0
+}
@@ -0,0 +1,29 @@
+package katsu2d
+
+// RoofComponent marks an occluding layer, such as a building roof, that fades
+// out while a revealer stands inside its trigger region, or inside a trigger
+// zone named Zone. Only revealers on the roof's elevation level reveal it.
+// The fade is applied to the entity's SpriteComponent opacity.
+type RoofComponent struct {
+	Region      Rectangle // Trigger region in world space
+	Zone        string    // TriggerZoneComponent name revealing the roof, if any
+	HiddenAlpha float64   // Opacity while revealed, e.g. 0 or 0.2
+	FadeSpeed   float64   // Opacity change per second, 0 snaps instantly
+	Alpha       float64   // Current opacity, driven by the system
+	Revealed    bool      // Whether a revealer is inside the region
+}
+
+// NewRoofComponent creates a roof over region that fades fully out in 0.25s.
+func NewRoofComponent(region Rectangle) RoofComponent {
+	return RoofComponent{
+		Region:    region,
+		FadeSpeed: 4,
+		Alpha:     1,
+	}
+}
+
+// RoofRevealerComponent marks entities, typically the player, whose position
+// reveals the roofs they stand under.
+type RoofRevealerComponent struct {
+	Radius float64 // Reveal when within this distance of the region
+}
@@ -0,0 +1,39 @@
+package katsu2d
+
+import "github.com/edwinsyarief/teishoku"
+
+// ScriptContext identifies the entity a script callback runs for.
+type ScriptContext struct {
+	World  *teishoku.World
+	Entity teishoku.Entity
+}
+
+// ScriptComponent attaches one-off behavior to an entity without writing a
+// dedicated system. All callbacks are optional.
+type ScriptComponent struct {
+	OnStart     func(ctx *ScriptContext)
+	Update      func(ctx *ScriptContext, dt float64)
+	Draw        func(ctx *ScriptContext, rdr *BatchRenderer)
+	OnCollision func(ctx *ScriptContext, other teishoku.Entity)
+	OnEvent     func(ctx *ScriptContext, event any)
+	Disabled    bool
+	started     bool
+}
+
+// SendScriptEvent delivers event to e's script OnEvent hook, if any.
+func SendScriptEvent(w *teishoku.World, e teishoku.Entity, event any) {
+	s := teishoku.GetComponent[ScriptComponent](w, e)
+	if s == nil || s.Disabled || s.OnEvent == nil {
+		return
+	}
+	s.OnEvent(&ScriptContext{World: w, Entity: e}, event)
+}
+
+// SendScriptCollision calls e's script OnCollision hook, if any.
+func SendScriptCollision(w *teishoku.World, e, other teishoku.Entity) {
+	s := teishoku.GetComponent[ScriptComponent](w, e)
+	if s == nil || s.Disabled || s.OnCollision == nil {
+		return
+	}
+	s.OnCollision(&ScriptContext{World: w, Entity: e}, other)
+}
@@ -0,0 +1,80 @@
+package katsu2d
+
+import (
+	_ "embed"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+//go:embed internal_assets/shaders/sprite_flash.kage
+var _spriteFlashShaderSource []byte
+
+//go:embed internal_assets/shaders/sprite_dissolve.kage
+var _spriteDissolveShaderSource []byte
+
+//go:embed internal_assets/shaders/sprite_outline.kage
+var _spriteOutlineShaderSource []byte
+
+var spriteFlashShader, spriteDissolveShader, spriteOutlineShader *ebiten.Shader
+
+// ShaderComponent draws an entity's sprite with a Kage shader instead of the
+// shared batch. The sprite texture is imageSrc0, Images are bound from
+// imageSrc1 on, and the vertex color carries the sprite color and opacity.
+// Each shaded sprite is its own draw call, so keep them for effects.
+type ShaderComponent struct {
+	Shader   *ebiten.Shader
+	Uniforms map[string]any
+	Images   [3]*ebiten.Image
+	Blend    ebiten.Blend
+	// Disabled falls back to the normal batched sprite.
+	Disabled bool
+}
+
+// SetUniform sets a uniform of the shader.
+func (self *ShaderComponent) SetUniform(name string, value any) {
+	if self.Uniforms == nil {
+		self.Uniforms = make(map[string]any)
+	}
+	self.Uniforms[name] = value
+}
+
+// NewShaderComponent creates a component drawing with shader.
+func NewShaderComponent(shader *ebiten.Shader) ShaderComponent {
+	return ShaderComponent{Shader: shader, Uniforms: make(map[string]any)}
+}
+
+// NewFlashShader tints the sprite towards col by the Amount uniform, e.g.
+// tweened from 1 to 0 on hit.
+func NewFlashShader(col color.RGBA) ShaderComponent {
+	c := NewShaderComponent(builtinShader(&spriteFlashShader, _spriteFlashShaderSource, "flash"))
+	c.SetUniform("FlashColor", colorUniform(col))
+	c.SetUniform("Amount", float32(1))
+	return c
+}
+
+// NewDissolveShader eats the sprite away in noisy blobs as the Progress
+// uniform goes from 0 to 1, glowing edgeColor along the border.
+func NewDissolveShader(edgeColor color.RGBA) ShaderComponent {
+	c := NewShaderComponent(builtinShader(&spriteDissolveShader, _spriteDissolveShaderSource, "dissolve"))
+	c.SetUniform("Progress", float32(0))
+	c.SetUniform("EdgeWidth", float32(0.05))
+	c.SetUniform("EdgeColor", colorUniform(edgeColor))
+	c.SetUniform("Scale", float32(4))
+	return c
+}
+
+// NewOutlineShader outlines the sprite's opaque pixels with col, thickness
+// pixels wide. The outline is drawn inside the sprite's bounds, so leave
+// transparent padding around the art.
+func NewOutlineShader(col color.RGBA, thickness float64) ShaderComponent {
+	c := NewShaderComponent(builtinShader(&spriteOutlineShader, _spriteOutlineShaderSource, "outline"))
+	c.SetUniform("OutlineColor", colorUniform(col))
+	c.SetUniform("Thickness", float32(thickness))
+	return c
+}
+
+// colorUniform converts col to a vec4 uniform with straight alpha.
+func colorUniform(col color.RGBA) [4]float32 {
+	return [4]float32{float32(col.R) / 255, float32(col.G) / 255, float32(col.B) / 255, float32(col.A) / 255}
+}
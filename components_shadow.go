@@ -0,0 +1,55 @@
+package katsu2d
+
+import "image/color"
+
+// ShadowMode selects how a ShadowComponent is drawn.
+type ShadowMode int
+
+const (
+	// ShadowEllipse draws a soft ellipse with Size as its radii.
+	ShadowEllipse ShadowMode = iota
+	// ShadowSilhouette draws a squashed, tinted copy of the entity's sprite,
+	// with Size as the scale applied to it.
+	ShadowSilhouette
+	// ShadowSkewed draws the sprite's silhouette sheared along the world's
+	// ShadowLight, with a length proportional to Height.
+	ShadowSkewed
+)
+
+// ShadowComponent draws a blob shadow at an entity's ground anchor, which is
+// the bottom center of its sprite or its position when it has none.
+type ShadowComponent struct {
+	Mode    ShadowMode
+	Offset  Point
+	Size    Point
+	Color   color.RGBA
+	Opacity float64
+	// Softness is the fraction of the ellipse radius that fades out.
+	Softness float64
+	// Height is the caster height for skewed shadows. Zero uses the
+	// sprite's scaled height.
+	Height float64
+}
+
+// NewShadowComponent creates a soft elliptical shadow with the given radii.
+func NewShadowComponent(radiusX, radiusY float64) ShadowComponent {
+	return ShadowComponent{
+		Mode:     ShadowEllipse,
+		Size:     Point{X: radiusX, Y: radiusY},
+		Color:    color.RGBA{A: 255},
+		Opacity:  0.4,
+		Softness: 0.6,
+	}
+}
+
+// NewSkewedShadowComponent creates a silhouette shadow cast along the
+// world's ShadowLight.
+func NewSkewedShadowComponent(height float64) ShadowComponent {
+	return ShadowComponent{
+		Mode:    ShadowSkewed,
+		Size:    Point{X: 1, Y: 1},
+		Color:   color.RGBA{A: 255},
+		Opacity: 0.4,
+		Height:  height,
+	}
+}
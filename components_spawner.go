@@ -0,0 +1,83 @@
+package katsu2d
+
+import "github.com/edwinsyarief/teishoku"
+
+// SpawnFunc creates an entity at the given world position and returns it.
+type SpawnFunc func(w *teishoku.World, pos Vector) teishoku.Entity
+
+// SpawnMode selects how a spawner schedules its spawns.
+type SpawnMode int
+
+const (
+	// SpawnWaves spawns the configured waves one after another.
+	SpawnWaves SpawnMode = iota
+	// SpawnContinuous spawns at a rate given by the Rate curve over time.
+	SpawnContinuous
+)
+
+// SpawnWave is a group of spawns. Delay is waited before the wave starts and
+// Interval between individual spawns. With WaitForClear the wave only starts
+// once everything spawned earlier is gone.
+type SpawnWave struct {
+	Count        int
+	Interval     float64
+	Delay        float64
+	WaitForClear bool
+}
+
+// SpawnerComponent spawns entities at points or inside an area around the
+// spawner's transform.
+type SpawnerComponent struct {
+	Spawn SpawnFunc
+	Mode  SpawnMode
+
+	// Points are offsets from the spawner, used in turn. If Area is not
+	// empty a random point inside it is used instead.
+	Points []Point
+	Area   Rectangle
+
+	Waves []SpawnWave
+	// Rate is spawns per second over elapsed time in SpawnContinuous mode.
+	Rate *AnimationCurve
+	// Duration ends continuous spawning after this many seconds; 0 runs forever.
+	Duration float64
+
+	MaxAlive int
+	// ActivationRadius only spawns while a SpawnTriggerComponent entity is
+	// within this distance. 0 disables the check.
+	ActivationRadius float64
+	// Condition is a blackboard expression that must hold to spawn.
+	Condition string
+	Active    bool
+
+	Elapsed  float64
+	Wave     int
+	Spawned  int
+	Finished bool
+
+	waveTimer   float64
+	waveSpawned int
+	accumulator float64
+	nextPoint   int
+	alive       []teishoku.Entity
+}
+
+// NewSpawnerComponent creates an active spawner using the given spawn func.
+func NewSpawnerComponent(spawn SpawnFunc, mode SpawnMode) SpawnerComponent {
+	return SpawnerComponent{Spawn: spawn, Mode: mode, Active: true}
+}
+
+// Alive returns the entities spawned by this spawner that still exist.
+func (self *SpawnerComponent) Alive() []teishoku.Entity {
+	return self.alive
+}
+
+// Reset restarts the spawner from the first wave.
+func (self *SpawnerComponent) Reset() {
+	self.Elapsed, self.Wave, self.Spawned, self.Finished = 0, 0, 0, false
+	self.waveTimer, self.waveSpawned, self.accumulator, self.nextPoint = 0, 0, 0, 0
+}
+
+// SpawnTriggerComponent marks entities, usually the player, that activate
+// spawners with an ActivationRadius.
+type SpawnTriggerComponent struct{}
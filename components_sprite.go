@@ -2,10 +2,60 @@ package katsu2d
 
 import "image/color"
 
+// SpriteOrigin selects how a sprite is anchored relative to its transform position.
+type SpriteOrigin int
+
+const (
+	// OriginNone leaves anchoring entirely to the transform offset.
+	OriginNone SpriteOrigin = iota
+	OriginTopLeft
+	OriginCenter
+	OriginBottomCenter
+	// OriginCustom anchors at the normalized (0..1) OriginAnchor point.
+	OriginCustom
+)
+
 type SpriteComponent struct {
 	TextureID     int
 	Width, Height int
 	Bound         Bound
 	Color         color.RGBA
 	Opacity       float64
+	// Origin anchors the sprite; any preset other than OriginNone tracks
+	// the size of the current source rectangle.
+	Origin       SpriteOrigin
+	OriginAnchor Point
+	// NormalMapID is a texture laid out like TextureID holding the sprite's
+	// normals; the SpriteSystem then shades it with PointLightComponents.
+	// 0 disables lighting.
+	NormalMapID int
+}
+
+// Anchor returns the normalized anchor point for the sprite's origin preset.
+func (self *SpriteComponent) Anchor() Point {
+	switch self.Origin {
+	case OriginCenter:
+		return Point{X: 0.5, Y: 0.5}
+	case OriginBottomCenter:
+		return Point{X: 0.5, Y: 1}
+	case OriginCustom:
+		return self.OriginAnchor
+	}
+	return Point{}
+}
+
+// SyncSizeToBound resizes the sprite to its source rectangle when an origin
+// preset is active, so the anchor follows frames of varying dimensions.
+func (self *SpriteComponent) SyncSizeToBound() {
+	if self.Origin == OriginNone || IsBoundEmpty(self.Bound) {
+		return
+	}
+	self.Width = int(self.Bound.Max.X - self.Bound.Min.X)
+	self.Height = int(self.Bound.Max.Y - self.Bound.Min.Y)
+}
+
+// OriginOffset returns the local offset in pixels implied by the origin preset.
+func (self *SpriteComponent) OriginOffset() Vector {
+	anchor := self.Anchor()
+	return V(anchor.X*float64(self.Width), anchor.Y*float64(self.Height))
 }
@@ -0,0 +1,11 @@
+package katsu2d
+
+// StateMachineComponent runs a hierarchical state machine for an entity.
+type StateMachineComponent struct {
+	Machine *StateMachine
+}
+
+// NewStateMachineComponent creates a component running a new machine from def.
+func NewStateMachineComponent(def *StateMachineDefinition) StateMachineComponent {
+	return StateMachineComponent{Machine: NewStateMachine(def)}
+}
@@ -0,0 +1,18 @@
+package katsu2d
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+// StaticComponent tags a sprite as static decoration. Its transformed
+// vertices are cached and only recomputed when the transform or sprite changes.
+type StaticComponent struct {
+	transform TransformComponent
+	sprite    SpriteComponent
+	quad      [4]ebiten.Vertex
+	group     int
+	cached    bool
+}
+
+// Invalidate forces the cached geometry to be rebuilt on the next update.
+func (self *StaticComponent) Invalidate() {
+	self.cached = false
+}
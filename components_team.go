@@ -0,0 +1,20 @@
+package katsu2d
+
+import "github.com/edwinsyarief/teishoku"
+
+// Team identifies the side an entity fights for. TeamNeutral is neither
+// friend nor foe of anyone.
+type Team uint8
+
+const TeamNeutral Team = 0
+
+// TeamComponent assigns an entity to a team.
+type TeamComponent struct {
+	Team Team
+}
+
+// OwnerComponent references the entity that owns this one, e.g. the shooter
+// of a bullet. Entities without a TeamComponent inherit their owner's team.
+type OwnerComponent struct {
+	Owner teishoku.Entity
+}
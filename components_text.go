@@ -25,4 +25,7 @@ type TextComponent struct {
 	CachedWidth       float64
 	CachedHeight      float64
 	Color             color.RGBA
+	// RichText parses Caption as markup with inline colors, icons and
+	// effects; see ParseRichText.
+	RichText bool
 }
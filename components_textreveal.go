@@ -0,0 +1,124 @@
+package katsu2d
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/edwinsyarief/teishoku"
+)
+
+// RevealCharFunc is called for every character a TextRevealComponent
+// reveals, e.g. to play a blip sound. Icons are reported as
+// utf8.RuneError.
+type RevealCharFunc func(w *teishoku.World, e teishoku.Entity, r rune)
+
+// TextRevealComponent types Source into the entity's TextComponent caption
+// one character at a time. A [pause=seconds] tag in Source holds the reveal.
+// With RichText set on the text, other markup tags are copied through
+// without delay and an [icon=name] counts as one character.
+type TextRevealComponent struct {
+	Source string
+	// Speed is the number of characters revealed per second; 0 reveals
+	// everything at once.
+	Speed  float64
+	OnChar RevealCharFunc
+
+	revealed int
+	elapsed  float64
+	pause    float64
+	done     bool
+	skip     bool
+	written  bool
+	parsed   string
+	rich     bool
+	tokens   []revealToken
+}
+
+type revealToken struct {
+	text  string
+	pause float64
+	// char is set for tokens revealed one per step.
+	char rune
+}
+
+// NewTextRevealComponent reveals source at speed characters per second.
+func NewTextRevealComponent(source string, speed float64) TextRevealComponent {
+	return TextRevealComponent{Source: source, Speed: speed}
+}
+
+// Skip reveals the rest of the text on the next update, without calling
+// OnChar.
+func (self *TextRevealComponent) Skip() {
+	self.skip = true
+}
+
+// Restart reveals source from the beginning.
+func (self *TextRevealComponent) Restart(source string) {
+	self.Source = source
+	self.parsed = ""
+	self.revealed, self.elapsed, self.pause = 0, 0, 0
+	self.done, self.skip, self.written = false, false, false
+}
+
+// Done reports whether the whole text has been revealed.
+func (self *TextRevealComponent) Done() bool {
+	return self.done
+}
+
+// tokenize splits Source into reveal steps when it changed.
+func (self *TextRevealComponent) tokenize(rich bool) {
+	if self.parsed == self.Source && self.rich == rich && self.tokens != nil {
+		return
+	}
+	if self.parsed != self.Source {
+		self.revealed, self.elapsed, self.pause = 0, 0, 0
+		self.done, self.written = false, false
+	}
+	self.parsed, self.rich = self.Source, rich
+	self.tokens = self.tokens[:0]
+	s := self.Source
+	for len(s) > 0 {
+		if s[0] == '[' {
+			if rich && strings.HasPrefix(s, "[[") {
+				self.tokens = append(self.tokens, revealToken{text: "[[", char: '['})
+				s = s[2:]
+				continue
+			}
+			if end := strings.IndexByte(s, ']'); end > 0 {
+				tag := s[1:end]
+				if v, ok := strings.CutPrefix(tag, "pause="); ok {
+					if d, err := strconv.ParseFloat(v, 64); err == nil {
+						self.tokens = append(self.tokens, revealToken{pause: d})
+						s = s[end+1:]
+						continue
+					}
+				}
+				if rich {
+					token := revealToken{text: s[:end+1]}
+					if strings.HasPrefix(tag, "icon=") {
+						token.char = utf8.RuneError
+					}
+					self.tokens = append(self.tokens, token)
+					s = s[end+1:]
+					continue
+				}
+			}
+		}
+		r, size := utf8.DecodeRuneInString(s)
+		self.tokens = append(self.tokens, revealToken{text: s[:size], char: r})
+		s = s[size:]
+	}
+	if self.tokens == nil {
+		self.tokens = []revealToken{}
+	}
+}
+
+// caption returns the markup revealed so far.
+func (self *TextRevealComponent) caption() string {
+	var sb strings.Builder
+	for _, t := range self.tokens[:self.revealed] {
+		sb.WriteString(t.text)
+	}
+	return sb.String()
+}
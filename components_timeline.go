@@ -0,0 +1,291 @@
+package katsu2d
+
+import (
+	"slices"
+
+	"github.com/edwinsyarief/teishoku"
+)
+
+// timelineAction is one keyframed action of a Timeline. Cues fire once when
+// the playhead passes start; spans interpolate over duration.
+type timelineAction struct {
+	start, duration float64
+	ease            EaseType
+	cue             func(w *teishoku.World)
+	begin           func(w *teishoku.World)
+	apply           func(w *teishoku.World, progress float64)
+	begun           bool
+}
+
+// Timeline sequences keyframed actions over time for cutscenes: camera
+// moves, entity tweens, audio cues, text reveals and script callbacks.
+// Build it with the At, Span and helper methods, then play it with a
+// TimelineComponent. Spans capture their start state when the playhead
+// first reaches them, so seeking back and forth replays them exactly.
+type Timeline struct {
+	// Loop restarts the timeline when it ends.
+	Loop bool
+
+	actions    []timelineAction
+	time       float64
+	paused     bool
+	done       bool
+	onComplete func(w *teishoku.World)
+}
+
+// NewTimeline creates an empty timeline.
+func NewTimeline() *Timeline {
+	return &Timeline{}
+}
+
+// At adds a cue calling fn when the playhead reaches time seconds.
+func (self *Timeline) At(time float64, fn func(w *teishoku.World)) *Timeline {
+	return self.add(timelineAction{start: time, cue: fn})
+}
+
+// Span adds an action from time over duration seconds. begin, which may be
+// nil, runs when the playhead first enters the span, e.g. to capture start
+// values; apply then receives the eased progress from 0 to 1.
+func (self *Timeline) Span(time, duration float64, ease EaseType, begin func(w *teishoku.World), apply func(w *teishoku.World, progress float64)) *Timeline {
+	return self.add(timelineAction{start: time, duration: max(duration, 0), ease: ease, begin: begin, apply: apply})
+}
+
+func (self *Timeline) add(action timelineAction) *Timeline {
+	i, _ := slices.BinarySearchFunc(self.actions, action.start, func(a timelineAction, t float64) int {
+		if a.start <= t {
+			return -1
+		}
+		return 1
+	})
+	self.actions = slices.Insert(self.actions, i, action)
+	return self
+}
+
+// MoveCamera moves the world's main camera to position and zoom from time
+// over duration seconds. It stops any camera follow when it begins.
+func (self *Timeline) MoveCamera(time, duration float64, position Vector, zoom float64, ease EaseType) *Timeline {
+	var fromPos Vector
+	var fromZoom float64
+	return self.Span(time, duration, ease, func(w *teishoku.World) {
+		if cam := GetCamera(w); cam != nil {
+			cam.StopFollowing()
+			fromPos, fromZoom = cam.Position, cam.zoom()
+		}
+	}, func(w *teishoku.World, t float64) {
+		if cam := GetCamera(w); cam != nil {
+			cam.Position = fromPos.Lerp(position, t)
+			cam.Zoom = fromZoom + (zoom-fromZoom)*t
+		}
+	})
+}
+
+// MoveEntity moves e to position from time over duration seconds.
+func (self *Timeline) MoveEntity(time, duration float64, e teishoku.Entity, position Vector, ease EaseType) *Timeline {
+	var from Vector
+	return self.Span(time, duration, ease, func(w *teishoku.World) {
+		if t := timelineTransform(w, e); t != nil {
+			from = Vector(t.Position)
+		}
+	}, func(w *teishoku.World, progress float64) {
+		if t := timelineTransform(w, e); t != nil {
+			t.Position = Point(from.Lerp(position, progress))
+			t.IsDirty = true
+		}
+	})
+}
+
+// FadeEntity fades e's sprite opacity to opacity from time over duration
+// seconds.
+func (self *Timeline) FadeEntity(time, duration float64, e teishoku.Entity, opacity float64, ease EaseType) *Timeline {
+	var from float64
+	sprite := func(w *teishoku.World) *SpriteComponent {
+		if !IsAlive(w, e) {
+			return nil
+		}
+		return teishoku.GetComponent[SpriteComponent](w, e)
+	}
+	return self.Span(time, duration, ease, func(w *teishoku.World) {
+		if s := sprite(w); s != nil {
+			from = s.Opacity
+		}
+	}, func(w *teishoku.World, progress float64) {
+		if s := sprite(w); s != nil {
+			s.Opacity = from + (opacity-from)*progress
+		}
+	})
+}
+
+// PlaySound plays a one-shot sound at time.
+func (self *Timeline) PlaySound(time float64, track TrackID) *Timeline {
+	return self.At(time, func(w *teishoku.World) {
+		if am := GetAudioManager(w); am != nil {
+			am.PlaySound(track, 0, nil)
+		}
+	})
+}
+
+// PlayMusic starts a music track at time.
+func (self *Timeline) PlayMusic(time float64, track TrackID, loop bool) *Timeline {
+	return self.At(time, func(w *teishoku.World) {
+		if am := GetAudioManager(w); am != nil {
+			am.PlayMusic(track, loop)
+		}
+	})
+}
+
+// RevealText starts revealing text on e's TextComponent at time, at speed
+// characters per second.
+func (self *Timeline) RevealText(time float64, e teishoku.Entity, text string, speed float64) *Timeline {
+	return self.At(time, func(w *teishoku.World) {
+		if !IsAlive(w, e) {
+			return
+		}
+		if reveal := teishoku.GetComponent[TextRevealComponent](w, e); reveal != nil {
+			reveal.Speed = speed
+			reveal.Restart(text)
+			return
+		}
+		teishoku.SetComponent(w, e, NewTextRevealComponent(text, speed))
+	})
+}
+
+// SendScript delivers event to e's script OnEvent hook at time.
+func (self *Timeline) SendScript(time float64, e teishoku.Entity, event any) *Timeline {
+	return self.At(time, func(w *teishoku.World) {
+		if IsAlive(w, e) {
+			SendScriptEvent(w, e, event)
+		}
+	})
+}
+
+// OnComplete calls fn each time the timeline reaches its end.
+func (self *Timeline) OnComplete(fn func(w *teishoku.World)) *Timeline {
+	self.onComplete = fn
+	return self
+}
+
+func timelineTransform(w *teishoku.World, e teishoku.Entity) *TransformComponent {
+	if !IsAlive(w, e) {
+		return nil
+	}
+	return teishoku.GetComponent[TransformComponent](w, e)
+}
+
+// Duration returns the time the last action ends.
+func (self *Timeline) Duration() float64 {
+	end := 0.0
+	for _, a := range self.actions {
+		end = max(end, a.start+a.duration)
+	}
+	return end
+}
+
+// Time returns the playhead in seconds.
+func (self *Timeline) Time() float64 {
+	return self.time
+}
+
+// Pause stops the playhead.
+func (self *Timeline) Pause() {
+	self.paused = true
+}
+
+// Resume continues after Pause.
+func (self *Timeline) Resume() {
+	self.paused = false
+}
+
+// Paused reports whether the timeline is paused.
+func (self *Timeline) Paused() bool {
+	return self.paused
+}
+
+// Done reports whether the timeline has reached its end.
+func (self *Timeline) Done() bool {
+	return self.done
+}
+
+// Seek moves the playhead to time, putting every span in its state at that
+// time. Cues between the old and new time are skipped, e.g. so skipping a
+// cutscene does not play all of its sounds.
+func (self *Timeline) Seek(w *teishoku.World, time float64) {
+	self.time = Clamp(time, 0, self.Duration())
+	self.done = false
+	// Later spans are applied last so they win on shared targets, and
+	// spans the playhead is now before are rewound in reverse order.
+	for i := len(self.actions) - 1; i >= 0; i-- {
+		if a := &self.actions[i]; a.apply != nil && a.begun && self.time < a.start {
+			a.apply(w, 0)
+		}
+	}
+	for i := range self.actions {
+		if a := &self.actions[i]; a.apply != nil && self.time >= a.start {
+			self.applySpan(w, a)
+		}
+	}
+}
+
+// Restart seeks to the start and resumes.
+func (self *Timeline) Restart(w *teishoku.World) {
+	self.Seek(w, 0)
+	self.paused = false
+}
+
+// Update advances the playhead by dt, firing the cues it passes and
+// applying the spans it is in. It reports whether the timeline ended during
+// this update.
+func (self *Timeline) Update(w *teishoku.World, dt float64) bool {
+	if self.paused || self.done {
+		return false
+	}
+	duration := self.Duration()
+	prev := self.time
+	self.time = min(self.time+dt, duration)
+	ended := self.time >= duration
+	for i := range self.actions {
+		a := &self.actions[i]
+		if a.cue != nil {
+			if a.start >= prev && (a.start < self.time || (ended && a.start <= self.time)) {
+				a.cue(w)
+			}
+			continue
+		}
+		if self.time >= a.start && (prev <= a.start+a.duration || !a.begun) {
+			self.applySpan(w, a)
+		}
+	}
+	if !ended {
+		return false
+	}
+	if self.onComplete != nil {
+		self.onComplete(w)
+	}
+	if self.Loop && duration > 0 {
+		self.time = 0
+		return true
+	}
+	self.done = true
+	return true
+}
+
+func (self *Timeline) applySpan(w *teishoku.World, a *timelineAction) {
+	if !a.begun {
+		a.begun = true
+		if a.begin != nil {
+			a.begin(w)
+		}
+	}
+	progress := 1.0
+	if a.duration > 0 {
+		progress = Clamp((self.time-a.start)/a.duration, 0, 1)
+	}
+	a.apply(w, EaseTypes[float64](a.ease)(progress, 0, 1, 1))
+}
+
+// TimelineComponent plays a Timeline on an entity, usually a dedicated
+// cutscene entity. The TimelineSystem advances it and publishes a
+// TimelineFinishedEvent with ID when it ends.
+type TimelineComponent struct {
+	ID       string
+	Timeline *Timeline
+}
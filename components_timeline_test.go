@@ -0,0 +1,106 @@
+package katsu2d
+
+import (
+	"testing"
+
+	"github.com/edwinsyarief/teishoku"
+)
+
+// testTimeline returns a timeline moving *value from its start to 10
+// between 1s and 3s, with cues counted at 0.5s and 2.5s.
+func testTimeline(value *float64, cues *[]float64) *Timeline {
+	var from float64
+	cue := func(at float64) func(*teishoku.World) {
+		return func(*teishoku.World) { *cues = append(*cues, at) }
+	}
+	return NewTimeline().
+		At(2.5, cue(2.5)).
+		Span(1, 2, Linear, func(*teishoku.World) { from = *value }, func(_ *teishoku.World, t float64) {
+			*value = from + (10-from)*t
+		}).
+		At(0.5, cue(0.5))
+}
+
+// TestTimelineUpdate verifies that cues fire once as the playhead passes
+// them and that spans interpolate between their keyframes.
+func TestTimelineUpdate(t *testing.T) {
+	value := 4.0
+	var cues []float64
+	tl := testTimeline(&value, &cues)
+	if got := tl.Duration(); got != 3 {
+		t.Fatalf("Duration = %v, want 3", got)
+	}
+	steps := []struct {
+		dt, value float64
+		cues      int
+	}{
+		{0.5, 4, 0},
+		{0.5, 4, 1},
+		{1, 7, 1},
+		{1, 10, 2},
+	}
+	for i, step := range steps {
+		ended := tl.Update(nil, step.dt)
+		if value != step.value || len(cues) != step.cues {
+			t.Fatalf("step %d: value %v with %d cues, want %v with %d", i, value, len(cues), step.value, step.cues)
+		}
+		if ended != (i == len(steps)-1) {
+			t.Fatalf("step %d: ended = %v", i, ended)
+		}
+	}
+	if !tl.Done() || tl.Update(nil, 1) {
+		t.Fatal("finished timeline kept running")
+	}
+}
+
+// TestTimelineSeek verifies that seeking puts spans in their state at the
+// new time in either direction and skips the cues in between.
+func TestTimelineSeek(t *testing.T) {
+	value := 4.0
+	var cues []float64
+	tl := testTimeline(&value, &cues)
+
+	tl.Seek(nil, 2)
+	if value != 7 || len(cues) != 0 {
+		t.Fatalf("after seeking to 2: value %v with cues %v, want 7 and none", value, cues)
+	}
+	tl.Seek(nil, 0)
+	if value != 4 || tl.Time() != 0 {
+		t.Fatalf("after seeking to 0: value %v at %v, want 4 at 0", value, tl.Time())
+	}
+	tl.Seek(nil, 99)
+	if value != 10 || tl.Time() != 3 {
+		t.Fatalf("after seeking past the end: value %v at %v, want 10 at 3", value, tl.Time())
+	}
+	tl.Seek(nil, 1.5)
+	if value != 5.5 {
+		t.Fatalf("after seeking back to 1.5: value %v, want 5.5", value)
+	}
+	tl.Update(nil, 1.5)
+	if value != 10 || len(cues) != 1 || cues[0] != 2.5 {
+		t.Fatalf("after playing to the end: value %v with cues %v, want 10 and [2.5]", value, cues)
+	}
+}
+
+// TestTimelinePauseAndLoop verifies that paused timelines hold and looping
+// ones restart after completing.
+func TestTimelinePauseAndLoop(t *testing.T) {
+	value := 0.0
+	var cues []float64
+	completed := 0
+	tl := testTimeline(&value, &cues).OnComplete(func(*teishoku.World) { completed++ })
+	tl.Loop = true
+
+	tl.Pause()
+	if tl.Update(nil, 2) || tl.Time() != 0 {
+		t.Fatal("paused timeline advanced")
+	}
+	tl.Resume()
+	if !tl.Update(nil, 5) || completed != 1 || tl.Done() || tl.Time() != 0 {
+		t.Fatalf("loop: completed %d, done %v, time %v", completed, tl.Done(), tl.Time())
+	}
+	tl.Update(nil, 0.75)
+	if len(cues) != 3 {
+		t.Fatalf("cues %v, want the first cue again after looping", cues)
+	}
+}
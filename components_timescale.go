@@ -0,0 +1,37 @@
+package katsu2d
+
+// TimeScaleRegionComponent defines a region, centered on the entity's
+// transform, inside which entities experience scaled time.
+type TimeScaleRegionComponent struct {
+	Radius      float64 // Circle radius; used when HalfExtents is empty
+	HalfExtents Point   // Half size of a rectangular region
+	Scale       float64 // Time multiplier, e.g. 0.25 for slow-mo, 2 for haste
+}
+
+// Contains reports whether p lies inside the region centered at center.
+func (self *TimeScaleRegionComponent) Contains(center, p Vector) bool {
+	if !IsPointEmpty(self.HalfExtents) {
+		d := p.Sub(center).Abs()
+		return d.X <= self.HalfExtents.X && d.Y <= self.HalfExtents.Y
+	}
+	return center.DistanceSquaredTo(p) <= self.Radius*self.Radius
+}
+
+// LocalTimeComponent opts an entity into local time scaling. Systems that
+// support it scale their dt via ScaledDelta.
+type LocalTimeComponent struct {
+	Base     float64 // The entity's own multiplier, 0 is treated as 1
+	Scale    float64 // Effective multiplier, computed by TimeScaleRegionSystem
+	computed bool
+}
+
+// Effective returns the multiplier to apply to dt for this entity.
+func (self *LocalTimeComponent) Effective() float64 {
+	if self.computed {
+		return self.Scale
+	}
+	if self.Base == 0 {
+		return 1
+	}
+	return self.Base
+}
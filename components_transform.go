@@ -2,6 +2,38 @@ package katsu2d
 
 type TransformComponent struct {
 	Position, Scale, Offset, Origin Point
-	Rotation, Z                     float64
-	IsDirty                         bool
+	// Skew holds the shear angles in radians along the X and Y axes.
+	Skew Point
+	// Pivot is the point, relative to Offset, around which rotation and
+	// skew are applied. A zero pivot rotates around the offset point.
+	Pivot       Point
+	Rotation, Z float64
+	IsDirty     bool
+	// Position before the last fixed step, for render interpolation.
+	previous    Point
+	interpolate bool
+}
+
+// Interpolated returns the position blended from the one before the last
+// fixed step by alpha.
+func (self *TransformComponent) Interpolated(alpha float64) Point {
+	if !self.interpolate {
+		return self.Position
+	}
+	return Point{
+		X: self.previous.X + (self.Position.X-self.previous.X)*alpha,
+		Y: self.previous.Y + (self.Position.Y-self.previous.Y)*alpha,
+	}
+}
+
+// ResetInterpolation stops interpolating from the previous position until
+// the next fixed step, e.g. after a teleport.
+func (self *TransformComponent) ResetInterpolation() {
+	self.interpolate = false
+}
+
+// HasSkewOrPivot reports whether the transform uses skew or a custom pivot,
+// which requires matrix-based rendering instead of the fast quad path.
+func (self *TransformComponent) HasSkewOrPivot() bool {
+	return !IsPointEmpty(self.Skew) || !IsPointEmpty(self.Pivot)
 }
@@ -0,0 +1,33 @@
+package katsu2d
+
+// TriggerZoneComponent is a polygon area with Points relative to the
+// entity position. The TriggerZoneSystem publishes TriggerEnterEvent and
+// TriggerExitEvent as collider entities move in and out of it.
+type TriggerZoneComponent struct {
+	Name     string
+	Points   []Vector
+	Disabled bool
+}
+
+// NewTriggerZoneComponent creates a trigger zone called name.
+func NewTriggerZoneComponent(name string, points ...Vector) TriggerZoneComponent {
+	return TriggerZoneComponent{Name: name, Points: points}
+}
+
+// Contains reports whether p lies inside the zone placed at position.
+func (self *TriggerZoneComponent) Contains(position, p Vector) bool {
+	return len(self.Points) >= 3 && PointInPolygon(p.Sub(position), self.Points)
+}
+
+// Bounds returns the world rectangle around the zone placed at position.
+func (self *TriggerZoneComponent) Bounds(position Vector) Rectangle {
+	if len(self.Points) == 0 {
+		return Rectangle{Min: position, Max: position}
+	}
+	bounds := Rectangle{Min: self.Points[0], Max: self.Points[0]}
+	for _, p := range self.Points[1:] {
+		bounds.Min = V(min(bounds.Min.X, p.X), min(bounds.Min.Y, p.Y))
+		bounds.Max = V(max(bounds.Max.X, p.X), max(bounds.Max.Y, p.Y))
+	}
+	return Rectangle{Min: bounds.Min.Add(position), Max: bounds.Max.Add(position)}
+}
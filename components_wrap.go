@@ -0,0 +1,7 @@
+package katsu2d
+
+// WorldWrapComponent opts an entity into world wrapping. Radius is the extent
+// used to decide when to draw it across a seam; 0 uses the world's Margin.
+type WorldWrapComponent struct {
+	Radius float64
+}
@@ -0,0 +1,238 @@
+package katsu2d
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/edwinsyarief/katsu2d/logger"
+	"github.com/edwinsyarief/teishoku"
+)
+
+// ContentIssue is one problem found by a content validation pass.
+type ContentIssue struct {
+	// Source names where the problem was found: "engine", a scene name,
+	// "audio" or a document name.
+	Source string
+	// Entity is the offending entity, or the zero entity.
+	Entity  teishoku.Entity
+	Message string
+}
+
+func (self ContentIssue) String() string {
+	if self.Entity == (teishoku.Entity{}) {
+		return fmt.Sprintf("%s: %s", self.Source, self.Message)
+	}
+	return fmt.Sprintf("%s: entity %v: %s", self.Source, self.Entity, self.Message)
+}
+
+// ContentError reports every issue of a validation pass at once.
+type ContentError struct {
+	Issues []ContentIssue
+}
+
+func (self *ContentError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "katsu2d: %d content issue(s)", len(self.Issues))
+	for _, issue := range self.Issues {
+		sb.WriteString("\n  ")
+		sb.WriteString(issue.String())
+	}
+	return sb.String()
+}
+
+// ContentValidator is a validation rule, e.g. a game specific check of its
+// own components.
+type ContentValidator func(v *ContentValidation)
+
+// ContentValidation collects the issues of a validation pass.
+type ContentValidation struct {
+	engine *Engine
+	source string
+	issues []ContentIssue
+}
+
+// Engine returns the engine being validated.
+func (self *ContentValidation) Engine() *Engine {
+	return self.engine
+}
+
+// EachWorld calls fn with the engine world and the world of every scene,
+// reporting issues against that source meanwhile.
+func (self *ContentValidation) EachWorld(fn func(w *teishoku.World)) {
+	self.In("engine", func() { fn(self.engine.World()) })
+	names := make([]string, 0, len(self.engine.scm.scenes))
+	for name := range self.engine.scm.scenes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		self.In(name, func() { fn(self.engine.scm.scenes[name].World()) })
+	}
+}
+
+// In reports issues found by fn against source.
+func (self *ContentValidation) In(source string, fn func()) {
+	prev := self.source
+	self.source = source
+	fn()
+	self.source = prev
+}
+
+// Reportf records an issue about e; pass the zero entity for issues not
+// tied to one.
+func (self *ContentValidation) Reportf(e teishoku.Entity, format string, args ...any) {
+	self.issues = append(self.issues, ContentIssue{
+		Source:  self.source,
+		Entity:  e,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// Issues returns the issues recorded so far.
+func (self *ContentValidation) Issues() []ContentIssue {
+	return self.issues
+}
+
+// WithContentValidation validates the content on the first update, with
+// the built-in checks and rules, logging every issue. In strict mode the
+// game then stops with a ContentError.
+func WithContentValidation(strict bool, rules ...ContentValidator) Option {
+	return func(e *Engine) {
+		e.lifecycle.onStart = append(e.lifecycle.onStart, func(e *Engine) {
+			err := e.ValidateContent(rules...)
+			if err == nil {
+				return
+			}
+			for _, issue := range err.(*ContentError).Issues {
+				logger.GetLogger().Error("content: %s", issue)
+			}
+			if strict {
+				e.contentError = err
+				e.Exit()
+			}
+		})
+	}
+}
+
+// ValidateContent checks the loaded assets and every scene for common
+// content errors, then runs rules, and returns a *ContentError listing all
+// issues found, or nil. The built-in checks find sprites and audio
+// emitters referencing missing assets, animation frames outside their
+// texture, and tracks not matching the audio sample rate.
+func (self *Engine) ValidateContent(rules ...ContentValidator) error {
+	v := &ContentValidation{engine: self}
+	v.EachWorld(func(w *teishoku.World) {
+		validateSprites(v, w)
+		validateAudioEmitters(v, w)
+	})
+	v.In("audio", func() { validateTracks(v) })
+	for _, rule := range rules {
+		rule(v)
+	}
+	if len(v.issues) == 0 {
+		return nil
+	}
+	return &ContentError{Issues: v.issues}
+}
+
+func validateSprites(v *ContentValidation, w *teishoku.World) {
+	tm := v.engine.TextureManager()
+	filter := teishoku.NewFilter[SpriteComponent](w)
+	for filter.Next() {
+		e, s := filter.Entity(), filter.Get()
+		// Get falls back to the default texture, so check the ID first.
+		if !tm.has(s.TextureID) {
+			v.Reportf(e, "sprite references missing texture %d", s.TextureID)
+			continue
+		}
+		img := tm.Get(s.TextureID)
+		size := img.Bounds().Size()
+		if !IsBoundEmpty(s.Bound) && !boundInside(s.Bound, size.X, size.Y) {
+			v.Reportf(e, "sprite bound %v is outside texture %d (%dx%d)", s.Bound, s.TextureID, size.X, size.Y)
+		}
+		anim := teishoku.GetComponent[AnimationComponent](w, e)
+		if anim == nil {
+			continue
+		}
+		if len(anim.Frames) == 0 {
+			v.Reportf(e, "animation has no frames")
+		}
+		for i, frame := range anim.Frames {
+			if !boundInside(frame, size.X, size.Y) {
+				v.Reportf(e, "animation frame %d %v is outside texture %d (%dx%d)", i, frame, s.TextureID, size.X, size.Y)
+			}
+		}
+	}
+}
+
+// boundInside reports whether b is a non-empty area within a w x h image.
+func boundInside(b Bound, w, h int) bool {
+	return b.Min.X >= 0 && b.Min.Y >= 0 && b.Max.X > b.Min.X && b.Max.Y > b.Min.Y &&
+		b.Max.X <= float64(w) && b.Max.Y <= float64(h)
+}
+
+func validateAudioEmitters(v *ContentValidation, w *teishoku.World) {
+	am := v.engine.AudioManager()
+	filter := teishoku.NewFilter[AudioEmitterComponent](w)
+	for filter.Next() {
+		if track := filter.Get().Track; am.trackList[track] == nil {
+			v.Reportf(filter.Entity(), "audio emitter references missing track %d", track)
+		}
+	}
+}
+
+func validateTracks(v *ContentValidation) {
+	am := v.engine.AudioManager()
+	rate := am.audioContext.SampleRate()
+	ids := make([]TrackID, 0, len(am.trackList))
+	for id := range am.trackList {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	for _, id := range ids {
+		info, err := am.TrackInfo(id)
+		if err != nil {
+			v.Reportf(teishoku.Entity{}, "track %d: %v", id, err)
+			continue
+		}
+		if info.SampleRate > 0 && info.SampleRate != rate {
+			v.Reportf(teishoku.Entity{}, "track %d is %d Hz but audio plays at %d Hz and would be pitched", id, info.SampleRate, rate)
+		}
+	}
+}
+
+// ValidateWorldDocument returns a rule checking a serialized world, such as
+// a prefab or scene file, for an unsupported format or content version and
+// for components and resources without a registered codec after migration.
+func ValidateWorldDocument(name string, data []byte) ContentValidator {
+	return func(v *ContentValidation) {
+		v.In(name, func() {
+			var doc WorldDocument
+			if err := json.Unmarshal(data, &doc); err != nil {
+				v.Reportf(teishoku.Entity{}, "decoding world: %v", err)
+				return
+			}
+			if doc.Version != worldFormatVersion {
+				v.Reportf(teishoku.Entity{}, "unsupported world format version %d", doc.Version)
+			}
+			if err := doc.migrate(); err != nil {
+				v.Reportf(teishoku.Entity{}, "%v", err)
+				return
+			}
+			for _, ed := range doc.Entities {
+				for component := range ed.Components {
+					if !slices.ContainsFunc(componentCodecs, func(c componentCodec) bool { return c.name == component }) {
+						v.Reportf(ed.Entity, "no codec registered for component %q", component)
+					}
+				}
+			}
+			for resource := range doc.Resources {
+				if !slices.ContainsFunc(resourceCodecs, func(c resourceCodec) bool { return c.name == resource }) {
+					v.Reportf(teishoku.Entity{}, "no codec registered for resource %q", resource)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package katsu2d
+
+import (
+	"compress/gzip"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/edwinsyarief/katsu2d/logger"
+	"github.com/edwinsyarief/teishoku"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// CrashReporter recovers panics in the engine's Update and Draw and writes
+// a crash report directory with the stack trace, recent log lines and,
+// optionally, world snapshots and a screenshot.
+type CrashReporter struct {
+	Dir string
+	// Version identifies the game build in reports.
+	Version string
+	// LogLines is the number of recent log lines included.
+	LogLines int
+	// WorldSnapshot adds the gzipped SerializeWorld output of the engine
+	// and current scene worlds.
+	WorldSnapshot bool
+	// Screenshot adds a PNG of the frame at the time of the crash.
+	Screenshot bool
+	// ShowScreen keeps the game open on a crash screen until a key is
+	// pressed; otherwise the engine exits right after writing the report.
+	ShowScreen bool
+	// OnCrash is called after the report is written.
+	OnCrash func(err *CrashError)
+}
+
+// NewCrashReporter creates a reporter writing to dir with the crash screen,
+// world snapshots and screenshots enabled.
+func NewCrashReporter(dir, version string) *CrashReporter {
+	return &CrashReporter{
+		Dir:           dir,
+		Version:       version,
+		LogLines:      100,
+		WorldSnapshot: true,
+		Screenshot:    true,
+		ShowScreen:    true,
+	}
+}
+
+// CrashError is returned from Engine.Run after a recovered panic.
+type CrashError struct {
+	Value any
+	Stack []byte
+	// Dir is the report directory, empty if it could not be written.
+	Dir string
+
+	shot bool
+}
+
+func (self *CrashError) Error() string {
+	if self.Dir == "" {
+		return fmt.Sprintf("katsu2d: crashed: %v", self.Value)
+	}
+	return fmt.Sprintf("katsu2d: crashed: %v (report in %s)", self.Value, self.Dir)
+}
+
+// WithCrashReporter recovers panics with r.
+func WithCrashReporter(r *CrashReporter) Option {
+	return func(e *Engine) {
+		e.crashReporter = r
+	}
+}
+
+// recoverCrash reports a panic recovered from Update or Draw. screen is the
+// partially drawn frame for a panic in Draw.
+func (self *Engine) recoverCrash(value any, screen *ebiten.Image) {
+	stack := debug.Stack()
+	logger.GetLogger().Error("panic: %v\n%s", value, stack)
+	self.crash = &CrashError{Value: value, Stack: stack}
+	dir, err := self.crashReporter.write(self, self.crash)
+	if err != nil {
+		logger.GetLogger().Error("writing crash report: %v", err)
+	}
+	self.crash.Dir = dir
+	if screen != nil {
+		self.crashReporter.writeScreenshot(self.crash, screen)
+	}
+	if self.crashReporter.OnCrash != nil {
+		self.crashReporter.OnCrash(self.crash)
+	}
+}
+
+func (self *CrashReporter) write(e *Engine, crash *CrashError) (string, error) {
+	dir := filepath.Join(self.Dir, "crash_"+time.Now().Format("20060102_150405"))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "time: %s\n", time.Now().Format(time.RFC3339))
+	fmt.Fprintf(&sb, "version: %s\n", self.Version)
+	fmt.Fprintf(&sb, "go: %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, dep := range info.Deps {
+			if dep.Path == "github.com/edwinsyarief/katsu2d" {
+				fmt.Fprintf(&sb, "katsu2d: %s\n", dep.Version)
+			}
+		}
+	}
+	fmt.Fprintf(&sb, "\npanic: %v\n\n%s\n", crash.Value, crash.Stack)
+	if self.LogLines > 0 {
+		sb.WriteString("\nrecent log:\n")
+		for _, line := range logger.Recent(self.LogLines) {
+			sb.WriteString(line)
+			sb.WriteByte('\n')
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "report.txt"), []byte(sb.String()), 0o644); err != nil {
+		return dir, err
+	}
+	if self.WorldSnapshot {
+		writeWorldDump(filepath.Join(dir, "world_engine.json.gz"), e.World())
+		if e.scm.current != nil {
+			writeWorldDump(filepath.Join(dir, "world_scene.json.gz"), e.scm.current.World())
+		}
+	}
+	return dir, nil
+}
+
+// writeWorldDump writes a gzipped world serialization, giving up quietly
+// if the world is too broken to serialize.
+func writeWorldDump(path string, w *teishoku.World) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.GetLogger().Error("serializing world for crash report: %v", r)
+		}
+	}()
+	data, err := SerializeWorld(w)
+	if err != nil {
+		logger.GetLogger().Error("serializing world for crash report: %v", err)
+		return
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	zw := gzip.NewWriter(f)
+	zw.Write(data)
+	zw.Close()
+}
+
+func (self *CrashReporter) writeScreenshot(crash *CrashError, screen *ebiten.Image) {
+	if !self.Screenshot || crash.shot || crash.Dir == "" {
+		return
+	}
+	crash.shot = true
+	b := screen.Bounds()
+	img := image.NewRGBA(b)
+	screen.ReadPixels(img.Pix)
+	f, err := os.Create(filepath.Join(crash.Dir, "screenshot.png"))
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	png.Encode(f, img)
+}
+
+// updateCrashed returns the crash error once the game should exit.
+func (self *Engine) updateCrashed() error {
+	r := self.crashReporter
+	if !r.ShowScreen {
+		// Wait for one crash frame when the screenshot is still missing.
+		if r.Screenshot && !self.crash.shot && self.crash.Dir != "" {
+			return nil
+		}
+		return self.crash
+	}
+	if ebiten.IsWindowBeingClosed() ||
+		len(inpututil.AppendJustPressedKeys(nil)) > 0 ||
+		inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		return self.crash
+	}
+	return nil
+}
+
+// drawCrashed draws the crash screen. After a crash in Update it first
+// draws the frame once more for the screenshot.
+func (self *Engine) drawCrashed(screen *ebiten.Image) {
+	if self.crashReporter.Screenshot && !self.crash.shot && self.crash.Dir != "" {
+		func() {
+			defer func() { recover() }()
+			self.draw(screen)
+		}()
+		self.crashReporter.writeScreenshot(self.crash, screen)
+	}
+	screen.Fill(color.Black)
+	msg := "The game crashed.\n\n"
+	if self.crash.Dir != "" {
+		msg += "A report was saved to:\n" + self.crash.Dir + "\n\n"
+	}
+	msg += "Press any key to exit."
+	ebitenutil.DebugPrintAt(screen, msg, 16, 16)
+}
@@ -0,0 +1,187 @@
+package katsu2d
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"sort"
+
+	"github.com/edwinsyarief/teishoku"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// EntityDrawer draws a single entity. Systems implement it so their entities
+// can be submitted as draw commands without allocating closures.
+type EntityDrawer interface {
+	DrawEntity(*teishoku.World, teishoku.Entity, *BatchRenderer)
+}
+
+// ZDrawSystem is a DrawSystem that submits z-keyed draw commands instead of
+// drawing directly, so its output interleaves by Z with other such systems.
+type ZDrawSystem interface {
+	DrawSystem
+	SubmitDraws(*teishoku.World, *DrawCommandList)
+}
+
+// DrawCommand is a single z-keyed unit of drawing work.
+type DrawCommand struct {
+	Z float64
+	// Texture is the image, or atlas page, the command samples from, if
+	// known. It lets a banded list group commands that can share a draw
+	// call, so pass TextureManager.Page rather than a sub-image.
+	Texture *ebiten.Image
+	Drawer  EntityDrawer
+	Entity  teishoku.Entity
+	Func    func(*BatchRenderer)
+}
+
+// DrawCommandList collects draw commands from many systems and executes them
+// sorted by Z. Commands with equal Z keep their submission order.
+type DrawCommandList struct {
+	// Band, when positive, treats all Z values within the same band of this
+	// size as one layer: commands in it are grouped by texture, so tiles,
+	// sprites, particles and shapes sharing an atlas page end up in one draw
+	// call. Order between different textures in a band is not preserved.
+	Band     float64
+	commands []DrawCommand
+	textures map[*ebiten.Image]int
+	keys     []drawCommandKey
+	sorted   []DrawCommand
+}
+
+// drawCommandKey orders a command within a banded list.
+type drawCommandKey struct {
+	band    float64
+	texture int
+	index   int
+}
+
+// Submit queues an entity to be drawn by drawer at depth z.
+func (self *DrawCommandList) Submit(z float64, drawer EntityDrawer, e teishoku.Entity) {
+	self.commands = append(self.commands, DrawCommand{Z: z, Drawer: drawer, Entity: e})
+}
+
+// SubmitTextured queues an entity drawn with texture at depth z, allowing
+// it to batch with other commands using the same texture. Pass the atlas
+// page of atlas textures, see TextureManager.Page.
+func (self *DrawCommandList) SubmitTextured(z float64, texture *ebiten.Image, drawer EntityDrawer, e teishoku.Entity) {
+	self.commands = append(self.commands, DrawCommand{Z: z, Texture: texture, Drawer: drawer, Entity: e})
+}
+
+// SubmitFunc queues an arbitrary draw function at depth z.
+func (self *DrawCommandList) SubmitFunc(z float64, fn func(*BatchRenderer)) {
+	self.commands = append(self.commands, DrawCommand{Z: z, Func: fn})
+}
+
+// Len returns the number of queued commands.
+func (self *DrawCommandList) Len() int {
+	return len(self.commands)
+}
+
+// Execute runs all queued commands in Z order and clears the list.
+func (self *DrawCommandList) Execute(w *teishoku.World, rdr *BatchRenderer) {
+	sort.SliceStable(self.commands, func(i, j int) bool {
+		return self.commands[i].Z < self.commands[j].Z
+	})
+	if self.Band > 0 {
+		self.groupByTexture(rdr)
+	}
+	for _, cmd := range self.commands {
+		if cmd.Func != nil {
+			rdr.SetScreenSpace(false)
+			cmd.Func(rdr)
+			continue
+		}
+		entityRenderSpace(w, cmd.Entity, rdr)
+		cmd.Drawer.DrawEntity(w, cmd.Entity, rdr)
+	}
+	rdr.SetScreenSpace(false)
+	self.Reset()
+}
+
+// groupByTexture reorders the Z-sorted commands of each band by texture,
+// keeping the Z order within a texture, and reports the flushes avoided.
+func (self *DrawCommandList) groupByTexture(rdr *BatchRenderer) {
+	if self.textures == nil {
+		self.textures = make(map[*ebiten.Image]int)
+	}
+	clear(self.textures)
+	self.keys = self.keys[:0]
+	for i, cmd := range self.commands {
+		// Commands without a texture share key 0 and draw first in a band.
+		texture := 0
+		if cmd.Texture != nil {
+			texture = self.textures[cmd.Texture]
+			if texture == 0 {
+				texture = len(self.textures) + 1
+				self.textures[cmd.Texture] = texture
+			}
+		}
+		self.keys = append(self.keys, drawCommandKey{band: math.Floor(cmd.Z / self.Band), texture: texture, index: i})
+	}
+	before := self.textureSwitches()
+	slices.SortFunc(self.keys, func(a, b drawCommandKey) int {
+		return cmp.Or(cmp.Compare(a.band, b.band), cmp.Compare(a.texture, b.texture), cmp.Compare(a.index, b.index))
+	})
+	self.sorted = self.sorted[:0]
+	for _, key := range self.keys {
+		self.sorted = append(self.sorted, self.commands[key.index])
+	}
+	clear(self.commands)
+	self.commands, self.sorted = self.sorted, self.commands[:0]
+	rdr.AddFlushSavings(before - self.textureSwitches())
+}
+
+// textureSwitches counts the changes of known texture along the list.
+func (self *DrawCommandList) textureSwitches() int {
+	switches := 0
+	var last *ebiten.Image
+	for _, cmd := range self.commands {
+		if cmd.Texture != nil && cmd.Texture != last {
+			if last != nil {
+				switches++
+			}
+			last = cmd.Texture
+		}
+	}
+	return switches
+}
+
+// Reset clears the list without running it.
+func (self *DrawCommandList) Reset() {
+	clear(self.commands)
+	self.commands = self.commands[:0]
+}
+
+// drawSystemsInOrder runs draw systems in registration order. Every
+// ZDrawSystem submits into the command list, and the sorted list is executed
+// in the slot of the first ZDrawSystem.
+func drawSystemsInOrder(w *teishoku.World, rdr *BatchRenderer, systems []DrawSystem, list *DrawCommandList) {
+	toggles := GetDrawToggles(w)
+	first := -1
+	for i, ds := range systems {
+		if !toggles.Visible(ds) {
+			continue
+		}
+		if zs, ok := ds.(ZDrawSystem); ok {
+			if first < 0 {
+				first = i
+			}
+			zs.SubmitDraws(w, list)
+		}
+	}
+	for i, ds := range systems {
+		if i == first {
+			list.Execute(w, rdr)
+			continue
+		}
+		if _, ok := ds.(ZDrawSystem); ok || !toggles.Visible(ds) {
+			continue
+		}
+		arena, prev := arenaScope(w, ds)
+		ds.Draw(w, rdr)
+		if arena != nil {
+			arena.label = prev
+		}
+	}
+}
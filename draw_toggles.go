@@ -0,0 +1,57 @@
+package katsu2d
+
+import (
+	"github.com/edwinsyarief/katsu2d/logger"
+	"github.com/edwinsyarief/teishoku"
+)
+
+// DrawToggles hides draw systems without removing them, e.g. HUD layers
+// for screenshots. A LayerSytem is a draw system, so hiding it hides the
+// whole layer. The engine shares one with its world and every scene added
+// to it.
+type DrawToggles struct {
+	hidden map[DrawSystem]bool
+}
+
+// NewDrawToggles creates draw toggles with every system visible.
+func NewDrawToggles() *DrawToggles {
+	return &DrawToggles{hidden: make(map[DrawSystem]bool)}
+}
+
+// GetDrawToggles returns the world's draw toggles, or nil if none are set.
+func GetDrawToggles(w *teishoku.World) *DrawToggles {
+	return GetResource[DrawToggles](w)
+}
+
+// SetVisible shows or hides systems. Systems whose type cannot be a map
+// key, such as structs holding slices passed by value, are always visible;
+// pass them by pointer to toggle them.
+func (self *DrawToggles) SetVisible(visible bool, systems ...DrawSystem) {
+	for _, ds := range systems {
+		if !comparableSystem(ds) {
+			logger.GetLogger().Warn("DrawToggles: system %T is not comparable and cannot be hidden", ds)
+			continue
+		}
+		if visible {
+			delete(self.hidden, ds)
+			continue
+		}
+		self.hidden[ds] = true
+	}
+}
+
+// Visible reports whether ds is drawn. Every system is visible on nil
+// toggles.
+func (self *DrawToggles) Visible(ds DrawSystem) bool {
+	return self == nil || len(self.hidden) == 0 || !comparableSystem(ds) || !self.hidden[ds]
+}
+
+// DrawToggles returns the engine's draw toggles.
+func (self *Engine) DrawToggles() *DrawToggles {
+	return self.drawToggles
+}
+
+// SetDrawSystemVisible shows or hides systems in the engine and its scenes.
+func (self *Engine) SetDrawSystemVisible(visible bool, systems ...DrawSystem) {
+	self.drawToggles.SetVisible(visible, systems...)
+}
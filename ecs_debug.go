@@ -0,0 +1,162 @@
+package katsu2d
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"runtime/debug"
+	"sync"
+
+	"github.com/edwinsyarief/teishoku"
+)
+
+// entityDebugChecks enables stale-handle tracking. It defaults to on in
+// builds tagged katsu2d_debug.
+var entityDebugChecks = entityDebugBuild
+
+// SetEntityDebugChecks toggles stale-handle tracking at runtime. While on,
+// TrackEntity and DestroyEntity record call stacks so accesses through stale
+// handles can report where the entity was created and removed. The engine
+// tracks the entities it creates and removes itself, except particles, which
+// come and go in bulk; the camera and constraint targets are checked.
+func SetEntityDebugChecks(enabled bool) {
+	entityDebugChecks = enabled
+}
+
+// EntityDebugChecks reports whether stale-handle tracking is on.
+func EntityDebugChecks() bool {
+	return entityDebugChecks
+}
+
+type entityRecord struct {
+	version uint32
+	created []byte
+	removed []byte
+}
+
+// entityTracker is a world resource holding the recorded stacks per entity
+// ID. A record is replaced when its slot is reused by a tracked entity and
+// dropped once a stale access to it was reported.
+type entityTracker struct {
+	mu      sync.Mutex
+	records map[uint32]*entityRecord
+}
+
+func getEntityTracker(w *teishoku.World) *entityTracker {
+	return EnsureResource(w, func() *entityTracker {
+		return &entityTracker{records: make(map[uint32]*entityRecord)}
+	})
+}
+
+// IsAlive reports whether e still refers to a live entity of w. Handles go
+// stale once their entity is removed, even if the ID is later reused.
+func IsAlive(w *teishoku.World, e teishoku.Entity) bool {
+	return w != nil && w.IsValid(e)
+}
+
+// TrackEntity records where e was created when debug checks are on and
+// returns e, so it can wrap CreateEntity calls. The record of an earlier
+// entity in the same slot is deleted.
+func TrackEntity(w *teishoku.World, e teishoku.Entity) teishoku.Entity {
+	if !entityDebugChecks {
+		return e
+	}
+	t := getEntityTracker(w)
+	t.mu.Lock()
+	t.records[e.ID] = &entityRecord{version: e.Version, created: debug.Stack()}
+	t.mu.Unlock()
+	return e
+}
+
+// DestroyEntity removes e from w. When debug checks are on it also records
+// where the removal happened.
+func DestroyEntity(w *teishoku.World, e teishoku.Entity) {
+	if entityDebugChecks && w.IsValid(e) {
+		t := getEntityTracker(w)
+		t.mu.Lock()
+		rec := t.records[e.ID]
+		if rec == nil || rec.version != e.Version {
+			rec = &entityRecord{version: e.Version}
+			t.records[e.ID] = rec
+		}
+		rec.removed = debug.Stack()
+		t.mu.Unlock()
+	}
+	w.RemoveEntity(e)
+}
+
+// CheckEntity reports whether e is alive. When it is not and debug checks
+// are on, the first stale access of a tracked handle is logged with the
+// recorded stacks and the record is dropped.
+func CheckEntity(w *teishoku.World, e teishoku.Entity) bool {
+	if IsAlive(w, e) {
+		return true
+	}
+	if entityDebugChecks && w != nil {
+		t := getEntityTracker(w)
+		t.mu.Lock()
+		rec := t.records[e.ID]
+		if rec != nil && rec.version == e.Version {
+			delete(t.records, e.ID)
+		} else {
+			rec = nil
+		}
+		t.mu.Unlock()
+		if rec != nil {
+			log.Print(entityRecordReport(e, rec))
+		}
+	}
+	return false
+}
+
+// MustGet returns e's component T. It panics naming the entity and the
+// component when e is stale or lacks T; with debug checks on, the message
+// of a stale access includes where the entity was created and removed.
+func MustGet[T any](w *teishoku.World, e teishoku.Entity) *T {
+	if !IsAlive(w, e) {
+		msg := fmt.Sprintf("katsu2d: MustGet[%s] on stale entity %s", componentName[T](), entityString(e))
+		if entityDebugChecks && w != nil {
+			msg += "\n" + staleEntityReport(w, e)
+		}
+		panic(msg)
+	}
+	c := teishoku.GetComponent[T](w, e)
+	if c == nil {
+		panic(fmt.Sprintf("katsu2d: entity %s has no %s", entityString(e), componentName[T]()))
+	}
+	return c
+}
+
+func componentName[T any]() string {
+	t := reflect.TypeFor[T]()
+	if t.PkgPath() == "" {
+		return t.String()
+	}
+	return t.PkgPath() + "." + t.Name()
+}
+
+func entityString(e teishoku.Entity) string {
+	return fmt.Sprintf("%d:%d", e.ID, e.Version)
+}
+
+func staleEntityReport(w *teishoku.World, e teishoku.Entity) string {
+	t := getEntityTracker(w)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	rec := t.records[e.ID]
+	if rec == nil || rec.version != e.Version {
+		return fmt.Sprintf("stale entity handle %s (no tracking record; create it with TrackEntity)", entityString(e))
+	}
+	return entityRecordReport(e, rec)
+}
+
+func entityRecordReport(e teishoku.Entity, rec *entityRecord) string {
+	msg := fmt.Sprintf("stale entity handle %s", entityString(e))
+	if rec.created != nil {
+		msg += "\ncreated at:\n" + string(rec.created)
+	}
+	if rec.removed != nil {
+		msg += "\nremoved at:\n" + string(rec.removed)
+	}
+	return msg
+}
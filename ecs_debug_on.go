@@ -0,0 +1,6 @@
+//go:build katsu2d_debug
+// +build katsu2d_debug
+
+package katsu2d
+
+const entityDebugBuild = true
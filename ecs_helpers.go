@@ -2,90 +2,60 @@ package katsu2d
 
 import (
 	"image"
+	"math"
 
 	"github.com/edwinsyarief/teishoku"
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 )
 
 func updateHiResDisplayResource(w *teishoku.World, width, height int) {
-	if ok, _ := teishoku.HasResource[HiResDisplaySize](w.Resources()); !ok {
-		w.Resources().Add(&HiResDisplaySize{
-			Width:  width,
-			Height: height,
-		})
-	} else {
-		layout, _ := teishoku.GetResource[HiResDisplaySize](w.Resources())
-		layout.Width = width
-		layout.Height = height
-	}
+	layout := EnsureResource(w, func() *HiResDisplaySize { return &HiResDisplaySize{} })
+	layout.Width = width
+	layout.Height = height
 }
 
 func initializeAssetManagers(w *teishoku.World,
 	tm *TextureManager, fm *FontManager, am *AudioManager, shm *ShaderManager, scm *SceneManager) {
-	if ok, _ := teishoku.HasResource[TextureManager](w.Resources()); !ok {
-		w.Resources().Add(tm)
-	}
-	if ok, _ := teishoku.HasResource[FontManager](w.Resources()); !ok {
-		w.Resources().Add(fm)
-	}
-	if ok, _ := teishoku.HasResource[AudioManager](w.Resources()); !ok {
-		w.Resources().Add(am)
-	}
-	if ok, _ := teishoku.HasResource[ShaderManager](w.Resources()); !ok {
-		w.Resources().Add(shm)
-	}
-	if ok, _ := teishoku.HasResource[SceneManager](w.Resources()); !ok {
-		w.Resources().Add(scm)
-	}
+	EnsureResource(w, func() *TextureManager { return tm })
+	EnsureResource(w, func() *FontManager { return fm })
+	EnsureResource(w, func() *AudioManager { return am })
+	EnsureResource(w, func() *ShaderManager { return shm })
+	EnsureResource(w, func() *SceneManager { return scm })
 }
 
 func GetHiResDisplayInfo(w *teishoku.World) *HiResDisplaySize {
-	res, _ := teishoku.GetResource[HiResDisplaySize](w.Resources())
-	return res
+	return GetResource[HiResDisplaySize](w)
 }
 
 func GetTextureManager(w *teishoku.World) *TextureManager {
-	res, _ := teishoku.GetResource[TextureManager](w.Resources())
-	return res
+	return GetResource[TextureManager](w)
 }
 
 func GetFontManager(w *teishoku.World) *FontManager {
-	res, _ := teishoku.GetResource[FontManager](w.Resources())
-	return res
+	return GetResource[FontManager](w)
 }
 
 func GetAudioManager(w *teishoku.World) *AudioManager {
-	res, _ := teishoku.GetResource[AudioManager](w.Resources())
-	return res
+	return GetResource[AudioManager](w)
 }
 
 func GetShaderManager(w *teishoku.World) *ShaderManager {
-	res, _ := teishoku.GetResource[ShaderManager](w.Resources())
-	return res
+	return GetResource[ShaderManager](w)
 }
 
 func GetSceneManager(w *teishoku.World) *SceneManager {
-	res, _ := teishoku.GetResource[SceneManager](w.Resources())
-	return res
+	return GetResource[SceneManager](w)
 }
 
 func getEventBus(w *teishoku.World) *teishoku.EventBus {
-	if ok, _ := teishoku.HasResource[teishoku.EventBus](w.Resources()); !ok {
-		w.Resources().Add(&teishoku.EventBus{})
-	}
-	eb, _ := teishoku.GetResource[teishoku.EventBus](w.Resources())
-	return eb
-}
-
-func Subscribe[T any](w *teishoku.World, fn func(T)) {
-	eb := getEventBus(w)
-	teishoku.Subscribe(eb, fn)
+	return EnsureResource(w, func() *teishoku.EventBus { return &teishoku.EventBus{} })
 }
 
-func Publish[T any](w *teishoku.World, o T) {
-	eb := getEventBus(w)
-	teishoku.Publish(eb, o)
+// publishTo publishes o when w is set, for resources usable without a world.
+func publishTo[T any](w *teishoku.World, o T) {
+	if w != nil {
+		Publish(w, o)
+	}
 }
 
 func (self *Transform) SetFromComponent(comp *TransformComponent) {
@@ -94,6 +64,8 @@ func (self *Transform) SetFromComponent(comp *TransformComponent) {
 	self.SetOffset(Vector(comp.Offset))
 	self.SetOrigin(Vector(comp.Origin))
 	self.SetScale(Vector(comp.Scale))
+	self.SetSkew(Vector(comp.Skew))
+	self.SetPivot(Vector(comp.Pivot))
 	self.SetRotation(comp.Rotation)
 }
 
@@ -171,6 +143,14 @@ func Bind(input *InputComponent, action Action, primary InputCode, modifiers ...
 	input.Bindings[action] = append(input.Bindings[action], binding)
 }
 
+// BindAxis adds an axis binding for an action.
+func BindAxis(input *InputComponent, action Action, binding AxisBinding) {
+	if input.Axes == nil {
+		input.Axes = make(map[Action][]AxisBinding)
+	}
+	input.Axes[action] = append(input.Axes[action], binding)
+}
+
 // BatchBind replaces all existing bindings with a new set.
 // This is useful for loading a saved or preset control scheme.
 func BatchBind(input *InputComponent, bindings map[Action][]KeyConfig) {
@@ -200,6 +180,8 @@ func NewKeyConfig(key any, modifiers ...any) KeyConfig {
 // toInputCode converts a generic Ebitengine input type to a standardized InputCode.
 func toInputCode(v any) InputCode {
 	switch code := v.(type) {
+	case InputCode:
+		return code
 	case ebiten.Key:
 		return InputCode{Type: InputTypeKeyboard, Code: int(code)}
 	case ebiten.MouseButton:
@@ -210,6 +192,14 @@ func toInputCode(v any) InputCode {
 		return InputCode{Type: InputTypeGamepad, Code: int(code)}
 	case ebiten.StandardGamepadAxis:
 		return InputCode{Type: InputTypeAnalog, Code: int(code)}
+	case MouseAxis:
+		return InputCode{Type: InputTypeMouseMotion, Code: int(code)}
+	case TouchGesture:
+		return InputCode{Type: InputTypeTouch, Code: int(code)}
+	case VirtualButton:
+		return InputCode{Type: InputTypeVirtualButton, Code: int(code)}
+	case VirtualStickAxis:
+		return InputCode{Type: InputTypeVirtualAxis, Code: int(code)}
 	default:
 		// Panicking here is okay for development, but in a production game
 		// this should be handled gracefully with an error log.
@@ -227,34 +217,89 @@ func isPressed(id int, code InputCode) bool {
 	case InputTypeGamepad:
 		return ebiten.IsGamepadButtonPressed(ebiten.GamepadID(id), ebiten.GamepadButton(code.Code)) ||
 			ebiten.IsStandardGamepadButtonPressed(ebiten.GamepadID(id), ebiten.StandardGamepadButton(code.Code))
+	case InputTypeTouch:
+		pressed, _, _ := touchInput.gesture(TouchGesture(code.Code))
+		return pressed
+	case InputTypeVirtualButton:
+		return virtualInput.buttons[VirtualButton(code.Code)]
 	}
 	return false
 }
 
-// isJustPressed checks if the given InputCode was pressed in the current frame.
+// isJustPressed checks if the given InputCode was pressed since the previous
+// step.
 func isJustPressed(id int, code InputCode) bool {
 	switch code.Type {
 	case InputTypeMouse:
-		return inpututil.IsMouseButtonJustPressed(ebiten.MouseButton(code.Code))
+		return inputEdges.pressed[inputEdge{Type: InputTypeMouse, Code: code.Code}]
 	case InputTypeKeyboard:
-		return inpututil.IsKeyJustPressed(ebiten.Key(code.Code))
+		return inputEdges.pressed[inputEdge{Type: InputTypeKeyboard, Code: code.Code}]
 	case InputTypeGamepad:
-		return inpututil.IsGamepadButtonJustPressed(ebiten.GamepadID(id), ebiten.GamepadButton(code.Code)) ||
-			inpututil.IsStandardGamepadButtonJustPressed(ebiten.GamepadID(id), ebiten.StandardGamepadButton(code.Code))
+		return inputEdges.pressed[inputEdge{Type: InputTypeGamepad, ID: id, Code: code.Code}] ||
+			inputEdges.pressed[inputEdge{Type: inputEdgeStandard, ID: id, Code: code.Code}]
+	case InputTypeTouch:
+		_, justPressed, _ := touchInput.gesture(TouchGesture(code.Code))
+		return justPressed
+	case InputTypeVirtualButton:
+		button := VirtualButton(code.Code)
+		return virtualInput.buttons[button] && !virtualInput.prevButtons[button]
 	}
 	return false
 }
 
-// isJustReleased checks if the given InputCode was released in the current frame.
+// isJustReleased checks if the given InputCode was released since the
+// previous step.
 func isJustReleased(id int, code InputCode) bool {
 	switch code.Type {
 	case InputTypeMouse:
-		return inpututil.IsMouseButtonJustReleased(ebiten.MouseButton(code.Code))
+		return inputEdges.released[inputEdge{Type: InputTypeMouse, Code: code.Code}]
 	case InputTypeKeyboard:
-		return inpututil.IsKeyJustReleased(ebiten.Key(code.Code))
+		return inputEdges.released[inputEdge{Type: InputTypeKeyboard, Code: code.Code}]
 	case InputTypeGamepad:
-		return inpututil.IsGamepadButtonJustReleased(ebiten.GamepadID(id), ebiten.GamepadButton(code.Code)) ||
-			inpututil.IsStandardGamepadButtonJustReleased(ebiten.GamepadID(id), ebiten.StandardGamepadButton(code.Code))
+		return inputEdges.released[inputEdge{Type: InputTypeGamepad, ID: id, Code: code.Code}] ||
+			inputEdges.released[inputEdge{Type: inputEdgeStandard, ID: id, Code: code.Code}]
+	case InputTypeTouch:
+		_, _, justReleased := touchInput.gesture(TouchGesture(code.Code))
+		return justReleased
+	case InputTypeVirtualButton:
+		button := VirtualButton(code.Code)
+		return !virtualInput.buttons[button] && virtualInput.prevButtons[button]
 	}
 	return false
 }
+
+// inputValue returns the analog value of code: -1 to 1 for stick axes, 0 to
+// 1 for triggers and buttons, and pixels for mouse motion.
+func inputValue(id int, code InputCode, mouseDelta Vector) float64 {
+	switch code.Type {
+	case InputTypeAnalog:
+		return ebiten.StandardGamepadAxisValue(ebiten.GamepadID(id), ebiten.StandardGamepadAxis(code.Code))
+	case InputTypeVirtualAxis:
+		return virtualInput.axes[VirtualStickAxis(code.Code)]
+	case InputTypeMouseMotion:
+		if MouseAxis(code.Code) == MouseAxisY {
+			return mouseDelta.Y
+		}
+		return mouseDelta.X
+	case InputTypeGamepad:
+		if ebiten.IsStandardGamepadLayoutAvailable(ebiten.GamepadID(id)) {
+			return ebiten.StandardGamepadButtonValue(ebiten.GamepadID(id), ebiten.StandardGamepadButton(code.Code))
+		}
+	}
+	if isPressed(id, code) {
+		return 1
+	}
+	return 0
+}
+
+// applyDeadZone zeroes |v| below deadZone and rescales the rest to 0-1.
+func applyDeadZone(v, deadZone float64) float64 {
+	if deadZone <= 0 {
+		return v
+	}
+	a := math.Abs(v)
+	if a <= deadZone {
+		return 0
+	}
+	return math.Copysign(min((a-deadZone)/(1-deadZone), 1), v)
+}
@@ -0,0 +1,113 @@
+package katsu2d
+
+import (
+	"math"
+
+	"github.com/edwinsyarief/teishoku"
+)
+
+// ElevationMap is a grid of cliff levels covering the world, used to keep
+// entities on their level and to shade cliff edges.
+type ElevationMap struct {
+	Origin        Vector
+	CellSize      float64
+	Width, Height int
+	Levels        []int
+	revision      int
+}
+
+// NewElevationMap creates a map of width x height cells at level 0.
+func NewElevationMap(width, height int, cellSize float64) *ElevationMap {
+	return &ElevationMap{
+		CellSize: cellSize,
+		Width:    width,
+		Height:   height,
+		Levels:   make([]int, width*height),
+	}
+}
+
+// SetElevationMap installs m as the world's elevation map.
+func SetElevationMap(w *teishoku.World, m *ElevationMap) {
+	if current := GetElevationMap(w); current != nil {
+		revision := current.revision
+		*current = *m
+		current.revision = revision + 1
+		return
+	}
+	SetResource(w, m)
+}
+
+// GetElevationMap returns the world's elevation map, or nil if none is set.
+func GetElevationMap(w *teishoku.World) *ElevationMap {
+	return GetResource[ElevationMap](w)
+}
+
+// Cell returns the cell containing pos.
+func (self *ElevationMap) Cell(pos Vector) (int, int) {
+	return int(math.Floor((pos.X - self.Origin.X) / self.CellSize)),
+		int(math.Floor((pos.Y - self.Origin.Y) / self.CellSize))
+}
+
+// InBounds reports whether the cell lies inside the map.
+func (self *ElevationMap) InBounds(x, y int) bool {
+	return x >= 0 && y >= 0 && x < self.Width && y < self.Height
+}
+
+// At returns the level of a cell; cells outside the map are level 0.
+func (self *ElevationMap) At(x, y int) int {
+	if !self.InBounds(x, y) {
+		return 0
+	}
+	return self.Levels[y*self.Width+x]
+}
+
+// Set changes the level of a cell.
+func (self *ElevationMap) Set(x, y, level int) {
+	if self.InBounds(x, y) && self.Levels[y*self.Width+x] != level {
+		self.Levels[y*self.Width+x] = level
+		self.revision++
+	}
+}
+
+// Revision changes whenever a cell is modified through Set or MarkChanged.
+func (self *ElevationMap) Revision() int {
+	return self.revision
+}
+
+// MarkChanged signals that Levels was edited directly.
+func (self *ElevationMap) MarkChanged() {
+	self.revision++
+}
+
+// LevelAt returns the level under a world position.
+func (self *ElevationMap) LevelAt(pos Vector) int {
+	return self.At(self.Cell(pos))
+}
+
+// IsWalkable reports whether an entity on level can stand at pos. The
+// ElevationSystem uses it to keep entities on their level.
+func (self *ElevationMap) IsWalkable(pos Vector, level int) bool {
+	return self.LevelAt(pos) == level
+}
+
+// ElevationOf returns the level of e, or 0 if it has no ElevationComponent.
+func ElevationOf(w *teishoku.World, e teishoku.Entity) int {
+	if el := teishoku.GetComponent[ElevationComponent](w, e); el != nil {
+		return el.Level
+	}
+	return 0
+}
+
+// SameElevation reports whether a and b are on the same level and can
+// therefore interact or collide.
+func SameElevation(w *teishoku.World, a, b teishoku.Entity) bool {
+	return ElevationOf(w, a) == ElevationOf(w, b)
+}
+
+// elevationLift returns how far e's sprite is drawn above its position.
+func elevationLift(w *teishoku.World, e teishoku.Entity) float64 {
+	if el := teishoku.GetComponent[ElevationComponent](w, e); el != nil {
+		return el.Height
+	}
+	return 0
+}
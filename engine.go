@@ -2,11 +2,14 @@ package katsu2d
 
 import (
 	"embed"
+	"image"
 	"image/color"
 	"math"
+	"time"
 
 	_ "github.com/silbinarywolf/preferdiscretegpu"
 
+	"github.com/edwinsyarief/katsu2d/jobs"
 	"github.com/edwinsyarief/teishoku"
 	"github.com/hajimehoshi/ebiten/v2"
 )
@@ -23,7 +26,7 @@ type Engine struct {
 	renderer    *BatchRenderer
 	windowTitle string
 	// Engine-level systems
-	updateSystems         []UpdateSystem
+	updateSystems         systemGroups
 	backgroundDrawSystems []DrawSystem
 	overlayDrawSystems    []DrawSystem
 	// Game settings
@@ -43,6 +46,46 @@ type Engine struct {
 	useAtlas bool
 	// Layout properties
 	layoutHasChanged bool
+	// Cameras of the scene being drawn
+	cameras []*Camera
+	// Frame timing for the presentation group
+	lastFrameTime time.Time
+	// Post-processing
+	colorGrading *ColorGrading
+	postProcess  *PostProcess
+	postBuffer   *ebiten.Image
+
+	textureBudget     int64
+	overTextureBudget bool
+	sceneBuffer       *ebiten.Image
+	renderScale       *RenderScale
+	scaledBuffer      *ebiten.Image
+	// Audio auto-pause
+	pauseAudioOnFocusLoss bool
+	pauseAudioWithEngine  bool
+	audioAutoPaused       bool
+	// audioAutoPausedIDs are the sources the engine paused itself, so
+	// sources the game paused stay paused when focus returns.
+	audioAutoPausedIDs []PlaybackID
+	// Save slots
+	saveSlots *SaveSlotManager
+	autoSave  *AutoSave
+	lastScene *Scene
+	// Start, exit and focus hooks
+	lifecycle lifecycle
+	// Pause and time scale per TimeGroup
+	timeControl *TimeControl
+	// Hidden draw systems and photo mode
+	drawToggles *DrawToggles
+	frameStats  *FrameStats
+	photo       *PhotoMode
+	// Fixed timestep updates
+	fixed fixedTimestep
+	// Startup content validation failure in strict mode
+	contentError error
+	// Panic recovery
+	crashReporter *CrashReporter
+	crash         *CrashError
 }
 
 // Option is a functional option for configuring the engine.
@@ -105,6 +148,58 @@ func WithClearColor(col color.Color) Option {
 	}
 }
 
+// WithColorGrading grades the background and scene with a LUT before
+// overlays are drawn.
+func WithColorGrading(grading *ColorGrading) Option {
+	return func(e *Engine) {
+		e.colorGrading = grading
+	}
+}
+
+// WithPostProcess runs a shader chain over the background and scene, after
+// color grading and before overlays are drawn.
+func WithPostProcess(pp *PostProcess) Option {
+	return func(e *Engine) {
+		e.postProcess = pp
+	}
+}
+
+// WithRenderScale renders the scene at a fraction of the output resolution
+// and upscales it.
+func WithRenderScale(scale *RenderScale) Option {
+	return func(e *Engine) {
+		e.renderScale = scale
+	}
+}
+
+// WithAudioAutoPause selects when the engine pauses and later resumes all
+// audio: when the window loses focus (only observable while the game runs
+// unfocused, see ebiten.SetRunnableOnUnfocused) and when the time scale is
+// set to zero. Only the sources playing at that point are resumed, so audio
+// the game paused itself stays paused. Pausing on focus loss is enabled by
+// default.
+func WithAudioAutoPause(onFocusLoss, onEnginePause bool) Option {
+	return func(e *Engine) {
+		e.pauseAudioOnFocusLoss = onFocusLoss
+		e.pauseAudioWithEngine = onEnginePause
+	}
+}
+
+// WithSaveSlots attaches a SaveSlotManager storing saves in dir. The engine
+// tracks play time and captures a thumbnail of the game before overlays.
+func WithSaveSlots(dir string) Option {
+	return func(e *Engine) {
+		e.saveSlots = NewSaveSlotManager(dir)
+	}
+}
+
+// WithAutoSave runs an auto-save policy against the slots of WithSaveSlots.
+func WithAutoSave(auto *AutoSave) Option {
+	return func(e *Engine) {
+		e.autoSave = auto
+	}
+}
+
 // WithTextureAtlas enables or disables the texture atlas system.
 func WithTextureAtlas(enabled bool) Option {
 	return func(e *Engine) {
@@ -139,7 +234,14 @@ func WithOverlaySystem(sys any) Option {
 // WithUpdateSystem adds an UpdateSystem that update before the scene update.
 func WithUpdateSystem(sys UpdateSystem) Option {
 	return func(e *Engine) {
-		e.updateSystems = append(e.updateSystems, sys)
+		e.updateSystems.add(SimulationGroup, sys)
+	}
+}
+
+// WithUpdateSystemInGroup adds an UpdateSystem to the given system group.
+func WithUpdateSystemInGroup(group SystemGroup, sys UpdateSystem) Option {
+	return func(e *Engine) {
+		e.updateSystems.add(group, sys)
 	}
 }
 
@@ -149,15 +251,17 @@ func NewEngine(opts ...Option) *Engine {
 }
 func NewEngineWithInitialCapacity(cap int, opts ...Option) *Engine {
 	e := &Engine{
-		world:    teishoku.NewWorld(cap),
-		fm:       NewFontManager(),
-		am:       NewAudioManager(44100),
-		renderer: NewBatchRenderer(),
-		// We can add global update systems here, such as input handlers.
-		updateSystems:         make([]UpdateSystem, 0),
+		world:                 teishoku.NewWorld(cap),
+		fm:                    NewFontManager(),
+		am:                    NewAudioManager(44100),
+		renderer:              NewBatchRenderer(),
 		backgroundDrawSystems: make([]DrawSystem, 0),
 		overlayDrawSystems:    make([]DrawSystem, 0),
 		timeScale:             1.0,
+		timeControl:           NewTimeControl(),
+		drawToggles:           NewDrawToggles(),
+		frameStats:            NewFrameStats(),
+		pauseAudioOnFocusLoss: true,
 		windowWidth:           800,
 		windowHeight:          600,
 		windowTitle:           "Game",
@@ -177,6 +281,11 @@ func NewEngineWithInitialCapacity(cap int, opts ...Option) *Engine {
 		tmOpts = append(tmOpts, WithAtlasSize(e.atlasWidth, e.atlasHeight))
 	}
 	e.scm = NewSceneManager(e)
+	SetResource(e.World(), e.timeControl)
+	SetResource(e.World(), e.drawToggles)
+	SetResource(e.World(), e.frameStats)
+	e.renderer.arena = GetFrameArena(e.World())
+	e.photo = newPhotoMode(e)
 	e.tm = NewTextureManager(tmOpts...)
 
 	initializeAssetManagers(e.World(),
@@ -214,6 +323,12 @@ func (self *Engine) AudioManager() *AudioManager {
 	return self.am
 }
 
+// SaveSlots returns the engine's save slot manager, or nil if WithSaveSlots
+// was not used.
+func (self *Engine) SaveSlots() *SaveSlotManager {
+	return self.saveSlots
+}
+
 func (self *Engine) ShaderManager() *ShaderManager {
 	return self.shm
 }
@@ -229,8 +344,23 @@ func (self *Engine) FontManager() *FontManager {
 }
 
 // SwitchScene switches to a named scene.
-func (self *Engine) SwitchScene(name string) {
-	self.scm.SwitchTo(name)
+func (self *Engine) SwitchScene(name string, transition ...SceneTransition) {
+	self.scm.SwitchTo(name, transition...)
+}
+
+// PushScene enters a named scene on top of the current one.
+func (self *Engine) PushScene(name string, transition ...SceneTransition) {
+	self.scm.Push(name, transition...)
+}
+
+// PopScene returns to the scene below the current one.
+func (self *Engine) PopScene(transition ...SceneTransition) {
+	self.scm.Pop(transition...)
+}
+
+// ReplaceScene replaces the current scene with a named scene.
+func (self *Engine) ReplaceScene(name string, transition ...SceneTransition) {
+	self.scm.Replace(name, transition...)
 }
 
 // AddScene adds a scene to the scene manager by name.
@@ -238,15 +368,20 @@ func (self *Engine) AddScene(name string, scene *Scene) {
 	self.scm.AddScene(name, scene)
 }
 
-// AddUpdateSystem adds an update system to the engine's global update systems.
+// AddUpdateSystem adds an update system to the engine's global simulation group.
 func (self *Engine) AddUpdateSystem(sys UpdateSystem) {
-	self.updateSystems = append(self.updateSystems, sys)
+	self.updateSystems.add(SimulationGroup, sys)
+}
+
+// AddUpdateSystemToGroup adds an update system to the given engine system group.
+func (self *Engine) AddUpdateSystemToGroup(group SystemGroup, sys UpdateSystem) {
+	self.updateSystems.add(group, sys)
 }
 
 // AddBackgroundSystem adds a system that updates and/or draws before the scene.
 func (self *Engine) AddBackgroundSystem(sys any) {
 	if us, ok := sys.(UpdateSystem); ok {
-		self.updateSystems = append(self.updateSystems, us)
+		self.updateSystems.add(SimulationGroup, us)
 	}
 	if ds, ok := sys.(DrawSystem); ok {
 		self.backgroundDrawSystems = append(self.backgroundDrawSystems, ds)
@@ -256,7 +391,7 @@ func (self *Engine) AddBackgroundSystem(sys any) {
 // AddOverlaySystem adds a system that updates and/or draws after the scene.
 func (self *Engine) AddOverlaySystem(sys any) {
 	if us, ok := sys.(UpdateSystem); ok {
-		self.updateSystems = append(self.updateSystems, us)
+		self.updateSystems.add(SimulationGroup, us)
 	}
 	if ds, ok := sys.(DrawSystem); ok {
 		self.overlayDrawSystems = append(self.overlayDrawSystems, ds)
@@ -268,9 +403,92 @@ func (self *Engine) SetTimeScale(ts float64) {
 	self.timeScale = ts
 }
 
+// SetColorGrading replaces the scene color grading; nil disables it.
+func (self *Engine) SetColorGrading(grading *ColorGrading) {
+	self.colorGrading = grading
+}
+
+// SetPostProcess replaces the post-processing chain; nil disables it.
+func (self *Engine) SetPostProcess(pp *PostProcess) {
+	self.postProcess = pp
+}
+
+// PostProcess returns the post-processing chain, if any.
+func (self *Engine) PostProcess() *PostProcess {
+	return self.postProcess
+}
+
+// SetRenderScale replaces the scene render scale; nil renders at full resolution.
+func (self *Engine) SetRenderScale(scale *RenderScale) {
+	self.renderScale = scale
+}
+
+// RenderScale returns the active render scale settings, if any.
+func (self *Engine) RenderScale() *RenderScale {
+	return self.renderScale
+}
+
+// ColorGrading returns the active color grading, if any.
+func (self *Engine) ColorGrading() *ColorGrading {
+	return self.colorGrading
+}
+
 // Update implements ebiten.Game.Update.
-func (self *Engine) Update() error {
-	dt := (1.0 / 60.0) * self.timeScale
+func (self *Engine) Update() (err error) {
+	if self.crashReporter == nil {
+		return self.update()
+	}
+	if self.crash != nil {
+		return self.updateCrashed()
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			self.recoverCrash(r, nil)
+			err = self.updateCrashed()
+		}
+	}()
+	return self.update()
+}
+
+func (self *Engine) update() error {
+	start := time.Now()
+	defer func() { self.frameStats.addUpdate(time.Since(start)) }()
+	if self.updateLifecycle() {
+		if self.contentError != nil {
+			return self.contentError
+		}
+		return ebiten.Termination
+	}
+	// Apply results of background jobs that finished since the last tick.
+	jobs.Sync()
+	sampleInputEdges()
+	if self.fixed.step == 0 {
+		self.step(1.0 / 60.0)
+		clearInputEdges()
+	} else {
+		// Every drawn scene is snapshotted, so suspended scenes under a
+		// transparent one render where they stopped.
+		for range self.fixedSteps() {
+			self.snapshotTransforms(self.World())
+			for _, scene := range self.scm.visibleScenes() {
+				self.snapshotTransforms(scene.World())
+			}
+			self.step(self.fixed.step)
+			clearInputEdges()
+		}
+		self.publishAlpha(self.World())
+		for _, scene := range self.scm.visibleScenes() {
+			self.publishAlpha(scene.World())
+		}
+	}
+	self.updateAudioPause()
+	return nil
+}
+
+// step advances the simulation by realDt seconds, scaled by the time scale.
+func (self *Engine) step(realDt float64) {
+	dt := realDt * self.timeScale
+	self.scm.updateTransition(realDt)
 	if self.layoutHasChanged {
 		updateHiResDisplayResource(self.World(), self.hiResWidth, self.hiResHeight)
 		Publish(self.World(), EngineLayoutChangedEvent{
@@ -278,10 +496,8 @@ func (self *Engine) Update() error {
 			Height: self.hiResHeight,
 		})
 	}
-	// Update the engine's global systems first.
-	for _, us := range self.updateSystems {
-		us.Update(self.World(), dt)
-	}
+	// Update the engine's global simulation systems first.
+	self.updateSystems.update(SimulationGroup, self.World(), dt)
 
 	// Then, update the active scene's systems.
 	if self.scm.current != nil {
@@ -295,40 +511,279 @@ func (self *Engine) Update() error {
 			self.scm.current.OnUpdate(dt)
 		}
 	}
+	// Late update systems observe the final state of the simulation step.
+	self.updateSystems.update(LateUpdateGroup, self.World(), dt)
+	if self.scm.current != nil {
+		self.scm.current.LateUpdate(dt)
+	}
 	// Finally, update the audio manager.
 	self.am.Update(dt)
-	return nil
+	if self.saveSlots != nil && self.timeScale > 0 {
+		self.saveSlots.AddPlayTime(realDt)
+	}
+	self.updateAutoSave(realDt)
+}
+
+// updateAudioPause pauses the playing audio, or resumes what it paused, as
+// the focus and time scale change, according to WithAudioAutoPause.
+func (self *Engine) updateAudioPause() {
+	pause := (self.pauseAudioOnFocusLoss && !ebiten.IsFocused()) ||
+		(self.pauseAudioWithEngine && self.timeScale == 0)
+	if pause == self.audioAutoPaused {
+		return
+	}
+	self.audioAutoPaused = pause
+	if pause {
+		self.audioAutoPausedIDs = self.am.pausePlaying(self.audioAutoPausedIDs[:0])
+	} else {
+		self.am.resumePaused(self.audioAutoPausedIDs)
+		self.audioAutoPausedIDs = self.audioAutoPausedIDs[:0]
+	}
+}
+
+// updateAutoSave requests saves on scene changes and drives the auto-save
+// timers on real time.
+func (self *Engine) updateAutoSave(dt float64) {
+	if self.autoSave == nil {
+		return
+	}
+	if self.scm.current != self.lastScene && !self.scm.IsTransitioning() {
+		if self.lastScene != nil && self.autoSave.OnSceneChange {
+			self.autoSave.Request(AutoSaveSceneChange)
+		}
+		self.lastScene = self.scm.current
+	}
+	self.autoSave.slots = self.saveSlots
+	self.autoSave.world = self.World()
+	self.autoSave.update(dt)
 }
 
 // Draw implements ebiten.Game.Draw. This method orchestrates the entire rendering pipeline.
 func (self *Engine) Draw(screen *ebiten.Image) {
+	if self.crashReporter == nil {
+		self.draw(screen)
+		return
+	}
+	if self.crash != nil {
+		self.drawCrashed(screen)
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			self.recoverCrash(r, screen)
+		}
+	}()
+	self.draw(screen)
+}
+
+func (self *Engine) draw(screen *ebiten.Image) {
+	start := time.Now()
+	defer func() { self.frameStats.endFrame(time.Since(start)) }()
+	// The scene renders into an offscreen buffer when it is post-processed.
+	target := screen
+	if self.colorGrading != nil || self.postProcess.Active() {
+		target = self.offscreenBuffer(screen)
+	}
 	if self.clearColor != nil || !self.clearScreenEachFrame {
 		fillColor := self.clearColor
 		if fillColor == nil {
 			fillColor = color.Black
 		}
-		screen.Fill(fillColor)
+		target.Fill(fillColor)
 	}
-	self.renderer.Begin(screen)
-	// Draw the engine's background systems (bottom-most layer).
-	for _, ds := range self.backgroundDrawSystems {
-		ds.Draw(self.World(), self.renderer)
+	// Run the presentation group once per rendered frame.
+	realDt := self.frameDelta()
+	frameDt := realDt * self.timeScale
+	self.updateSystems.update(PresentationGroup, self.World(), frameDt)
+	if self.scm.current != nil {
+		self.scm.current.Present(frameDt)
 	}
+	self.renderer.Begin(target)
+	// Draw the engine's background systems (bottom-most layer).
+	self.drawSystems(self.backgroundDrawSystems)
 	// Draw the active scene's content (the main game world).
 	if self.scm.current != nil {
 		if self.scm.current.OnBeforeDraw != nil {
-			self.scm.current.OnBeforeDraw(screen)
+			self.scm.current.OnBeforeDraw(target)
 		}
-		self.scm.current.Draw(self.scm.current.World(), self.renderer)
+		self.drawScene(target, realDt)
 		if self.scm.current.OnAfterDraw != nil {
-			self.scm.current.OnAfterDraw(screen)
+			self.scm.current.OnAfterDraw(target)
 		}
 	}
+	if target != screen {
+		self.renderer.Flush()
+		self.applyPostProcessing(screen, target, frameDt)
+		self.renderer.Begin(screen)
+	}
+	// Save thumbnails show the game without HUD and overlays.
+	if self.saveSlots != nil && self.saveSlots.ThumbnailPending() {
+		self.renderer.Flush()
+		self.saveSlots.CaptureThumbnail(screen)
+	}
 	// Draw the engine's overlay systems (UI, HUD, FPS counter - top-most layer).
-	for _, ds := range self.overlayDrawSystems {
-		ds.Draw(self.World(), self.renderer)
+	self.drawSystems(self.overlayDrawSystems)
+	// Scene transitions cover everything.
+	self.scm.drawTransition(self.renderer)
+	if self.autoSave != nil {
+		self.autoSave.draw(self.World(), self.renderer)
+	}
+	self.photo.capturePending(screen)
+	self.renderer.EndFrame()
+	self.checkTextureBudget()
+	// Temporary frame allocations end with the frame.
+	resetFrameArena(self.World())
+	for _, scene := range self.scm.visibleScenes() {
+		resetFrameArena(scene.World())
+	}
+}
+
+// drawSystems draws the visible systems of an engine layer.
+func (self *Engine) drawSystems(systems []DrawSystem) {
+	for _, ds := range systems {
+		if self.drawToggles.Visible(ds) {
+			ds.Draw(self.World(), self.renderer)
+		}
 	}
-	self.renderer.Flush()
+}
+
+// RenderStats returns the renderer counters of the last drawn frame.
+func (self *Engine) RenderStats() RenderStats {
+	return self.renderer.Stats()
+}
+
+// drawScene draws the visible scenes through their cameras, at a reduced
+// resolution when a render scale is active.
+func (self *Engine) drawScene(target *ebiten.Image, realDt float64) {
+	size := target.Bounds().Size()
+	scale := 1.0
+	if self.renderScale != nil {
+		self.renderScale.Update(realDt)
+		scale = self.renderScale.Current()
+	}
+
+	sceneTarget := target
+	if scale < 1 {
+		w, h := max(int(float64(size.X)*scale), 1), max(int(float64(size.Y)*scale), 1)
+		var created bool
+		if self.scaledBuffer, created = resizeRenderTarget(self.scaledBuffer, w, h, "render scale"); !created {
+			self.scaledBuffer.Clear()
+		}
+		sceneTarget = self.scaledBuffer
+		self.renderer.Flush()
+		self.renderer.Begin(sceneTarget)
+	}
+
+	// Scenes below a transparent top scene are drawn first. The cameras on
+	// each scene world pan and zoom its content, each into its viewport.
+	targetScale := V(float64(sceneTarget.Bounds().Dx())/float64(size.X), float64(sceneTarget.Bounds().Dy())/float64(size.Y))
+	for _, scene := range self.scm.visibleScenes() {
+		world := scene.World()
+		self.cameras = Cameras(world, self.cameras[:0])
+		if len(self.cameras) == 0 {
+			if sceneTarget != target {
+				var view Matrix
+				view.Scale(targetScale.X, targetScale.Y)
+				self.renderer.SetView(view)
+			}
+			scene.Draw(world, self.renderer)
+			self.renderer.ResetView()
+			continue
+		}
+		for _, cam := range self.cameras {
+			self.drawCamera(scene, cam, sceneTarget, size.X, size.Y, targetScale)
+		}
+		clear(self.cameras)
+	}
+
+	if sceneTarget != target {
+		self.renderer.Flush()
+		op := &ebiten.DrawImageOptions{Filter: ebiten.FilterLinear}
+		op.GeoM.Scale(float64(size.X)/float64(sceneTarget.Bounds().Dx()), float64(size.Y)/float64(sceneTarget.Bounds().Dy()))
+		target.DrawImage(sceneTarget, op)
+		self.renderer.Begin(target)
+	}
+}
+
+// drawCamera draws scene through cam into its viewport of target, which
+// is scale times the width x height screen.
+func (self *Engine) drawCamera(scene *Scene, cam *Camera, target *ebiten.Image, width, height int, scale Vector) {
+	vp := cam.ViewportRect(width, height)
+	if vp.Empty() {
+		return
+	}
+	cam.Width, cam.Height = vp.Dx(), vp.Dy()
+	view := cam.Matrix()
+	view.Translate(float64(vp.Min.X), float64(vp.Min.Y))
+	view.Scale(scale.X, scale.Y)
+	full := vp == image.Rect(0, 0, width, height)
+	if !full {
+		// Clip to the viewport by drawing into a sub image.
+		self.renderer.Flush()
+		sub := image.Rect(
+			int(float64(vp.Min.X)*scale.X), int(float64(vp.Min.Y)*scale.Y),
+			int(float64(vp.Max.X)*scale.X), int(float64(vp.Max.Y)*scale.Y),
+		).Add(target.Bounds().Min)
+		self.renderer.Begin(target.SubImage(sub).(*ebiten.Image))
+	}
+	// Each camera draws the occlusion region it is in.
+	selectOcclusionRegion(scene.World(), cam)
+	self.renderer.camera = cam
+	self.renderer.SetView(view)
+	scene.Draw(scene.World(), self.renderer)
+	self.renderer.ResetView()
+	self.renderer.camera = nil
+	if !full {
+		self.renderer.Flush()
+		self.renderer.Begin(target)
+	}
+}
+
+// applyPostProcessing color grades and post-processes the scene in src
+// onto screen.
+func (self *Engine) applyPostProcessing(screen, src *ebiten.Image, dt float64) {
+	if !self.postProcess.Active() {
+		self.colorGrading.Update(dt)
+		self.colorGrading.Apply(screen, src)
+		return
+	}
+	self.postProcess.Update(dt)
+	if self.colorGrading != nil {
+		size := src.Bounds().Size()
+		var created bool
+		if self.postBuffer, created = resizeRenderTarget(self.postBuffer, size.X, size.Y, "color grading"); !created {
+			self.postBuffer.Clear()
+		}
+		self.colorGrading.Update(dt)
+		self.colorGrading.Apply(self.postBuffer, src)
+		src = self.postBuffer
+	}
+	self.postProcess.Apply(screen, src)
+}
+
+// offscreenBuffer returns a buffer matching the screen size, recreating it
+// when the layout changes.
+func (self *Engine) offscreenBuffer(screen *ebiten.Image) *ebiten.Image {
+	size := screen.Bounds().Size()
+	var created bool
+	self.sceneBuffer, created = resizeRenderTarget(self.sceneBuffer, size.X, size.Y, "scene")
+	if !created && self.clearColor == nil && self.clearScreenEachFrame {
+		self.sceneBuffer.Clear()
+	}
+	return self.sceneBuffer
+}
+
+// frameDelta returns the real time elapsed since the previous rendered frame,
+// clamped to avoid huge steps after stalls (e.g. window dragging).
+func (self *Engine) frameDelta() float64 {
+	now := time.Now()
+	if self.lastFrameTime.IsZero() {
+		self.lastFrameTime = now
+		return 1.0 / 60.0
+	}
+	dt := now.Sub(self.lastFrameTime).Seconds()
+	self.lastFrameTime = now
+	return math.Min(dt, 0.25)
 }
 
 // Layout implements ebiten.Game.Layout.
@@ -375,14 +830,20 @@ func (self *Engine) Run() error {
 	} else {
 		ebiten.SetScreenClearedEveryFrame(self.clearScreenEachFrame)
 	}
-	for _, us := range self.updateSystems {
-		us.Initialize(self.World())
-	}
+	self.updateSystems.initialize(self.World())
 	for _, bs := range self.backgroundDrawSystems {
 		bs.Initialize(self.World())
 	}
 	for _, os := range self.overlayDrawSystems {
 		os.Initialize(self.World())
 	}
-	return ebiten.RunGame(self)
+	// Fixed steps are counted from real time, once per rendered frame.
+	if self.fixed.step > 0 {
+		ebiten.SetTPS(ebiten.SyncWithFPS)
+	}
+	// The close button goes through updateLifecycle for WithCloseConfirm.
+	ebiten.SetWindowClosingHandled(true)
+	err := ebiten.RunGame(self)
+	self.shutdown()
+	return err
 }
@@ -0,0 +1,144 @@
+package katsu2d
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+// lifecycle holds the engine's start, exit and focus hooks and state.
+type lifecycle struct {
+	onStart      []func(*Engine)
+	onExit       []func(*Engine)
+	onFocus      []func(e *Engine, focused bool)
+	confirmClose func(*Engine) bool
+	pauseOnBlur  bool
+
+	started     bool
+	exiting     bool
+	shutDown    bool
+	focused     bool
+	blurPaused  bool
+	pausedScale float64
+}
+
+// WithOnStart runs fn on the first update, once the window and graphics
+// context exist.
+func WithOnStart(fn func(*Engine)) Option {
+	return func(e *Engine) {
+		e.lifecycle.onStart = append(e.lifecycle.onStart, fn)
+	}
+}
+
+// WithOnExit runs fn during a clean shutdown, before pending saves are
+// flushed and audio is stopped.
+func WithOnExit(fn func(*Engine)) Option {
+	return func(e *Engine) {
+		e.lifecycle.onExit = append(e.lifecycle.onExit, fn)
+	}
+}
+
+// WithOnFocusChange runs fn when the window gains or loses focus. Changes
+// are only observable while the game runs unfocused, see
+// ebiten.SetRunnableOnUnfocused.
+func WithOnFocusChange(fn func(e *Engine, focused bool)) Option {
+	return func(e *Engine) {
+		e.lifecycle.onFocus = append(e.lifecycle.onFocus, fn)
+	}
+}
+
+// WithPauseOnFocusLoss sets the time scale to zero while the window is
+// unfocused and restores it when focus returns.
+func WithPauseOnFocusLoss(pause bool) Option {
+	return func(e *Engine) {
+		e.lifecycle.pauseOnBlur = pause
+	}
+}
+
+// WithCloseConfirm asks confirm before the window close button exits the
+// game. Returning false keeps the game running, e.g. to show a dialog that
+// later calls Exit.
+func WithCloseConfirm(confirm func(*Engine) bool) Option {
+	return func(e *Engine) {
+		e.lifecycle.confirmClose = confirm
+	}
+}
+
+// Exit shuts the game down cleanly at the end of the current update.
+func (self *Engine) Exit() {
+	self.lifecycle.exiting = true
+}
+
+// Exiting reports whether Exit was called.
+func (self *Engine) Exiting() bool {
+	return self.lifecycle.exiting
+}
+
+// Focused reports whether the window had focus at the last update.
+func (self *Engine) Focused() bool {
+	return self.lifecycle.focused
+}
+
+// updateLifecycle runs the start hooks, tracks focus and handles the close
+// button. It reports whether the game should terminate.
+func (self *Engine) updateLifecycle() bool {
+	lc := &self.lifecycle
+	if !lc.started {
+		lc.started = true
+		lc.focused = ebiten.IsFocused()
+		for _, fn := range lc.onStart {
+			fn(self)
+		}
+		Publish(self.World(), EngineStartedEvent{})
+	}
+	if focused := ebiten.IsFocused(); focused != lc.focused {
+		lc.focused = focused
+		self.applyFocusPause(focused)
+		for _, fn := range lc.onFocus {
+			fn(self, focused)
+		}
+		Publish(self.World(), EngineFocusChangedEvent{Focused: focused})
+	}
+	if ebiten.IsWindowBeingClosed() && !lc.exiting {
+		if lc.confirmClose == nil || lc.confirmClose(self) {
+			self.Exit()
+		}
+	}
+	if lc.exiting {
+		self.shutdown()
+		return true
+	}
+	return false
+}
+
+// applyFocusPause pauses on focus loss for WithPauseOnFocusLoss, leaving a
+// time scale changed meanwhile by the game alone.
+func (self *Engine) applyFocusPause(focused bool) {
+	lc := &self.lifecycle
+	if !lc.pauseOnBlur {
+		return
+	}
+	if !focused && !lc.blurPaused {
+		lc.blurPaused, lc.pausedScale = true, self.timeScale
+		self.timeScale = 0
+	} else if focused && lc.blurPaused {
+		lc.blurPaused = false
+		if self.timeScale == 0 {
+			self.timeScale = lc.pausedScale
+		}
+	}
+}
+
+// shutdown runs the exit hooks, waits for pending saves and stops audio. It
+// runs at most once.
+func (self *Engine) shutdown() {
+	lc := &self.lifecycle
+	if lc.shutDown {
+		return
+	}
+	lc.shutDown = true
+	for _, fn := range lc.onExit {
+		fn(self)
+	}
+	Publish(self.World(), EngineExitEvent{})
+	if self.saveSlots != nil {
+		self.saveSlots.Flush()
+	}
+	self.am.StopAll()
+}
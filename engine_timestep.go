@@ -0,0 +1,143 @@
+package katsu2d
+
+import (
+	"time"
+
+	"github.com/edwinsyarief/teishoku"
+)
+
+// maxFixedSteps caps the steps run in one frame, so a slow frame cannot
+// snowball into ever more steps.
+const maxFixedSteps = 5
+
+// FixedTimestep is added to the engine and scene worlds when the engine
+// runs with WithFixedTimestep. Draw systems read Alpha to interpolate
+// transforms between the last two steps.
+type FixedTimestep struct {
+	// Step is the fixed update duration in seconds.
+	Step float64
+	// Alpha is how far the rendered frame lies between the previous and
+	// the current step, in [0, 1).
+	Alpha float64
+}
+
+// GetFixedTimestep returns the world's fixed timestep, or nil when the
+// engine updates once per tick.
+func GetFixedTimestep(w *teishoku.World) *FixedTimestep {
+	return GetResource[FixedTimestep](w)
+}
+
+// WithFixedTimestep runs the update systems at hz steps per second,
+// independent of the display rate, and interpolates sprite, text, shape
+// and line positions when drawing.
+func WithFixedTimestep(hz int) Option {
+	return func(e *Engine) {
+		if hz > 0 {
+			e.fixed.step = 1 / float64(hz)
+		}
+	}
+}
+
+type fixedTimestep struct {
+	step    float64
+	acc     float64
+	last    time.Time
+	filters map[*teishoku.World]*teishoku.Filter[TransformComponent]
+	cameras []*Camera
+}
+
+// InterpolationAlpha returns the render interpolation alpha, which is 0
+// without a fixed timestep.
+func (self *Engine) InterpolationAlpha() float64 {
+	if self.fixed.step == 0 {
+		return 0
+	}
+	return self.fixed.acc / self.fixed.step
+}
+
+// fixedSteps accumulates the real time since the previous call and returns
+// the number of steps to run.
+func (self *Engine) fixedSteps() int {
+	fs := &self.fixed
+	now := time.Now()
+	if fs.last.IsZero() {
+		fs.last = now
+		fs.acc = fs.step
+	}
+	fs.acc += now.Sub(fs.last).Seconds()
+	fs.last = now
+	n := int(fs.acc / fs.step)
+	fs.acc -= float64(n) * fs.step
+	if n > maxFixedSteps {
+		n = maxFixedSteps
+		fs.acc = 0
+	}
+	return n
+}
+
+// snapshotTransforms stores every position of w as the previous position
+// before a fixed step moves it.
+func (self *Engine) snapshotTransforms(w *teishoku.World) {
+	fs := &self.fixed
+	if fs.filters == nil {
+		fs.filters = make(map[*teishoku.World]*teishoku.Filter[TransformComponent])
+	}
+	filter, ok := fs.filters[w]
+	if !ok {
+		filter = filter.New(w)
+		fs.filters[w] = filter
+	}
+	filter.Reset()
+	for filter.Next() {
+		t := filter.Get()
+		t.previous = t.Position
+		t.interpolate = true
+	}
+	self.eachCamera(w, func(cam *Camera) {
+		cam.previous = cam.Position
+		cam.interpolate = true
+	})
+}
+
+// forgetTransforms drops the snapshot filter of w, e.g. once its scene
+// exits.
+func (self *Engine) forgetTransforms(w *teishoku.World) {
+	delete(self.fixed.filters, w)
+}
+
+// eachCamera calls fn for the main camera and the CameraComponent cameras
+// of w.
+func (self *Engine) eachCamera(w *teishoku.World, fn func(cam *Camera)) {
+	fs := &self.fixed
+	fs.cameras = Cameras(w, fs.cameras[:0])
+	for _, cam := range fs.cameras {
+		fn(cam)
+	}
+	clear(fs.cameras)
+}
+
+// publishAlpha updates the FixedTimestep resource of w.
+func (self *Engine) publishAlpha(w *teishoku.World) {
+	ts := EnsureResource(w, func() *FixedTimestep {
+		return &FixedTimestep{}
+	})
+	ts.Step = self.fixed.step
+	ts.Alpha = self.InterpolationAlpha()
+	self.eachCamera(w, func(cam *Camera) {
+		cam.alpha = ts.Alpha
+	})
+}
+
+// setRenderTransform sets tr from t, interpolating the position when w
+// runs on a fixed timestep and layering e's JuiceComponent on top.
+func setRenderTransform(w *teishoku.World, e teishoku.Entity, tr *Transform, t *TransformComponent) {
+	tr.SetFromComponent(t)
+	if t.interpolate {
+		if ts := GetFixedTimestep(w); ts != nil {
+			tr.SetPosition(Vector(t.Interpolated(ts.Alpha)))
+		}
+	}
+	if j := teishoku.GetComponent[JuiceComponent](w, e); j != nil {
+		j.apply(tr)
+	}
+}
@@ -6,12 +6,164 @@ type EngineLayoutChangedEvent struct {
 	Width, Height int
 }
 
+// EngineStartedEvent is published on the engine world before the first
+// update.
+type EngineStartedEvent struct{}
+
+// EngineExitEvent is published on the engine world during a clean shutdown.
+type EngineExitEvent struct{}
+
+// EngineFocusChangedEvent is published when the window gains or loses focus.
+type EngineFocusChangedEvent struct {
+	Focused bool
+}
+
 type TweenFinishedEvent struct {
 	Entity teishoku.Entity
 	ID     string
 }
 
+// TimelineFinishedEvent is published each time the timeline of a
+// TimelineComponent reaches its end.
+type TimelineFinishedEvent struct {
+	Entity teishoku.Entity
+	ID     string
+}
+
 type TimerFinishedEvent struct {
 	Entity teishoku.Entity
 	ID     string
 }
+
+// AnimationFrameEvent is published when an animation enters a frame tagged
+// with AnimationComponent.AddFrameEvent.
+type AnimationFrameEvent struct {
+	Entity teishoku.Entity
+	Name   string
+	Frame  int
+}
+
+type PathFinishedEvent struct {
+	Entity teishoku.Entity
+}
+
+type RoofRevealChangedEvent struct {
+	Entity   teishoku.Entity
+	Revealed bool
+}
+
+type BlackboardChangedEvent struct {
+	Key      string
+	Old, New any
+}
+
+// QuestProgressEvent counts towards ObjectiveCount objectives with a matching
+// tag, e.g. publish {Tag: "kill_slime"} when a slime dies.
+type QuestProgressEvent struct {
+	Tag    string
+	Amount int
+}
+
+type QuestUpdatedEvent struct {
+	QuestID   string
+	Stage     int
+	Objective int
+}
+
+type QuestCompletedEvent struct {
+	QuestID string
+}
+
+type QuestFailedEvent struct {
+	QuestID string
+}
+
+type EntitySpawnedEvent struct {
+	Spawner teishoku.Entity
+	Entity  teishoku.Entity
+}
+
+type SpawnWaveStartedEvent struct {
+	Entity teishoku.Entity
+	Wave   int
+}
+
+type SpawnWaveCompletedEvent struct {
+	Entity teishoku.Entity
+	Wave   int
+}
+
+type SpawnerFinishedEvent struct {
+	Entity teishoku.Entity
+}
+
+type StateChangedEvent struct {
+	Entity   teishoku.Entity
+	From, To string
+}
+
+type ElevationChangedEvent struct {
+	Entity   teishoku.Entity
+	From, To int
+}
+
+// TextRevealFinishedEvent is published when a TextRevealComponent has
+// revealed its whole text.
+type TextRevealFinishedEvent struct {
+	Entity teishoku.Entity
+}
+
+// OrderChangedEvent is published when OrderableSystem changes the index of
+// at least one entity during a frame.
+type OrderChangedEvent struct{}
+
+// AutoSaveStartedEvent is published on the engine world when an auto-save
+// begins writing.
+type AutoSaveStartedEvent struct {
+	Slot   string
+	Reason string
+}
+
+// AutoSaveFinishedEvent is published on the engine world when an auto-save
+// has been written, with Err set if it failed.
+type AutoSaveFinishedEvent struct {
+	Slot   string
+	Reason string
+	Err    error
+}
+
+// CollisionEnterEvent is published when two colliders start overlapping.
+// The contact normal points from A towards B.
+type CollisionEnterEvent struct {
+	A, B    teishoku.Entity
+	Contact Contact
+	// Sensor is set when either collider is a sensor.
+	Sensor bool
+}
+
+// CollisionStayEvent is published every update while two colliders overlap.
+type CollisionStayEvent struct {
+	A, B    teishoku.Entity
+	Contact Contact
+	Sensor  bool
+}
+
+// CollisionExitEvent is published when two colliders stop overlapping or
+// one of them is removed.
+type CollisionExitEvent struct {
+	A, B teishoku.Entity
+}
+
+// TriggerEnterEvent is published when a collider's center enters a
+// TriggerZoneComponent.
+type TriggerEnterEvent struct {
+	Zone, Entity teishoku.Entity
+	Name         string
+}
+
+// TriggerExitEvent is published when a collider's center leaves a trigger
+// zone or either of them is removed.
+type TriggerExitEvent struct {
+	Zone, Entity teishoku.Entity
+	Name         string
+}
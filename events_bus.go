@@ -0,0 +1,108 @@
+package katsu2d
+
+import (
+	"reflect"
+	"slices"
+
+	"github.com/edwinsyarief/teishoku"
+)
+
+// eventRouter layers priorities, cancellation and sticky values over the
+// world's teishoku event bus. Channels are keyed by event type.
+type eventRouter struct {
+	channels map[reflect.Type]any
+}
+
+type eventSubscriber[T any] struct {
+	priority int
+	fn       func(T) bool
+}
+
+type eventChannel[T any] struct {
+	// subscribers is replaced rather than modified on subscribe, so a
+	// dispatch in progress keeps iterating its own copy.
+	subscribers []eventSubscriber[T]
+	sticky      T
+	hasSticky   bool
+}
+
+func getEventChannel[T any](w *teishoku.World) *eventChannel[T] {
+	router := EnsureResource(w, func() *eventRouter {
+		return &eventRouter{channels: make(map[reflect.Type]any)}
+	})
+	t := reflect.TypeFor[T]()
+	if ch, ok := router.channels[t]; ok {
+		return ch.(*eventChannel[T])
+	}
+	ch := &eventChannel[T]{}
+	router.channels[t] = ch
+	return ch
+}
+
+// Subscribe calls fn for every event of type T published on w, after
+// higher priority subscribers and unless one of them stopped the event.
+func Subscribe[T any](w *teishoku.World, fn func(T)) {
+	SubscribePriority(w, 0, func(e T) bool {
+		fn(e)
+		return false
+	})
+}
+
+// SubscribePriority calls fn for events of type T in descending priority
+// order, e.g. UI above gameplay for clicks. Subscribers sharing a priority
+// run in subscription order. fn returns true to stop the event from
+// reaching the remaining subscribers. A sticky event is delivered to fn
+// immediately.
+func SubscribePriority[T any](w *teishoku.World, priority int, fn func(T) bool) {
+	ch := getEventChannel[T](w)
+	i, _ := slices.BinarySearchFunc(ch.subscribers, priority, func(s eventSubscriber[T], p int) int {
+		if s.priority >= p {
+			return -1
+		}
+		return 1
+	})
+	ch.subscribers = slices.Insert(slices.Clip(ch.subscribers), i, eventSubscriber[T]{priority: priority, fn: fn})
+	if ch.hasSticky {
+		fn(ch.sticky)
+	}
+}
+
+// Publish sends o to the subscribers of its type on w.
+func Publish[T any](w *teishoku.World, o T) {
+	Dispatch(w, o)
+}
+
+// Dispatch sends o to the subscribers of its type on w and reports whether
+// a subscriber stopped it. Handlers subscribed directly on the world's
+// teishoku event bus only see events nobody stopped.
+func Dispatch[T any](w *teishoku.World, o T) bool {
+	for _, s := range getEventChannel[T](w).subscribers {
+		if s.fn(o) {
+			return true
+		}
+	}
+	teishoku.Publish(getEventBus(w), o)
+	return false
+}
+
+// PublishSticky publishes o and keeps it as the current value of its type,
+// so later subscribers receive it as soon as they subscribe. Use it for
+// state such as the locale or window size.
+func PublishSticky[T any](w *teishoku.World, o T) bool {
+	ch := getEventChannel[T](w)
+	ch.sticky, ch.hasSticky = o, true
+	return Dispatch(w, o)
+}
+
+// StickyEvent returns the last sticky event of type T published on w.
+func StickyEvent[T any](w *teishoku.World) (T, bool) {
+	ch := getEventChannel[T](w)
+	return ch.sticky, ch.hasSticky
+}
+
+// ClearSticky forgets the sticky event of type T.
+func ClearSticky[T any](w *teishoku.World) {
+	ch := getEventChannel[T](w)
+	var zero T
+	ch.sticky, ch.hasSticky = zero, false
+}
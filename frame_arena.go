@@ -0,0 +1,195 @@
+package katsu2d
+
+import (
+	"cmp"
+	"reflect"
+	"slices"
+
+	"github.com/edwinsyarief/teishoku"
+)
+
+// FrameArena hands out temporary slices that live until the end of the
+// frame, when the engine resets it. Buffers are kept between frames, so
+// query results, vertex staging and sort buffers stop producing garbage
+// once the arena has warmed up. Slices must not be kept past the frame.
+type FrameArena struct {
+	// Debug attributes arena usage to the system that requested it; see
+	// Usage.
+	Debug bool
+
+	pools     map[reflect.Type]arenaPool
+	label     string
+	usage     map[string]*ArenaUsage
+	lastUsage []ArenaUsage
+	bytes     int
+	lastBytes int
+}
+
+// ArenaUsage is the arena memory a system requested during a frame.
+type ArenaUsage struct {
+	System string
+	Bytes  int
+	Allocs int
+}
+
+type arenaPool interface {
+	reset()
+	capacity() int
+}
+
+type typedArenaPool[T any] struct {
+	bufs [][]T
+	used int
+	size int
+}
+
+func (self *typedArenaPool[T]) reset() {
+	self.used = 0
+}
+
+func (self *typedArenaPool[T]) capacity() int {
+	total := 0
+	for _, b := range self.bufs {
+		total += cap(b)
+	}
+	return total * self.size
+}
+
+func (self *typedArenaPool[T]) take(capacity int) []T {
+	if self.used == len(self.bufs) {
+		self.bufs = append(self.bufs, nil)
+	}
+	buf := self.bufs[self.used]
+	if cap(buf) < capacity {
+		buf = make([]T, 0, max(capacity, 2*cap(buf)))
+		self.bufs[self.used] = buf
+	}
+	self.used++
+	return buf[:0]
+}
+
+// GetFrameArena returns the world's frame arena, creating it on first use.
+func GetFrameArena(w *teishoku.World) *FrameArena {
+	return EnsureResource(w, func() *FrameArena {
+		return &FrameArena{
+			pools: make(map[reflect.Type]arenaPool),
+			usage: make(map[string]*ArenaUsage),
+		}
+	})
+}
+
+// ArenaSlice returns a zeroed slice of n elements from the world's frame
+// arena.
+func ArenaSlice[T any](w *teishoku.World, n int) []T {
+	s := ArenaBuffer[T](w, n)[:n]
+	clear(s)
+	return s
+}
+
+// ArenaBuffer returns an empty slice with at least the given capacity from
+// the world's frame arena, for building results with append. Appending past
+// the capacity falls back to the heap.
+func ArenaBuffer[T any](w *teishoku.World, capacity int) []T {
+	return arenaBuffer[T](GetFrameArena(w), capacity)
+}
+
+// arenaBuffer is ArenaBuffer on a given arena.
+func arenaBuffer[T any](arena *FrameArena, capacity int) []T {
+	t := reflect.TypeFor[T]()
+	pool, ok := arena.pools[t].(*typedArenaPool[T])
+	if !ok {
+		pool = &typedArenaPool[T]{size: int(t.Size())}
+		arena.pools[t] = pool
+	}
+	buf := pool.take(capacity)
+	arena.record(capacity * pool.size)
+	return buf
+}
+
+func (self *FrameArena) record(bytes int) {
+	self.bytes += bytes
+	if !self.Debug {
+		return
+	}
+	u := self.usage[self.label]
+	if u == nil {
+		u = &ArenaUsage{System: self.label}
+		self.usage[self.label] = u
+	}
+	u.Bytes += bytes
+	u.Allocs++
+}
+
+// Reset makes every buffer handed out this frame available again. The
+// engine calls it at the end of each frame.
+func (self *FrameArena) Reset() {
+	for _, pool := range self.pools {
+		pool.reset()
+	}
+	self.lastBytes, self.bytes = self.bytes, 0
+	self.lastUsage = self.lastUsage[:0]
+	for _, u := range self.usage {
+		if u.Allocs > 0 {
+			self.lastUsage = append(self.lastUsage, *u)
+		}
+		*u = ArenaUsage{System: u.System}
+	}
+	slices.SortFunc(self.lastUsage, func(a, b ArenaUsage) int { return cmp.Compare(b.Bytes, a.Bytes) })
+}
+
+// Bytes returns the arena memory requested during the last frame.
+func (self *FrameArena) Bytes() int {
+	return self.lastBytes
+}
+
+// Capacity returns the memory the arena currently retains.
+func (self *FrameArena) Capacity() int {
+	total := 0
+	for _, pool := range self.pools {
+		total += pool.capacity()
+	}
+	return total
+}
+
+// Usage returns the arena memory each system requested during the last
+// frame, largest first. It is only gathered while Debug is set.
+func (self *FrameArena) Usage() []ArenaUsage {
+	return self.lastUsage
+}
+
+// resetFrameArena resets the arena of w if it has one.
+func resetFrameArena(w *teishoku.World) {
+	if arena := GetResource[FrameArena](w); arena != nil {
+		arena.Reset()
+	}
+}
+
+// arenaScope labels arena requests with the system about to run while the
+// world's arena is in debug mode, and returns the label to restore.
+func arenaScope(w *teishoku.World, sys any) (*FrameArena, string) {
+	arena := GetResource[FrameArena](w)
+	if arena == nil || !arena.Debug {
+		return nil, ""
+	}
+	prev := arena.label
+	arena.label = reflect.TypeOf(sys).String()
+	return arena, prev
+}
+
+// runUpdateSystem updates us, attributing its arena usage to it.
+func runUpdateSystem(w *teishoku.World, us UpdateSystem, dt float64) {
+	if tc := GetTimeControl(w); tc != nil {
+		var running bool
+		if dt, running = tc.systemDelta(us, dt); !running {
+			return
+		}
+	}
+	if hs := GetResource[HitStops](w); hs != nil && hs.freezes(w, us) {
+		return
+	}
+	arena, prev := arenaScope(w, us)
+	us.Update(w, dt)
+	if arena != nil {
+		arena.label = prev
+	}
+}
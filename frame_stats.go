@@ -0,0 +1,133 @@
+package katsu2d
+
+import (
+	"runtime/debug"
+	"runtime/metrics"
+	"time"
+
+	"github.com/edwinsyarief/teishoku"
+)
+
+// frameStatsHistory is the number of frames kept by FrameStats.
+const frameStatsHistory = 240
+
+// FrameTiming is the cost of one drawn frame.
+type FrameTiming struct {
+	// Update is the time spent in every tick run since the previous frame,
+	// Draw the time spent drawing this one.
+	Update, Draw time.Duration
+	// Ticks is the number of updates run since the previous frame.
+	Ticks int
+	// GC is set when a garbage collection finished during the frame, and
+	// GCPause is how long it stopped the world.
+	GC      bool
+	GCPause time.Duration
+	// Heap is the size of the live heap objects at the end of the frame.
+	Heap uint64
+}
+
+// Total returns the time spent in updates and drawing.
+func (self FrameTiming) Total() time.Duration {
+	return self.Update + self.Draw
+}
+
+// FrameStats records the timing and memory of recent frames for
+// performance overlays. The engine shares one with its world and every
+// scene added to it.
+type FrameStats struct {
+	frames  [frameStatsHistory]FrameTiming
+	next    int
+	count   int
+	current FrameTiming
+	numGC   uint32
+	gc      debug.GCStats
+	samples []metrics.Sample
+}
+
+// NewFrameStats creates empty frame stats.
+func NewFrameStats() *FrameStats {
+	return &FrameStats{samples: []metrics.Sample{
+		{Name: "/memory/classes/heap/objects:bytes"},
+		{Name: "/gc/cycles/total:gc-cycles"},
+	}}
+}
+
+// GetFrameStats returns the world's frame stats, or nil if none are set.
+func GetFrameStats(w *teishoku.World) *FrameStats {
+	return GetResource[FrameStats](w)
+}
+
+// Len returns the number of recorded frames.
+func (self *FrameStats) Len() int {
+	return self.count
+}
+
+// Frame returns the i-th recorded frame, oldest first.
+func (self *FrameStats) Frame(i int) FrameTiming {
+	return self.frames[(self.next-self.count+i+frameStatsHistory)%frameStatsHistory]
+}
+
+// Last returns the most recent frame.
+func (self *FrameStats) Last() FrameTiming {
+	if self.count == 0 {
+		return FrameTiming{}
+	}
+	return self.Frame(self.count - 1)
+}
+
+// Average returns the mean update and draw time of the recorded frames.
+func (self *FrameStats) Average() FrameTiming {
+	var avg FrameTiming
+	if self.count == 0 {
+		return avg
+	}
+	for i := range self.count {
+		f := self.Frame(i)
+		avg.Update += f.Update
+		avg.Draw += f.Draw
+	}
+	avg.Update /= time.Duration(self.count)
+	avg.Draw /= time.Duration(self.count)
+	avg.Heap = self.Last().Heap
+	return avg
+}
+
+// NumGC returns the number of garbage collections since the game started.
+func (self *FrameStats) NumGC() uint32 {
+	return self.numGC
+}
+
+func (self *FrameStats) addUpdate(d time.Duration) {
+	self.current.Update += d
+	self.current.Ticks++
+}
+
+// endFrame records the current frame with its draw time and samples the
+// heap and collector.
+func (self *FrameStats) endFrame(draw time.Duration) {
+	f := self.current
+	f.Draw = draw
+	metrics.Read(self.samples)
+	if s := self.samples[0]; s.Value.Kind() == metrics.KindUint64 {
+		f.Heap = s.Value.Uint64()
+	}
+	if s := self.samples[1]; s.Value.Kind() == metrics.KindUint64 {
+		if n := uint32(s.Value.Uint64()); n != self.numGC {
+			self.numGC = n
+			debug.ReadGCStats(&self.gc)
+			f.GC = true
+			if len(self.gc.Pause) > 0 {
+				f.GCPause = self.gc.Pause[0]
+			}
+		}
+	}
+	self.frames[self.next] = f
+	self.next = (self.next + 1) % frameStatsHistory
+	self.count = min(self.count+1, frameStatsHistory)
+	self.current = FrameTiming{}
+}
+
+// FrameStats returns the engine's frame stats.
+func (self *Engine) FrameStats() *FrameStats {
+	return self.frameStats
+}
@@ -0,0 +1,129 @@
+package katsu2d
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/colorm"
+)
+
+// ImageEffect generates a variant of a texture. The result may be larger than
+// the source; inset is where the source's top-left corner ends up.
+type ImageEffect interface {
+	Apply(src *ebiten.Image) (dst *ebiten.Image, inset Point)
+}
+
+// Blur softens an image with a box blur of the given radius in pixels.
+type Blur struct {
+	Radius int
+}
+
+func (self Blur) Apply(src *ebiten.Image) (*ebiten.Image, Point) {
+	pad := max(self.Radius, 0)
+	return blurImage(src, self.Radius, pad), Point{X: float64(pad), Y: float64(pad)}
+}
+
+// Glow draws a blurred, tinted halo behind an image.
+type Glow struct {
+	Radius   int
+	Color    color.RGBA
+	Strength float64
+}
+
+func (self Glow) Apply(src *ebiten.Image) (*ebiten.Image, Point) {
+	pad := max(self.Radius, 0)
+	halo := blurImage(silhouette(src, self.Color), self.Radius, pad)
+	strength := self.Strength
+	if strength <= 0 {
+		strength = 1
+	}
+	dst := ebiten.NewImage(halo.Bounds().Dx(), halo.Bounds().Dy())
+	op := &ebiten.DrawImageOptions{}
+	op.ColorScale.Scale(float32(strength), float32(strength), float32(strength), float32(strength))
+	dst.DrawImage(halo, op)
+	halo.Deallocate()
+
+	op = &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(pad), float64(pad))
+	dst.DrawImage(src, op)
+	return dst, Point{X: float64(pad), Y: float64(pad)}
+}
+
+// DropShadow draws a blurred, offset silhouette behind an image.
+type DropShadow struct {
+	Offset Point
+	Radius int
+	Color  color.RGBA
+}
+
+func (self DropShadow) Apply(src *ebiten.Image) (*ebiten.Image, Point) {
+	r := max(self.Radius, 0)
+	left := float64(r) + max(-self.Offset.X, 0)
+	top := float64(r) + max(-self.Offset.Y, 0)
+	right := float64(r) + max(self.Offset.X, 0)
+	bottom := float64(r) + max(self.Offset.Y, 0)
+
+	shadow := blurImage(silhouette(src, self.Color), self.Radius, r)
+	w := src.Bounds().Dx() + int(left+right)
+	h := src.Bounds().Dy() + int(top+bottom)
+	dst := ebiten.NewImage(w, h)
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(left-float64(r)+self.Offset.X, top-float64(r)+self.Offset.Y)
+	dst.DrawImage(shadow, op)
+	shadow.Deallocate()
+
+	op = &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(left, top)
+	dst.DrawImage(src, op)
+	return dst, Point{X: left, Y: top}
+}
+
+// effectKey identifies an effect and its parameters for caching.
+func effectKey(effect ImageEffect) string {
+	return fmt.Sprintf("%T%+v", effect, effect)
+}
+
+// silhouette returns src with every pixel replaced by clr, keeping alpha.
+func silhouette(src *ebiten.Image, clr color.RGBA) *ebiten.Image {
+	dst := ebiten.NewImage(src.Bounds().Dx(), src.Bounds().Dy())
+	var cm colorm.ColorM
+	cm.Scale(0, 0, 0, float64(clr.A)/255)
+	cm.Translate(float64(clr.R)/255, float64(clr.G)/255, float64(clr.B)/255, 0)
+	op := &colorm.DrawImageOptions{}
+	op.GeoM.Translate(-float64(src.Bounds().Min.X), -float64(src.Bounds().Min.Y))
+	colorm.DrawImage(dst, src, cm, op)
+	return dst
+}
+
+// blurImage applies a separable box blur on the GPU by summing shifted copies.
+// The result is padded by pad pixels on every side.
+func blurImage(src *ebiten.Image, radius, pad int) *ebiten.Image {
+	w, h := src.Bounds().Dx()+pad*2, src.Bounds().Dy()+pad*2
+	minX, minY := float64(src.Bounds().Min.X), float64(src.Bounds().Min.Y)
+	dst := ebiten.NewImage(w, h)
+	if radius <= 0 {
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(float64(pad)-minX, float64(pad)-minY)
+		dst.DrawImage(src, op)
+		return dst
+	}
+
+	k := float32(1) / float32(radius*2+1)
+	tmp := ebiten.NewImage(w, h)
+	for dx := -radius; dx <= radius; dx++ {
+		op := &ebiten.DrawImageOptions{Blend: ebiten.BlendLighter}
+		op.GeoM.Translate(float64(pad+dx)-minX, float64(pad)-minY)
+		op.ColorScale.Scale(k, k, k, k)
+		tmp.DrawImage(src, op)
+	}
+	for dy := -radius; dy <= radius; dy++ {
+		op := &ebiten.DrawImageOptions{Blend: ebiten.BlendLighter}
+		op.GeoM.Translate(0, float64(dy))
+		op.ColorScale.Scale(k, k, k, k)
+		dst.DrawImage(tmp, op)
+	}
+	tmp.Deallocate()
+	return dst
+}
@@ -0,0 +1,88 @@
+package katsu2d
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// inputEdge identifies a button whose press or release was sampled.
+// Standard gamepad buttons use inputEdgeStandard as their type, as they
+// share codes with the raw gamepad buttons.
+type inputEdge struct {
+	Type InputType
+	ID   int
+	Code int
+}
+
+const inputEdgeStandard InputType = -1
+
+// inputEdges latches the just pressed and just released edges of keys,
+// mouse buttons and gamepad buttons. Ebitengine reports an edge for one
+// tick, but with a fixed timestep a tick runs zero or several steps: the
+// engine samples every tick and clears the latch after a step, so each
+// edge is seen by exactly one step.
+var inputEdges = struct {
+	pressed, released map[inputEdge]bool
+	keys              []ebiten.Key
+	gamepads          []ebiten.GamepadID
+}{
+	pressed:  make(map[inputEdge]bool),
+	released: make(map[inputEdge]bool),
+}
+
+// sampleInputEdges adds the edges of the current tick to the latch.
+func sampleInputEdges() {
+	e := &inputEdges
+	e.keys = inpututil.AppendJustPressedKeys(e.keys[:0])
+	for _, k := range e.keys {
+		e.pressed[inputEdge{Type: InputTypeKeyboard, Code: int(k)}] = true
+	}
+	e.keys = inpututil.AppendJustReleasedKeys(e.keys[:0])
+	for _, k := range e.keys {
+		e.released[inputEdge{Type: InputTypeKeyboard, Code: int(k)}] = true
+	}
+	for b := ebiten.MouseButton0; b <= ebiten.MouseButtonMax; b++ {
+		latchEdge(inputEdge{Type: InputTypeMouse, Code: int(b)},
+			inpututil.IsMouseButtonJustPressed(b), inpututil.IsMouseButtonJustReleased(b))
+	}
+	e.gamepads = ebiten.AppendGamepadIDs(e.gamepads[:0])
+	for _, id := range e.gamepads {
+		for b := ebiten.GamepadButton0; b <= ebiten.GamepadButtonMax; b++ {
+			latchEdge(inputEdge{Type: InputTypeGamepad, ID: int(id), Code: int(b)},
+				inpututil.IsGamepadButtonJustPressed(id, b), inpututil.IsGamepadButtonJustReleased(id, b))
+		}
+		if !ebiten.IsStandardGamepadLayoutAvailable(id) {
+			continue
+		}
+		for b := ebiten.StandardGamepadButton(0); b <= ebiten.StandardGamepadButtonMax; b++ {
+			latchEdge(inputEdge{Type: inputEdgeStandard, ID: int(id), Code: int(b)},
+				inpututil.IsStandardGamepadButtonJustPressed(id, b), inpututil.IsStandardGamepadButtonJustReleased(id, b))
+		}
+	}
+}
+
+func latchEdge(edge inputEdge, pressed, released bool) {
+	if pressed {
+		inputEdges.pressed[edge] = true
+	}
+	if released {
+		inputEdges.released[edge] = true
+	}
+}
+
+// clearInputEdges empties the latch once a step has seen its edges.
+func clearInputEdges() {
+	clear(inputEdges.pressed)
+	clear(inputEdges.released)
+}
+
+// keyJustPressed reports whether key was pressed since the previous step.
+func keyJustPressed(key ebiten.Key) bool {
+	return inputEdges.pressed[inputEdge{Type: InputTypeKeyboard, Code: int(key)}]
+}
+
+// mouseButtonJustPressed reports whether button was pressed since the
+// previous step.
+func mouseButtonJustPressed(button ebiten.MouseButton) bool {
+	return inputEdges.pressed[inputEdge{Type: InputTypeMouse, Code: int(button)}]
+}
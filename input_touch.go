@@ -0,0 +1,189 @@
+package katsu2d
+
+import (
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// TouchGesture is a touch input bound like a key with InputTypeTouch.
+type TouchGesture int
+
+const (
+	// TouchPress is pressed while any finger touches the screen.
+	TouchPress TouchGesture = iota
+	// TouchTap is pressed for the frame a short touch that did not move
+	// is released.
+	TouchTap
+	// TouchHold is pressed while a finger rests in place past HoldTime.
+	TouchHold
+)
+
+// Touch is the state of one finger on the screen, in screen pixels.
+type Touch struct {
+	ID                     ebiten.TouchID
+	Start, Position, Delta Vector
+	// Duration is how long the finger has been down, in seconds.
+	Duration     float64
+	JustPressed  bool
+	JustReleased bool
+	// Tapped is set on release when the touch was short and did not drag.
+	Tapped bool
+	// Held is set once the touch stayed in place for HoldTime, and
+	// JustHeld for that frame only.
+	Held, JustHeld bool
+	// Dragging is set once the touch moved DragDistance from its start.
+	Dragging bool
+	// Claimed touches are owned by a virtual control and ignored by the
+	// touch gestures bound to actions.
+	Claimed bool
+}
+
+// TouchInput tracks every touch and recognizes taps, holds and drags. Like
+// Ebitengine's input state it is shared by the whole game; the InputSystem
+// and VirtualControlsSystem update it once per tick.
+type TouchInput struct {
+	// TapTime is the longest touch, in seconds, that counts as a tap.
+	TapTime float64
+	// HoldTime is how long, in seconds, a touch must rest to be held.
+	HoldTime float64
+	// DragDistance is how far, in pixels, a touch moves to become a drag.
+	DragDistance float64
+
+	touches []Touch
+	ids     []ebiten.TouchID
+	tick    int64
+}
+
+var touchInput = &TouchInput{TapTime: 0.25, HoldTime: 0.5, DragDistance: 10, tick: -1}
+
+// GetTouchInput returns the touch state.
+func GetTouchInput() *TouchInput {
+	return touchInput
+}
+
+// Touches returns the active touches and those released this frame.
+func (self *TouchInput) Touches() []Touch {
+	return self.touches
+}
+
+// Touch returns the touch with id.
+func (self *TouchInput) Touch(id ebiten.TouchID) (*Touch, bool) {
+	for i := range self.touches {
+		if self.touches[i].ID == id {
+			return &self.touches[i], true
+		}
+	}
+	return nil, false
+}
+
+// Tap returns the first unclaimed tap of this frame.
+func (self *TouchInput) Tap() (Touch, bool) {
+	for _, t := range self.touches {
+		if t.Tapped && !t.Claimed {
+			return t, true
+		}
+	}
+	return Touch{}, false
+}
+
+// update advances the touches by dt once per tick.
+func (self *TouchInput) update(dt float64) {
+	tick := ebiten.Tick()
+	if tick == self.tick {
+		return
+	}
+	self.tick = tick
+
+	// Touches released last frame are gone now.
+	self.touches = slices.DeleteFunc(self.touches, func(t Touch) bool { return t.JustReleased })
+	self.ids = ebiten.AppendTouchIDs(self.ids[:0])
+	for i := range self.touches {
+		t := &self.touches[i]
+		t.JustPressed, t.JustHeld = false, false
+		if slices.Contains(self.ids, t.ID) {
+			continue
+		}
+		t.JustReleased = true
+		x, y := inpututil.TouchPositionInPreviousTick(t.ID)
+		t.Delta = V(float64(x), float64(y)).Sub(t.Position)
+		t.Position = V(float64(x), float64(y))
+		t.Tapped = !t.Dragging && t.Duration <= self.TapTime
+	}
+	for _, id := range self.ids {
+		x, y := ebiten.TouchPosition(id)
+		pos := V(float64(x), float64(y))
+		t, ok := self.Touch(id)
+		if !ok {
+			self.touches = append(self.touches, Touch{ID: id, Start: pos, Position: pos, JustPressed: true})
+			continue
+		}
+		t.Delta = pos.Sub(t.Position)
+		t.Position = pos
+		t.Duration += dt
+		if !t.Dragging && pos.DistanceTo(t.Start) >= self.DragDistance {
+			t.Dragging = true
+		}
+		if !t.Held && !t.Dragging && t.Duration >= self.HoldTime {
+			t.Held, t.JustHeld = true, true
+		}
+	}
+}
+
+// gesture reports the pressed, just pressed and just released state of g
+// over the unclaimed touches.
+func (self *TouchInput) gesture(g TouchGesture) (pressed, justPressed, justReleased bool) {
+	down := false
+	for _, t := range self.touches {
+		if t.Claimed {
+			continue
+		}
+		switch g {
+		case TouchPress:
+			down = down || !t.JustReleased
+			justPressed = justPressed || t.JustPressed
+			justReleased = justReleased || t.JustReleased
+		case TouchTap:
+			pressed = pressed || t.Tapped
+			justPressed = justPressed || t.Tapped
+		case TouchHold:
+			pressed = pressed || (t.Held && !t.JustReleased)
+			justPressed = justPressed || t.JustHeld
+			justReleased = justReleased || (t.Held && t.JustReleased)
+		}
+	}
+	if g == TouchPress {
+		pressed = down
+		justReleased = justReleased && !down
+	}
+	return pressed, justPressed, justReleased
+}
+
+// VirtualButton is an on-screen button of a VirtualControlsSystem, bound
+// to actions like a key.
+type VirtualButton int
+
+// VirtualStickAxis is one axis of an on-screen joystick, bound to actions
+// with NewAxisBinding.
+type VirtualStickAxis int
+
+// VirtualStickX returns the horizontal axis of virtual stick n.
+func VirtualStickX(n int) VirtualStickAxis {
+	return VirtualStickAxis(n * 2)
+}
+
+// VirtualStickY returns the vertical axis of virtual stick n.
+func VirtualStickY(n int) VirtualStickAxis {
+	return VirtualStickAxis(n*2 + 1)
+}
+
+// virtualInput holds the state the virtual controls feed to the bindings.
+var virtualInput = struct {
+	buttons, prevButtons map[VirtualButton]bool
+	axes                 map[VirtualStickAxis]float64
+}{
+	buttons:     make(map[VirtualButton]bool),
+	prevButtons: make(map[VirtualButton]bool),
+	axes:        make(map[VirtualStickAxis]float64),
+}
@@ -0,0 +1,233 @@
+// Package jobs runs background work on a pool of worker goroutines and hands
+// results back to the main loop at frame sync points.
+package jobs
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// Handle tracks a submitted job or group of chunks.
+type Handle struct {
+	pending atomic.Int32
+	done    chan struct{}
+	pool    *Pool
+}
+
+func newHandle(pool *Pool, count int) *Handle {
+	h := &Handle{done: make(chan struct{}), pool: pool}
+	h.pending.Store(int32(count))
+	if count <= 0 {
+		close(h.done)
+	}
+	return h
+}
+
+func (self *Handle) finish() {
+	if self.pending.Add(-1) == 0 {
+		close(self.done)
+	}
+}
+
+// Wait blocks until the job has finished. While its chunks are queued the
+// caller runs queued jobs itself, so waiting inside a job cannot deadlock
+// the pool.
+func (self *Handle) Wait() {
+	for !self.Done() {
+		if self.pool == nil || !self.pool.runOne() {
+			<-self.done
+			return
+		}
+	}
+}
+
+// Done reports whether the job has finished without blocking.
+func (self *Handle) Done() bool {
+	select {
+	case <-self.done:
+		return true
+	default:
+		return false
+	}
+}
+
+type completion struct {
+	handle *Handle
+	fn     func()
+}
+
+// Pool is a fixed set of workers consuming a shared job queue.
+type Pool struct {
+	mu          sync.Mutex
+	cond        *sync.Cond
+	queue       []func()
+	workers     int
+	closed      bool
+	completions []completion
+	syncMu      sync.Mutex
+}
+
+// NewPool starts a pool with the given number of workers. A count of zero
+// or less uses GOMAXPROCS.
+func NewPool(workers int) *Pool {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	p := &Pool{workers: workers}
+	p.cond = sync.NewCond(&p.mu)
+	for range workers {
+		go p.work()
+	}
+	return p
+}
+
+func (self *Pool) work() {
+	for {
+		self.mu.Lock()
+		for len(self.queue) == 0 && !self.closed {
+			self.cond.Wait()
+		}
+		if len(self.queue) == 0 {
+			self.mu.Unlock()
+			return
+		}
+		job := self.queue[0]
+		self.queue[0] = nil
+		self.queue = self.queue[1:]
+		self.mu.Unlock()
+		job()
+	}
+}
+
+// runOne runs the next queued job on the calling goroutine. It reports
+// false when the queue is empty.
+func (self *Pool) runOne() bool {
+	self.mu.Lock()
+	if len(self.queue) == 0 {
+		self.mu.Unlock()
+		return false
+	}
+	job := self.queue[0]
+	self.queue[0] = nil
+	self.queue = self.queue[1:]
+	self.mu.Unlock()
+	job()
+	return true
+}
+
+func (self *Pool) push(job func()) {
+	self.mu.Lock()
+	if self.closed {
+		self.mu.Unlock()
+		job()
+		return
+	}
+	self.queue = append(self.queue, job)
+	self.mu.Unlock()
+	self.cond.Signal()
+}
+
+// Workers returns the number of worker goroutines.
+func (self *Pool) Workers() int {
+	return self.workers
+}
+
+// Submit queues fn to run on a worker.
+func (self *Pool) Submit(fn func()) *Handle {
+	h := newHandle(self, 1)
+	self.push(func() {
+		defer h.finish()
+		fn()
+	})
+	return h
+}
+
+// SubmitChunked splits the range [0, n) into one chunk per worker and calls
+// fn(start, end) for each chunk in parallel.
+func (self *Pool) SubmitChunked(n int, fn func(start, end int)) *Handle {
+	chunks := min(self.workers, n)
+	h := newHandle(self, chunks)
+	for i := range chunks {
+		start, end := i*n/chunks, (i+1)*n/chunks
+		self.push(func() {
+			defer h.finish()
+			fn(start, end)
+		})
+	}
+	return h
+}
+
+// Then schedules fn to run on the thread calling Sync once h has finished.
+// Use it to apply background results to the world safely.
+func (self *Pool) Then(h *Handle, fn func()) {
+	self.syncMu.Lock()
+	self.completions = append(self.completions, completion{handle: h, fn: fn})
+	self.syncMu.Unlock()
+}
+
+// Sync runs the Then callbacks of finished jobs. The engine calls it for the
+// default pool once per update.
+func (self *Pool) Sync() {
+	self.syncMu.Lock()
+	var ready []func()
+	pending := self.completions[:0]
+	for _, c := range self.completions {
+		if c.handle.Done() {
+			ready = append(ready, c.fn)
+		} else {
+			pending = append(pending, c)
+		}
+	}
+	clear(self.completions[len(pending):])
+	self.completions = pending
+	self.syncMu.Unlock()
+
+	for _, fn := range ready {
+		fn()
+	}
+}
+
+// Close stops the workers after the queued jobs have run. Jobs submitted
+// afterwards run synchronously.
+func (self *Pool) Close() {
+	self.mu.Lock()
+	self.closed = true
+	self.mu.Unlock()
+	self.cond.Broadcast()
+}
+
+var (
+	defaultPool atomic.Pointer[Pool]
+	defaultOnce sync.Once
+)
+
+// Default returns the shared pool sized to GOMAXPROCS.
+func Default() *Pool {
+	defaultOnce.Do(func() {
+		defaultPool.Store(NewPool(0))
+	})
+	return defaultPool.Load()
+}
+
+// Submit queues fn on the default pool.
+func Submit(fn func()) *Handle {
+	return Default().Submit(fn)
+}
+
+// SubmitChunked splits [0, n) across the default pool.
+func SubmitChunked(n int, fn func(start, end int)) *Handle {
+	return Default().SubmitChunked(n, fn)
+}
+
+// Then schedules fn on the default pool's next Sync after h finishes.
+func Then(h *Handle, fn func()) {
+	Default().Then(h, fn)
+}
+
+// Sync runs finished Then callbacks of the default pool, if it was created.
+func Sync() {
+	if p := defaultPool.Load(); p != nil {
+		p.Sync()
+	}
+}
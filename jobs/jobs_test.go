@@ -0,0 +1,114 @@
+package jobs
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitTimeout fails the test when h does not finish in time.
+func waitTimeout(t *testing.T, h *Handle) {
+	t.Helper()
+	finished := make(chan struct{})
+	go func() {
+		h.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+	case <-time.After(5 * time.Second):
+		t.Fatal("job did not finish")
+	}
+}
+
+// TestSubmit verifies that every submitted job runs once.
+func TestSubmit(t *testing.T) {
+	p := NewPool(4)
+	defer p.Close()
+	var count atomic.Int32
+	handles := make([]*Handle, 100)
+	for i := range handles {
+		handles[i] = p.Submit(func() { count.Add(1) })
+	}
+	for _, h := range handles {
+		waitTimeout(t, h)
+		if !h.Done() {
+			t.Fatal("handle not done after Wait")
+		}
+	}
+	if got := count.Load(); got != 100 {
+		t.Errorf("ran %d jobs, want 100", got)
+	}
+}
+
+// TestSubmitChunked verifies that the chunks cover the range exactly once,
+// including ranges smaller than the pool.
+func TestSubmitChunked(t *testing.T) {
+	p := NewPool(4)
+	defer p.Close()
+	for _, n := range []int{0, 3, 1000} {
+		hits := make([]atomic.Int32, n)
+		waitTimeout(t, p.SubmitChunked(n, func(start, end int) {
+			for i := start; i < end; i++ {
+				hits[i].Add(1)
+			}
+		}))
+		for i := range hits {
+			if got := hits[i].Load(); got != 1 {
+				t.Fatalf("n=%d: index %d visited %d times", n, i, got)
+			}
+		}
+	}
+}
+
+// TestThen verifies that completions run on Sync, only once their job has
+// finished.
+func TestThen(t *testing.T) {
+	p := NewPool(2)
+	defer p.Close()
+	release := make(chan struct{})
+	blocked := p.Submit(func() { <-release })
+	var ran atomic.Int32
+	p.Then(blocked, func() { ran.Add(1) })
+
+	p.Sync()
+	if ran.Load() != 0 {
+		t.Fatal("completion ran before its job finished")
+	}
+	close(release)
+	waitTimeout(t, blocked)
+	p.Sync()
+	p.Sync()
+	if got := ran.Load(); got != 1 {
+		t.Errorf("completion ran %d times, want 1", got)
+	}
+}
+
+// TestNestedWait verifies that a job waiting on chunks it submitted does
+// not deadlock a single worker pool.
+func TestNestedWait(t *testing.T) {
+	p := NewPool(1)
+	defer p.Close()
+	var sum atomic.Int64
+	waitTimeout(t, p.Submit(func() {
+		p.SubmitChunked(10, func(start, end int) {
+			for i := start; i < end; i++ {
+				sum.Add(int64(i))
+			}
+		}).Wait()
+	}))
+	if got := sum.Load(); got != 45 {
+		t.Errorf("sum = %d, want 45", got)
+	}
+}
+
+// TestClose verifies that jobs submitted after Close run synchronously.
+func TestClose(t *testing.T) {
+	p := NewPool(2)
+	p.Close()
+	ran := false
+	h := p.Submit(func() { ran = true })
+	if !ran || !h.Done() {
+		t.Error("job submitted after Close did not run synchronously")
+	}
+}
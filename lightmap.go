@@ -0,0 +1,210 @@
+package katsu2d
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/edwinsyarief/katsu2d/jobs"
+	"github.com/edwinsyarief/teishoku"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// StaticLight is a light baked into a Lightmap.
+type StaticLight struct {
+	Position  Vector
+	Color     color.RGBA
+	Radius    float64
+	Intensity float64
+}
+
+// LightOccluder reports whether a lightmap cell blocks light.
+type LightOccluder func(x, y int) bool
+
+// ElevationOccluder blocks light in every cell of m above level.
+func ElevationOccluder(m *ElevationMap, level int) LightOccluder {
+	return func(x, y int) bool {
+		return m.At(x, y) > level
+	}
+}
+
+// Lightmap is a grid of baked light, one texel per cell, multiplied over the
+// scene by a LightmapSystem. Linear filtering between texels softens it.
+type Lightmap struct {
+	Origin        Vector
+	CellSize      float64
+	Width, Height int
+	Ambient       color.RGBA
+	// Samples is the number of samples per cell along each axis; more
+	// samples give softer shadow edges.
+	Samples  int
+	Lights   []StaticLight
+	Occluder LightOccluder
+
+	pixels []byte
+	image  *ebiten.Image
+}
+
+// NewLightmap creates a lightmap of width x height cells.
+func NewLightmap(width, height int, cellSize float64, ambient color.RGBA) *Lightmap {
+	return &Lightmap{
+		CellSize: cellSize,
+		Width:    width,
+		Height:   height,
+		Ambient:  ambient,
+		Samples:  2,
+	}
+}
+
+// NewLightmapForElevation creates a lightmap matching m's grid, with cells
+// above level occluding light.
+func NewLightmapForElevation(m *ElevationMap, level int, ambient color.RGBA) *Lightmap {
+	lm := NewLightmap(m.Width, m.Height, m.CellSize, ambient)
+	lm.Origin = m.Origin
+	lm.Occluder = ElevationOccluder(m, level)
+	return lm
+}
+
+// SetLightmap installs lm as the world's lightmap.
+func SetLightmap(w *teishoku.World, lm *Lightmap) {
+	if current := GetLightmap(w); current != nil {
+		*current = *lm
+		return
+	}
+	SetResource(w, lm)
+}
+
+// GetLightmap returns the world's lightmap, or nil if none is set.
+func GetLightmap(w *teishoku.World) *Lightmap {
+	return GetResource[Lightmap](w)
+}
+
+// AddLight adds a static light to bake.
+func (self *Lightmap) AddLight(light StaticLight) {
+	self.Lights = append(self.Lights, light)
+}
+
+// CollectLights adds every entity with a StaticLightComponent in w.
+func (self *Lightmap) CollectLights(w *teishoku.World) {
+	var filter *teishoku.Filter2[TransformComponent, StaticLightComponent]
+	filter = filter.New(w)
+	filter.Reset()
+	for filter.Next() {
+		t, light := filter.Get()
+		self.AddLight(StaticLight{
+			Position:  Vector(t.Position),
+			Color:     light.Color,
+			Radius:    light.Radius,
+			Intensity: light.Intensity,
+		})
+	}
+}
+
+// Bake computes the light of every cell, spreading rows over the job pool,
+// and uploads the result. It is meant to run once at load or offline.
+func (self *Lightmap) Bake() {
+	self.pixels = make([]byte, self.Width*self.Height*4)
+	jobs.SubmitChunked(self.Height, func(start, end int) {
+		for y := start; y < end; y++ {
+			for x := 0; x < self.Width; x++ {
+				c := self.bakeCell(x, y)
+				i := (y*self.Width + x) * 4
+				self.pixels[i], self.pixels[i+1], self.pixels[i+2], self.pixels[i+3] = c.R, c.G, c.B, 255
+			}
+		}
+	}).Wait()
+	self.image, _ = resizeRenderTarget(self.image, self.Width, self.Height, "lightmap")
+	self.image.WritePixels(self.pixels)
+}
+
+// Image returns the baked lightmap texture, or nil before Bake.
+func (self *Lightmap) Image() *ebiten.Image {
+	return self.image
+}
+
+// At returns the baked light of a cell.
+func (self *Lightmap) At(x, y int) color.RGBA {
+	if self.pixels == nil || x < 0 || y < 0 || x >= self.Width || y >= self.Height {
+		return self.Ambient
+	}
+	i := (y*self.Width + x) * 4
+	return color.RGBA{R: self.pixels[i], G: self.pixels[i+1], B: self.pixels[i+2], A: 255}
+}
+
+func (self *Lightmap) bakeCell(x, y int) color.RGBA {
+	samples := max(self.Samples, 1)
+	r, g, b := 0.0, 0.0, 0.0
+	for sy := range samples {
+		for sx := range samples {
+			p := self.Origin.Add(V(
+				(float64(x)+(float64(sx)+0.5)/float64(samples))*self.CellSize,
+				(float64(y)+(float64(sy)+0.5)/float64(samples))*self.CellSize))
+			for _, light := range self.Lights {
+				d := p.DistanceTo(light.Position)
+				if d >= light.Radius || !self.visible(light.Position, x, y) {
+					continue
+				}
+				falloff := 1 - d/light.Radius
+				f := falloff * falloff * light.Intensity / 255
+				r += float64(light.Color.R) * f
+				g += float64(light.Color.G) * f
+				b += float64(light.Color.B) * f
+			}
+		}
+	}
+	n := float64(samples * samples)
+	return color.RGBA{
+		R: uint8(Clamp(float64(self.Ambient.R)+r/n*255, 0, 255)),
+		G: uint8(Clamp(float64(self.Ambient.G)+g/n*255, 0, 255)),
+		B: uint8(Clamp(float64(self.Ambient.B)+b/n*255, 0, 255)),
+		A: 255,
+	}
+}
+
+// visible walks the cells between a light and a target cell, reporting
+// whether none of them occlude. The light's and the target's own cells do
+// not block, so walls stay lit on their facing side.
+func (self *Lightmap) visible(from Vector, tx, ty int) bool {
+	if self.Occluder == nil {
+		return true
+	}
+	fx := (from.X - self.Origin.X) / self.CellSize
+	fy := (from.Y - self.Origin.Y) / self.CellSize
+	x, y := int(math.Floor(fx)), int(math.Floor(fy))
+	dx, dy := float64(tx)+0.5-fx, float64(ty)+0.5-fy
+	stepX, stepY := 1, 1
+	if dx < 0 {
+		stepX = -1
+	}
+	if dy < 0 {
+		stepY = -1
+	}
+	nextX, nextY := math.Inf(1), math.Inf(1)
+	deltaX, deltaY := math.Inf(1), math.Inf(1)
+	if dx != 0 {
+		deltaX = math.Abs(1 / dx)
+		nextX = (math.Floor(fx) + float64(max(stepX, 0)) - fx) / dx
+	}
+	if dy != 0 {
+		deltaY = math.Abs(1 / dy)
+		nextY = (math.Floor(fy) + float64(max(stepY, 0)) - fy) / dy
+	}
+	for x != tx || y != ty {
+		if nextX < nextY {
+			if nextX > 1 {
+				break
+			}
+			x += stepX
+			nextX += deltaX
+		} else {
+			if nextY > 1 {
+				break
+			}
+			y += stepY
+			nextY += deltaY
+		}
+		if (x != tx || y != ty) && self.Occluder(x, y) {
+			return false
+		}
+	}
+	return true
+}
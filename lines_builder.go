@@ -4,6 +4,7 @@ import (
 	"image/color"
 	"math"
 
+	"github.com/edwinsyarief/katsu2d/colors"
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
@@ -50,6 +51,14 @@ type LineBuilder struct {
 	closed bool
 	// interpolateColor is a flag to enable color interpolation if multiple colors are provided.
 	interpolateColor bool
+	// feather is the width of the anti-aliasing rim along the line's sides (0 disables it).
+	feather float64
+	// gradientStart and gradientEnd place the built points on the width and
+	// color gradients of a longer path, such as one dash of a dashed line.
+	gradientStart, gradientEnd float64
+	// gradientClosed wraps the gradients back to their first stop, like a
+	// closed line, even when the built points are open.
+	gradientClosed bool
 }
 
 // NewLineBuilder is the constructor for a LineBuilder.
@@ -61,6 +70,7 @@ func NewLineBuilder() *LineBuilder {
 		tileAspect:     1.0,
 		width:          10.0,
 		defaultColor:   color.RGBA{R: 102, G: 128, B: 255, A: 255},
+		gradientEnd:    1,
 	}
 }
 
@@ -158,6 +168,11 @@ func (self *LineBuilder) Build() {
 		// Add two triangles to form the rectangular line segment.
 		self.addTriangle(vA1, vA2, vB1, colorA, colorA, colorB)
 		self.addTriangle(vA2, vB2, vB1, colorA, colorB, colorB)
+		if self.feather > 0 {
+			rim := normal.ScaleF(self.feather)
+			self.vertices, self.indices = appendFeatherQuad(self.vertices, self.indices, vA1, vB1, vA1.Add(rim), vB1.Add(rim), colorA, colorB)
+			self.vertices, self.indices = appendFeatherQuad(self.vertices, self.indices, vA2, vB2, vA2.Sub(rim), vB2.Sub(rim), colorA, colorB)
+		}
 		// --- Joint ---
 		// Handles the geometry for the connection between line segments.
 		if !isClosed && i >= n-2 {
@@ -269,24 +284,8 @@ func (self *LineBuilder) Build() {
 }
 
 // addTriangle appends three vertices and their corresponding indices to the builder's slices.
-// The indices are generated sequentially.
 func (self *LineBuilder) addTriangle(v1, v2, v3 Vector, c1, c2, c3 color.RGBA) {
-	idx := uint16(len(self.vertices))
-	// Convert RGBA to normalized float32 for Ebiten vertices.
-	r1, g1, b1, a1 := c1.R, c1.G, c1.B, c1.A
-	cr1, cg1, cb1, ca1 := float32(r1)/255, float32(g1)/255, float32(b1)/255, float32(a1)/255
-	r2, g2, b2, a2 := c2.R, c2.G, c2.B, c2.A
-	cr2, cg2, cb2, ca2 := float32(r2)/255, float32(g2)/255, float32(b2)/255, float32(a2)/255
-	r3, g3, b3, a3 := c3.R, c3.G, c3.B, c3.A
-	cr3, cg3, cb3, ca3 := float32(r3)/255, float32(g3)/255, float32(b3)/255, float32(a3)/255
-	// Append the new vertices.
-	self.vertices = append(self.vertices,
-		ebiten.Vertex{DstX: float32(v1.X), DstY: float32(v1.Y), ColorR: cr1, ColorG: cg1, ColorB: cb1, ColorA: ca1},
-		ebiten.Vertex{DstX: float32(v2.X), DstY: float32(v2.Y), ColorR: cr2, ColorG: cg2, ColorB: cb2, ColorA: ca2},
-		ebiten.Vertex{DstX: float32(v3.X), DstY: float32(v3.Y), ColorR: cr3, ColorG: cg3, ColorB: cb3, ColorA: ca3},
-	)
-	// Append the indices for the new triangle.
-	self.indices = append(self.indices, idx, idx+1, idx+2)
+	self.vertices, self.indices = appendMeshTriangle(self.vertices, self.indices, v1, v2, v3, c1, c2, c3)
 }
 
 // lerpWidth performs linear interpolation on the widths slice.
@@ -296,11 +295,11 @@ func (self *LineBuilder) lerpWidth(t float64) float64 {
 		return 1.0 // Return default scaling if not enough widths provided.
 	}
 	widths := self.widths
-	if self.closed {
-		widths = append(widths, self.widths[0])
+	if self.closed || self.gradientClosed {
+		widths = append(widths[:len(widths):len(widths)], self.widths[0])
 	}
 	// Calculate the index and local interpolation value.
-	pos := t * float64(len(widths)-1)
+	pos := self.gradientT(t) * float64(len(widths)-1)
 	idx1 := int(pos)
 	idx2 := idx1 + 1
 	if idx2 >= len(widths) {
@@ -317,34 +316,11 @@ func (self *LineBuilder) lerpWidth(t float64) float64 {
 // newArc generates the vertices and indices for a circular arc.
 // It is used for creating round caps and joints.
 func (self *LineBuilder) newArc(center, vbegin Vector, angleDelta float64, clr color.RGBA, uvRect Rect) {
-	radius := vbegin.Length()
-	angleStep := math.Pi / float64(self.roundPrecision)
-	steps := int(math.Abs(angleDelta) / angleStep)
-	if angleDelta < 0 {
-		angleStep = -angleStep
-	}
-	t := vbegin.Angle()
-	r, g, b, a := clr.R, clr.G, clr.B, clr.A
-	cr, cg, cb, ca := float32(r)/255, float32(g)/255, float32(b)/255, float32(a)/255
-	vi := len(self.vertices)
-	// Add the center vertex for the arc fan.
-	self.vertices = append(self.vertices, ebiten.Vertex{DstX: float32(center.X), DstY: float32(center.Y), ColorR: cr, ColorG: cg, ColorB: cb, ColorA: ca})
 	if self.textureMode != LineTextureNone {
 		self.uvs = append(self.uvs, interpolate(uvRect, V(0.5, 0.5)))
 	}
-	// Generate vertices along the arc.
-	for i := 0; i <= steps; i++ {
-		angle := t + angleStep*float64(i)
-		if i == steps {
-			angle = t + angleDelta
-		}
-		rpos := center.Add(V(math.Cos(angle), math.Sin(angle)).ScaleF(radius))
-		self.vertices = append(self.vertices, ebiten.Vertex{DstX: float32(rpos.X), DstY: float32(rpos.Y), ColorR: cr, ColorG: cg, ColorB: cb, ColorA: ca})
-	}
-	// Add indices to form triangles for the arc fan.
-	for i := 0; i < steps; i++ {
-		self.indices = append(self.indices, uint16(vi), uint16(vi+i+1), uint16(vi+i+2))
-	}
+	self.vertices, self.indices = appendMeshArc(self.vertices, self.indices,
+		center, vbegin, angleDelta, math.Pi/float64(self.roundPrecision), clr)
 }
 
 // lerpColor performs linear interpolation on the colors slice.
@@ -359,25 +335,16 @@ func (self *LineBuilder) lerpColor(t float64) color.RGBA {
 	if len(self.colors) == 1 {
 		return self.colors[0]
 	}
-	colors := self.colors
-	if self.closed {
-		colors = append(colors, self.colors[0])
-	}
-	// Calculate the segment and local interpolation value.
-	pos := t * float64(len(colors)-1)
-	segment := int(pos)
-	tInSegment := pos - float64(segment)
-	// Clamp the segment index to the valid range.
-	if segment >= len(colors)-1 {
-		segment = len(colors) - 2
-		tInSegment = 1.0
-	}
-	if segment < 0 {
-		segment = 0
-		tInSegment = 0.0
+	stops := colors.Palette(self.colors)
+	if self.closed || self.gradientClosed {
+		stops = append(stops[:len(stops):len(stops)], self.colors[0])
 	}
-	// Use a utility function to perform the actual color interpolation.
-	return LerpPremultipliedRGBA(colors[segment], colors[segment+1], tInSegment)
+	return stops.At(self.gradientT(t))
+}
+
+// gradientT maps t along the built points to the gradients' range.
+func (self *LineBuilder) gradientT(t float64) float64 {
+	return self.gradientStart + (self.gradientEnd-self.gradientStart)*t
 }
 
 // interpolate performs linear interpolation between the corners of a rectangle.
@@ -4,6 +4,7 @@ import (
 	"image/color"
 	"math"
 
+	"github.com/edwinsyarief/katsu2d/colors"
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
@@ -63,6 +64,8 @@ type GridLine struct {
 	bottomRightColor color.RGBA
 	// isColorInterpolated determines if color interpolation is enabled.
 	isColorInterpolated bool
+	// feather is the width of the anti-aliasing rim along each line (0 disables it).
+	feather float64
 }
 
 // NewGridLine creates a new grid with the specified dimensions and properties.
@@ -322,6 +325,14 @@ func (self *GridLine) addLine(p1, p2, center Vector) {
 	)
 	// Append the six indices to form two triangles from the four vertices.
 	self.indices = append(self.indices, idx, idx+1, idx+2, idx+1, idx+3, idx+2)
+	// Fade both sides out to transparent for anti-aliasing.
+	if self.feather > 0 {
+		rim := normal.ScaleF(self.feather)
+		self.vertices, self.indices = appendFeatherQuad(self.vertices, self.indices,
+			v1, v3, self.transformPos(lv1.Add(rim), center), self.transformPos(lv3.Add(rim), center), c1, c3)
+		self.vertices, self.indices = appendFeatherQuad(self.vertices, self.indices,
+			v2, v4, self.transformPos(lv2.Sub(rim), center), self.transformPos(lv4.Sub(rim), center), c2, c4)
+	}
 }
 
 // getColor returns the color for a given position on the grid.
@@ -345,10 +356,10 @@ func (self *GridLine) calculateInterpolatedColor(pos Vector, totalWidth, totalHe
 	tx = math.Max(0, math.Min(1, tx))
 	ty = math.Max(0, math.Min(1, ty))
 	// Perform linear interpolation horizontally (top colors and bottom colors).
-	topColor := LerpPremultipliedRGBA(self.topLeftColor, self.topRightColor, tx)
-	bottomColor := LerpPremultipliedRGBA(self.bottomLeftColor, self.bottomRightColor, tx)
+	topColor := colors.Lerp(self.topLeftColor, self.topRightColor, tx)
+	bottomColor := colors.Lerp(self.bottomLeftColor, self.bottomRightColor, tx)
 	// Perform linear interpolation vertically on the results to get the final color.
-	finalColor := LerpPremultipliedRGBA(topColor, bottomColor, ty)
+	finalColor := colors.Lerp(topColor, bottomColor, ty)
 	return finalColor
 }
 
@@ -391,6 +402,13 @@ func (self *GridLine) InterpolateColor(topLeft, topRight, bottomLeft, bottomRigh
 	self.buildMesh()
 }
 
+// SetFeather sets the width of the anti-aliasing rim along each grid line.
+// This triggers a mesh rebuild; a value of 0 disables feathering.
+func (self *GridLine) SetFeather(feather float64) {
+	self.feather = feather
+	self.buildMesh()
+}
+
 // SetCornerJoin sets the join types for the four corners of the grid.
 // This triggers a mesh rebuild to apply the new corner styles.
 func (self *GridLine) SetCornerJoin(tl, tr, bl, br GridCornerJoinType) {
@@ -42,6 +42,9 @@ type Line struct {
 	isClosed        bool       // Whether the line forms a closed loop
 	catmullRom      bool       // If true, the line will be smoothed using a Catmull-Rom spline
 	debugDraw       bool       // If true, draws debug points and lines to visualize the mesh
+	feather         float64    // Width of the anti-aliasing rim along the sides (0 = disabled)
+	dashPattern     []float64  // Alternating on/off lengths (empty = solid)
+	dashOffset      float64    // Distance to shift the dash pattern along the line
 }
 
 // NewLine creates a new Line instance with default settings.
@@ -72,6 +75,15 @@ func NewLine() *Line {
 	return line
 }
 
+// Texture returns the image the line mesh samples from, or nil when the line
+// is untextured and should use the default white texture.
+func (self *Line) Texture() *ebiten.Image {
+	if self.textureMode != LineTextureNone {
+		return self.texture
+	}
+	return nil
+}
+
 // GetMesh returns the current vertex and index data for the line's mesh.
 func (self *Line) GetMesh() ([]ebiten.Vertex, []uint16) {
 	return self.vertices, self.indices
@@ -182,6 +194,21 @@ func (self *Line) EnableCatmullRom(enable bool, precision float64) {
 	self.splinePrecision = precision
 }
 
+// SetFeather sets the width of the anti-aliasing rim along the line's sides.
+// A value of 0.0 disables feathering.
+func (self *Line) SetFeather(feather float64) {
+	self.isDirty = true
+	self.feather = feather
+}
+
+// SetDashPattern splits the line into dashes using alternating on/off lengths,
+// shifted along the line by offset. An empty pattern draws a solid line.
+func (self *Line) SetDashPattern(pattern []float64, offset float64) {
+	self.isDirty = true
+	self.dashPattern = pattern
+	self.dashOffset = offset
+}
+
 // SetDebugDraw enables or disables the drawing of debug points and lines to visualize the mesh.
 func (self *Line) SetDebugDraw(enable bool) {
 	self.debugDraw = enable
@@ -198,16 +225,45 @@ func (self *Line) BuildMesh() {
 	// Process points based on Catmull-Rom and resampling settings.
 	pointsToProcess := self.points
 	if self.catmullRom && len(self.points) >= 4 {
-		pointsToProcess = self.generateSplinePoints(self.points, int(self.splinePrecision))
+		pointsToProcess = catmullRomPath(self.points, int(self.splinePrecision))
 	}
 	self.debugPoints = pointsToProcess
 	if self.stepDistance > 0 && len(pointsToProcess) > 1 {
-		self.debugPoints = self.resamplePoints(pointsToProcess, self.stepDistance)
+		self.debugPoints = resamplePath(pointsToProcess, self.stepDistance)
+	}
+	// Dashed lines build one open mesh per dash and merge them. Each dash
+	// samples the width and color gradients at its place on the whole line.
+	if len(self.dashPattern) > 0 {
+		dashes, length := dashPath(self.debugPoints, self.isClosed, self.dashPattern, self.dashOffset)
+		for _, dash := range dashes {
+			builder := self.newBuilder(dash.points, false)
+			if length > 0 {
+				builder.gradientStart, builder.gradientEnd = dash.start/length, dash.end/length
+			}
+			builder.gradientClosed = self.isClosed
+			builder.Build()
+			base := uint16(len(self.vertices))
+			self.vertices = append(self.vertices, builder.vertices...)
+			for _, i := range builder.indices {
+				self.indices = append(self.indices, base+i)
+			}
+		}
+		self.isDirty = false
+		return
 	}
 	// Use a LineBuilder to construct the mesh from the final set of points.
+	builder := self.newBuilder(self.debugPoints, self.isClosed)
+	builder.Build()
+	self.vertices = builder.vertices
+	self.indices = builder.indices
+	self.isDirty = false
+}
+
+// newBuilder creates a LineBuilder configured with the line's styling.
+func (self *Line) newBuilder(points []Vector, closed bool) *LineBuilder {
 	builder := NewLineBuilder()
-	builder.points = self.debugPoints
-	builder.closed = self.isClosed
+	builder.points = points
+	builder.closed = closed
 	builder.width = self.width
 	builder.widths = self.widths
 	builder.defaultColor = self.defaultColor
@@ -219,10 +275,8 @@ func (self *Line) BuildMesh() {
 	builder.roundPrecision = self.roundPrecision
 	builder.textureMode = self.textureMode
 	builder.tileAspect = self.tileAspect
-	builder.Build()
-	self.vertices = builder.vertices
-	self.indices = builder.indices
-	self.isDirty = false
+	builder.feather = self.feather
+	return builder
 }
 
 // Draw renders the line to the specified screen using the provided options.
@@ -314,61 +368,3 @@ func (self *Line) resetMesh() {
 	self.indices = self.indices[:0]
 	self.isDirty = true
 }
-
-// generateSplinePoints creates a new set of points from the original points
-// by interpolating a smooth Catmull-Rom spline.
-func (self *Line) generateSplinePoints(points []Vector, precision int) []Vector {
-	if len(points) < 2 {
-		return points
-	}
-	newPoints := make([]Vector, 0)
-	newPoints = append(newPoints, points[0])
-	for i := 0; i < len(points)-1; i++ {
-		p0 := points[i]
-		if i > 0 {
-			p0 = points[i-1]
-		}
-		p1 := points[i]
-		p2 := points[i+1]
-		p3 := points[i+1]
-		if i < len(points)-2 {
-			p3 = points[i+2]
-		}
-		for j := 1; j <= precision; j++ {
-			t := float64(j) / float64(precision)
-			newPos := catmullRomPoint(p0, p1, p2, p3, t)
-			newPoints = append(newPoints, newPos)
-		}
-	}
-	return newPoints
-}
-
-// resamplePoints creates a new slice of points with a uniform distance between them.
-// This is useful for effects like dashed lines or uniform texture tiling.
-func (self *Line) resamplePoints(points []Vector, step float64) []Vector {
-	if len(points) < 2 || step <= 0 {
-		return points
-	}
-	newPoints := make([]Vector, 0, len(points))
-	newPoints = append(newPoints, points[0])
-	distanceNeeded := step
-	for i := 0; i < len(points)-1; i++ {
-		p1 := points[i]
-		p2 := points[i+1]
-		segmentVec := p2.Sub(p1)
-		segmentLen := segmentVec.Length()
-		if segmentLen < 1e-6 {
-			continue
-		}
-		for segmentLen >= distanceNeeded {
-			t := distanceNeeded / segmentLen
-			newPos := p1.Lerp(p2, t)
-			newPoints = append(newPoints, newPos)
-			segmentLen -= distanceNeeded
-			p1 = newPoints[len(newPoints)-1]
-			distanceNeeded = step
-		}
-		distanceNeeded -= segmentLen
-	}
-	return newPoints
-}
@@ -0,0 +1,208 @@
+package katsu2d
+
+import "math"
+
+// PolylineHit describes the closest point found on a polyline.
+type PolylineHit struct {
+	Point    Vector  // Closest point on the path
+	Segment  int     // Index of the segment's first point
+	Distance float64 // Distance from the query point to Point
+	Along    float64 // Distance travelled along the path to reach Point
+}
+
+// polylineSegments returns the number of segments in a path.
+func polylineSegments(points []Vector, closed bool) int {
+	if len(points) < 2 {
+		return 0
+	}
+	if closed {
+		return len(points)
+	}
+	return len(points) - 1
+}
+
+// polylineSegment returns the endpoints of segment i.
+func polylineSegment(points []Vector, i int) (Vector, Vector) {
+	return points[i], points[(i+1)%len(points)]
+}
+
+// ClosestPointOnSegment returns the point on segment ab closest to p.
+func ClosestPointOnSegment(p, a, b Vector) Vector {
+	ab := b.Sub(a)
+	lenSq := ab.LengthSquared()
+	if lenSq == 0 {
+		return a
+	}
+	t := Clamp(p.Sub(a).Dot(ab)/lenSq, 0, 1)
+	return a.Add(ab.ScaleF(t))
+}
+
+// PointInPolygon reports whether p lies inside the closed polygon, using
+// the even-odd rule.
+func PointInPolygon(p Vector, polygon []Vector) bool {
+	inside := false
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		a, b := polygon[i], polygon[j]
+		if (a.Y > p.Y) != (b.Y > p.Y) && p.X < (b.X-a.X)*(p.Y-a.Y)/(b.Y-a.Y)+a.X {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// PolylineLength returns the total length of a path.
+func PolylineLength(points []Vector, closed bool) float64 {
+	length := 0.0
+	for i := 0; i < polylineSegments(points, closed); i++ {
+		a, b := polylineSegment(points, i)
+		length += a.DistanceTo(b)
+	}
+	return length
+}
+
+// ClosestPointOnPolyline finds the point on a path closest to p.
+// It returns false if the path has fewer than two points.
+func ClosestPointOnPolyline(points []Vector, closed bool, p Vector) (PolylineHit, bool) {
+	best := PolylineHit{Distance: math.Inf(1)}
+	along := 0.0
+	found := false
+	for i := 0; i < polylineSegments(points, closed); i++ {
+		a, b := polylineSegment(points, i)
+		q := ClosestPointOnSegment(p, a, b)
+		if d := q.DistanceTo(p); d < best.Distance {
+			best = PolylineHit{Point: q, Segment: i, Distance: d, Along: along + a.DistanceTo(q)}
+			found = true
+		}
+		along += a.DistanceTo(b)
+	}
+	return best, found
+}
+
+// DistanceToPolyline returns the shortest distance from p to a path,
+// or +Inf if the path has fewer than two points.
+func DistanceToPolyline(points []Vector, closed bool, p Vector) float64 {
+	hit, _ := ClosestPointOnPolyline(points, closed, p)
+	return hit.Distance
+}
+
+// PointAtDistance returns the position and unit tangent at the given distance
+// along a path. Distances are clamped to the path, or wrapped if it is closed.
+func PointAtDistance(points []Vector, closed bool, distance float64) (Vector, Vector) {
+	if len(points) == 0 {
+		return Vector{}, Vector{}
+	}
+	total := PolylineLength(points, closed)
+	if len(points) < 2 || total == 0 {
+		return points[0], Vector{}
+	}
+	if closed {
+		distance = Repeat(distance, total)
+	} else {
+		distance = Clamp(distance, 0, total)
+	}
+	segments := polylineSegments(points, closed)
+	for i := 0; i < segments; i++ {
+		a, b := polylineSegment(points, i)
+		segLen := a.DistanceTo(b)
+		if segLen == 0 {
+			continue
+		}
+		if distance <= segLen || i == segments-1 {
+			t := Clamp(distance/segLen, 0, 1)
+			return a.Lerp(b, t), b.Sub(a).Normalize()
+		}
+		distance -= segLen
+	}
+	a, b := polylineSegment(points, segments-1)
+	return b, b.Sub(a).Normalize()
+}
+
+// SegmentIntersection returns the intersection point of segments a1a2 and b1b2.
+// Parallel or non-overlapping segments report false.
+func SegmentIntersection(a1, a2, b1, b2 Vector) (Vector, bool) {
+	r := a2.Sub(a1)
+	s := b2.Sub(b1)
+	denom := r.Cross(s)
+	if math.Abs(denom) < 1e-12 {
+		return Vector{}, false
+	}
+	qp := b1.Sub(a1)
+	t := qp.Cross(s) / denom
+	u := qp.Cross(r) / denom
+	if t < 0 || t > 1 || u < 0 || u > 1 {
+		return Vector{}, false
+	}
+	return a1.Add(r.ScaleF(t)), true
+}
+
+// PolylineIntersections returns every point where segment ab crosses a path,
+// in path order.
+func PolylineIntersections(points []Vector, closed bool, a, b Vector) []Vector {
+	var hits []Vector
+	for i := 0; i < polylineSegments(points, closed); i++ {
+		p1, p2 := polylineSegment(points, i)
+		if hit, ok := SegmentIntersection(p1, p2, a, b); ok {
+			hits = append(hits, hit)
+		}
+	}
+	return hits
+}
+
+// Path returns the processed points the line mesh is built from, after
+// spline smoothing and resampling, falling back to the raw points.
+func (self *Line) Path() []Vector {
+	if len(self.points) < 2 {
+		return self.points
+	}
+	self.BuildMesh()
+	if len(self.debugPoints) > 0 {
+		return self.debugPoints
+	}
+	return self.points
+}
+
+// Length returns the length of the line's path.
+func (self *Line) Length() float64 {
+	return PolylineLength(self.Path(), self.isClosed)
+}
+
+// ClosestPoint finds the point on the line's path closest to p.
+func (self *Line) ClosestPoint(p Vector) (PolylineHit, bool) {
+	return ClosestPointOnPolyline(self.Path(), self.isClosed, p)
+}
+
+// DistanceTo returns the shortest distance from p to the line's path.
+func (self *Line) DistanceTo(p Vector) float64 {
+	return DistanceToPolyline(self.Path(), self.isClosed, p)
+}
+
+// PointAtDistance returns the position and unit tangent at a distance along the line.
+func (self *Line) PointAtDistance(distance float64) (Vector, Vector) {
+	return PointAtDistance(self.Path(), self.isClosed, distance)
+}
+
+// Intersections returns the points where segment ab crosses the line's path.
+func (self *Line) Intersections(a, b Vector) []Vector {
+	return PolylineIntersections(self.Path(), self.isClosed, a, b)
+}
+
+// Path returns the builder's input points.
+func (self *LineBuilder) Path() []Vector {
+	return self.points
+}
+
+// SetPath replaces the builder's input points.
+func (self *LineBuilder) SetPath(points []Vector, closed bool) {
+	self.points = points
+	self.closed = closed
+}
+
+// ClosestPoint finds the point on the builder's path closest to p.
+func (self *LineBuilder) ClosestPoint(p Vector) (PolylineHit, bool) {
+	return ClosestPointOnPolyline(self.points, self.closed, p)
+}
+
+// PointAtDistance returns the position and unit tangent at a distance along the builder's path.
+func (self *LineBuilder) PointAtDistance(distance float64) (Vector, Vector) {
+	return PointAtDistance(self.points, self.closed, distance)
+}
@@ -379,45 +379,13 @@ func (self *RibbonTrails) Draw(screen *ebiten.Image, op *ebiten.DrawTrianglesOpt
 
 // addTriangle adds a single triangle to the vertex and index buffers.
 func (self *RibbonTrails) addTriangle(v1, v2, v3 Vector, c1, c2, c3 color.RGBA) {
-	idx := uint16(len(self.vertices))
-	r1, g1, b1, a1 := c1.R, c1.G, c1.B, c1.A
-	cr1, cg1, cb1, ca1 := float32(r1)/255, float32(g1)/255, float32(b1)/255, float32(a1)/255
-	r2, g2, b2, a2 := c2.R, c2.G, c2.B, c2.A
-	cr2, cg2, cb2, ca2 := float32(r2)/255, float32(g2)/255, float32(b2)/255, float32(a2)/255
-	r3, g3, b3, a3 := c3.R, c3.G, c3.B, c3.A
-	cr3, cg3, cb3, ca3 := float32(r3)/255, float32(g3)/255, float32(b3)/255, float32(a3)/255
-	self.vertices = append(self.vertices,
-		ebiten.Vertex{DstX: float32(v1.X), DstY: float32(v1.Y), ColorR: cr1, ColorG: cg1, ColorB: cb1, ColorA: ca1},
-		ebiten.Vertex{DstX: float32(v2.X), DstY: float32(v2.Y), ColorR: cr2, ColorG: cg2, ColorB: cb2, ColorA: ca2},
-		ebiten.Vertex{DstX: float32(v3.X), DstY: float32(v3.Y), ColorR: cr3, ColorG: cg3, ColorB: cb3, ColorA: ca3},
-	)
-	self.indices = append(self.indices, idx, idx+1, idx+2)
+	self.vertices, self.indices = appendMeshTriangle(self.vertices, self.indices, v1, v2, v3, c1, c2, c3)
 }
 
 // addArc creates a series of triangles to form a rounded arc at a joint.
 func (self *RibbonTrails) addArc(center, vbegin Vector, angleDelta float64, clr color.RGBA) {
-	radius := vbegin.Length()
-	angleStep := math.Pi / float64(self.roundPrecision)
-	steps := int(math.Abs(angleDelta) / angleStep)
-	if angleDelta < 0 {
-		angleStep = -angleStep
-	}
-	t := vbegin.Angle()
-	r, g, b, a := clr.R, clr.G, clr.B, clr.A
-	cr, cg, cb, ca := float32(r)/255, float32(g)/255, float32(b)/255, float32(a)/255
-	vi := len(self.vertices)
-	self.vertices = append(self.vertices, ebiten.Vertex{DstX: float32(center.X), DstY: float32(center.Y), ColorR: cr, ColorG: cg, ColorB: cb, ColorA: ca})
-	for i := 0; i <= steps; i++ {
-		angle := t + angleStep*float64(i)
-		if i == steps {
-			angle = t + angleDelta
-		}
-		rpos := center.Add(V(math.Cos(angle), math.Sin(angle)).ScaleF(radius))
-		self.vertices = append(self.vertices, ebiten.Vertex{DstX: float32(rpos.X), DstY: float32(rpos.Y), ColorR: cr, ColorG: cg, ColorB: cb, ColorA: ca})
-	}
-	for i := 0; i < steps; i++ {
-		self.indices = append(self.indices, uint16(vi), uint16(vi+i+1), uint16(vi+i+2))
-	}
+	self.vertices, self.indices = appendMeshArc(self.vertices, self.indices,
+		center, vbegin, angleDelta, math.Pi/float64(self.roundPrecision), clr)
 }
 
 // interpolateRibbonWidth linearly interpolates between widths based on a progress value (0.0 to 1.0).
@@ -1,5 +1,13 @@
 package katsu2d
 
+import (
+	"image/color"
+	"math"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
 func catmullRomPoint(p0, p1, p2, p3 Vector, t float64) Vector {
 	t2 := t * t
 	t3 := t2 * t
@@ -11,3 +19,206 @@ func catmullRomPoint(p0, p1, p2, p3 Vector, t float64) Vector {
 
 	return c1.Add(c2).Add(c3).Add(c4).ScaleF(0.5)
 }
+
+// appendMeshTriangle appends a single colored triangle to a mesh.
+// It is shared by every line mesh builder so their output stays consistent.
+func appendMeshTriangle(vertices []ebiten.Vertex, indices []uint16, v1, v2, v3 Vector, c1, c2, c3 color.RGBA) ([]ebiten.Vertex, []uint16) {
+	idx := uint16(len(vertices))
+	vertices = append(vertices,
+		meshVertex(v1, c1),
+		meshVertex(v2, c2),
+		meshVertex(v3, c3),
+	)
+	indices = append(indices, idx, idx+1, idx+2)
+	return vertices, indices
+}
+
+// appendMeshArc appends a triangle fan approximating a circular arc, used
+// for round caps and joints. angleStep is the angle covered by each segment.
+func appendMeshArc(vertices []ebiten.Vertex, indices []uint16, center, vbegin Vector, angleDelta, angleStep float64, clr color.RGBA) ([]ebiten.Vertex, []uint16) {
+	radius := vbegin.Length()
+	steps := int(math.Abs(angleDelta) / angleStep)
+	if angleDelta < 0 {
+		angleStep = -angleStep
+	}
+	t := vbegin.Angle()
+	vi := len(vertices)
+	// Add the center vertex for the arc fan.
+	vertices = append(vertices, meshVertex(center, clr))
+	// Generate vertices along the arc.
+	for i := 0; i <= steps; i++ {
+		angle := t + angleStep*float64(i)
+		if i == steps {
+			angle = t + angleDelta
+		}
+		rpos := center.Add(V(math.Cos(angle), math.Sin(angle)).ScaleF(radius))
+		vertices = append(vertices, meshVertex(rpos, clr))
+	}
+	// Add indices to form triangles for the arc fan.
+	for i := 0; i < steps; i++ {
+		indices = append(indices, uint16(vi), uint16(vi+i+1), uint16(vi+i+2))
+	}
+	return vertices, indices
+}
+
+// appendFeatherQuad appends a strip from the edge a-b out to the rim ra-rb,
+// fading from the edge colors to transparent for anti-aliasing.
+func appendFeatherQuad(vertices []ebiten.Vertex, indices []uint16, a, b, ra, rb Vector, ca, cb color.RGBA) ([]ebiten.Vertex, []uint16) {
+	idx := uint16(len(vertices))
+	vertices = append(vertices,
+		meshVertex(a, ca),
+		meshVertex(b, cb),
+		meshVertex(ra, color.RGBA{}),
+		meshVertex(rb, color.RGBA{}),
+	)
+	indices = append(indices, idx, idx+2, idx+1, idx+1, idx+2, idx+3)
+	return vertices, indices
+}
+
+func meshVertex(pos Vector, clr color.RGBA) ebiten.Vertex {
+	return ebiten.Vertex{
+		DstX:   float32(pos.X),
+		DstY:   float32(pos.Y),
+		ColorR: float32(clr.R) / 255,
+		ColorG: float32(clr.G) / 255,
+		ColorB: float32(clr.B) / 255,
+		ColorA: float32(clr.A) / 255,
+	}
+}
+
+// catmullRomPath creates a new set of points by interpolating a smooth
+// Catmull-Rom spline through the given points.
+func catmullRomPath(points []Vector, precision int) []Vector {
+	if len(points) < 2 {
+		return points
+	}
+	newPoints := make([]Vector, 0)
+	newPoints = append(newPoints, points[0])
+	for i := 0; i < len(points)-1; i++ {
+		p0 := points[i]
+		if i > 0 {
+			p0 = points[i-1]
+		}
+		p1 := points[i]
+		p2 := points[i+1]
+		p3 := points[i+1]
+		if i < len(points)-2 {
+			p3 = points[i+2]
+		}
+		for j := 1; j <= precision; j++ {
+			t := float64(j) / float64(precision)
+			newPoints = append(newPoints, catmullRomPoint(p0, p1, p2, p3, t))
+		}
+	}
+	return newPoints
+}
+
+// resamplePath creates a new slice of points with a uniform distance between them.
+func resamplePath(points []Vector, step float64) []Vector {
+	if len(points) < 2 || step <= 0 {
+		return points
+	}
+	newPoints := make([]Vector, 0, len(points))
+	newPoints = append(newPoints, points[0])
+	distanceNeeded := step
+	for i := 0; i < len(points)-1; i++ {
+		p1 := points[i]
+		p2 := points[i+1]
+		segmentVec := p2.Sub(p1)
+		segmentLen := segmentVec.Length()
+		if segmentLen < 1e-6 {
+			continue
+		}
+		for segmentLen >= distanceNeeded {
+			t := distanceNeeded / segmentLen
+			newPos := p1.Lerp(p2, t)
+			newPoints = append(newPoints, newPos)
+			segmentLen -= distanceNeeded
+			p1 = newPoints[len(newPoints)-1]
+			distanceNeeded = step
+		}
+		distanceNeeded -= segmentLen
+	}
+	return newPoints
+}
+
+// dashIntervals returns the "on" spans of a dash pattern that fall within
+// [start, end], where both are distances along a path. The pattern alternates
+// on and off lengths and is shifted by offset. An empty pattern is solid.
+func dashIntervals(start, end float64, pattern []float64, offset float64) [][2]float64 {
+	period := 0.0
+	for _, d := range pattern {
+		period += math.Max(d, 0)
+	}
+	if len(pattern) == 0 || period <= 0 {
+		return [][2]float64{{start, end}}
+	}
+	// Odd patterns repeat twice so on and off alternate consistently.
+	if len(pattern)%2 == 1 {
+		pattern = append(slices.Clone(pattern), pattern...)
+		period *= 2
+	}
+	var spans [][2]float64
+	// Find the dash that contains start.
+	pos := Repeat(start+offset, period)
+	idx := 0
+	for pos >= math.Max(pattern[idx], 0) && idx < len(pattern)-1 {
+		pos -= math.Max(pattern[idx], 0)
+		idx++
+	}
+	cursor := start
+	remaining := math.Max(pattern[idx], 0) - pos
+	for cursor < end {
+		next := math.Min(cursor+remaining, end)
+		if idx%2 == 0 && next > cursor {
+			spans = append(spans, [2]float64{cursor, next})
+		}
+		cursor = next
+		idx = (idx + 1) % len(pattern)
+		remaining = math.Max(pattern[idx], 0)
+	}
+	return spans
+}
+
+// pathDash is one dash of a dashed path, with the distances along the
+// whole path where it starts and ends.
+type pathDash struct {
+	points     []Vector
+	start, end float64
+}
+
+// dashPath splits a path into the sub-paths covered by a dash pattern and
+// returns them with the length of the whole path.
+func dashPath(points []Vector, closed bool, pattern []float64, offset float64) ([]pathDash, float64) {
+	if closed && len(points) > 2 {
+		points = append(slices.Clone(points), points[0])
+	}
+	var dashes []pathDash
+	var current pathDash
+	along := 0.0
+	for i := 0; i < len(points)-1; i++ {
+		a, b := points[i], points[i+1]
+		segLen := a.DistanceTo(b)
+		if segLen < 1e-9 {
+			continue
+		}
+		for _, span := range dashIntervals(along, along+segLen, pattern, offset) {
+			p0 := a.Lerp(b, (span[0]-along)/segLen)
+			p1 := a.Lerp(b, (span[1]-along)/segLen)
+			if n := len(current.points); n > 0 && current.points[n-1].DistanceTo(p0) < 1e-6 {
+				current.points = append(current.points, p1)
+				current.end = span[1]
+				continue
+			}
+			if len(current.points) > 1 {
+				dashes = append(dashes, current)
+			}
+			current = pathDash{points: []Vector{p0, p1}, start: span[0], end: span[1]}
+		}
+		along += segLen
+	}
+	if len(current.points) > 1 {
+		dashes = append(dashes, current)
+	}
+	return dashes, along
+}
@@ -1,5 +1,51 @@
 package logger
 
+import (
+	"strings"
+	"sync"
+)
+
+// historySize is the number of recent log lines kept for crash reports.
+const historySize = 256
+
+// lineHistory keeps the most recent log lines in a ring buffer.
+type lineHistory struct {
+	mu    sync.Mutex
+	lines [historySize]string
+	next  int
+	count int
+}
+
+var history lineHistory
+
+// Write records each line of p, so the history can sit behind log.SetOutput.
+func (self *lineHistory) Write(p []byte) (int, error) {
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		self.add(line)
+	}
+	return len(p), nil
+}
+
+func (self *lineHistory) add(line string) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.lines[self.next] = line
+	self.next = (self.next + 1) % historySize
+	self.count = min(self.count+1, historySize)
+}
+
+// Recent returns up to n of the most recent log lines, oldest first.
+func Recent(n int) []string {
+	history.mu.Lock()
+	defer history.mu.Unlock()
+	n = min(n, history.count)
+	out := make([]string, 0, n)
+	for i := n; i > 0; i-- {
+		out = append(out, history.lines[(history.next-i+historySize)%historySize])
+	}
+	return out
+}
+
 type Logger struct {
 	isDebug bool
 }
@@ -13,6 +59,10 @@ func (self *Logger) Error(format string, v ...any) {
 	self.log("ERROR", format, v...)
 }
 
+func (self *Logger) Warn(format string, v ...any) {
+	self.log("WARN", format, v...)
+}
+
 func (self *Logger) Info(format string, v ...any) {
 	if self.isDebug {
 		self.log("INFO", format, v...)
@@ -5,6 +5,7 @@ package logger
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
@@ -22,6 +23,7 @@ func init() {
 	once.Do(func() {
 		logger, err := initLogger()
 		if err != nil {
+			log.SetOutput(io.MultiWriter(os.Stderr, &history))
 			log.Printf("Failed to initialize file logger: %v. Falling back to stdout.", err)
 			instance = &Logger{}
 		} else {
@@ -47,7 +49,7 @@ func initLogger() (*Logger, error) {
 		return nil, fmt.Errorf("failed to open log file: %v", err)
 	}
 
-	log.SetOutput(file)
+	log.SetOutput(io.MultiWriter(file, &history))
 	return &Logger{
 		isDebug: false,
 	}, nil
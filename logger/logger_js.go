@@ -24,6 +24,7 @@ func GetLogger() *Logger {
 
 func (l *Logger) log(level string, format string, v ...any) {
 	message := fmt.Sprintf("[%s] "+format, append([]any{level}, v...)...)
+	history.add(message)
 	consoleLog := js.Global().Get("console").Get("log").Call("bind", js.Global().Get("console"))
 	consoleLog.Invoke(message)
 }
@@ -0,0 +1,54 @@
+package katsu2d
+
+import "sort"
+
+// CurveKey is a single keyframe of an AnimationCurve.
+type CurveKey struct {
+	Time  float64
+	Value float64
+}
+
+// AnimationCurve maps time to a value by interpolating between keys.
+// Times before the first key or after the last are clamped.
+type AnimationCurve struct {
+	Keys   []CurveKey
+	Smooth bool
+}
+
+// NewAnimationCurve creates a linear curve from the given keys.
+func NewAnimationCurve(keys ...CurveKey) *AnimationCurve {
+	c := &AnimationCurve{Keys: append([]CurveKey(nil), keys...)}
+	c.sort()
+	return c
+}
+
+// AddKey inserts a key, keeping keys ordered by time.
+func (self *AnimationCurve) AddKey(time, value float64) {
+	self.Keys = append(self.Keys, CurveKey{Time: time, Value: value})
+	self.sort()
+}
+
+func (self *AnimationCurve) sort() {
+	sort.SliceStable(self.Keys, func(i, j int) bool { return self.Keys[i].Time < self.Keys[j].Time })
+}
+
+// Evaluate returns the curve value at time t.
+func (self *AnimationCurve) Evaluate(t float64) float64 {
+	n := len(self.Keys)
+	if n == 0 {
+		return 0
+	}
+	if t <= self.Keys[0].Time {
+		return self.Keys[0].Value
+	}
+	if t >= self.Keys[n-1].Time {
+		return self.Keys[n-1].Value
+	}
+	i := sort.Search(n, func(i int) bool { return self.Keys[i].Time > t })
+	a, b := self.Keys[i-1], self.Keys[i]
+	f := (t - a.Time) / (b.Time - a.Time)
+	if self.Smooth {
+		f = f * f * (3 - 2*f)
+	}
+	return Lerp(a.Value, b.Value, f)
+}
@@ -14,6 +14,7 @@ type Transform struct {
 	parentMatrix                    Matrix
 	parentInverted                  Matrix
 	position, scale, offset, origin Vector
+	skew, pivot                     Vector
 	rotation                        float64
 	isDirty                         bool // A single dirty flag for performance caching.
 }
@@ -35,6 +36,8 @@ func (self *Transform) Reset() {
 	self.scale = V2(1)    // Default scale (1, 1)
 	self.offset = V2(0)   // Default offset (0, 0)
 	self.origin = V2(0)   // Default origin (0, 0)
+	self.skew = V2(0)     // Default skew (0, 0)
+	self.pivot = V2(0)    // Default pivot (0, 0)
 	self.rotation = 0.0   // Default rotation (0.0)
 	// Mark as dirty to force world matrix recalculation next time Matrix() is called.
 	self.isDirty = true
@@ -180,6 +183,31 @@ func (self *Transform) Offset() Vector {
 	return self.offset
 }
 
+// Skew and Pivot
+// --------------
+// SetSkew updates the shear angles (in radians) along the X and Y axes.
+func (self *Transform) SetSkew(skew Vector) {
+	self.isDirty = true
+	self.skew = skew
+}
+
+// Skew returns the local shear angles in radians.
+func (self *Transform) Skew() Vector {
+	return self.skew
+}
+
+// SetPivot updates the point, relative to the offset and in local units,
+// around which rotation and skew are applied.
+func (self *Transform) SetPivot(pivot Vector) {
+	self.isDirty = true
+	self.pivot = pivot
+}
+
+// Pivot returns the local rotation pivot.
+func (self *Transform) Pivot() Vector {
+	return self.pivot
+}
+
 // Transform Modifiers
 // -------------------
 // Abs returns a new transform with the absolute world properties of this transform,
@@ -191,6 +219,8 @@ func (self *Transform) Abs() Transform {
 	abs.SetScale(self.Scale())
 	abs.SetOffset(self.Offset())
 	abs.SetOrigin(self.Origin())
+	abs.SetSkew(self.Skew())
+	abs.SetPivot(self.Pivot())
 	return abs
 }
 
@@ -217,6 +247,8 @@ func (self *Transform) Replace(new Transformer) {
 	self.SetRotation(nt.Rotation())
 	self.SetOrigin(nt.Origin())
 	self.SetScale(nt.Scale())
+	self.SetSkew(nt.Skew())
+	self.SetPivot(nt.Pivot())
 }
 
 // Connect establishes a parent-child relationship, preserving the object's
@@ -278,7 +310,14 @@ func (self *Transform) Matrix() Matrix {
 		-self.offset.X*self.scale.X,
 		-self.offset.Y*self.scale.Y,
 	)
+	// Skew and rotate around the pivot, which defaults to the offset point.
+	pivotX, pivotY := self.pivot.X*self.scale.X, self.pivot.Y*self.scale.Y
+	localMatrix.Translate(-pivotX, -pivotY)
+	if !self.skew.IsZero() {
+		localMatrix.Skew(self.skew.X, self.skew.Y)
+	}
 	localMatrix.Rotate(float64(self.rotation))
+	localMatrix.Translate(pivotX, pivotY)
 	// Move to the absolute position.
 	localMatrix.Translate(self.position.X, self.position.Y)
 	// Save this local matrix as the parent matrix for children, without the origin offset.
@@ -0,0 +1,59 @@
+package katsu2d
+
+import (
+	"github.com/edwinsyarief/katsu2d/pathfind"
+	"github.com/edwinsyarief/teishoku"
+)
+
+// FindWorldPath finds a smoothed path on grid between two world positions.
+// The path starts at from and ends at to, passing through the centers of
+// the turning cells in between.
+func FindWorldPath(grid *pathfind.Grid, from, to Vector) ([]Point, bool) {
+	cells, ok := grid.FindSmoothPath(grid.CellAt(from.X, from.Y), grid.CellAt(to.X, to.Y))
+	if !ok {
+		return nil, false
+	}
+	// The first and last cells are replaced by the exact endpoints, so the
+	// path does not detour through the centers of their cells.
+	points := make([]Point, max(len(cells), 2))
+	points[0] = Point(from)
+	for i := 1; i < len(cells)-1; i++ {
+		x, y := grid.CellCenter(cells[i])
+		points[i] = Point{X: x, Y: y}
+	}
+	points[len(points)-1] = Point(to)
+	return points, true
+}
+
+// NavigateTo finds a path on grid from e's position to target and makes
+// the PathFollowSystem walk e along it at speed, adding the PathComponent
+// and PathFollowerComponent as needed. A PathFinishedEvent is published on
+// arrival. It reports false, leaving e untouched, when target cannot be
+// reached.
+func NavigateTo(w *teishoku.World, e teishoku.Entity, grid *pathfind.Grid, target Vector, speed float64) bool {
+	t := teishoku.GetComponent[TransformComponent](w, e)
+	if t == nil {
+		return false
+	}
+	points, ok := FindWorldPath(grid, Vector(t.Position), target)
+	if !ok {
+		return false
+	}
+	path, follower := teishoku.GetComponent2[PathComponent, PathFollowerComponent](w, e)
+	if path == nil || follower == nil {
+		teishoku.SetComponent2(w, e, PathComponent{}, PathFollowerComponent{})
+		path, follower = teishoku.GetComponent2[PathComponent, PathFollowerComponent](w, e)
+	}
+	path.Points = points
+	path.Curve = PathLinear
+	path.Closed = false
+	path.Invalidate()
+	*follower = PathFollowerComponent{
+		Speed:           speed,
+		Mode:            PathOnce,
+		OrientToTangent: follower.OrientToTangent,
+		RotationOffset:  follower.RotationOffset,
+		Active:          true,
+	}
+	return true
+}
@@ -0,0 +1,176 @@
+package katsu2d
+
+import (
+	"slices"
+
+	"github.com/edwinsyarief/teishoku"
+)
+
+// OcclusionBake configures BakeOcclusion.
+type OcclusionBake struct {
+	// ViewExtent is half the largest viewport, in world units, that can
+	// look out of a region, e.g. half the screen at the lowest zoom.
+	ViewExtent Vector
+	// Occluders are solid areas such as walls that block sight between a
+	// region and a group.
+	Occluders []Rectangle
+	// Samples is the number of sight line end points per rectangle edge.
+	// Zero uses 3.
+	Samples int
+}
+
+// OcclusionSet is a baked potentially visible set: for every map region,
+// the occlusion groups that can be seen while the camera is inside it.
+// Bake it once with BakeOcclusion, store it with the map, and add it to the
+// world as a resource together with an OcclusionSystem.
+type OcclusionSet struct {
+	Regions []Rectangle `json:"regions"`
+	// Visible lists, per region, the sorted groups visible from it.
+	Visible [][]int `json:"visible"`
+
+	// selected is the current region plus one, so the zero value of a set
+	// loaded from JSON or built by hand draws every group.
+	selected int
+	visible  []bool
+}
+
+// BakeOcclusion computes which groups, given by their world bounds, are
+// potentially visible from each region. A group is visible when it lies
+// within the view extent of the region and a sight line between them
+// misses every occluder.
+func BakeOcclusion(regions []Rectangle, groups map[int]Rectangle, opts OcclusionBake) *OcclusionSet {
+	samples := opts.Samples
+	if samples <= 0 {
+		samples = 3
+	}
+	set := &OcclusionSet{
+		Regions: slices.Clone(regions),
+		Visible: make([][]int, len(regions)),
+	}
+	for i, region := range regions {
+		view := Rectangle{Min: region.Min.Sub(opts.ViewExtent), Max: region.Max.Add(opts.ViewExtent)}
+		from := occlusionSamples(region, samples)
+		for group, bounds := range groups {
+			if !view.Intersects(bounds) {
+				continue
+			}
+			if region.Intersects(bounds) || occlusionSightLine(from, occlusionSamples(bounds, samples), opts.Occluders) {
+				set.Visible[i] = append(set.Visible[i], group)
+			}
+		}
+		slices.Sort(set.Visible[i])
+	}
+	return set
+}
+
+// occlusionSamples returns points spread along the edges of rect.
+func occlusionSamples(rect Rectangle, n int) []Vector {
+	corners := rect.GetCorners()
+	points := make([]Vector, 0, 4*n+1)
+	points = append(points, rect.Center())
+	for i, corner := range corners {
+		next := corners[(i+1)%4]
+		for s := range n {
+			points = append(points, corner.Add(next.Sub(corner).ScaleF(float64(s)/float64(n))))
+		}
+	}
+	return points
+}
+
+// occlusionSightLine reports whether any pair of points can see each other
+// past the occluders.
+func occlusionSightLine(from, to []Vector, occluders []Rectangle) bool {
+	for _, a := range from {
+		for _, b := range to {
+			blocked := false
+			for _, occluder := range occluders {
+				if segmentHitsRectangle(a, b, occluder) {
+					blocked = true
+					break
+				}
+			}
+			if !blocked {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// segmentHitsRectangle reports whether segment ab touches rect. Unlike a
+// zero radius capsule test it does not rely on an exact zero distance.
+func segmentHitsRectangle(a, b Vector, rect Rectangle) bool {
+	if rect.Contains(a) || rect.Contains(b) {
+		return true
+	}
+	corners := [4]Vector{rect.Min, V(rect.Max.X, rect.Min.Y), rect.Max, V(rect.Min.X, rect.Max.Y)}
+	for i, corner := range corners {
+		if _, ok := SegmentIntersection(a, b, corner, corners[(i+1)%4]); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// GetOcclusionSet returns the world's occlusion set, or nil if none is set.
+func GetOcclusionSet(w *teishoku.World) *OcclusionSet {
+	return GetResource[OcclusionSet](w)
+}
+
+// RegionAt returns the index of the region containing p, or -1.
+func (self *OcclusionSet) RegionAt(p Vector) int {
+	for i, region := range self.Regions {
+		if region.Contains(p) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Region returns the region the camera was last placed in, or -1 when it
+// is outside every region and all groups are drawn.
+func (self *OcclusionSet) Region() int {
+	return self.selected - 1
+}
+
+// SetRegion selects the region whose visible groups are drawn; -1 draws
+// every group.
+func (self *OcclusionSet) SetRegion(region int) {
+	if region < 0 || region >= len(self.Regions) {
+		region = -1
+	}
+	if region+1 == self.selected && self.visible != nil {
+		return
+	}
+	self.selected = region + 1
+	clear(self.visible)
+	self.visible = self.visible[:0]
+	if region < 0 {
+		return
+	}
+	for _, group := range self.Visible[region] {
+		if group >= len(self.visible) {
+			self.visible = append(self.visible, make([]bool, group+1-len(self.visible))...)
+		}
+		self.visible[group] = true
+	}
+}
+
+// GroupVisible reports whether group is visible from the current region.
+// Every group is visible outside the regions and on a nil set.
+func (self *OcclusionSet) GroupVisible(group int) bool {
+	if self == nil || self.selected == 0 {
+		return true
+	}
+	return group >= 0 && group < len(self.visible) && self.visible[group]
+}
+
+// EntityVisible reports whether e is in a visible group. Entities without
+// an OcclusionGroupComponent are always visible.
+func (self *OcclusionSet) EntityVisible(w *teishoku.World, e teishoku.Entity) bool {
+	if self == nil || self.selected == 0 {
+		return true
+	}
+	g := teishoku.GetComponent[OcclusionGroupComponent](w, e)
+	return g == nil || self.GroupVisible(g.Group)
+}
@@ -0,0 +1,69 @@
+package katsu2d
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+// bakeTestOcclusion bakes one region with a nearby group, a group out of
+// view and a group behind a wall.
+func bakeTestOcclusion() *OcclusionSet {
+	regions := []Rectangle{NewRectangle(0, 0, 10, 10)}
+	groups := map[int]Rectangle{
+		1: NewRectangle(20, 0, 25, 10),
+		2: NewRectangle(100, 0, 110, 10),
+		3: NewRectangle(0, 20, 10, 25),
+	}
+	return BakeOcclusion(regions, groups, OcclusionBake{
+		ViewExtent: V(30, 30),
+		Occluders:  []Rectangle{NewRectangle(-50, 14, 50, 16)},
+	})
+}
+
+// TestBakeOcclusion verifies that groups out of view or behind occluders
+// are not visible from a region.
+func TestBakeOcclusion(t *testing.T) {
+	set := bakeTestOcclusion()
+	if want := []int{1}; !slices.Equal(set.Visible[0], want) {
+		t.Errorf("visible groups = %v, want %v", set.Visible[0], want)
+	}
+	if got := set.RegionAt(V(5, 5)); got != 0 {
+		t.Errorf("RegionAt inside = %d, want 0", got)
+	}
+	if got := set.RegionAt(V(50, 50)); got != -1 {
+		t.Errorf("RegionAt outside = %d, want -1", got)
+	}
+}
+
+// TestOcclusionSetFromJSON verifies that a loaded set draws every group
+// until a region is selected.
+func TestOcclusionSetFromJSON(t *testing.T) {
+	data, err := json.Marshal(bakeTestOcclusion())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var set OcclusionSet
+	if err := json.Unmarshal(data, &set); err != nil {
+		t.Fatal(err)
+	}
+	if set.Region() != -1 {
+		t.Errorf("Region() = %d, want -1", set.Region())
+	}
+	for group := 1; group <= 3; group++ {
+		if !set.GroupVisible(group) {
+			t.Errorf("group %d hidden before a region is selected", group)
+		}
+	}
+
+	set.SetRegion(0)
+	if !set.GroupVisible(1) || set.GroupVisible(2) || set.GroupVisible(3) {
+		t.Errorf("visibility in region 0 = %v %v %v, want true false false",
+			set.GroupVisible(1), set.GroupVisible(2), set.GroupVisible(3))
+	}
+
+	set.SetRegion(-1)
+	if !set.GroupVisible(3) {
+		t.Error("group hidden outside every region")
+	}
+}
@@ -0,0 +1,305 @@
+// Package pathfind finds paths across walkability grids with A* and
+// smooths them into few straight segments. Grids are built from a
+// callback or a procgen grid and placed in world space by their cell size
+// and origin.
+package pathfind
+
+import (
+	"container/heap"
+	"image"
+	"math"
+	"slices"
+
+	"github.com/edwinsyarief/katsu2d/jobs"
+	"github.com/edwinsyarief/katsu2d/procgen"
+)
+
+// Grid is a row-major grid of movement costs. A cost of 0 blocks the cell;
+// walkable cells cost at least 1 per step.
+type Grid struct {
+	Width, Height int
+	// CellSize and Origin place the grid in world space.
+	CellSize         float64
+	OriginX, OriginY float64
+	// Diagonal allows diagonal steps that do not cut wall corners.
+	Diagonal bool
+	Costs    []float64
+}
+
+// NewGrid creates a grid with every cell walkable at cost 1.
+func NewGrid(width, height int, cellSize float64) *Grid {
+	g := &Grid{Width: width, Height: height, CellSize: cellSize, Diagonal: true, Costs: make([]float64, width*height)}
+	for i := range g.Costs {
+		g.Costs[i] = 1
+	}
+	return g
+}
+
+// FromFunc creates a grid whose cells are walkable where walkable returns
+// true, e.g. from the solid tiles of a tilemap.
+func FromFunc(width, height int, cellSize float64, walkable func(x, y int) bool) *Grid {
+	g := NewGrid(width, height, cellSize)
+	for y := range height {
+		for x := range width {
+			if !walkable(x, y) {
+				g.Costs[y*width+x] = 0
+			}
+		}
+	}
+	return g
+}
+
+// FromProcgen creates a grid from a generated map, walkable on the given
+// tile values.
+func FromProcgen(src *procgen.Grid, cellSize float64, walkable ...int) *Grid {
+	return FromFunc(src.Width, src.Height, cellSize, func(x, y int) bool {
+		return slices.Contains(walkable, src.At(x, y, 0))
+	})
+}
+
+// In reports whether x, y lies inside the grid.
+func (self *Grid) In(x, y int) bool {
+	return x >= 0 && y >= 0 && x < self.Width && y < self.Height
+}
+
+// Cost returns the step cost of x, y, 0 when blocked or off the grid.
+func (self *Grid) Cost(x, y int) float64 {
+	if !self.In(x, y) {
+		return 0
+	}
+	return self.Costs[y*self.Width+x]
+}
+
+// SetCost sets the step cost of x, y; 0 blocks it and other costs are
+// raised to 1.
+func (self *Grid) SetCost(x, y int, cost float64) {
+	if self.In(x, y) {
+		if cost > 0 {
+			cost = max(cost, 1)
+		}
+		self.Costs[y*self.Width+x] = cost
+	}
+}
+
+// Walkable reports whether x, y can be entered.
+func (self *Grid) Walkable(x, y int) bool {
+	return self.Cost(x, y) > 0
+}
+
+// SetWalkable opens x, y at cost 1 or blocks it.
+func (self *Grid) SetWalkable(x, y int, walkable bool) {
+	cost := 0.0
+	if walkable {
+		cost = 1
+	}
+	self.SetCost(x, y, cost)
+}
+
+// CellAt returns the cell holding the world point x, y.
+func (self *Grid) CellAt(x, y float64) image.Point {
+	return image.Pt(int(math.Floor((x-self.OriginX)/self.CellSize)), int(math.Floor((y-self.OriginY)/self.CellSize)))
+}
+
+// CellCenter returns the world position of the center of cell.
+func (self *Grid) CellCenter(cell image.Point) (float64, float64) {
+	return self.OriginX + (float64(cell.X)+0.5)*self.CellSize, self.OriginY + (float64(cell.Y)+0.5)*self.CellSize
+}
+
+var (
+	straightSteps = []image.Point{{1, 0}, {-1, 0}, {0, 1}, {0, -1}}
+	diagonalSteps = []image.Point{{1, 0}, {-1, 0}, {0, 1}, {0, -1}, {1, 1}, {1, -1}, {-1, 1}, {-1, -1}}
+)
+
+// FindPath returns the cheapest path of cells from one cell to another,
+// both included, and false when to cannot be reached.
+func (self *Grid) FindPath(from, to image.Point) ([]image.Point, bool) {
+	if !self.Walkable(from.X, from.Y) || !self.Walkable(to.X, to.Y) {
+		return nil, false
+	}
+	if from == to {
+		return []image.Point{from}, true
+	}
+	steps := straightSteps
+	if self.Diagonal {
+		steps = diagonalSteps
+	}
+	n := self.Width * self.Height
+	cost := make([]float64, n)
+	parent := make([]int32, n)
+	closed := make([]bool, n)
+	for i := range cost {
+		cost[i] = math.Inf(1)
+		parent[i] = -1
+	}
+	index := func(p image.Point) int { return p.Y*self.Width + p.X }
+	start, goal := index(from), index(to)
+	cost[start] = 0
+	open := &openSet{{index: start, priority: self.heuristic(from, to)}}
+	for open.Len() > 0 {
+		current := heap.Pop(open).(node).index
+		if current == goal {
+			break
+		}
+		if closed[current] {
+			continue
+		}
+		closed[current] = true
+		p := image.Pt(current%self.Width, current/self.Width)
+		for _, step := range steps {
+			next := p.Add(step)
+			stepCost := self.Cost(next.X, next.Y)
+			if stepCost == 0 {
+				continue
+			}
+			if step.X != 0 && step.Y != 0 {
+				// Diagonals may not squeeze past wall corners.
+				if !self.Walkable(p.X+step.X, p.Y) || !self.Walkable(p.X, p.Y+step.Y) {
+					continue
+				}
+				stepCost *= math.Sqrt2
+			}
+			ni := index(next)
+			if closed[ni] {
+				continue
+			}
+			if c := cost[current] + stepCost; c < cost[ni] {
+				cost[ni] = c
+				parent[ni] = int32(current)
+				heap.Push(open, node{index: ni, priority: c + self.heuristic(next, to)})
+			}
+		}
+	}
+	if parent[goal] < 0 {
+		return nil, false
+	}
+	var path []image.Point
+	for i := goal; i >= 0; i = int(parent[i]) {
+		path = append(path, image.Pt(i%self.Width, i/self.Width))
+	}
+	slices.Reverse(path)
+	return path, true
+}
+
+// heuristic estimates the cost from a to b at the minimum step cost:
+// octile distance with diagonals, Manhattan distance without.
+func (self *Grid) heuristic(a, b image.Point) float64 {
+	dx, dy := math.Abs(float64(a.X-b.X)), math.Abs(float64(a.Y-b.Y))
+	if !self.Diagonal {
+		return dx + dy
+	}
+	return max(dx, dy) + (math.Sqrt2-1)*min(dx, dy)
+}
+
+// LineOfSight reports whether the straight line between the centers of a
+// and b crosses only walkable cells of equal or lower cost than a.
+func (self *Grid) LineOfSight(a, b image.Point) bool {
+	limit := self.Cost(a.X, a.Y)
+	dx, dy := b.X-a.X, b.Y-a.Y
+	sx, sy := sign(dx), sign(dy)
+	nx, ny := abs(dx), abs(dy)
+	p := a
+	// Walk the supercover of the line so corners touched diagonally
+	// count as crossed.
+	for ix, iy := 0, 0; ix < nx || iy < ny; {
+		decision := (1+2*ix)*ny - (1+2*iy)*nx
+		switch {
+		case decision == 0:
+			if !self.Walkable(p.X+sx, p.Y) || !self.Walkable(p.X, p.Y+sy) {
+				return false
+			}
+			p.X += sx
+			p.Y += sy
+			ix++
+			iy++
+		case decision < 0:
+			p.X += sx
+			ix++
+		default:
+			p.Y += sy
+			iy++
+		}
+		if c := self.Cost(p.X, p.Y); c == 0 || c > limit {
+			return false
+		}
+	}
+	return true
+}
+
+// Smooth drops the cells of path that a straight line can skip, keeping
+// the first and last cell.
+func (self *Grid) Smooth(path []image.Point) []image.Point {
+	if len(path) <= 2 {
+		return path
+	}
+	smoothed := []image.Point{path[0]}
+	anchor := path[0]
+	for i := 2; i < len(path); i++ {
+		if !self.LineOfSight(anchor, path[i]) {
+			anchor = path[i-1]
+			smoothed = append(smoothed, anchor)
+		}
+	}
+	return append(smoothed, path[len(path)-1])
+}
+
+// FindSmoothPath is FindPath followed by Smooth.
+func (self *Grid) FindSmoothPath(from, to image.Point) ([]image.Point, bool) {
+	path, ok := self.FindPath(from, to)
+	if !ok {
+		return nil, false
+	}
+	return self.Smooth(path), true
+}
+
+// FindSmoothPathAsync runs FindSmoothPath on the job pool and passes the
+// result to done from the next jobs.Sync, which the engine runs at the start
+// of each update. The grid must not be changed until done has run.
+func (self *Grid) FindSmoothPathAsync(from, to image.Point, done func(path []image.Point, ok bool)) *jobs.Handle {
+	var path []image.Point
+	var ok bool
+	h := jobs.Submit(func() {
+		path, ok = self.FindSmoothPath(from, to)
+	})
+	jobs.Then(h, func() { done(path, ok) })
+	return h
+}
+
+func sign(v int) int {
+	switch {
+	case v < 0:
+		return -1
+	case v > 0:
+		return 1
+	}
+	return 0
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+type node struct {
+	index    int
+	priority float64
+}
+
+// openSet is a min-heap of nodes by priority.
+type openSet []node
+
+func (self openSet) Len() int           { return len(self) }
+func (self openSet) Less(i, j int) bool { return self[i].priority < self[j].priority }
+func (self openSet) Swap(i, j int)      { self[i], self[j] = self[j], self[i] }
+
+func (self *openSet) Push(x any) {
+	*self = append(*self, x.(node))
+}
+
+func (self *openSet) Pop() any {
+	old := *self
+	n := old[len(old)-1]
+	*self = old[:len(old)-1]
+	return n
+}
@@ -0,0 +1,109 @@
+package pathfind
+
+import (
+	"image"
+	"slices"
+	"testing"
+
+	"github.com/edwinsyarief/katsu2d/jobs"
+)
+
+// gridFromRows builds a grid from rows of '.' (walkable) and '#' (wall).
+func gridFromRows(rows ...string) *Grid {
+	return FromFunc(len(rows[0]), len(rows), 1, func(x, y int) bool {
+		return rows[y][x] != '#'
+	})
+}
+
+// TestFindPathBlockedGoal verifies that walls and enclosed goals cannot be reached.
+func TestFindPathBlockedGoal(t *testing.T) {
+	g := gridFromRows(
+		"...",
+		".##",
+		".#.",
+	)
+	if _, ok := g.FindPath(image.Pt(0, 0), image.Pt(1, 1)); ok {
+		t.Error("found a path to a wall cell")
+	}
+	if _, ok := g.FindPath(image.Pt(0, 0), image.Pt(2, 2)); ok {
+		t.Error("found a path to an enclosed cell")
+	}
+	if _, ok := g.FindSmoothPath(image.Pt(0, 0), image.Pt(2, 2)); ok {
+		t.Error("found a smooth path to an enclosed cell")
+	}
+}
+
+// TestFindPathNoCornerCutting verifies that diagonal steps never squeeze
+// past a wall corner.
+func TestFindPathNoCornerCutting(t *testing.T) {
+	g := gridFromRows(
+		".#",
+		"..",
+	)
+	path, ok := g.FindPath(image.Pt(0, 0), image.Pt(1, 1))
+	if !ok {
+		t.Fatal("expected a path")
+	}
+	want := []image.Point{{0, 0}, {0, 1}, {1, 1}}
+	if !slices.Equal(path, want) {
+		t.Errorf("path = %v, want %v", path, want)
+	}
+	for i := 1; i < len(path); i++ {
+		a, b := path[i-1], path[i]
+		if a.X != b.X && a.Y != b.Y && (!g.Walkable(b.X, a.Y) || !g.Walkable(a.X, b.Y)) {
+			t.Errorf("step %v -> %v cuts a wall corner", a, b)
+		}
+	}
+}
+
+// TestFindSmoothPath verifies that smoothing keeps only the turning cells
+// of a winding path.
+func TestFindSmoothPath(t *testing.T) {
+	g := gridFromRows(
+		"......",
+		"####..",
+		"......",
+		".#####",
+		"......",
+	)
+	path, ok := g.FindSmoothPath(image.Pt(0, 0), image.Pt(0, 4))
+	if !ok {
+		t.Fatal("expected a path")
+	}
+	want := []image.Point{{0, 0}, {4, 0}, {4, 2}, {0, 2}, {0, 4}}
+	if !slices.Equal(path, want) {
+		t.Errorf("smoothed path = %v, want %v", path, want)
+	}
+	for i := 1; i < len(path); i++ {
+		if !g.LineOfSight(path[i-1], path[i]) {
+			t.Errorf("no line of sight between %v and %v", path[i-1], path[i])
+		}
+	}
+
+	g.SetWalkable(4, 1, false)
+	g.SetWalkable(5, 1, false)
+	if _, ok := g.FindSmoothPath(image.Pt(0, 0), image.Pt(0, 4)); ok {
+		t.Error("found a path through a closed corridor")
+	}
+}
+
+// TestFindSmoothPathAsync verifies that the async search hands the same
+// path to its callback on Sync.
+func TestFindSmoothPathAsync(t *testing.T) {
+	g := gridFromRows(
+		"......",
+		"####..",
+		"......",
+	)
+	want, _ := g.FindSmoothPath(image.Pt(0, 0), image.Pt(0, 2))
+	var got []image.Point
+	called := false
+	h := g.FindSmoothPathAsync(image.Pt(0, 0), image.Pt(0, 2), func(path []image.Point, ok bool) {
+		got, called = path, ok
+	})
+	h.Wait()
+	jobs.Sync()
+	if !called || !slices.Equal(got, want) {
+		t.Errorf("async path = %v (called %v), want %v", got, called, want)
+	}
+}
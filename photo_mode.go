@@ -0,0 +1,252 @@
+package katsu2d
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// PhotoMode hides the UI, detaches the camera for free movement and zoom,
+// and captures screenshots at up to 4x resolution. Exit restores the
+// camera, draw toggles and time scale as they were on Enter.
+type PhotoMode struct {
+	// Hide lists draw systems hidden while active, such as HUD layers.
+	Hide []DrawSystem
+	// HideOverlays hides every engine overlay system while active.
+	HideOverlays bool
+	// Freeze stops time while active.
+	Freeze bool
+	// MinZoom and MaxZoom clamp the free camera zoom, regardless of the
+	// limits gameplay puts on the camera.
+	MinZoom, MaxZoom float64
+
+	engine *Engine
+	active bool
+
+	camera    *Camera
+	saved     Camera
+	hidden    []DrawSystem
+	timeScale float64
+
+	captureScale int
+	captureDone  func(*image.RGBA)
+	cameras      []*Camera
+}
+
+func newPhotoMode(e *Engine) *PhotoMode {
+	return &PhotoMode{
+		HideOverlays: true,
+		Freeze:       true,
+		MinZoom:      0.1,
+		MaxZoom:      10,
+		engine:       e,
+	}
+}
+
+// PhotoMode returns the engine's photo mode.
+func (self *Engine) PhotoMode() *PhotoMode {
+	return self.photo
+}
+
+// Active reports whether photo mode is on.
+func (self *PhotoMode) Active() bool {
+	return self.active
+}
+
+// Enter turns photo mode on.
+func (self *PhotoMode) Enter() {
+	if self.active {
+		return
+	}
+	self.active = true
+	e := self.engine
+
+	// Take the camera of the scene on top, or the engine's own.
+	self.camera = nil
+	if e.scm.current != nil {
+		self.camera = GetCamera(e.scm.current.World())
+	}
+	if self.camera == nil {
+		self.camera = GetCamera(e.World())
+	}
+	if self.camera != nil {
+		self.saved = *self.camera
+		self.camera.Following = false
+		self.camera.Bounds = nil
+	}
+
+	// Only hide what is visible, so Exit leaves hidden systems hidden.
+	self.hidden = self.hidden[:0]
+	hide := func(ds DrawSystem) {
+		if e.drawToggles.Visible(ds) {
+			e.drawToggles.SetVisible(false, ds)
+			self.hidden = append(self.hidden, ds)
+		}
+	}
+	for _, ds := range self.Hide {
+		hide(ds)
+	}
+	if self.HideOverlays {
+		for _, ds := range e.overlayDrawSystems {
+			hide(ds)
+		}
+	}
+
+	self.timeScale = e.timeScale
+	if self.Freeze {
+		e.timeScale = 0
+	}
+}
+
+// Exit turns photo mode off and restores the state from before Enter.
+func (self *PhotoMode) Exit() {
+	if !self.active {
+		return
+	}
+	self.active = false
+	if self.camera != nil {
+		*self.camera = self.saved
+		self.camera = nil
+	}
+	self.engine.drawToggles.SetVisible(true, self.hidden...)
+	self.hidden = self.hidden[:0]
+	self.engine.timeScale = self.timeScale
+}
+
+// Toggle enters or exits photo mode.
+func (self *PhotoMode) Toggle() {
+	if self.active {
+		self.Exit()
+	} else {
+		self.Enter()
+	}
+}
+
+// Camera returns the camera under free control, or nil when inactive or
+// no camera is set.
+func (self *PhotoMode) Camera() *Camera {
+	return self.camera
+}
+
+// Pan moves the free camera by delta screen pixels.
+func (self *PhotoMode) Pan(delta Vector) {
+	if self.camera == nil {
+		return
+	}
+	origin := self.camera.ScreenToWorld(Vector{})
+	self.camera.Position = self.camera.Position.Add(self.camera.ScreenToWorld(delta).Sub(origin))
+}
+
+// ZoomBy multiplies the free camera zoom by factor, within MinZoom and
+// MaxZoom.
+func (self *PhotoMode) ZoomBy(factor float64) {
+	if self.camera == nil {
+		return
+	}
+	self.SetZoom(self.camera.zoom() * factor)
+}
+
+// SetZoom sets the free camera zoom, within MinZoom and MaxZoom.
+func (self *PhotoMode) SetZoom(zoom float64) {
+	if self.camera == nil {
+		return
+	}
+	if self.MinZoom > 0 {
+		zoom = max(zoom, self.MinZoom)
+	}
+	if self.MaxZoom > 0 {
+		zoom = min(zoom, self.MaxZoom)
+	}
+	self.camera.Zoom = zoom
+}
+
+// Rotate turns the free camera by angle radians.
+func (self *PhotoMode) Rotate(angle float64) {
+	if self.camera != nil {
+		self.camera.Rotation += angle
+	}
+}
+
+// Capture renders the next frame at scale times the screen resolution,
+// clamped to 1-4, and passes it to done downscaled to the screen size.
+// Systems drawing in screen space are not supersampled.
+func (self *PhotoMode) Capture(scale int, done func(*image.RGBA)) {
+	self.captureScale = min(max(scale, 1), 4)
+	self.captureDone = done
+}
+
+// capturePending takes a requested capture of a frame the size of screen.
+func (self *PhotoMode) capturePending(screen *ebiten.Image) {
+	if self.captureDone == nil {
+		return
+	}
+	done := self.captureDone
+	self.captureDone = nil
+	e := self.engine
+	e.renderer.Flush()
+
+	size := screen.Bounds().Size()
+	scale := self.captureScale
+	big := ebiten.NewImage(size.X*scale, size.Y*scale)
+	fill := e.clearColor
+	if fill == nil {
+		fill = color.Black
+	}
+	big.Fill(fill)
+
+	var zoom Matrix
+	zoom.Scale(float64(scale), float64(scale))
+	e.renderer.Begin(big)
+	e.renderer.SetView(zoom)
+	e.drawSystems(e.backgroundDrawSystems)
+	e.renderer.ResetView()
+	// Scenes are drawn through their cameras and viewports like on screen,
+	// at the capture scale.
+	for _, scene := range e.scm.visibleScenes() {
+		world := scene.World()
+		self.cameras = Cameras(world, self.cameras[:0])
+		if len(self.cameras) == 0 {
+			e.renderer.SetView(zoom)
+			scene.Draw(world, e.renderer)
+			e.renderer.ResetView()
+			continue
+		}
+		for _, cam := range self.cameras {
+			e.drawCamera(scene, cam, big, size.X, size.Y, V(float64(scale), float64(scale)))
+		}
+		clear(self.cameras)
+	}
+	e.renderer.SetView(zoom)
+	e.drawSystems(e.overlayDrawSystems)
+	e.renderer.ResetView()
+	e.renderer.Flush()
+
+	out := downscaleImage(big, size.X, size.Y)
+	img := image.NewRGBA(image.Rect(0, 0, size.X, size.Y))
+	out.ReadPixels(img.Pix)
+	out.Deallocate()
+	e.renderer.Begin(screen)
+	done(img)
+}
+
+// downscaleImage shrinks src to width x height, halving repeatedly so
+// every source pixel contributes. src is deallocated.
+func downscaleImage(src *ebiten.Image, width, height int) *ebiten.Image {
+	for {
+		size := src.Bounds().Size()
+		w, h := width, height
+		if size.X >= width*2 && size.Y >= height*2 {
+			w, h = size.X/2, size.Y/2
+		}
+		dst := ebiten.NewImage(w, h)
+		op := &ebiten.DrawImageOptions{Filter: ebiten.FilterLinear, Blend: ebiten.BlendCopy}
+		op.GeoM.Scale(float64(w)/float64(size.X), float64(h)/float64(size.Y))
+		dst.DrawImage(src, op)
+		src.Deallocate()
+		if w == width && h == height {
+			return dst
+		}
+		src = dst
+	}
+}
@@ -0,0 +1,79 @@
+package katsu2d
+
+import (
+	"math"
+
+	"github.com/edwinsyarief/teishoku"
+)
+
+// PhysicsMaterial is how a collider surface responds to contacts.
+type PhysicsMaterial struct {
+	// Friction from 0 (ice) to 1 removes sliding speed along the contact.
+	Friction float64
+	// Restitution from 0 (no bounce) to 1 keeps the approach speed.
+	Restitution float64
+}
+
+// PhysicsMaterials names the materials of ColliderComponent.Material. A
+// pair of materials combines by the geometric mean of the frictions and
+// the larger restitution, unless SetPair overrides it.
+type PhysicsMaterials struct {
+	// Default is used for colliders without a material or with an unknown
+	// one.
+	Default   PhysicsMaterial
+	materials map[string]PhysicsMaterial
+	pairs     map[[2]string]PhysicsMaterial
+}
+
+// NewPhysicsMaterials creates a table with a slightly grippy default.
+func NewPhysicsMaterials() *PhysicsMaterials {
+	return &PhysicsMaterials{
+		Default:   PhysicsMaterial{Friction: 0.4},
+		materials: make(map[string]PhysicsMaterial),
+		pairs:     make(map[[2]string]PhysicsMaterial),
+	}
+}
+
+// GetPhysicsMaterials returns the world's materials, creating them on first
+// use.
+func GetPhysicsMaterials(w *teishoku.World) *PhysicsMaterials {
+	return EnsureResource(w, NewPhysicsMaterials)
+}
+
+// Set defines the material called name.
+func (self *PhysicsMaterials) Set(name string, material PhysicsMaterial) {
+	self.materials[name] = material
+}
+
+// Get returns the material called name, or Default.
+func (self *PhysicsMaterials) Get(name string) PhysicsMaterial {
+	if m, ok := self.materials[name]; ok {
+		return m
+	}
+	return self.Default
+}
+
+// SetPair overrides the combined material of a and b, e.g. to make rubber
+// on ice extra slippery.
+func (self *PhysicsMaterials) SetPair(a, b string, material PhysicsMaterial) {
+	self.pairs[materialPair(a, b)] = material
+}
+
+// Combine returns the material of a contact between a and b.
+func (self *PhysicsMaterials) Combine(a, b string) PhysicsMaterial {
+	if m, ok := self.pairs[materialPair(a, b)]; ok {
+		return m
+	}
+	ma, mb := self.Get(a), self.Get(b)
+	return PhysicsMaterial{
+		Friction:    math.Sqrt(ma.Friction * mb.Friction),
+		Restitution: max(ma.Restitution, mb.Restitution),
+	}
+}
+
+func materialPair(a, b string) [2]string {
+	if a > b {
+		a, b = b, a
+	}
+	return [2]string{a, b}
+}
@@ -0,0 +1,231 @@
+package katsu2d
+
+import (
+	_ "embed"
+	"fmt"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+//go:embed internal_assets/shaders/vignette.kage
+var _vignetteShaderSource []byte
+
+//go:embed internal_assets/shaders/crt.kage
+var _crtShaderSource []byte
+
+//go:embed internal_assets/shaders/bloom.kage
+var _bloomShaderSource []byte
+
+var vignetteShader, crtShader, bloomShader *ebiten.Shader
+
+// PostEffect is one Kage shader pass of a PostProcess chain. The shader
+// reads the previous pass from imageSrc0 and Images from imageSrc1 on. The
+// chain sets the Resolution (vec2, pixels) and Time (float, seconds)
+// uniforms unless Uniforms holds them.
+type PostEffect struct {
+	Name     string
+	Shader   *ebiten.Shader
+	Uniforms map[string]any
+	Images   [3]*ebiten.Image
+	Enabled  bool
+}
+
+// SetUniform sets a uniform of the effect's shader.
+func (self *PostEffect) SetUniform(name string, value any) {
+	self.Uniforms[name] = value
+}
+
+// PostProcess runs a chain of shader effects over the rendered background
+// and scene, after color grading and before overlays, so the HUD stays
+// untouched.
+type PostProcess struct {
+	effects  []*PostEffect
+	buffers  [2]*ebiten.Image
+	time     float64
+	vertices [4]ebiten.Vertex
+	options  ebiten.DrawTrianglesShaderOptions
+}
+
+// NewPostProcess creates an empty chain.
+func NewPostProcess() *PostProcess {
+	return &PostProcess{}
+}
+
+// Add appends an effect running shader and returns it for setting
+// uniforms. Effect names should be unique.
+func (self *PostProcess) Add(name string, shader *ebiten.Shader) *PostEffect {
+	effect := &PostEffect{Name: name, Shader: shader, Uniforms: make(map[string]any), Enabled: true}
+	self.effects = append(self.effects, effect)
+	return effect
+}
+
+// AddSource compiles Kage source and appends it as an effect.
+func (self *PostProcess) AddSource(name string, source []byte) (*PostEffect, error) {
+	shader, err := ebiten.NewShader(source)
+	if err != nil {
+		return nil, fmt.Errorf("katsu2d: compiling post effect %s: %w", name, err)
+	}
+	return self.Add(name, shader), nil
+}
+
+// Effect returns the effect called name, or nil.
+func (self *PostProcess) Effect(name string) *PostEffect {
+	if i := slices.IndexFunc(self.effects, func(e *PostEffect) bool { return e.Name == name }); i >= 0 {
+		return self.effects[i]
+	}
+	return nil
+}
+
+// Effects returns the chain in running order.
+func (self *PostProcess) Effects() []*PostEffect {
+	return self.effects
+}
+
+// Remove takes the effect called name out of the chain.
+func (self *PostProcess) Remove(name string) {
+	self.effects = slices.DeleteFunc(self.effects, func(e *PostEffect) bool { return e.Name == name })
+}
+
+// SetUniform sets a uniform of the effect called name.
+func (self *PostProcess) SetUniform(name, uniform string, value any) {
+	if effect := self.Effect(name); effect != nil {
+		effect.SetUniform(uniform, value)
+	}
+}
+
+// SetEnabled turns the effect called name on or off.
+func (self *PostProcess) SetEnabled(name string, enabled bool) {
+	if effect := self.Effect(name); effect != nil {
+		effect.Enabled = enabled
+	}
+}
+
+// Active reports whether any effect is enabled.
+func (self *PostProcess) Active() bool {
+	return self != nil && slices.ContainsFunc(self.effects, func(e *PostEffect) bool { return e.Enabled && e.Shader != nil })
+}
+
+// Update advances the Time uniform.
+func (self *PostProcess) Update(dt float64) {
+	self.time += dt
+}
+
+// Apply runs the enabled effects over src into dst, ping-ponging through
+// buffers the size of src.
+func (self *PostProcess) Apply(dst, src *ebiten.Image) {
+	last := -1
+	for i, effect := range self.effects {
+		if effect.Enabled && effect.Shader != nil {
+			last = i
+		}
+	}
+	if last < 0 {
+		dst.DrawImage(src, nil)
+		return
+	}
+	size := src.Bounds().Size()
+	in, n := src, 0
+	for i, effect := range self.effects[:last+1] {
+		if !effect.Enabled || effect.Shader == nil {
+			continue
+		}
+		out := dst
+		if i != last {
+			out = self.buffer(n%2, size.X, size.Y)
+			n++
+		}
+		self.pass(out, in, effect)
+		in = out
+	}
+}
+
+// buffer returns ping-pong buffer i cleared at the given size.
+func (self *PostProcess) buffer(i, w, h int) *ebiten.Image {
+	buf, created := resizeRenderTarget(self.buffers[i], w, h, "post-process")
+	self.buffers[i] = buf
+	if !created {
+		buf.Clear()
+	}
+	return buf
+}
+
+func (self *PostProcess) pass(dst, src *ebiten.Image, effect *PostEffect) {
+	b := src.Bounds()
+	x0, y0, x1, y1 := float32(b.Min.X), float32(b.Min.Y), float32(b.Max.X), float32(b.Max.Y)
+	self.vertices = [4]ebiten.Vertex{
+		{DstX: 0, DstY: 0, SrcX: x0, SrcY: y0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: x1 - x0, DstY: 0, SrcX: x1, SrcY: y0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: x1 - x0, DstY: y1 - y0, SrcX: x1, SrcY: y1, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: 0, DstY: y1 - y0, SrcX: x0, SrcY: y1, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+	}
+	if self.options.Uniforms == nil {
+		self.options.Uniforms = make(map[string]any)
+	}
+	clear(self.options.Uniforms)
+	self.options.Uniforms["Resolution"] = [2]float32{x1 - x0, y1 - y0}
+	self.options.Uniforms["Time"] = float32(self.time)
+	for name, value := range effect.Uniforms {
+		self.options.Uniforms[name] = value
+	}
+	self.options.Images = [4]*ebiten.Image{src, effect.Images[0], effect.Images[1], effect.Images[2]}
+	dst.DrawTrianglesShader(self.vertices[:], []uint16{0, 1, 2, 0, 2, 3}, effect.Shader, &self.options)
+}
+
+// builtinShader compiles one of the built-in effect shaders on first use.
+func builtinShader(shader **ebiten.Shader, source []byte, name string) *ebiten.Shader {
+	if *shader == nil {
+		s, err := ebiten.NewShader(source)
+		if err != nil {
+			panic("Failed to compile " + name + " shader: " + err.Error())
+		}
+		*shader = s
+	}
+	return *shader
+}
+
+// AddVignette appends a vignette darkening the corners. Strength mixes it
+// in, Radius and Softness are fractions of the screen; tune them with the
+// uniforms of the same names.
+func (self *PostProcess) AddVignette(strength float64) *PostEffect {
+	effect := self.Add("vignette", builtinShader(&vignetteShader, _vignetteShaderSource, "vignette"))
+	effect.SetUniform("Strength", float32(strength))
+	effect.SetUniform("Radius", float32(0.75))
+	effect.SetUniform("Softness", float32(0.45))
+	return effect
+}
+
+// AddCRT appends a curved, scanlined CRT look with the Curvature,
+// Scanlines and Aberration (pixels) uniforms.
+func (self *PostProcess) AddCRT() *PostEffect {
+	effect := self.Add("crt", builtinShader(&crtShader, _crtShaderSource, "CRT"))
+	effect.SetUniform("Curvature", float32(0.04))
+	effect.SetUniform("Scanlines", float32(0.15))
+	effect.SetUniform("Aberration", float32(1))
+	return effect
+}
+
+// AddBloom appends a glow around colors brighter than threshold, with the
+// Threshold, Intensity and Radius (pixels) uniforms.
+func (self *PostProcess) AddBloom(threshold, intensity float64) *PostEffect {
+	effect := self.Add("bloom", builtinShader(&bloomShader, _bloomShaderSource, "bloom"))
+	effect.SetUniform("Threshold", float32(threshold))
+	effect.SetUniform("Intensity", float32(intensity))
+	effect.SetUniform("Radius", float32(6))
+	return effect
+}
+
+// AddLUT appends color grading with a strip LUT as described on
+// ColorGrading, with the Strength uniform.
+func (self *PostProcess) AddLUT(lut *ebiten.Image) (*PostEffect, error) {
+	size, err := lutSize(lut)
+	if err != nil {
+		return nil, err
+	}
+	effect := self.Add("lut", builtinShader(&lutShader, _lutShaderSource, "LUT"))
+	effect.SetUniform("LutSize", float32(size))
+	effect.SetUniform("Blend", float32(0))
+	effect.SetUniform("Strength", float32(1))
+	effect.Images[0], effect.Images[1] = lut, lut
+	return effect, nil
+}
@@ -0,0 +1,116 @@
+package procgen
+
+import (
+	"math/rand/v2"
+
+	"github.com/edwinsyarief/katsu2d/jobs"
+)
+
+// Caves configures cellular automata cave generation.
+type Caves struct {
+	Seed int64
+	// FillChance is the initial chance of a cell being wall.
+	FillChance float64
+	// Steps is the number of smoothing passes.
+	Steps int
+	// A wall stays a wall with at least DeathLimit wall neighbours, and a
+	// floor becomes wall with more than BirthLimit.
+	BirthLimit, DeathLimit int
+	Wall, Floor            int
+}
+
+// NewCaves creates a cave config with common defaults.
+func NewCaves(seed int64, wall, floor int) Caves {
+	return Caves{Seed: seed, FillChance: 0.45, Steps: 5, BirthLimit: 4, DeathLimit: 3, Wall: wall, Floor: floor}
+}
+
+// Generate creates a cave grid. Cells off the grid count as wall, so caves
+// are closed at the border. Each smoothing pass spreads its rows over the
+// job pool.
+func (self Caves) Generate(width, height int) *Grid {
+	rng := rand.New(rand.NewPCG(uint64(self.Seed), uint64(self.Seed)>>1|1))
+	g := NewGrid(width, height, self.Floor)
+	for i := range g.Cells {
+		if rng.Float64() < self.FillChance {
+			g.Cells[i] = self.Wall
+		}
+	}
+	next := NewGrid(width, height, self.Floor)
+	for range self.Steps {
+		jobs.SubmitChunked(height, func(start, end int) {
+			for y := start; y < end; y++ {
+				for x := range width {
+					walls := self.wallNeighbours(g, x, y)
+					wall := walls > self.BirthLimit
+					if g.At(x, y, self.Wall) == self.Wall {
+						wall = walls >= self.DeathLimit
+					}
+					if wall {
+						next.Set(x, y, self.Wall)
+					} else {
+						next.Set(x, y, self.Floor)
+					}
+				}
+			}
+		}).Wait()
+		g, next = next, g
+	}
+	return g
+}
+
+func (self Caves) wallNeighbours(g *Grid, x, y int) int {
+	n := 0
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			if (dx != 0 || dy != 0) && g.At(x+dx, y+dy, self.Wall) == self.Wall {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// KeepLargestRegion turns every region of value not connected to the
+// largest one into fill, removing unreachable cave pockets.
+func KeepLargestRegion(g *Grid, value, fill int) {
+	region := make([]int, len(g.Cells))
+	var sizes []int
+	var stack []int
+	for start, v := range g.Cells {
+		if v != value || region[start] != 0 {
+			continue
+		}
+		sizes = append(sizes, 0)
+		id := len(sizes)
+		region[start] = id
+		stack = append(stack[:0], start)
+		for len(stack) > 0 {
+			i := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			sizes[id-1]++
+			x, y := i%g.Width, i/g.Width
+			for _, d := range [4][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+				nx, ny := x+d[0], y+d[1]
+				if !g.In(nx, ny) {
+					continue
+				}
+				j := ny*g.Width + nx
+				if g.Cells[j] == value && region[j] == 0 {
+					region[j] = id
+					stack = append(stack, j)
+				}
+			}
+		}
+	}
+	largest := 0
+	for i, size := range sizes {
+		if size > sizes[largest] {
+			largest = i
+		}
+	}
+	for i, id := range region {
+		if id != 0 && id != largest+1 {
+			g.Cells[i] = fill
+		}
+	}
+}
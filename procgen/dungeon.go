@@ -0,0 +1,82 @@
+package procgen
+
+import (
+	"image"
+	"math/rand/v2"
+)
+
+// Dungeon configures room and corridor dungeon generation.
+type Dungeon struct {
+	Seed     int64
+	MaxRooms int
+	// MinRoomSize and MaxRoomSize bound room sides in cells.
+	MinRoomSize, MaxRoomSize int
+	Wall, Floor              int
+}
+
+// NewDungeon creates a dungeon config with common defaults.
+func NewDungeon(seed int64, wall, floor int) Dungeon {
+	return Dungeon{Seed: seed, MaxRooms: 12, MinRoomSize: 4, MaxRoomSize: 10, Wall: wall, Floor: floor}
+}
+
+// Generate places non-overlapping rooms and joins each to the previous one
+// with an L-shaped corridor. It returns the grid and the rooms in the order
+// they were connected.
+func (self Dungeon) Generate(width, height int) (*Grid, []image.Rectangle) {
+	rng := rand.New(rand.NewPCG(uint64(self.Seed), uint64(self.Seed)>>1|1))
+	g := NewGrid(width, height, self.Wall)
+	minSize := max(self.MinRoomSize, 1)
+	maxSize := max(self.MaxRoomSize, minSize)
+	var rooms []image.Rectangle
+	// Give up after a bounded number of attempts on crowded maps.
+	for attempt := 0; attempt < self.MaxRooms*8 && len(rooms) < self.MaxRooms; attempt++ {
+		w := minSize + rng.IntN(maxSize-minSize+1)
+		h := minSize + rng.IntN(maxSize-minSize+1)
+		if w+2 > width || h+2 > height {
+			continue
+		}
+		x := 1 + rng.IntN(width-w-1)
+		y := 1 + rng.IntN(height-h-1)
+		room := image.Rect(x, y, x+w, y+h)
+		// Keep a wall between rooms.
+		padded := room.Inset(-1)
+		overlaps := false
+		for _, other := range rooms {
+			if padded.Overlaps(other) {
+				overlaps = true
+				break
+			}
+		}
+		if overlaps {
+			continue
+		}
+		g.FillRect(x, y, w, h, self.Floor)
+		if len(rooms) > 0 {
+			self.corridor(g, rng, center(rooms[len(rooms)-1]), center(room))
+		}
+		rooms = append(rooms, room)
+	}
+	return g, rooms
+}
+
+func (self Dungeon) corridor(g *Grid, rng *rand.Rand, from, to image.Point) {
+	corner := image.Pt(to.X, from.Y)
+	if rng.IntN(2) == 0 {
+		corner = image.Pt(from.X, to.Y)
+	}
+	self.line(g, from, corner)
+	self.line(g, corner, to)
+}
+
+func (self Dungeon) line(g *Grid, from, to image.Point) {
+	for x := min(from.X, to.X); x <= max(from.X, to.X); x++ {
+		g.Set(x, from.Y, self.Floor)
+	}
+	for y := min(from.Y, to.Y); y <= max(from.Y, to.Y); y++ {
+		g.Set(from.X, y, self.Floor)
+	}
+}
+
+func center(r image.Rectangle) image.Point {
+	return image.Pt((r.Min.X+r.Max.X)/2, (r.Min.Y+r.Max.Y)/2)
+}
@@ -0,0 +1,89 @@
+// Package procgen generates tile grids: noise terrain, cellular automata
+// caves and room and corridor dungeons. Cells hold caller chosen tile
+// values.
+package procgen
+
+// Grid is a row-major grid of tile values.
+type Grid struct {
+	Width, Height int
+	Cells         []int
+}
+
+// NewGrid creates a grid with every cell set to fill.
+func NewGrid(width, height, fill int) *Grid {
+	g := &Grid{Width: width, Height: height, Cells: make([]int, width*height)}
+	g.Fill(fill)
+	return g
+}
+
+// In reports whether x, y lies inside the grid.
+func (self *Grid) In(x, y int) bool {
+	return x >= 0 && y >= 0 && x < self.Width && y < self.Height
+}
+
+// At returns the value at x, y, or outside for cells off the grid.
+func (self *Grid) At(x, y, outside int) int {
+	if !self.In(x, y) {
+		return outside
+	}
+	return self.Cells[y*self.Width+x]
+}
+
+// Set sets the value at x, y. Cells off the grid are ignored.
+func (self *Grid) Set(x, y, value int) {
+	if self.In(x, y) {
+		self.Cells[y*self.Width+x] = value
+	}
+}
+
+// Fill sets every cell to value.
+func (self *Grid) Fill(value int) {
+	for i := range self.Cells {
+		self.Cells[i] = value
+	}
+}
+
+// FillRect sets the cells of the rectangle x, y, w, h to value.
+func (self *Grid) FillRect(x, y, w, h, value int) {
+	for cy := y; cy < y+h; cy++ {
+		for cx := x; cx < x+w; cx++ {
+			self.Set(cx, cy, value)
+		}
+	}
+}
+
+// Rows returns the grid as rows of values.
+func (self *Grid) Rows() [][]int {
+	rows := make([][]int, self.Height)
+	for y := range rows {
+		rows[y] = self.Cells[y*self.Width : (y+1)*self.Width]
+	}
+	return rows
+}
+
+// DualGrid returns the dual grid masks for value: a (Width+1)x(Height+1)
+// grid of corners, each holding a 4-bit mask of which surrounding cells
+// equal value (1 top-left, 2 top-right, 4 bottom-left, 8 bottom-right).
+// Each mask picks one of 16 tiles drawn offset by half a cell.
+func (self *Grid) DualGrid(value int) *Grid {
+	dual := NewGrid(self.Width+1, self.Height+1, 0)
+	for y := 0; y <= self.Height; y++ {
+		for x := 0; x <= self.Width; x++ {
+			mask := 0
+			if self.At(x-1, y-1, value-1) == value {
+				mask |= 1
+			}
+			if self.At(x, y-1, value-1) == value {
+				mask |= 2
+			}
+			if self.At(x-1, y, value-1) == value {
+				mask |= 4
+			}
+			if self.At(x, y, value-1) == value {
+				mask |= 8
+			}
+			dual.Set(x, y, mask)
+		}
+	}
+	return dual
+}
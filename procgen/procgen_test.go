@@ -0,0 +1,167 @@
+package procgen
+
+import (
+	"image"
+	"slices"
+	"testing"
+)
+
+const (
+	wall  = 1
+	floor = 0
+)
+
+// connected reports whether every cell of value is reachable from every
+// other through edge neighbours.
+func connected(g *Grid, value int) bool {
+	start := slices.Index(g.Cells, value)
+	if start < 0 {
+		return true
+	}
+	seen := make([]bool, len(g.Cells))
+	seen[start] = true
+	stack := []int{start}
+	reached := 0
+	for len(stack) > 0 {
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		reached++
+		x, y := i%g.Width, i/g.Width
+		for _, d := range [4][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+			nx, ny := x+d[0], y+d[1]
+			if j := ny*g.Width + nx; g.In(nx, ny) && !seen[j] && g.Cells[j] == value {
+				seen[j] = true
+				stack = append(stack, j)
+			}
+		}
+	}
+	count := 0
+	for _, v := range g.Cells {
+		if v == value {
+			count++
+		}
+	}
+	return reached == count
+}
+
+// TestTerrainSeed verifies that heightmaps depend only on the seed and stay
+// in [0, 1].
+func TestTerrainSeed(t *testing.T) {
+	a := NewTerrain(7).Heightmap(64, 48)
+	if b := NewTerrain(7).Heightmap(64, 48); !slices.Equal(a, b) {
+		t.Fatal("same seed produced different heightmaps")
+	}
+	if c := NewTerrain(8).Heightmap(64, 48); slices.Equal(a, c) {
+		t.Fatal("different seeds produced the same heightmap")
+	}
+	for i, h := range a {
+		if h < 0 || h > 1 {
+			t.Fatalf("height %d = %v, want [0, 1]", i, h)
+		}
+	}
+}
+
+// TestTerrainLayers verifies that cells take the highest layer they reach,
+// whatever order the layers are given in.
+func TestTerrainLayers(t *testing.T) {
+	terrain := NewTerrain(3)
+	heights := terrain.Heightmap(32, 32)
+	g := terrain.Generate(32, 32, 0, TerrainLayer{Threshold: 0.6, Value: 2}, TerrainLayer{Threshold: 0.4, Value: 1})
+	for i, h := range heights {
+		want := 0
+		if h >= 0.6 {
+			want = 2
+		} else if h >= 0.4 {
+			want = 1
+		}
+		if g.Cells[i] != want {
+			t.Fatalf("cell %d with height %v = %d, want %d", i, h, g.Cells[i], want)
+		}
+	}
+}
+
+// TestCaves verifies that caves depend only on the seed and that
+// KeepLargestRegion leaves one connected floor region.
+func TestCaves(t *testing.T) {
+	a := NewCaves(11, wall, floor).Generate(80, 60)
+	if b := NewCaves(11, wall, floor).Generate(80, 60); !slices.Equal(a.Cells, b.Cells) {
+		t.Fatal("same seed produced different caves")
+	}
+	if c := NewCaves(12, wall, floor).Generate(80, 60); slices.Equal(a.Cells, c.Cells) {
+		t.Fatal("different seeds produced the same caves")
+	}
+	if len(a.Cells) != 80*60 {
+		t.Fatalf("got %d cells, want %d", len(a.Cells), 80*60)
+	}
+	for i, v := range a.Cells {
+		if v != wall && v != floor {
+			t.Fatalf("cell %d = %d, want wall or floor", i, v)
+		}
+	}
+	KeepLargestRegion(a, floor, wall)
+	if !connected(a, floor) {
+		t.Fatal("floor is not connected after KeepLargestRegion")
+	}
+	if !slices.Contains(a.Cells, floor) {
+		t.Fatal("KeepLargestRegion removed every floor cell")
+	}
+}
+
+// TestDungeon verifies that dungeons depend only on the seed, keep their
+// rooms inside the border and apart, and connect every floor cell.
+func TestDungeon(t *testing.T) {
+	for seed := range int64(20) {
+		g, rooms := NewDungeon(seed, wall, floor).Generate(64, 48)
+		g2, rooms2 := NewDungeon(seed, wall, floor).Generate(64, 48)
+		if !slices.Equal(g.Cells, g2.Cells) || !slices.Equal(rooms, rooms2) {
+			t.Fatalf("seed %d produced different dungeons", seed)
+		}
+		if len(rooms) == 0 {
+			t.Fatalf("seed %d placed no rooms", seed)
+		}
+		inner := image.Rect(1, 1, 63, 47)
+		for i, room := range rooms {
+			if !room.In(inner) {
+				t.Fatalf("seed %d: room %v leaves %v", seed, room, inner)
+			}
+			for _, other := range rooms[:i] {
+				if room.Inset(-1).Overlaps(other) {
+					t.Fatalf("seed %d: rooms %v and %v touch", seed, room, other)
+				}
+			}
+			for y := room.Min.Y; y < room.Max.Y; y++ {
+				for x := room.Min.X; x < room.Max.X; x++ {
+					if g.At(x, y, wall) != floor {
+						t.Fatalf("seed %d: room %v has wall at %d,%d", seed, room, x, y)
+					}
+				}
+			}
+		}
+		for x := range g.Width {
+			if g.At(x, 0, floor) != wall || g.At(x, g.Height-1, floor) != wall {
+				t.Fatalf("seed %d: border open at column %d", seed, x)
+			}
+		}
+		if !connected(g, floor) {
+			t.Fatalf("seed %d: floor is not connected", seed)
+		}
+	}
+}
+
+// TestDualGrid verifies the corner masks around a single cell.
+func TestDualGrid(t *testing.T) {
+	g := NewGrid(3, 3, floor)
+	g.Set(1, 1, wall)
+	dual := g.DualGrid(wall)
+	if dual.Width != 4 || dual.Height != 4 {
+		t.Fatalf("dual grid is %dx%d, want 4x4", dual.Width, dual.Height)
+	}
+	want := map[image.Point]int{{1, 1}: 8, {2, 1}: 4, {1, 2}: 2, {2, 2}: 1}
+	for y := range dual.Height {
+		for x := range dual.Width {
+			if got := dual.At(x, y, -1); got != want[image.Pt(x, y)] {
+				t.Fatalf("corner %d,%d = %d, want %d", x, y, got, want[image.Pt(x, y)])
+			}
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package procgen
+
+import (
+	"sort"
+
+	"github.com/edwinsyarief/katsu2d/jobs"
+	"github.com/edwinsyarief/katsu2d/opensimplex"
+)
+
+// TerrainLayer assigns Value to cells whose height is at least Threshold.
+type TerrainLayer struct {
+	Threshold float64
+	Value     int
+}
+
+// Terrain configures fractal noise heightmaps.
+type Terrain struct {
+	Seed int64
+	// Scale is the size in cells of the largest noise features.
+	Scale float64
+	// Octaves, Persistence and Lacunarity shape the fractal detail.
+	Octaves     int
+	Persistence float64
+	Lacunarity  float64
+}
+
+// NewTerrain creates a terrain config with common defaults.
+func NewTerrain(seed int64) Terrain {
+	return Terrain{Seed: seed, Scale: 32, Octaves: 4, Persistence: 0.5, Lacunarity: 2}
+}
+
+// Heightmap returns row-major heights in [0, 1]. Rows are spread over the
+// job pool.
+func (self Terrain) Heightmap(width, height int) []float64 {
+	noise := opensimplex.NewNormalized(self.Seed)
+	scale := self.Scale
+	if scale <= 0 {
+		scale = 1
+	}
+	octaves := max(self.Octaves, 1)
+	heights := make([]float64, width*height)
+	jobs.SubmitChunked(height, func(start, end int) {
+		for y := start; y < end; y++ {
+			for x := range width {
+				freq, amp, sum, norm := 1/scale, 1.0, 0.0, 0.0
+				for range octaves {
+					sum += noise.Eval2(float64(x)*freq, float64(y)*freq) * amp
+					norm += amp
+					freq *= self.Lacunarity
+					amp *= self.Persistence
+				}
+				heights[y*width+x] = sum / norm
+			}
+		}
+	}).Wait()
+	return heights
+}
+
+// Generate fills a grid from the heightmap, giving each cell the value of
+// the highest layer it reaches. Cells below every layer get base.
+func (self Terrain) Generate(width, height, base int, layers ...TerrainLayer) *Grid {
+	sorted := append([]TerrainLayer(nil), layers...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Threshold < sorted[j].Threshold
+	})
+	g := NewGrid(width, height, base)
+	for i, h := range self.Heightmap(width, height) {
+		for _, layer := range sorted {
+			if h < layer.Threshold {
+				break
+			}
+			g.Cells[i] = layer.Value
+		}
+	}
+	return g
+}
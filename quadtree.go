@@ -52,17 +52,27 @@ func (self *Quadtree) Insert(obj teishoku.Entity) {
 // Query finds all entities within a given rectangular bounds.
 // It returns a slice of entities that are located inside the query rectangle.
 func (self *Quadtree) Query(bounds Rectangle) []teishoku.Entity {
-	var result []teishoku.Entity
-	self.root.query(bounds, &result)
-	return result
+	return self.QueryInto(nil, bounds)
+}
+
+// QueryInto appends the entities within bounds to dst and returns it, so
+// callers can reuse one slice across queries without allocating.
+func (self *Quadtree) QueryInto(dst []teishoku.Entity, bounds Rectangle) []teishoku.Entity {
+	self.root.query(bounds, &dst)
+	return dst
 }
 
 // QueryCircle finds all entities within a given circular area.
 // It returns a slice of entities that are located inside the query circle.
 func (self *Quadtree) QueryCircle(center Vector, radius float64) []teishoku.Entity {
-	var result []teishoku.Entity
-	self.root.queryCircle(center, radius, &result)
-	return result
+	return self.QueryCircleInto(nil, center, radius)
+}
+
+// QueryCircleInto appends the entities within the circle to dst and returns
+// it.
+func (self *Quadtree) QueryCircleInto(dst []teishoku.Entity, center Vector, radius float64) []teishoku.Entity {
+	self.root.queryCircle(center, radius, &dst)
+	return dst
 }
 
 // Clear resets the quadtree by releasing the old root and creating a new empty root node with the same bounds.
@@ -74,6 +84,13 @@ func (self *Quadtree) Clear() {
 	self.root.builder = self.builder
 }
 
+// Reset clears the quadtree and changes the area it covers.
+func (self *Quadtree) Reset(bounds Rectangle) {
+	self.root.release()
+	self.root = newQuadtreeNode(bounds, 0)
+	self.root.builder = self.builder
+}
+
 // quadtreeNode represents a single node in the quadtree.
 // It can either contain entities or have four children nodes.
 type quadtreeNode struct {
@@ -247,6 +247,30 @@ func TestMaxDepth(t *testing.T) {
 	} */
 }
 
+// TestQueryIntoNoAllocs guards against per-query allocations when the
+// caller reuses its result slice.
+func TestQueryIntoNoAllocs(t *testing.T) {
+	world, qt, _ := setupTestQuadtree()
+	builder := teishoku.NewBuilder[TransformComponent](world)
+	for i := 0; i < 200; i++ {
+		ent := builder.NewEntity()
+		teishoku.SetComponent(world, ent, TransformComponent{Position: Point{X: float64(i * 5), Y: float64(i * 5)}})
+		qt.Insert(ent)
+	}
+	queryBounds := Rectangle{Min: Vector{0, 0}, Max: Vector{500, 500}}
+	result := qt.QueryInto(nil, queryBounds)
+	if len(result) != 100 {
+		t.Fatalf("Expected 100 entities, got %d", len(result))
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		result = qt.QueryInto(result[:0], queryBounds)
+		result = qt.QueryCircleInto(result[:0], Vector{250, 250}, 100)
+	})
+	if allocs != 0 {
+		t.Errorf("Expected no allocations, got %.1f per run", allocs)
+	}
+}
+
 // Benchmark constants
 var benchSizes = []int{1000, 10000, 100000}
 
@@ -0,0 +1,263 @@
+package katsu2d
+
+import "github.com/edwinsyarief/teishoku"
+
+// ObjectiveKind determines how a quest objective is completed.
+type ObjectiveKind int
+
+const (
+	// ObjectiveCount completes after Required QuestProgressEvents with a matching Tag.
+	ObjectiveCount ObjectiveKind = iota
+	// ObjectiveReachArea completes when a QuestActorComponent entity enters Area.
+	ObjectiveReachArea
+	// ObjectiveCondition completes when Condition evaluates true on the blackboard.
+	ObjectiveCondition
+)
+
+// QuestObjective is a single goal within a quest stage.
+type QuestObjective struct {
+	ID          string
+	Description string
+	Kind        ObjectiveKind
+	Tag         string
+	Required    int
+	Area        Rectangle
+	Condition   string
+	Optional    bool
+}
+
+// QuestStage groups objectives that must all be completed before the quest
+// moves on to the next stage.
+type QuestStage struct {
+	ID          string
+	Description string
+	Objectives  []QuestObjective
+}
+
+// QuestDefinition describes a quest and its ordered stages.
+type QuestDefinition struct {
+	ID          string
+	Title       string
+	Description string
+	Stages      []QuestStage
+}
+
+// QuestStatus is the lifecycle state of a quest.
+type QuestStatus int
+
+const (
+	QuestInactive QuestStatus = iota
+	QuestActive
+	QuestCompleted
+	QuestFailed
+)
+
+// ObjectiveProgress tracks one objective of the current stage.
+type ObjectiveProgress struct {
+	Count int
+	Done  bool
+}
+
+// QuestState is the runtime progress of a quest, exposed for journal UIs.
+type QuestState struct {
+	Definition *QuestDefinition
+	Status     QuestStatus
+	Stage      int
+	Progress   []ObjectiveProgress
+}
+
+// CurrentStage returns the active stage, or nil once the quest has finished.
+func (self *QuestState) CurrentStage() *QuestStage {
+	if self.Stage < 0 || self.Stage >= len(self.Definition.Stages) {
+		return nil
+	}
+	return &self.Definition.Stages[self.Stage]
+}
+
+func (self *QuestState) enterStage(stage int) {
+	self.Stage = stage
+	self.Progress = self.Progress[:0]
+	if s := self.CurrentStage(); s != nil {
+		self.Progress = append(self.Progress, make([]ObjectiveProgress, len(s.Objectives))...)
+	}
+}
+
+// QuestSave is the persisted form of a quest's progress.
+type QuestSave struct {
+	Status QuestStatus
+	Stage  int
+	Counts []int
+	Done   []bool
+}
+
+// QuestLog holds quest definitions and their progress.
+type QuestLog struct {
+	definitions map[string]*QuestDefinition
+	states      map[string]*QuestState
+	order       []string
+	world       *teishoku.World
+}
+
+// NewQuestLog creates an empty quest log.
+func NewQuestLog() *QuestLog {
+	return &QuestLog{
+		definitions: make(map[string]*QuestDefinition),
+		states:      make(map[string]*QuestState),
+	}
+}
+
+// GetQuests returns the world's quest log, creating it on first use.
+func GetQuests(w *teishoku.World) *QuestLog {
+	return EnsureResource(w, func() *QuestLog {
+		log := NewQuestLog()
+		log.world = w
+		return log
+	})
+}
+
+// Define registers a quest definition. Redefining a quest keeps its progress.
+func (self *QuestLog) Define(def QuestDefinition) {
+	d := def
+	self.definitions[def.ID] = &d
+	if state, ok := self.states[def.ID]; ok {
+		state.Definition = &d
+		return
+	}
+	self.states[def.ID] = &QuestState{Definition: &d}
+	self.order = append(self.order, def.ID)
+}
+
+// Start activates a defined quest from its first stage.
+func (self *QuestLog) Start(id string) bool {
+	state, ok := self.states[id]
+	if !ok || state.Status == QuestActive {
+		return false
+	}
+	state.Status = QuestActive
+	state.enterStage(0)
+	publishTo(self.world, QuestUpdatedEvent{QuestID: id, Stage: 0, Objective: -1})
+	self.checkStage(state)
+	return true
+}
+
+// Fail marks an active quest as failed.
+func (self *QuestLog) Fail(id string) {
+	state, ok := self.states[id]
+	if !ok || state.Status != QuestActive {
+		return
+	}
+	state.Status = QuestFailed
+	publishTo(self.world, QuestFailedEvent{QuestID: id})
+}
+
+// State returns the progress of a quest, or nil if it is not defined.
+func (self *QuestLog) State(id string) *QuestState {
+	return self.states[id]
+}
+
+// Quests returns all quests with the given status in definition order.
+func (self *QuestLog) Quests(status QuestStatus) []*QuestState {
+	var res []*QuestState
+	for _, id := range self.order {
+		if state := self.states[id]; state.Status == status {
+			res = append(res, state)
+		}
+	}
+	return res
+}
+
+// Report adds amount to every active count objective matching tag.
+func (self *QuestLog) Report(tag string, amount int) {
+	for _, id := range self.order {
+		state := self.states[id]
+		stage := state.CurrentStage()
+		if state.Status != QuestActive || stage == nil {
+			continue
+		}
+		for i, obj := range stage.Objectives {
+			if obj.Kind != ObjectiveCount || obj.Tag != tag || state.Progress[i].Done {
+				continue
+			}
+			state.Progress[i].Count = min(state.Progress[i].Count+amount, max(obj.Required, 1))
+			publishTo(self.world, QuestUpdatedEvent{QuestID: id, Stage: state.Stage, Objective: i})
+			if state.Progress[i].Count >= max(obj.Required, 1) {
+				state.Progress[i].Done = true
+			}
+		}
+		self.checkStage(state)
+	}
+}
+
+// CompleteObjective marks an objective of the current stage as done.
+func (self *QuestLog) CompleteObjective(id string, objective int) {
+	state, ok := self.states[id]
+	if !ok || state.Status != QuestActive || objective < 0 || objective >= len(state.Progress) {
+		return
+	}
+	if state.Progress[objective].Done {
+		return
+	}
+	state.Progress[objective].Done = true
+	publishTo(self.world, QuestUpdatedEvent{QuestID: id, Stage: state.Stage, Objective: objective})
+	self.checkStage(state)
+}
+
+// checkStage advances the quest while all required objectives are done.
+func (self *QuestLog) checkStage(state *QuestState) {
+	for state.Status == QuestActive {
+		stage := state.CurrentStage()
+		if stage == nil {
+			state.Status = QuestCompleted
+			publishTo(self.world, QuestCompletedEvent{QuestID: state.Definition.ID})
+			return
+		}
+		for i, obj := range stage.Objectives {
+			if !obj.Optional && !state.Progress[i].Done {
+				return
+			}
+		}
+		state.enterStage(state.Stage + 1)
+		if state.CurrentStage() != nil {
+			publishTo(self.world, QuestUpdatedEvent{QuestID: state.Definition.ID, Stage: state.Stage, Objective: -1})
+		}
+	}
+}
+
+// Snapshot returns the progress of every started quest, suitable for saving.
+func (self *QuestLog) Snapshot() map[string]QuestSave {
+	res := make(map[string]QuestSave)
+	for id, state := range self.states {
+		if state.Status == QuestInactive {
+			continue
+		}
+		save := QuestSave{Status: state.Status, Stage: state.Stage}
+		for _, p := range state.Progress {
+			save.Counts = append(save.Counts, p.Count)
+			save.Done = append(save.Done, p.Done)
+		}
+		res[id] = save
+	}
+	return res
+}
+
+// Restore applies saved progress to defined quests without publishing events.
+func (self *QuestLog) Restore(saves map[string]QuestSave) {
+	for id, state := range self.states {
+		save, ok := saves[id]
+		if !ok {
+			state.Status = QuestInactive
+			state.enterStage(0)
+			continue
+		}
+		state.Status = save.Status
+		state.enterStage(save.Stage)
+		for i := range state.Progress {
+			if i < len(save.Counts) {
+				state.Progress[i].Count = save.Counts[i]
+			}
+			if i < len(save.Done) {
+				state.Progress[i].Done = save.Done[i]
+			}
+		}
+	}
+}
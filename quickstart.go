@@ -0,0 +1,42 @@
+package katsu2d
+
+// DefaultSceneName is the name of the scene created by Engine.UseDefault2D.
+const DefaultSceneName = "main"
+
+// QuickStart creates an engine with a window of the given title and size,
+// lets setup configure it and runs the game until it exits.
+//
+//	katsu2d.QuickStart("Demo", 640, 360, func(e *katsu2d.Engine) {
+//		scene := e.UseDefault2D()
+//		// create entities in scene.World()
+//	})
+func QuickStart(title string, width, height int, setup func(*Engine), opts ...Option) error {
+	opts = append([]Option{WithWindowTitle(title), WithWindowSize(width, height)}, opts...)
+	e := NewEngine(opts...)
+	if setup != nil {
+		setup(e)
+	}
+	return e.Run()
+}
+
+// UseDefault2D adds the common 2D systems in the order they must run: input
+// first, then tweens and animation, camera follow after the simulation, and
+// sprites below text when drawing.
+func (self *Scene) UseDefault2D() *Scene {
+	self.AddSystem(NewInputSystem())
+	self.AddSystem(NewTweenSystem())
+	self.AddSystem(NewAnimationSystem())
+	self.AddUpdateSystemToGroup(LateUpdateGroup, NewCameraSystem())
+	self.AddSystem(NewSpriteSystem())
+	self.AddSystem(NewTextSystem())
+	return self
+}
+
+// UseDefault2D creates a scene named DefaultSceneName with the default 2D
+// systems, makes it the active scene and returns it.
+func (self *Engine) UseDefault2D() *Scene {
+	scene := NewScene().UseDefault2D()
+	self.scm.AddScene(DefaultSceneName, scene)
+	self.scm.SwitchTo(DefaultSceneName)
+	return scene
+}
@@ -0,0 +1,67 @@
+package katsu2d
+
+// RenderScale controls the resolution the scene renders at, as a fraction
+// of the output. With Auto set it lowers the scale while frames take longer
+// than Budget and raises it again once there is headroom.
+type RenderScale struct {
+	Scale    float64
+	Min, Max float64
+	Auto     bool
+	// Budget is the target frame time in seconds.
+	Budget float64
+	// Step is how much the scale changes per adjustment.
+	Step float64
+	// Interval is the minimum time in seconds between adjustments.
+	Interval float64
+
+	average  float64
+	cooldown float64
+}
+
+// NewRenderScale creates a fixed render scale.
+func NewRenderScale(scale float64) *RenderScale {
+	return &RenderScale{Scale: scale, Min: scale, Max: scale}
+}
+
+// NewDynamicRenderScale creates a render scale that adapts between min and
+// 1 to keep frames within budget seconds.
+func NewDynamicRenderScale(min, budget float64) *RenderScale {
+	return &RenderScale{
+		Scale:    1,
+		Min:      min,
+		Max:      1,
+		Auto:     true,
+		Budget:   budget,
+		Step:     0.05,
+		Interval: 0.5,
+	}
+}
+
+// Current returns the scale clamped to a usable range.
+func (self *RenderScale) Current() float64 {
+	return Clamp(self.Scale, 0.1, 1)
+}
+
+// Update feeds the real duration of the last frame.
+func (self *RenderScale) Update(frameTime float64) {
+	if !self.Auto || self.Budget <= 0 {
+		return
+	}
+	if self.average == 0 {
+		self.average = frameTime
+	}
+	self.average += (frameTime - self.average) * 0.1
+	self.cooldown -= frameTime
+	if self.cooldown > 0 {
+		return
+	}
+	switch {
+	case self.average > self.Budget*1.05:
+		self.Scale = max(self.Scale-self.Step, self.Min)
+	case self.average < self.Budget*0.85:
+		self.Scale = min(self.Scale+self.Step, self.Max)
+	default:
+		return
+	}
+	self.cooldown = self.Interval
+}
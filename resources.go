@@ -0,0 +1,80 @@
+package katsu2d
+
+import (
+	"reflect"
+
+	"github.com/edwinsyarief/teishoku"
+)
+
+// ResourceRemover is implemented by resources that need to release
+// something when they are removed from, or replaced in, a world.
+type ResourceRemover interface {
+	OnRemove(w *teishoku.World)
+}
+
+// resourceHooks holds the remove hooks registered per resource type.
+type resourceHooks struct {
+	remove map[reflect.Type][]func(any)
+}
+
+// GetResource returns the world's resource of type T, or nil if none is set.
+func GetResource[T any](w *teishoku.World) *T {
+	res, _ := teishoku.GetResource[T](w.Resources())
+	return res
+}
+
+// HasResource reports whether the world has a resource of type T.
+func HasResource[T any](w *teishoku.World) bool {
+	ok, _ := teishoku.HasResource[T](w.Resources())
+	return ok
+}
+
+// SetResource installs value as the world's resource of type T and returns
+// it. An existing one is removed first, running its remove hooks.
+func SetResource[T any](w *teishoku.World, value *T) *T {
+	RemoveResource[T](w)
+	w.Resources().Add(value)
+	return value
+}
+
+// EnsureResource returns the world's resource of type T, adding the one
+// made by create on first use.
+func EnsureResource[T any](w *teishoku.World, create func() *T) *T {
+	if res := GetResource[T](w); res != nil {
+		return res
+	}
+	res := create()
+	w.Resources().Add(res)
+	return res
+}
+
+// RemoveResource removes the world's resource of type T, running the hooks
+// registered with OnResourceRemove and its ResourceRemover method. It
+// reports whether a resource was set.
+func RemoveResource[T any](w *teishoku.World) bool {
+	ok, id := teishoku.HasResource[T](w.Resources())
+	if !ok {
+		return false
+	}
+	res, _ := teishoku.GetResource[T](w.Resources())
+	w.Resources().Remove(id)
+	if hooks := GetResource[resourceHooks](w); hooks != nil {
+		for _, fn := range hooks.remove[reflect.TypeFor[T]()] {
+			fn(res)
+		}
+	}
+	if r, ok := any(res).(ResourceRemover); ok {
+		r.OnRemove(w)
+	}
+	return true
+}
+
+// OnResourceRemove registers fn to run when the world's resource of type T
+// is removed or replaced through RemoveResource or SetResource.
+func OnResourceRemove[T any](w *teishoku.World, fn func(*T)) {
+	hooks := EnsureResource(w, func() *resourceHooks {
+		return &resourceHooks{remove: make(map[reflect.Type][]func(any))}
+	})
+	t := reflect.TypeFor[T]()
+	hooks.remove[t] = append(hooks.remove[t], func(res any) { fn(res.(*T)) })
+}
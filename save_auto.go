@@ -0,0 +1,141 @@
+package katsu2d
+
+import (
+	"math"
+
+	"github.com/edwinsyarief/teishoku"
+)
+
+// Auto-save reasons passed to AutoSave.Snapshot and the auto-save events.
+const (
+	AutoSaveInterval    = "interval"
+	AutoSaveSceneChange = "scene"
+)
+
+// AutoSave saves a slot on a timer, on scene changes and on events. Requests
+// are throttled and coalesced, and the file is written on the job system.
+// Attach it with WithAutoSave.
+type AutoSave struct {
+	Slot string
+	// Interval saves every Interval seconds of play; zero disables it.
+	Interval float64
+	// OnSceneChange saves whenever the current scene changes.
+	OnSceneChange bool
+	// MinGap is the minimum number of seconds between two auto-saves.
+	// Requests arriving sooner are deferred, not dropped.
+	MinGap float64
+	// Snapshot captures the game state on the main thread. Returning nil
+	// data skips the save, e.g. during a cutscene.
+	Snapshot func(reason string) ([]byte, SaveMetadata, error)
+	// Indicator draws while a save is in progress, for at least
+	// IndicatorTime seconds. elapsed counts from the start of the save.
+	Indicator     func(w *teishoku.World, rdr *BatchRenderer, elapsed float64)
+	IndicatorTime float64
+
+	slots     *SaveSlotManager
+	world     *teishoku.World
+	sinceSave float64
+	sinceLast float64
+	indicator float64
+	showing   bool
+	saving    bool
+	pending   string
+	// thumbnail is set once a thumbnail was requested for the pending save.
+	thumbnail bool
+}
+
+// NewAutoSave creates an auto-save policy for a slot, saving every interval
+// seconds, with the default indicator.
+func NewAutoSave(slot string, interval float64, snapshot func(reason string) ([]byte, SaveMetadata, error)) *AutoSave {
+	return &AutoSave{
+		Slot:          slot,
+		Interval:      interval,
+		MinGap:        10,
+		Snapshot:      snapshot,
+		Indicator:     DefaultSaveIndicator,
+		IndicatorTime: 1,
+		sinceLast:     math.Inf(1),
+	}
+}
+
+// SaveOn requests an auto-save whenever an event of type T is published on
+// w, e.g. a checkpoint being reached.
+func SaveOn[T any](w *teishoku.World, a *AutoSave, reason string) {
+	Subscribe(w, func(T) {
+		a.Request(reason)
+	})
+}
+
+// Request asks for an auto-save at the next allowed moment.
+func (self *AutoSave) Request(reason string) {
+	if self.pending == "" {
+		self.pending = reason
+	}
+}
+
+// Saving reports whether a save is being written.
+func (self *AutoSave) Saving() bool {
+	return self.saving
+}
+
+// update advances the timers and starts a pending save when allowed.
+func (self *AutoSave) update(dt float64) {
+	self.sinceSave += dt
+	self.sinceLast += dt
+	if self.showing {
+		self.indicator += dt
+		self.showing = self.saving || self.indicator < self.IndicatorTime
+	}
+	if self.Interval > 0 && self.sinceSave >= self.Interval {
+		self.Request(AutoSaveInterval)
+	}
+	if self.pending == "" || self.saving || self.sinceLast < self.MinGap || self.slots == nil || self.Snapshot == nil {
+		return
+	}
+	// Save after the next frame is drawn, so the thumbnail shows the game
+	// as it is saved.
+	if !self.thumbnail {
+		self.thumbnail = true
+		self.slots.RequestThumbnail()
+	}
+	if self.slots.ThumbnailPending() {
+		return
+	}
+	self.thumbnail = false
+	reason := self.pending
+	self.pending = ""
+	self.sinceSave = 0
+	data, meta, err := self.Snapshot(reason)
+	if err == nil && data == nil {
+		return
+	}
+	self.sinceLast = 0
+	if err != nil {
+		publishTo(self.world, AutoSaveFinishedEvent{Slot: self.Slot, Reason: reason, Err: err})
+		return
+	}
+	self.saving, self.showing, self.indicator = true, true, 0
+	publishTo(self.world, AutoSaveStartedEvent{Slot: self.Slot, Reason: reason})
+	self.slots.SaveAsync(self.Slot, data, meta, func(err error) {
+		self.saving = false
+		publishTo(self.world, AutoSaveFinishedEvent{Slot: self.Slot, Reason: reason, Err: err})
+	})
+}
+
+// draw runs the indicator while a save is shown.
+func (self *AutoSave) draw(w *teishoku.World, rdr *BatchRenderer) {
+	if self.Indicator != nil && self.showing {
+		self.Indicator(w, rdr, self.indicator)
+	}
+}
+
+// DefaultSaveIndicator draws a small pulsing square in the bottom-right
+// corner of the screen.
+func DefaultSaveIndicator(w *teishoku.World, rdr *BatchRenderer, elapsed float64) {
+	const size, margin = 12, 16
+	bounds := rdr.GetScreen().Bounds()
+	alpha := 0.6 + 0.4*math.Cos(elapsed*2*Pi)
+	var m Matrix
+	m.Translate(float64(bounds.Max.X-size-margin), float64(bounds.Max.Y-size-margin))
+	rdr.AddQuadMatrix(m, GetTextureManager(w).Get(0), PremultiplyRGBA(1, 1, 1, alpha), 0, 0, 1, 1, size, size)
+}
@@ -0,0 +1,362 @@
+package katsu2d
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/edwinsyarief/katsu2d/jobs"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const saveFileMagic = "K2SV"
+
+// ErrSaveSlotNotFound is returned when a slot has no save.
+var ErrSaveSlotNotFound = errors.New("katsu2d: save slot not found")
+
+// SaveMetadata describes a save slot.
+type SaveMetadata struct {
+	Slot      string    `json:"slot"`
+	Label     string    `json:"label"`
+	Timestamp time.Time `json:"timestamp"`
+	// PlayTime is the accumulated play time in seconds.
+	PlayTime     float64           `json:"play_time"`
+	Custom       map[string]string `json:"custom,omitempty"`
+	HasThumbnail bool              `json:"has_thumbnail"`
+	// Version is the engine and content version that wrote the save.
+	Version VersionStamp `json:"version"`
+}
+
+// SaveSlotManager stores game saves in named slots inside a directory, each
+// as a single file holding the metadata, a thumbnail and the save data.
+// Files are written to a temporary file and swapped in with a rename, so a
+// crash mid-write never corrupts an existing save.
+type SaveSlotManager struct {
+	Dir string
+	// ThumbnailWidth and ThumbnailHeight size the captured screenshots.
+	ThumbnailWidth, ThumbnailHeight int
+
+	playTime     float64
+	thumb        *ebiten.Image
+	captured     bool
+	thumbPending bool
+	inflight     []*jobs.Handle
+}
+
+// NewSaveSlotManager creates a manager storing saves in dir.
+func NewSaveSlotManager(dir string) *SaveSlotManager {
+	return &SaveSlotManager{
+		Dir:             dir,
+		ThumbnailWidth:  160,
+		ThumbnailHeight: 90,
+	}
+}
+
+// PlayTime returns the play time in seconds, restored by Load.
+func (self *SaveSlotManager) PlayTime() float64 {
+	return self.playTime
+}
+
+// AddPlayTime advances the play time; the engine calls it every tick.
+func (self *SaveSlotManager) AddPlayTime(dt float64) {
+	self.playTime += dt
+}
+
+// RequestThumbnail captures the next drawn frame as the thumbnail of the
+// following saves. Request it a frame before calling Save, e.g. when the
+// pause menu is asked for, so the thumbnail shows the current game;
+// AutoSave does this itself. Without a request the last captured
+// thumbnail is used.
+func (self *SaveSlotManager) RequestThumbnail() {
+	self.thumbPending = self.ThumbnailWidth > 0 && self.ThumbnailHeight > 0
+}
+
+// ThumbnailPending reports whether a requested thumbnail has not been
+// captured yet.
+func (self *SaveSlotManager) ThumbnailPending() bool {
+	return self.thumbPending
+}
+
+// CaptureThumbnail keeps a downscaled copy of screen to store with the next
+// save. The engine calls it after drawing a frame while a thumbnail is
+// requested.
+func (self *SaveSlotManager) CaptureThumbnail(screen *ebiten.Image) {
+	self.thumbPending = false
+	if self.ThumbnailWidth <= 0 || self.ThumbnailHeight <= 0 {
+		return
+	}
+	self.thumb, _ = resizeRenderTarget(self.thumb, self.ThumbnailWidth, self.ThumbnailHeight, "save thumbnail")
+	size := screen.Bounds().Size()
+	op := &ebiten.DrawImageOptions{Filter: ebiten.FilterLinear, Blend: ebiten.BlendCopy}
+	op.GeoM.Scale(float64(self.ThumbnailWidth)/float64(size.X), float64(self.ThumbnailHeight)/float64(size.Y))
+	self.thumb.DrawImage(screen, op)
+	self.captured = true
+}
+
+// Save writes data to a slot with the last captured thumbnail. The
+// timestamp, slot and play time of meta are filled in.
+func (self *SaveSlotManager) Save(slot string, data []byte, meta SaveMetadata) error {
+	file, err := encodeSaveFile(data, self.stamp(slot, meta), self.thumbnailImage())
+	if err != nil {
+		return err
+	}
+	return self.write(slot, file)
+}
+
+// SaveAsync is Save with the encoding and file write done on the job
+// system. The data must not be modified until done runs; done is called
+// from jobs.Sync, which the engine runs at the start of each update.
+func (self *SaveSlotManager) SaveAsync(slot string, data []byte, meta SaveMetadata, done func(error)) *jobs.Handle {
+	meta = self.stamp(slot, meta)
+	thumb := self.thumbnailImage()
+	var err error
+	h := jobs.Submit(func() {
+		var file []byte
+		if file, err = encodeSaveFile(data, meta, thumb); err == nil {
+			err = self.write(slot, file)
+		}
+	})
+	if done != nil {
+		jobs.Then(h, func() { done(err) })
+	}
+	self.inflight = slices.DeleteFunc(self.inflight, (*jobs.Handle).Done)
+	self.inflight = append(self.inflight, h)
+	return h
+}
+
+// Flush waits for every SaveAsync write to finish and runs their done
+// callbacks, e.g. before the game exits.
+func (self *SaveSlotManager) Flush() {
+	for _, h := range self.inflight {
+		h.Wait()
+	}
+	self.inflight = self.inflight[:0]
+	jobs.Sync()
+}
+
+// Load reads the data and metadata of a slot and restores its play time.
+// Saves from a newer content version, or one without migrations to the
+// current version, fail the VersionStamp check; their metadata is still
+// returned.
+func (self *SaveSlotManager) Load(slot string) ([]byte, SaveMetadata, error) {
+	f, err := os.Open(self.path(slot))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, SaveMetadata{}, ErrSaveSlotNotFound
+	}
+	if err != nil {
+		return nil, SaveMetadata{}, err
+	}
+	defer f.Close()
+	meta, err := readSaveMetadata(f)
+	if err != nil {
+		return nil, SaveMetadata{}, err
+	}
+	if err := meta.Version.Check(); err != nil {
+		return nil, meta, err
+	}
+	if _, err := readSaveSection(f); err != nil {
+		return nil, SaveMetadata{}, err
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, SaveMetadata{}, err
+	}
+	self.playTime = meta.PlayTime
+	return data, meta, nil
+}
+
+// Metadata reads only the metadata of a slot.
+func (self *SaveSlotManager) Metadata(slot string) (SaveMetadata, error) {
+	f, err := os.Open(self.path(slot))
+	if errors.Is(err, os.ErrNotExist) {
+		return SaveMetadata{}, ErrSaveSlotNotFound
+	}
+	if err != nil {
+		return SaveMetadata{}, err
+	}
+	defer f.Close()
+	return readSaveMetadata(f)
+}
+
+// Thumbnail reads the screenshot stored with a slot, or nil if it has none.
+func (self *SaveSlotManager) Thumbnail(slot string) (image.Image, error) {
+	f, err := os.Open(self.path(slot))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrSaveSlotNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if _, err := readSaveMetadata(f); err != nil {
+		return nil, err
+	}
+	section, err := readSaveSection(f)
+	if err != nil || len(section) == 0 {
+		return nil, err
+	}
+	return png.Decode(bytes.NewReader(section))
+}
+
+// Slots lists the metadata of every slot, most recent first.
+func (self *SaveSlotManager) Slots() ([]SaveMetadata, error) {
+	entries, err := os.ReadDir(self.Dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var slots []SaveMetadata
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".sav")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		meta, err := self.Metadata(name)
+		if err != nil {
+			continue
+		}
+		slots = append(slots, meta)
+	}
+	slices.SortFunc(slots, func(a, b SaveMetadata) int {
+		return b.Timestamp.Compare(a.Timestamp)
+	})
+	return slots, nil
+}
+
+// Exists reports whether a slot holds a save.
+func (self *SaveSlotManager) Exists(slot string) bool {
+	_, err := os.Stat(self.path(slot))
+	return err == nil
+}
+
+// Delete removes a slot.
+func (self *SaveSlotManager) Delete(slot string) error {
+	err := os.Remove(self.path(slot))
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrSaveSlotNotFound
+	}
+	return err
+}
+
+func (self *SaveSlotManager) path(slot string) string {
+	return filepath.Join(self.Dir, filepath.Base(slot)+".sav")
+}
+
+// thumbnailImage reads back the captured thumbnail, or nil if none.
+func (self *SaveSlotManager) thumbnailImage() *image.RGBA {
+	if !self.captured {
+		return nil
+	}
+	img := image.NewRGBA(self.thumb.Bounds())
+	self.thumb.ReadPixels(img.Pix)
+	return img
+}
+
+// stamp fills in the slot, timestamp and play time of meta.
+func (self *SaveSlotManager) stamp(slot string, meta SaveMetadata) SaveMetadata {
+	meta.Slot = slot
+	meta.Timestamp = time.Now()
+	meta.Version = CurrentVersion()
+	if meta.PlayTime == 0 {
+		meta.PlayTime = self.playTime
+	}
+	return meta
+}
+
+// encodeSaveFile lays out a save file: magic, metadata JSON, thumbnail PNG
+// and data.
+func encodeSaveFile(data []byte, meta SaveMetadata, thumb *image.RGBA) ([]byte, error) {
+	var thumbBytes bytes.Buffer
+	if thumb != nil {
+		if err := png.Encode(&thumbBytes, thumb); err != nil {
+			return nil, fmt.Errorf("failed to encode save thumbnail: %w", err)
+		}
+		meta.HasThumbnail = true
+	}
+	metaBytes, err := json.Marshal(meta)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode save metadata: %w", err)
+	}
+	var buf bytes.Buffer
+	buf.WriteString(saveFileMagic)
+	writeSaveSection(&buf, metaBytes)
+	writeSaveSection(&buf, thumbBytes.Bytes())
+	buf.Write(data)
+	return buf.Bytes(), nil
+}
+
+// write stores a file atomically: it is written and synced under a
+// temporary name, then renamed over the previous save.
+func (self *SaveSlotManager) write(slot string, file []byte) error {
+	if err := os.MkdirAll(self.Dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(self.Dir, filepath.Base(slot)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(file); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), self.path(slot))
+}
+
+func writeSaveSection(buf *bytes.Buffer, section []byte) {
+	buf.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(section))))
+	buf.Write(section)
+}
+
+// readSaveSection reads a length-prefixed section. The section grows as
+// its bytes arrive rather than trusting the stored size, so a corrupt size
+// cannot allocate more than the file holds.
+func readSaveSection(r io.Reader) ([]byte, error) {
+	var size uint32
+	if err := binary.Read(r, binary.LittleEndian, &size); err != nil {
+		return nil, fmt.Errorf("corrupt save file: %w", err)
+	}
+	section, err := io.ReadAll(io.LimitReader(r, int64(size)))
+	if err != nil {
+		return nil, fmt.Errorf("corrupt save file: %w", err)
+	}
+	if len(section) != int(size) {
+		return nil, fmt.Errorf("corrupt save file: %w", io.ErrUnexpectedEOF)
+	}
+	return section, nil
+}
+
+func readSaveMetadata(r io.Reader) (SaveMetadata, error) {
+	magic := make([]byte, len(saveFileMagic))
+	if _, err := io.ReadFull(r, magic); err != nil || string(magic) != saveFileMagic {
+		return SaveMetadata{}, errors.New("corrupt save file: bad header")
+	}
+	section, err := readSaveSection(r)
+	if err != nil {
+		return SaveMetadata{}, err
+	}
+	var meta SaveMetadata
+	if err := json.Unmarshal(section, &meta); err != nil {
+		return SaveMetadata{}, fmt.Errorf("corrupt save file: %w", err)
+	}
+	return meta, nil
+}
@@ -0,0 +1,173 @@
+package katsu2d
+
+import (
+	"image/color"
+
+	"github.com/edwinsyarief/teishoku"
+)
+
+// TransitionKind selects how a scene change is covered.
+type TransitionKind int
+
+const (
+	// TransitionFade fades to a color and back using a FadeOverlaySystem.
+	TransitionFade TransitionKind = iota
+	// TransitionWipe sweeps a solid color across the screen and off again.
+	TransitionWipe
+)
+
+// WipeDirection is the direction a wipe transition travels in.
+type WipeDirection int
+
+const (
+	WipeRight WipeDirection = iota
+	WipeLeft
+	WipeDown
+	WipeUp
+)
+
+// SceneTransition describes how a SceneManager covers a scene change. The
+// change happens once the screen is fully covered, after Duration seconds,
+// and the new scene is revealed over another Duration seconds.
+type SceneTransition struct {
+	Kind      TransitionKind
+	Duration  float64
+	Color     color.RGBA
+	Direction WipeDirection
+}
+
+// FadeTransition fades to col and back, each half taking duration seconds.
+func FadeTransition(duration float64, col color.RGBA) SceneTransition {
+	return SceneTransition{Kind: TransitionFade, Duration: duration, Color: col}
+}
+
+// WipeTransition wipes col across the screen and off again, each half
+// taking duration seconds.
+func WipeTransition(duration float64, col color.RGBA, direction WipeDirection) SceneTransition {
+	return SceneTransition{Kind: TransitionWipe, Duration: duration, Color: col, Direction: direction}
+}
+
+// sceneTransition is a transition in progress.
+type sceneTransition struct {
+	SceneTransition
+	change   func()
+	elapsed  float64
+	covered  bool
+	world    *teishoku.World
+	fade     *FadeOverlaySystem
+	fadeItem teishoku.Entity
+	vertices Vertices
+	indices  Indices
+}
+
+// IsTransitioning reports whether a scene transition is in progress.
+func (self *SceneManager) IsTransitioning() bool {
+	return self.transition != nil
+}
+
+// run applies change, covered by the first transition if one is given.
+func (self *SceneManager) run(transition []SceneTransition, change func()) {
+	if self.transition != nil && !self.transition.covered {
+		// Queue behind the change the running transition is about to make.
+		previous := self.transition.change
+		self.transition.change = func() {
+			previous()
+			change()
+		}
+		return
+	}
+	if len(transition) == 0 || transition[0].Duration <= 0 {
+		change()
+		return
+	}
+	t := &sceneTransition{
+		SceneTransition: transition[0],
+		change:          change,
+		vertices:        make(Vertices, 4),
+		indices:         Indices{0, 1, 2, 0, 2, 3},
+	}
+	if t.Kind == TransitionFade {
+		t.world = teishoku.NewWorld(4)
+		SetResource(t.world, self.engine.TextureManager())
+		t.fade = NewFadeOverlaySystem()
+		t.fade.Initialize(t.world)
+		t.spawnFade(FadeTypeOut)
+	}
+	self.transition = t
+}
+
+// updateTransition advances the running transition, applying its scene
+// change once the screen is covered.
+func (self *SceneManager) updateTransition(dt float64) {
+	t := self.transition
+	if t == nil {
+		return
+	}
+	t.elapsed += dt
+	progress := Clamp(t.elapsed/t.Duration, 0, 1)
+	if t.fade != nil {
+		tween := teishoku.GetComponent[TweenComponent](t.world, t.fadeItem)
+		tween.Time = t.elapsed
+		tween.Current = Lerp(tween.Start, tween.End, progress)
+		tween.Finished = progress >= 1
+		t.fade.Update(t.world, dt)
+	}
+	if progress < 1 {
+		return
+	}
+	if t.covered {
+		self.transition = nil
+		return
+	}
+	t.change()
+	t.covered = true
+	t.elapsed = 0
+	if t.fade != nil {
+		DestroyEntity(t.world, t.fadeItem)
+		t.spawnFade(FadeTypeIn)
+	}
+}
+
+// drawTransition draws the running transition over the whole screen.
+func (self *SceneManager) drawTransition(rdr *BatchRenderer) {
+	t := self.transition
+	if t == nil {
+		return
+	}
+	if t.fade != nil {
+		t.fade.Draw(t.world, rdr)
+		return
+	}
+	progress := Clamp(t.elapsed/t.Duration, 0, 1)
+	// The covered span along the wipe axis, as fractions of the screen.
+	from, to := 0.0, progress
+	if t.covered {
+		from, to = progress, 1
+	}
+	if t.Direction == WipeLeft || t.Direction == WipeUp {
+		from, to = 1-to, 1-from
+	}
+	screen := rdr.GetScreen()
+	width, height := screen.Bounds().Dx(), screen.Bounds().Dy()
+	updateOverlayVertices(t.vertices, width, height, t.Color)
+	if t.Direction == WipeRight || t.Direction == WipeLeft {
+		t.vertices[0].DstX, t.vertices[3].DstX = float32(from*float64(width)), float32(from*float64(width))
+		t.vertices[1].DstX, t.vertices[2].DstX = float32(to*float64(width)), float32(to*float64(width))
+	} else {
+		t.vertices[0].DstY, t.vertices[1].DstY = float32(from*float64(height)), float32(from*float64(height))
+		t.vertices[2].DstY, t.vertices[3].DstY = float32(to*float64(height)), float32(to*float64(height))
+	}
+	rdr.AddCustomMeshes(t.vertices, t.indices, GetTextureManager(self.engine.World()).Get(0))
+}
+
+// spawnFade creates the fade overlay entity for one half of the transition.
+func (self *sceneTransition) spawnFade(fadeType FadeType) {
+	start, end := 0.0, 1.0
+	if fadeType == FadeTypeIn {
+		start, end = 1, 0
+	}
+	self.fadeItem = TrackEntity(self.world, self.world.CreateEntity())
+	teishoku.SetComponent2(self.world, self.fadeItem,
+		FadeOverlayComponent{FadeType: fadeType, FadeColor: self.Color, CurrentFade: start},
+		TweenComponent{Start: start, End: end, Duration: self.Duration, Current: start})
+}
@@ -1,6 +1,8 @@
 package katsu2d
 
 import (
+	"slices"
+
 	"github.com/edwinsyarief/teishoku"
 	"github.com/hajimehoshi/ebiten/v2"
 )
@@ -8,15 +10,30 @@ import (
 // Scene represents a game scene. It is a self-contained unit
 // with its own World, systems, and lifecycle hooks.
 type Scene struct {
-	world         *teishoku.World
-	OnEnter       func(*Engine)
-	OnExit        func(*Engine)
+	world   *teishoku.World
+	OnEnter func(*Engine)
+	OnExit  func(*Engine)
+	// OnPause and OnResume run when another scene is pushed on top of this
+	// one and when it is popped again.
+	OnPause       func(*Engine)
+	OnResume      func(*Engine)
 	OnUpdate      func(float64)
 	OnBeforeDraw  func(*ebiten.Image)
 	OnAfterDraw   func(*ebiten.Image)
 	UpdateSystems []UpdateSystem
-	DrawSystems   []DrawSystem
-	Width, Height int
+	// LateUpdateSystems run after all simulation systems of a tick.
+	LateUpdateSystems []UpdateSystem
+	// PresentationSystems run once per rendered frame before drawing.
+	PresentationSystems []UpdateSystem
+	DrawSystems         []DrawSystem
+	Width, Height       int
+	// Transparent scenes draw the scene below them first, e.g. for a pause
+	// menu over gameplay. The scene below does not update.
+	Transparent  bool
+	drawCommands DrawCommandList
+	// initialized is set once the systems have run Initialize, so entering
+	// the scene again does not subscribe their handlers twice.
+	initialized bool
 }
 
 // NewScene creates a new scene with its own dedicated World.
@@ -36,29 +53,81 @@ func (self *Scene) World() *teishoku.World {
 
 // AddSystem adds an update and/or draw system to the scene.
 func (self *Scene) AddSystem(sys any) {
-	if us, ok := sys.(UpdateSystem); ok {
+	us, isUpdate := sys.(UpdateSystem)
+	if isUpdate {
 		self.AddUpdateSystem(us)
 	}
 	if ds, ok := sys.(DrawSystem); ok {
-		self.AddDrawSystem(ds)
+		self.DrawSystems = append(self.DrawSystems, ds)
+		if !isUpdate {
+			self.initializeAdded(ds)
+		}
 	}
 }
 func (self *Scene) AddUpdateSystem(us UpdateSystem) {
 	self.UpdateSystems = append(self.UpdateSystems, us)
+	self.initializeAdded(us)
+}
+
+// AddUpdateSystemToGroup adds an update system to the given system group.
+func (self *Scene) AddUpdateSystemToGroup(group SystemGroup, us UpdateSystem) {
+	switch group {
+	case LateUpdateGroup:
+		self.LateUpdateSystems = append(self.LateUpdateSystems, us)
+	case PresentationGroup:
+		self.PresentationSystems = append(self.PresentationSystems, us)
+	default:
+		self.UpdateSystems = append(self.UpdateSystems, us)
+	}
+	self.initializeAdded(us)
 }
 func (self *Scene) AddDrawSystem(ds DrawSystem) {
 	self.DrawSystems = append(self.DrawSystems, ds)
+	self.initializeAdded(ds)
+}
+
+// initializeAdded initializes a system added after the scene was entered,
+// as entering again does not initialize systems.
+func (self *Scene) initializeAdded(sys interface{ Initialize(*teishoku.World) }) {
+	if self.initialized {
+		sys.Initialize(self.World())
+	}
+}
+
+// SetDrawBatchBand groups z-keyed draws whose Z fall in the same band of
+// the given size by texture, trading exact order within a band for fewer
+// draw calls. Zero, the default, keeps strict Z order.
+func (self *Scene) SetDrawBatchBand(band float64) {
+	self.drawCommands.Band = band
 }
+
 func (self *Scene) ClearSystems() {
 	self.UpdateSystems = self.UpdateSystems[:0]
+	self.LateUpdateSystems = self.LateUpdateSystems[:0]
+	self.PresentationSystems = self.PresentationSystems[:0]
 	self.DrawSystems = self.DrawSystems[:0]
+	self.initialized = false
 }
 
 // Update runs all the scene's update systems.
 func (self *Scene) Update(dt float64) {
 	// Then, run the scene's own update systems.
 	for _, us := range self.UpdateSystems {
-		us.Update(self.World(), dt)
+		runUpdateSystem(self.World(), us, dt)
+	}
+}
+
+// LateUpdate runs all the scene's late update systems.
+func (self *Scene) LateUpdate(dt float64) {
+	for _, us := range self.LateUpdateSystems {
+		runUpdateSystem(self.World(), us, dt)
+	}
+}
+
+// Present runs all the scene's presentation systems.
+func (self *Scene) Present(dt float64) {
+	for _, us := range self.PresentationSystems {
+		runUpdateSystem(self.World(), us, dt)
 	}
 }
 
@@ -73,17 +142,20 @@ func (self *Scene) OnLayoutChanged(width, height int) {
 }
 
 // Draw runs all the scene's draw systems using the engine's shared renderer.
+// Systems implementing ZDrawSystem are interleaved by Z.
 func (self *Scene) Draw(world *teishoku.World, renderer *BatchRenderer) {
-	for _, ds := range self.DrawSystems {
-		ds.Draw(world, renderer)
-	}
+	drawSystemsInOrder(world, renderer, self.DrawSystems, &self.drawCommands)
 }
 
-// SceneManager manages scenes and scene transitions.
+// SceneManager manages named scenes on a stack. Only the top scene updates;
+// scenes below a Transparent scene are still drawn, which suits pause menus
+// and dialogs shown over gameplay.
 type SceneManager struct {
-	engine  *Engine
-	scenes  map[string]*Scene
-	current *Scene
+	engine     *Engine
+	scenes     map[string]*Scene
+	stack      []*Scene
+	current    *Scene
+	transition *sceneTransition
 }
 
 // NewSceneManager creates a new scene manager.
@@ -96,6 +168,15 @@ func NewSceneManager(e *Engine) *SceneManager {
 
 // AddScene adds a scene by name.
 func (self *SceneManager) AddScene(name string, scene *Scene) {
+	if self.engine != nil && GetTimeControl(scene.World()) == nil {
+		SetResource(scene.World(), self.engine.timeControl)
+	}
+	if self.engine != nil && GetDrawToggles(scene.World()) == nil {
+		SetResource(scene.World(), self.engine.drawToggles)
+	}
+	if self.engine != nil && GetFrameStats(scene.World()) == nil {
+		SetResource(scene.World(), self.engine.frameStats)
+	}
 	self.scenes[name] = scene
 }
 
@@ -109,17 +190,104 @@ func (self *SceneManager) CurrentScene() *Scene {
 	return self.current
 }
 
-// SwitchTo switches to a new scene, running its OnEnter hook and the old scene's OnExit hook.
-// This is the only place where the active scene is changed.
-func (self *SceneManager) SwitchTo(name string) {
+// Stack returns the scene stack, bottom first.
+func (self *SceneManager) Stack() []*Scene {
+	return self.stack
+}
+
+// SwitchTo clears the stack and makes a named scene the only scene, running
+// the OnExit hooks of the removed scenes and the new scene's OnEnter hook.
+// An optional transition covers the change.
+func (self *SceneManager) SwitchTo(name string, transition ...SceneTransition) {
+	newScene, ok := self.scenes[name]
+	if !ok {
+		return
+	}
+	self.run(transition, func() {
+		for len(self.stack) > 0 {
+			self.exitTop()
+		}
+		self.enter(newScene)
+	})
+}
+
+// Push suspends the current scene and enters a named scene on top of it.
+func (self *SceneManager) Push(name string, transition ...SceneTransition) {
+	newScene, ok := self.scenes[name]
+	if !ok {
+		return
+	}
+	self.run(transition, func() {
+		if self.current != nil && self.current.OnPause != nil {
+			self.current.OnPause(self.engine)
+		}
+		self.enter(newScene)
+	})
+}
+
+// Pop exits the top scene and resumes the one below it.
+func (self *SceneManager) Pop(transition ...SceneTransition) {
+	if len(self.stack) == 0 {
+		return
+	}
+	self.run(transition, func() {
+		self.exitTop()
+		if self.current != nil {
+			w, h := self.engine.HiResSize()
+			updateHiResDisplayResource(self.current.World(), w, h)
+			self.current.OnLayoutChanged(w, h)
+			if self.current.OnResume != nil {
+				self.current.OnResume(self.engine)
+			}
+		}
+	})
+}
+
+// Replace exits the top scene and enters a named scene in its place.
+func (self *SceneManager) Replace(name string, transition ...SceneTransition) {
 	newScene, ok := self.scenes[name]
 	if !ok {
 		return
 	}
-	if self.current != nil && self.current.OnExit != nil {
-		self.current.OnExit(self.engine)
+	self.run(transition, func() {
+		if len(self.stack) > 0 {
+			self.exitTop()
+		}
+		self.enter(newScene)
+	})
+}
+
+// visibleScenes returns the scenes to draw, bottom first: the top scene and
+// every scene below an unbroken run of transparent scenes.
+func (self *SceneManager) visibleScenes() []*Scene {
+	first := len(self.stack) - 1
+	for first > 0 && self.stack[first].Transparent {
+		first--
 	}
-	self.current = newScene
+	return self.stack[max(first, 0):]
+}
+
+func (self *SceneManager) exitTop() {
+	top := self.stack[len(self.stack)-1]
+	self.stack[len(self.stack)-1] = nil
+	self.stack = self.stack[:len(self.stack)-1]
+	self.current = nil
+	if len(self.stack) > 0 {
+		self.current = self.stack[len(self.stack)-1]
+	}
+	if top.OnExit != nil {
+		top.OnExit(self.engine)
+	}
+	if self.engine != nil && !slices.Contains(self.stack, top) {
+		self.engine.forgetTransforms(top.World())
+	}
+}
+
+// enter pushes a scene and initializes it. This is the only place where a
+// scene is entered.
+func (self *SceneManager) enter(scene *Scene) {
+	self.stack = append(self.stack, scene)
+	self.current = scene
 	initializeAssetManagers(self.current.World(),
 		self.engine.TextureManager(),
 		self.engine.FontManager(),
@@ -132,11 +300,27 @@ func (self *SceneManager) SwitchTo(name string) {
 	if self.current.OnEnter != nil {
 		self.current.OnEnter(self.engine)
 	}
-	for _, us := range self.current.UpdateSystems {
-		us.Initialize(self.current.World())
+	self.current.initializeSystems()
+	self.current.OnLayoutChanged(w, h)
+}
+
+// initializeSystems initializes the scene's systems the first time the
+// scene is entered.
+func (self *Scene) initializeSystems() {
+	if self.initialized {
+		return
+	}
+	self.initialized = true
+	for _, us := range self.UpdateSystems {
+		us.Initialize(self.World())
 	}
-	for _, ds := range self.current.DrawSystems {
-		ds.Initialize(self.current.World())
+	for _, us := range self.LateUpdateSystems {
+		us.Initialize(self.World())
+	}
+	for _, us := range self.PresentationSystems {
+		us.Initialize(self.World())
+	}
+	for _, ds := range self.DrawSystems {
+		ds.Initialize(self.World())
 	}
-	self.current.OnLayoutChanged(w, h)
 }
@@ -0,0 +1,153 @@
+package katsu2d
+
+import (
+	"image/color"
+
+	"github.com/edwinsyarief/teishoku"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// ViewDock is the screen corner a docked SecondaryView is anchored to.
+type ViewDock int
+
+const (
+	DockTopLeft ViewDock = iota
+	DockTopRight
+	DockBottomLeft
+	DockBottomRight
+)
+
+// SecondaryView renders extra draw systems, such as a debug map or an editor
+// panel, from the same world into an offscreen buffer, which is then drawn
+// into a docked corner of the main window on top of the game. Ebitengine
+// drives a single window, so views are always such overlays. Register it
+// with Engine.AddOverlaySystem.
+type SecondaryView struct {
+	Width, Height int
+	Dock          ViewDock
+	Margin        float64
+	Scale         float64
+	Visible       bool
+	Background    color.RGBA
+	Border        color.RGBA
+	// Camera, when set, pans and zooms the view's content.
+	Camera *Camera
+	// World overrides the world the view draws; nil uses the world passed to Draw.
+	World *teishoku.World
+
+	drawSystems  []DrawSystem
+	drawCommands DrawCommandList
+	renderer     *BatchRenderer
+	buffer       *ebiten.Image
+	border       [4]ebiten.Vertex
+	initialized  bool
+}
+
+// NewSecondaryView creates a visible docked view of the given size drawing systems.
+func NewSecondaryView(width, height int, systems ...DrawSystem) *SecondaryView {
+	return &SecondaryView{
+		Width:       width,
+		Height:      height,
+		Dock:        DockTopRight,
+		Margin:      8,
+		Scale:       1,
+		Visible:     true,
+		Background:  color.RGBA{A: 200},
+		Border:      color.RGBA{R: 255, G: 255, B: 255, A: 160},
+		drawSystems: systems,
+		renderer:    NewBatchRenderer(),
+	}
+}
+
+// AddSystem adds a draw system to the view.
+func (self *SecondaryView) AddSystem(sys DrawSystem) {
+	self.drawSystems = append(self.drawSystems, sys)
+	self.initialized = false
+}
+
+// Toggle shows or hides the view.
+func (self *SecondaryView) Toggle() {
+	self.Visible = !self.Visible
+}
+
+func (self *SecondaryView) Initialize(w *teishoku.World) {
+	if self.initialized {
+		return
+	}
+	world := w
+	if self.World != nil {
+		world = self.World
+	}
+	for _, ds := range self.drawSystems {
+		ds.Initialize(world)
+	}
+	self.initialized = true
+}
+
+// Bounds returns where the view is drawn on a screen of the given size.
+func (self *SecondaryView) Bounds(screenWidth, screenHeight int) Rectangle {
+	scale := self.Scale
+	if scale <= 0 {
+		scale = 1
+	}
+	w, h := float64(self.Width)*scale, float64(self.Height)*scale
+	x, y := self.Margin, self.Margin
+	if self.Dock == DockTopRight || self.Dock == DockBottomRight {
+		x = float64(screenWidth) - w - self.Margin
+	}
+	if self.Dock == DockBottomLeft || self.Dock == DockBottomRight {
+		y = float64(screenHeight) - h - self.Margin
+	}
+	return NewRectangle(x, y, x+w, y+h)
+}
+
+func (self *SecondaryView) Draw(w *teishoku.World, rdr *BatchRenderer) {
+	if !self.Visible || self.Width <= 0 || self.Height <= 0 {
+		return
+	}
+	if !self.initialized {
+		self.Initialize(w)
+	}
+	world := w
+	if self.World != nil {
+		world = self.World
+	}
+	self.buffer, _ = resizeRenderTarget(self.buffer, self.Width, self.Height, "secondary view")
+	self.buffer.Fill(self.Background)
+
+	self.renderer.Begin(self.buffer)
+	if self.Camera != nil {
+		self.Camera.Width, self.Camera.Height = self.Width, self.Height
+		self.renderer.SetView(self.Camera.Matrix())
+	}
+	drawSystemsInOrder(world, self.renderer, self.drawSystems, &self.drawCommands)
+	self.renderer.Flush()
+	self.renderer.ResetView()
+
+	rdr.Flush()
+	screen := rdr.GetScreen()
+	bounds := self.Bounds(screen.Bounds().Dx(), screen.Bounds().Dy())
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(bounds.Width()/float64(self.Width), bounds.Height()/float64(self.Height))
+	op.GeoM.Translate(bounds.Min.X, bounds.Min.Y)
+	screen.DrawImage(self.buffer, op)
+	self.drawBorder(bounds, GetTextureManager(w), rdr)
+}
+
+func (self *SecondaryView) drawBorder(bounds Rectangle, tm *TextureManager, rdr *BatchRenderer) {
+	if self.Border.A == 0 || tm == nil {
+		return
+	}
+	img := tm.Get(0)
+	edges := [4][2]Vector{
+		{bounds.Min, V(bounds.Max.X, bounds.Min.Y+1)},
+		{V(bounds.Min.X, bounds.Max.Y-1), bounds.Max},
+		{bounds.Min, V(bounds.Min.X+1, bounds.Max.Y)},
+		{V(bounds.Max.X-1, bounds.Min.Y), bounds.Max},
+	}
+	for _, edge := range edges {
+		self.border = quadFromCorners(edge[0], V(edge[1].X, edge[0].Y), edge[1], V(edge[0].X, edge[1].Y),
+			self.Border, 0, 0, 1, 1)
+		rdr.AddQuads(self.border[:], img)
+	}
+}
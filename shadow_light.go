@@ -0,0 +1,43 @@
+package katsu2d
+
+import (
+	"math"
+
+	"github.com/edwinsyarief/teishoku"
+)
+
+// ShadowLight is the global light used by skewed shadows.
+type ShadowLight struct {
+	// Direction is the screen direction shadows are cast in.
+	Direction Vector
+	// Length is the shadow length per unit of entity height.
+	Length float64
+	// Opacity scales the opacity of every skewed shadow.
+	Opacity float64
+}
+
+// NewShadowLight creates a light casting shadows down and to the right.
+func NewShadowLight() *ShadowLight {
+	return &ShadowLight{Direction: V(0.6, 0.8), Length: 0.6, Opacity: 1}
+}
+
+// SetShadowLight sets the world's shadow light.
+func SetShadowLight(w *teishoku.World, light *ShadowLight) *ShadowLight {
+	return SetResource(w, light)
+}
+
+// GetShadowLight returns the world's shadow light, or nil if none is set.
+func GetShadowLight(w *teishoku.World) *ShadowLight {
+	return GetResource[ShadowLight](w)
+}
+
+// SetSunTime places the sun for t in [0, 1] from sunrise to sunset. Shadows
+// swing from west to east, shortest and darkest at noon and long and faint
+// near the horizon, for use with a day/night cycle.
+func (self *ShadowLight) SetSunTime(t float64) {
+	angle := math.Pi * Clamp(t, 0, 1)
+	elevation := max(math.Sin(angle), 0.15)
+	self.Direction = V(-math.Cos(angle), 0.35).Normalize()
+	self.Length = min(0.4/elevation, 2.5)
+	self.Opacity = 0.35 + 0.65*elevation
+}
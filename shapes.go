@@ -3,15 +3,32 @@ package katsu2d
 import (
 	"image/color"
 	"math"
+	"slices"
 
+	"github.com/edwinsyarief/katsu2d/colors"
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
+// Shape is a procedurally generated mesh. Setters flag the shape dirty so the
+// mesh is rebuilt on the next access; after changing public fields directly
+// call MarkDirty.
 type Shape interface {
 	Rebuild()
+	MarkDirty()
 	GetVertices() []ebiten.Vertex
 	GetIndices() []uint16
 }
+
+var (
+	_ Shape = (*RectangleShape)(nil)
+	_ Shape = (*CircleShape)(nil)
+	_ Shape = (*TriangleShape)(nil)
+	_ Shape = (*PolygonShape)(nil)
+	_ Shape = (*PentagonShape)(nil)
+	_ Shape = (*HexagonShape)(nil)
+	_ Shape = (*ArcShape)(nil)
+)
+
 type RectangleShape struct {
 	Vertices          []ebiten.Vertex
 	Indices           []uint16
@@ -23,6 +40,8 @@ type RectangleShape struct {
 	StrokeWidth       float32
 	FillColors        [4]color.RGBA // 0:TL, 1:TR, 2:BR, 3:BL
 	StrokeColors      [4]color.RGBA // 0:TL, 1:TR, 2:BR, 3:BL
+	StrokeStyle       StrokeStyle
+	Feather           float32 // Width of the anti-aliasing rim, 0 disables it
 	Dirty             bool
 }
 
@@ -35,6 +54,10 @@ func NewRectangleShape(width, height float32, col color.RGBA) *RectangleShape {
 		Dirty:        true,
 	}
 }
+func (self *RectangleShape) SetSize(width, height float32) {
+	self.Width, self.Height = width, height
+	self.Dirty = true
+}
 func (self *RectangleShape) SetColor(topLeft, topRight, bottomRight, bottomLeft color.RGBA) {
 	self.FillColors[0] = topLeft
 	self.FillColors[1] = topRight
@@ -69,9 +92,19 @@ func (self *RectangleShape) Rebuild() {
 	self.Vertices = nil
 	self.Indices = nil
 	self.generateFill()
+	fillEnd, fillIndices := len(self.Vertices), len(self.Indices)
 	if self.StrokeWidth > 0 {
 		self.generateStroke()
 	}
+	self.Vertices, self.Indices = finishShape(self.Vertices, self.Indices, fillEnd, fillIndices, self.StrokeStyle, self.Feather)
+}
+func (self *RectangleShape) SetStrokeStyle(style StrokeStyle) {
+	self.StrokeStyle = style
+	self.Dirty = true
+}
+func (self *RectangleShape) SetFeather(feather float32) {
+	self.Feather = feather
+	self.Dirty = true
 }
 
 type radii struct{ tl, tr, bl, br float32 }
@@ -193,10 +226,21 @@ func (self *RectangleShape) triangulateFill(fillPath []ebiten.Vertex, colors [4]
 		self.Indices = append(self.Indices, baseIndex, p1, p2)
 	}
 }
+
+// MarkDirty forces the mesh to be rebuilt, e.g. after editing fields directly.
+func (self *RectangleShape) MarkDirty() {
+	self.Dirty = true
+}
+func (self *RectangleShape) SetStrokeWidth(width float32) {
+	self.StrokeWidth = width
+	self.Dirty = true
+}
 func (self *RectangleShape) GetVertices() []ebiten.Vertex {
+	self.Rebuild()
 	return self.Vertices
 }
 func (self *RectangleShape) GetIndices() []uint16 {
+	self.Rebuild()
 	return self.Indices
 }
 
@@ -207,6 +251,8 @@ type CircleShape struct {
 	StrokeWidth  float32
 	FillColors   [4]color.RGBA
 	StrokeColors [4]color.RGBA
+	StrokeStyle  StrokeStyle
+	Feather      float32 // Width of the anti-aliasing rim, 0 disables it
 	Dirty        bool
 }
 
@@ -218,6 +264,10 @@ func NewCircleShape(radius float32, col color.RGBA) *CircleShape {
 		Dirty:        true,
 	}
 }
+func (self *CircleShape) SetRadius(radius float32) {
+	self.Radius = radius
+	self.Dirty = true
+}
 func (self *CircleShape) SetColor(topLeft, topRight, bottomRight, bottomLeft color.RGBA) {
 	self.FillColors[0] = topLeft
 	self.FillColors[1] = topRight
@@ -237,10 +287,21 @@ func (self *CircleShape) SetStroke(width float32, col color.RGBA) {
 	self.StrokeColors = [4]color.RGBA{col, col, col, col}
 	self.Dirty = true
 }
+
+// MarkDirty forces the mesh to be rebuilt, e.g. after editing fields directly.
+func (self *CircleShape) MarkDirty() {
+	self.Dirty = true
+}
+func (self *CircleShape) SetStrokeWidth(width float32) {
+	self.StrokeWidth = width
+	self.Dirty = true
+}
 func (self *CircleShape) GetVertices() []ebiten.Vertex {
+	self.Rebuild()
 	return self.Vertices
 }
 func (self *CircleShape) GetIndices() []uint16 {
+	self.Rebuild()
 	return self.Indices
 }
 func (self *CircleShape) Rebuild() {
@@ -251,24 +312,35 @@ func (self *CircleShape) Rebuild() {
 	self.Vertices = nil
 	self.Indices = nil
 	self.generateFill()
+	fillEnd, fillIndices := len(self.Vertices), len(self.Indices)
 	if self.StrokeWidth > 0 {
 		self.generateStroke()
 	}
+	self.Vertices, self.Indices = finishShape(self.Vertices, self.Indices, fillEnd, fillIndices, self.StrokeStyle, self.Feather)
+}
+func (self *CircleShape) SetStrokeStyle(style StrokeStyle) {
+	self.StrokeStyle = style
+	self.Dirty = true
+}
+func (self *CircleShape) SetFeather(feather float32) {
+	self.Feather = feather
+	self.Dirty = true
 }
 func (self *CircleShape) generateFill() {
-	path := self.generatePath(self.Radius, self.FillColors)
+	path := self.generatePath(self.Radius, self.FillColors, self.calculateSegments(self.Radius))
 	self.triangulateFill(path, self.FillColors)
 }
 func (self *CircleShape) generateStroke() {
 	sw := self.StrokeWidth
 	innerRadius := self.Radius
 	outerRadius := self.Radius + sw
-	innerPath := self.generatePath(innerRadius, self.StrokeColors)
-	outerPath := self.generatePath(outerRadius, self.StrokeColors)
+	// Both paths need the same vertex count to be joined into a ring.
+	segments := self.calculateSegments(outerRadius)
+	innerPath := self.generatePath(innerRadius, self.StrokeColors, segments)
+	outerPath := self.generatePath(outerRadius, self.StrokeColors, segments)
 	self.triangulateStroke(outerPath, innerPath)
 }
-func (self *CircleShape) generatePath(radius float32, colors [4]color.RGBA) []ebiten.Vertex {
-	segments := self.calculateSegments(radius)
+func (self *CircleShape) generatePath(radius float32, colors [4]color.RGBA, segments int) []ebiten.Vertex {
 	path := make([]ebiten.Vertex, 0, segments)
 	for i := 0; i < segments; i++ {
 		angle := 2 * math.Pi * float32(i) / float32(segments)
@@ -340,6 +412,8 @@ type TriangleShape struct {
 	CornerRadius  float32
 	FillColors    [4]color.RGBA // 0: Top, 1: Right, 2: Left
 	StrokeColors  [4]color.RGBA
+	StrokeStyle   StrokeStyle
+	Feather       float32 // Width of the anti-aliasing rim, 0 disables it
 	Dirty         bool
 }
 
@@ -352,6 +426,10 @@ func NewTriangleShape(width, height float32, col color.RGBA) *TriangleShape {
 		Dirty:        true,
 	}
 }
+func (self *TriangleShape) SetSize(width, height float32) {
+	self.Width, self.Height = width, height
+	self.Dirty = true
+}
 func (self *TriangleShape) SetColor(top, right, left color.RGBA) {
 	self.FillColors[0] = top
 	self.FillColors[1] = right
@@ -373,10 +451,21 @@ func (self *TriangleShape) SetCornerRadius(radius float32) {
 	self.CornerRadius = radius
 	self.Dirty = true
 }
+
+// MarkDirty forces the mesh to be rebuilt, e.g. after editing fields directly.
+func (self *TriangleShape) MarkDirty() {
+	self.Dirty = true
+}
+func (self *TriangleShape) SetStrokeWidth(width float32) {
+	self.StrokeWidth = width
+	self.Dirty = true
+}
 func (self *TriangleShape) GetVertices() []ebiten.Vertex {
+	self.Rebuild()
 	return self.Vertices
 }
 func (self *TriangleShape) GetIndices() []uint16 {
+	self.Rebuild()
 	return self.Indices
 }
 func (self *TriangleShape) Rebuild() {
@@ -387,9 +476,19 @@ func (self *TriangleShape) Rebuild() {
 	self.Vertices = nil
 	self.Indices = nil
 	self.generateFill()
+	fillEnd, fillIndices := len(self.Vertices), len(self.Indices)
 	if self.StrokeWidth > 0 {
 		self.generateStroke()
 	}
+	self.Vertices, self.Indices = finishShape(self.Vertices, self.Indices, fillEnd, fillIndices, self.StrokeStyle, self.Feather)
+}
+func (self *TriangleShape) SetStrokeStyle(style StrokeStyle) {
+	self.StrokeStyle = style
+	self.Dirty = true
+}
+func (self *TriangleShape) SetFeather(feather float32) {
+	self.Feather = feather
+	self.Dirty = true
 }
 func (self *TriangleShape) generateFill() {
 	path := self.generatePath(self.Width, self.Height, self.CornerRadius, self.FillColors)
@@ -509,6 +608,8 @@ type PolygonShape struct {
 	CornerRadius float32
 	FillColors   [4]color.RGBA
 	StrokeColors [4]color.RGBA
+	StrokeStyle  StrokeStyle
+	Feather      float32 // Width of the anti-aliasing rim, 0 disables it
 	Dirty        bool
 }
 
@@ -544,10 +645,17 @@ func (self *PolygonShape) SetCornerRadius(radius float32) {
 	self.CornerRadius = radius
 	self.Dirty = true
 }
+
+// MarkDirty forces the mesh to be rebuilt, e.g. after editing fields directly.
+func (self *PolygonShape) MarkDirty() {
+	self.Dirty = true
+}
 func (self *PolygonShape) GetVertices() []ebiten.Vertex {
+	self.Rebuild()
 	return self.Vertices
 }
 func (self *PolygonShape) GetIndices() []uint16 {
+	self.Rebuild()
 	return self.Indices
 }
 
@@ -576,9 +684,19 @@ func (self *PolygonShape) Rebuild() {
 	self.Vertices = nil
 	self.Indices = nil
 	self.generateFill()
+	fillEnd, fillIndices := len(self.Vertices), len(self.Indices)
 	if self.StrokeWidth > 0 {
 		self.generateStroke()
 	}
+	self.Vertices, self.Indices = finishShape(self.Vertices, self.Indices, fillEnd, fillIndices, self.StrokeStyle, self.Feather)
+}
+func (self *PolygonShape) SetStrokeStyle(style StrokeStyle) {
+	self.StrokeStyle = style
+	self.Dirty = true
+}
+func (self *PolygonShape) SetFeather(feather float32) {
+	self.Feather = feather
+	self.Dirty = true
 }
 func (self *PolygonShape) generateFill() {
 	path := self.generatePath(self.Radius, self.CornerRadius, self.FillColors)
@@ -838,37 +956,300 @@ func generateMiterPathForPolygon(points []ebiten.Vertex, strokeWidth float32) []
 	}
 	return path
 }
-func interpolateColor(x, y, width, height float32, colors [4]color.RGBA) color.RGBA {
-	u := x / width
-	v := y / height
-	if u < 0 {
-		u = 0
-	}
-	if u > 1 {
-		u = 1
-	}
-	if v < 0 {
-		v = 0
-	}
-	if v > 1 {
-		v = 1
-	}
-	c00 := colors[0] // TL
-	c10 := colors[1] // TR
-	c01 := colors[3] // BL
-	c11 := colors[2] // BR
-	r00, g00, b00, a00 := c00.RGBA()
-	r10, g10, b10, a10 := c10.RGBA()
-	r01, g01, b01, a01 := c01.RGBA()
-	r11, g11, b11, a11 := c11.RGBA()
-	r_interp := float32(r00)*(1-u)*(1-v) + float32(r10)*u*(1-v) + float32(r01)*(1-u)*v + float32(r11)*u*v
-	g_interp := float32(g00)*(1-u)*(1-v) + float32(g10)*u*(1-v) + float32(g01)*(1-u)*v + float32(g11)*u*v
-	b_interp := float32(b00)*(1-u)*(1-v) + float32(b10)*u*(1-v) + float32(b01)*(1-u)*v + float32(b11)*u*v
-	a_interp := float32(a00)*(1-u)*(1-v) + float32(a10)*u*(1-v) + float32(a01)*(1-u)*v + float32(a11)*u*v
-	return color.RGBA{
-		R: uint8(r_interp / 256),
-		G: uint8(g_interp / 256),
-		B: uint8(b_interp / 256),
-		A: uint8(a_interp / 256),
+
+// StrokeStyle adds dash patterns and gradients to a shape's stroke.
+type StrokeStyle struct {
+	Dash       []float32    // Alternating on/off lengths along the stroke; empty draws a solid stroke
+	DashOffset float32      // Distance to shift the dash pattern along the stroke
+	Gradient   []color.RGBA // Evenly spaced color stops along the stroke, overriding its colors
+}
+
+func (self StrokeStyle) isPlain() bool {
+	return len(self.Dash) == 0 && len(self.Gradient) == 0
+}
+
+// finishShape applies the stroke style and feathering to a freshly built shape.
+// Fill vertices are a center followed by the perimeter; any stroke appended
+// after fillEnd holds its outer path followed by an inner path of equal length.
+func finishShape(vertices []ebiten.Vertex, indices []uint16, fillEnd, fillIndices int, style StrokeStyle, feather float32) ([]ebiten.Vertex, []uint16) {
+	var outline []ebiten.Vertex
+	if fillEnd > 1 {
+		outline = slices.Clone(vertices[1:fillEnd])
+	}
+	if strokeVerts := len(vertices) - fillEnd; strokeVerts > 0 {
+		n := strokeVerts / 2
+		clampStrokeInner(vertices[fillEnd:fillEnd+n], vertices[fillEnd+n:fillEnd+2*n], strokeCenter(vertices, fillEnd, n))
+		outer := slices.Clone(vertices[fillEnd : fillEnd+n])
+		inner := slices.Clone(vertices[fillEnd+n : fillEnd+2*n])
+		outline = outer
+		if !style.isPlain() {
+			vertices, indices = vertices[:fillEnd], indices[:fillIndices]
+			vertices, indices = appendStyledStroke(vertices, indices, outer, inner, style)
+		}
+	}
+	if feather <= 0 || len(style.Dash) > 0 {
+		return vertices, indices
+	}
+	return appendFeatherRing(vertices, indices, outline, feather)
+}
+
+// strokeCenter returns the center of a shape: its fill center, or the
+// centroid of the stroke's outer path for shapes without a fill.
+func strokeCenter(vertices []ebiten.Vertex, fillEnd, n int) Vector {
+	if fillEnd > 0 {
+		return V(float64(vertices[0].DstX), float64(vertices[0].DstY))
+	}
+	var sum Vector
+	for _, v := range vertices[:n] {
+		sum = sum.Add(V(float64(v.DstX), float64(v.DstY)))
+	}
+	return sum.ScaleF(1 / float64(max(n, 1)))
+}
+
+// clampStrokeInner keeps every inner stroke vertex on the same side of the
+// center as its outer vertex. A stroke wider than the shape's radius would
+// otherwise push the inner path past the center, turning it inside out.
+func clampStrokeInner(outer, inner []ebiten.Vertex, center Vector) {
+	for i := range min(len(outer), len(inner)) {
+		o := V(float64(outer[i].DstX), float64(outer[i].DstY)).Sub(center)
+		in := V(float64(inner[i].DstX), float64(inner[i].DstY)).Sub(center)
+		if o.Dot(in) < 0 {
+			inner[i].DstX, inner[i].DstY = float32(center.X), float32(center.Y)
+		}
+	}
+}
+
+// appendStyledStroke emits a closed stroke ring between matching outer and
+// inner paths, honouring the dash pattern and gradient of the style.
+func appendStyledStroke(vertices []ebiten.Vertex, indices []uint16, outer, inner []ebiten.Vertex, style StrokeStyle) ([]ebiten.Vertex, []uint16) {
+	n := len(outer)
+	if n < 2 || len(inner) != n {
+		return vertices, indices
+	}
+	// Distances are measured along the centerline between both paths.
+	center := func(i int) Vector {
+		return V(float64(outer[i].DstX+inner[i].DstX)/2, float64(outer[i].DstY+inner[i].DstY)/2)
+	}
+	distances := make([]float64, n+1)
+	for i := 0; i < n; i++ {
+		distances[i+1] = distances[i] + center(i).DistanceTo(center((i+1)%n))
+	}
+	total := distances[n]
+	if total == 0 {
+		return vertices, indices
+	}
+	dash := make([]float64, len(style.Dash))
+	for i, d := range style.Dash {
+		dash[i] = float64(d)
+	}
+	lerp := func(a, b ebiten.Vertex, t float32) ebiten.Vertex {
+		return ebiten.Vertex{
+			DstX: a.DstX + (b.DstX-a.DstX)*t, DstY: a.DstY + (b.DstY-a.DstY)*t,
+			ColorR: a.ColorR + (b.ColorR-a.ColorR)*t, ColorG: a.ColorG + (b.ColorG-a.ColorG)*t,
+			ColorB: a.ColorB + (b.ColorB-a.ColorB)*t, ColorA: a.ColorA + (b.ColorA-a.ColorA)*t,
+		}
+	}
+	for i := 0; i < n; i++ {
+		j := (i + 1) % n
+		start, end := distances[i], distances[i+1]
+		if end-start < 1e-9 {
+			continue
+		}
+		for _, span := range dashIntervals(start, end, dash, float64(style.DashOffset)) {
+			t0 := float32((span[0] - start) / (end - start))
+			t1 := float32((span[1] - start) / (end - start))
+			quad := [4]ebiten.Vertex{
+				lerp(outer[i], outer[j], t0), lerp(outer[i], outer[j], t1),
+				lerp(inner[i], inner[j], t0), lerp(inner[i], inner[j], t1),
+			}
+			if len(style.Gradient) > 0 {
+				c0 := gradientAt(style.Gradient, span[0]/total)
+				c1 := gradientAt(style.Gradient, span[1]/total)
+				for k, c := range []color.RGBA{c0, c1, c0, c1} {
+					quad[k].ColorR, quad[k].ColorG = float32(c.R)/255, float32(c.G)/255
+					quad[k].ColorB, quad[k].ColorA = float32(c.B)/255, float32(c.A)/255
+				}
+			}
+			base := uint16(len(vertices))
+			vertices = append(vertices, quad[:]...)
+			indices = append(indices, base, base+2, base+1, base+1, base+2, base+3)
+		}
+	}
+	return vertices, indices
+}
+
+// gradientAt samples evenly spaced color stops at t (0..1).
+func gradientAt(stops []color.RGBA, t float64) color.RGBA {
+	return colors.Palette(stops).At(t)
+}
+
+// appendFeatherRing extrudes a closed outline outward by feather, fading to
+// transparent, and appends the resulting strip to the mesh.
+func appendFeatherRing(vertices []ebiten.Vertex, indices []uint16, outline []ebiten.Vertex, feather float32) ([]ebiten.Vertex, []uint16) {
+	n := len(outline)
+	if n < 3 {
+		return vertices, indices
+	}
+	// The winding decides which side of each edge is outside.
+	area := float32(0)
+	for i := range outline {
+		a, b := outline[i], outline[(i+1)%n]
+		area += a.DstX*b.DstY - b.DstX*a.DstY
+	}
+	side := float32(1)
+	if area < 0 {
+		side = -1
+	}
+	base := uint16(len(vertices))
+	vertices = append(vertices, outline...)
+	for i := range outline {
+		prev, curr, next := outline[(i+n-1)%n], outline[i], outline[(i+1)%n]
+		n1 := V(float64(curr.DstY-prev.DstY), float64(prev.DstX-curr.DstX)).Normalize()
+		n2 := V(float64(next.DstY-curr.DstY), float64(curr.DstX-next.DstX)).Normalize()
+		normal := n1.Add(n2).Normalize().ScaleF(float64(feather * side))
+		rim := curr
+		rim.DstX += float32(normal.X)
+		rim.DstY += float32(normal.Y)
+		rim.ColorR, rim.ColorG, rim.ColorB, rim.ColorA = 0, 0, 0, 0
+		vertices = append(vertices, rim)
+	}
+	for i := 0; i < n; i++ {
+		p0 := base + uint16(i)
+		p1 := base + uint16((i+1)%n)
+		p2 := base + uint16(n+i)
+		p3 := base + uint16(n+(i+1)%n)
+		indices = append(indices, p0, p2, p1, p1, p2, p3)
+	}
+	return vertices, indices
+}
+func interpolateColor(x, y, width, height float32, corners [4]color.RGBA) color.RGBA {
+	return colors.Bilinear(corners, float64(x/width), float64(y/height))
+}
+
+// ArcShape is a filled annulus sector, suited to cooldown rings and radial timers.
+// Angles are in radians, measured clockwise from the positive X axis; the shape
+// is centered at (OuterRadius, OuterRadius) like CircleShape.
+type ArcShape struct {
+	Vertices    []ebiten.Vertex
+	Indices     []uint16
+	InnerRadius float32
+	OuterRadius float32
+	StartAngle  float32
+	Sweep       float32
+	Feather     float32 // Width of the alpha falloff rim used for anti-aliasing
+	Color       color.RGBA
+	Dirty       bool
+}
+
+func NewArcShape(innerRadius, outerRadius float32, col color.RGBA) *ArcShape {
+	return &ArcShape{
+		InnerRadius: innerRadius,
+		OuterRadius: outerRadius,
+		StartAngle:  -math.Pi / 2,
+		Sweep:       2 * math.Pi,
+		Feather:     1,
+		Color:       col,
+		Dirty:       true,
+	}
+}
+func (self *ArcShape) SetColor(col color.RGBA) {
+	self.Color = col
+	self.Dirty = true
+}
+func (self *ArcShape) SetAngles(start, sweep float32) {
+	self.StartAngle = start
+	self.Sweep = sweep
+	self.Dirty = true
+}
+
+// SetProgress sets the sweep as a fraction (0..1) of a full turn.
+func (self *ArcShape) SetProgress(progress float32) {
+	self.Sweep = 2 * math.Pi * Clamp(progress, 0, 1)
+	self.Dirty = true
+}
+
+// MarkDirty forces the mesh to be rebuilt, e.g. after editing fields directly.
+func (self *ArcShape) MarkDirty() {
+	self.Dirty = true
+}
+func (self *ArcShape) GetVertices() []ebiten.Vertex {
+	self.Rebuild()
+	return self.Vertices
+}
+func (self *ArcShape) GetIndices() []uint16 {
+	self.Rebuild()
+	return self.Indices
+}
+func (self *ArcShape) Rebuild() {
+	if !self.Dirty {
+		return
+	}
+	self.Dirty = false
+	self.Vertices = nil
+	self.Indices = nil
+	if self.Sweep <= 0 || self.OuterRadius <= 0 {
+		return
+	}
+	self.generateFill()
+}
+func (self *ArcShape) calculateSegments() int {
+	arcLength := float64(self.OuterRadius * self.Sweep)
+	segments := int(arcLength / 1.5)
+	if segments < 8 {
+		segments = 8
+	}
+	if segments > 200 {
+		segments = 200
+	}
+	return segments
+}
+func (self *ArcShape) generateFill() {
+	f := self.Feather
+	// Each column holds four rings: inner rim, inner edge, outer edge, outer rim.
+	radii := [4]float32{self.InnerRadius - f, self.InnerRadius, self.OuterRadius, self.OuterRadius + f}
+	if radii[0] < 0 {
+		radii[0] = 0
+	}
+	alphas := [4]float32{0, 1, 1, 0}
+	if self.InnerRadius <= 0 || f <= 0 {
+		alphas[0] = 1
+	}
+	if f <= 0 {
+		alphas[3] = 1
+	}
+	full := self.Sweep >= 2*math.Pi
+	segments := self.calculateSegments()
+	type column struct {
+		angle float64
+		alpha float32
+	}
+	columns := make([]column, 0, segments+3)
+	if !full && f > 0 {
+		columns = append(columns, column{float64(self.StartAngle - f/self.OuterRadius), 0})
+	}
+	for i := 0; i <= segments; i++ {
+		columns = append(columns, column{float64(self.StartAngle + self.Sweep*float32(i)/float32(segments)), 1})
+	}
+	if !full && f > 0 {
+		columns = append(columns, column{float64(self.StartAngle + self.Sweep + f/self.OuterRadius), 0})
+	}
+	cx, cy := self.OuterRadius, self.OuterRadius
+	cr, cg, cb, ca := self.Color.RGBA()
+	for _, c := range columns {
+		cos, sin := float32(math.Cos(c.angle)), float32(math.Sin(c.angle))
+		for ring, r := range radii {
+			a := alphas[ring] * c.alpha
+			self.Vertices = append(self.Vertices, ebiten.Vertex{
+				DstX: cx + r*cos, DstY: cy + r*sin,
+				ColorR: float32(cr) / 0xffff * a, ColorG: float32(cg) / 0xffff * a, ColorB: float32(cb) / 0xffff * a, ColorA: float32(ca) / 0xffff * a,
+			})
+		}
+	}
+	for i := 0; i < len(columns)-1; i++ {
+		base := uint16(i * 4)
+		next := base + 4
+		for ring := uint16(0); ring < 3; ring++ {
+			self.Indices = append(self.Indices, base+ring, next+ring, base+ring+1, base+ring+1, next+ring, next+ring+1)
+		}
 	}
 }
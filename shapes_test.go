@@ -0,0 +1,67 @@
+package katsu2d
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// TestCircleStrokeRing verifies that circle strokes, including one wider
+// than the radius, join outer and inner paths of equal length into a ring
+// that stays outside the fill.
+func TestCircleStrokeRing(t *testing.T) {
+	for _, tc := range []struct{ radius, stroke float32 }{{10, 2}, {2, 6}} {
+		c := NewCircleShape(tc.radius, color.RGBA{R: 255, A: 255})
+		c.SetStroke(tc.stroke, color.RGBA{A: 255})
+		vertices, indices := c.GetVertices(), c.GetIndices()
+		for _, i := range indices {
+			if int(i) >= len(vertices) {
+				t.Fatalf("radius %v stroke %v: index %d out of %d vertices", tc.radius, tc.stroke, i, len(vertices))
+			}
+		}
+		fillEnd := 1 + c.calculateSegments(tc.radius)
+		stroke := len(vertices) - fillEnd
+		if stroke <= 0 || stroke%2 != 0 {
+			t.Fatalf("radius %v stroke %v: %d stroke vertices, want an even count", tc.radius, tc.stroke, stroke)
+		}
+		n := stroke / 2
+		center := V(float64(tc.radius), float64(tc.radius))
+		for i := range n {
+			outer := vertexDistance(vertices[fillEnd+i], center)
+			inner := vertexDistance(vertices[fillEnd+n+i], center)
+			if math.Abs(outer-float64(tc.radius+tc.stroke)) > 1e-3 {
+				t.Errorf("radius %v stroke %v: outer vertex %d at %v", tc.radius, tc.stroke, i, outer)
+			}
+			if math.Abs(inner-float64(tc.radius)) > 1e-3 {
+				t.Errorf("radius %v stroke %v: inner vertex %d at %v", tc.radius, tc.stroke, i, inner)
+			}
+		}
+	}
+}
+
+// TestFinishShapeClampsInnerStroke verifies that an inner stroke path
+// pushed past the shape's center is clamped to the center.
+func TestFinishShapeClampsInnerStroke(t *testing.T) {
+	square := func(half float32) []ebiten.Vertex {
+		return []ebiten.Vertex{
+			{DstX: -half, DstY: -half}, {DstX: half, DstY: -half},
+			{DstX: half, DstY: half}, {DstX: -half, DstY: half},
+		}
+	}
+	vertices := append([]ebiten.Vertex{{}}, square(1)...)
+	vertices = append(vertices, square(4)...)
+	// The inner path mirrors through the center, as a too wide stroke would.
+	vertices = append(vertices, square(-2)...)
+	vertices, _ = finishShape(vertices, nil, 5, 0, StrokeStyle{}, 0)
+	for i, v := range vertices[9:13] {
+		if v.DstX != 0 || v.DstY != 0 {
+			t.Errorf("inner vertex %d = (%v, %v), want the center", i, v.DstX, v.DstY)
+		}
+	}
+}
+
+func vertexDistance(v ebiten.Vertex, p Vector) float64 {
+	return V(float64(v.DstX), float64(v.DstY)).DistanceTo(p)
+}
@@ -0,0 +1,104 @@
+package katsu2d
+
+import (
+	"cmp"
+	_ "embed"
+	"image/color"
+	"math"
+	"slices"
+
+	"github.com/edwinsyarief/teishoku"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+//go:embed internal_assets/shaders/normal_lit.kage
+var _normalLitShaderSource []byte
+
+var normalLitShader *ebiten.Shader
+
+// maxSpriteLights is the number of point lights shading one sprite; the
+// nearest ones are used.
+const maxSpriteLights = 8
+
+// pointLight is a point light gathered for the frame.
+type pointLight struct {
+	position Vector
+	light    PointLightComponent
+}
+
+// spriteLighting shades normal-mapped sprites with the point lights of a
+// world.
+type spriteLighting struct {
+	lights     []pointLight
+	nearest    []int
+	shader     ShaderComponent
+	lightPos   []float32
+	lightColor []float32
+}
+
+// gather collects the point lights of w for this frame.
+func (self *spriteLighting) gather(w *teishoku.World) {
+	self.lights = self.lights[:0]
+	filter := teishoku.NewFilter2[TransformComponent, PointLightComponent](w)
+	for filter.Next() {
+		t, light := filter.Get()
+		self.lights = append(self.lights, pointLight{position: Vector(t.Position), light: *light})
+	}
+}
+
+// shade returns a shader component lighting a sprite at position, rotated
+// by rotation and mirrored along negative scale axes, with its normal map.
+func (self *spriteLighting) shade(position, scale Vector, rotation float64, ambient color.RGBA, normalMap *ebiten.Image, rdr *BatchRenderer) *ShaderComponent {
+	if self.shader.Shader == nil {
+		self.shader = NewShaderComponent(builtinShader(&normalLitShader, _normalLitShaderSource, "normal lighting"))
+		self.lightPos = make([]float32, maxSpriteLights*3)
+		self.lightColor = make([]float32, maxSpriteLights*4)
+	}
+	view, ok := rdr.View()
+	if !ok || rdr.ScreenSpace() {
+		view = Matrix{}
+	}
+	// Lights are passed in destination pixels, so the view's scale and
+	// rotation apply to their radius and the normals.
+	ox, oy := view.Apply(0, 0)
+	ux, uy := view.Apply(1, 0)
+	viewScale := math.Hypot(ux-ox, uy-oy)
+	viewRotation := math.Atan2(uy-oy, ux-ox)
+
+	self.nearest = self.nearest[:0]
+	for i, l := range self.lights {
+		if l.position.DistanceTo(position) < l.light.Radius+max(l.light.Height, 0) {
+			self.nearest = append(self.nearest, i)
+		}
+	}
+	slices.SortFunc(self.nearest, func(a, b int) int {
+		return cmp.Compare(self.lights[a].position.DistanceTo(position), self.lights[b].position.DistanceTo(position))
+	})
+	count := min(len(self.nearest), maxSpriteLights)
+	for i, index := range self.nearest[:count] {
+		l := self.lights[index]
+		x, y := view.Apply(l.position.X, l.position.Y)
+		self.lightPos[i*3], self.lightPos[i*3+1], self.lightPos[i*3+2] = float32(x), float32(y), float32(l.light.Height*viewScale)
+		c := colorUniform(l.light.Color)
+		for j := range 3 {
+			self.lightColor[i*4+j] = c[j] * c[3] * float32(l.light.Intensity)
+		}
+		self.lightColor[i*4+3] = float32(l.light.Radius * viewScale)
+	}
+	a := colorUniform(ambient)
+	flip := [2]float32{1, 1}
+	if scale.X < 0 {
+		flip[0] = -1
+	}
+	if scale.Y < 0 {
+		flip[1] = -1
+	}
+	self.shader.SetUniform("Ambient", [3]float32{a[0], a[1], a[2]})
+	self.shader.SetUniform("LightCount", count)
+	self.shader.SetUniform("LightPos", self.lightPos)
+	self.shader.SetUniform("LightColor", self.lightColor)
+	self.shader.SetUniform("Rotation", float32(rotation+viewRotation))
+	self.shader.SetUniform("Flip", flip)
+	self.shader.Images[0] = normalMap
+	return &self.shader
+}
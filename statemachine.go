@@ -0,0 +1,260 @@
+package katsu2d
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/edwinsyarief/katsu2d/logger"
+	"github.com/edwinsyarief/teishoku"
+)
+
+// StateContext is passed to state callbacks and transition guards.
+type StateContext struct {
+	World   *teishoku.World
+	Entity  teishoku.Entity
+	Vars    *Blackboard
+	Machine *StateMachine
+}
+
+// StateFunc is a state enter, update or exit callback.
+type StateFunc func(ctx *StateContext, dt float64)
+
+// MachineState is a node in a hierarchical state machine. A state with
+// children enters its Initial child, or its first child if Initial is empty.
+type MachineState struct {
+	Name     string
+	Parent   string
+	Initial  string
+	OnEnter  StateFunc
+	OnUpdate StateFunc
+	OnExit   StateFunc
+}
+
+// StateTransition moves from one state, or any of its substates, to another.
+// It fires when Event was triggered (if set), Condition holds on the
+// blackboard (if set) and Guard returns true (if set).
+type StateTransition struct {
+	From, To  string
+	Event     string
+	Condition string
+	Guard     func(ctx *StateContext) bool
+}
+
+// StateMachineDefinition describes states and transitions shared by any
+// number of machines.
+type StateMachineDefinition struct {
+	Initial     string
+	states      map[string]*MachineState
+	children    map[string][]string
+	transitions []StateTransition
+}
+
+// NewStateMachineDefinition creates a definition starting in initial.
+func NewStateMachineDefinition(initial string) *StateMachineDefinition {
+	return &StateMachineDefinition{
+		Initial:  initial,
+		states:   make(map[string]*MachineState),
+		children: make(map[string][]string),
+	}
+}
+
+// AddState registers a state. Parents must be added before their children.
+func (self *StateMachineDefinition) AddState(state MachineState) *StateMachineDefinition {
+	s := state
+	self.states[s.Name] = &s
+	if s.Parent != "" {
+		self.children[s.Parent] = append(self.children[s.Parent], s.Name)
+	}
+	return self
+}
+
+// AddTransition registers a transition. Transitions of outer states are
+// checked before those of their substates.
+func (self *StateMachineDefinition) AddTransition(t StateTransition) *StateMachineDefinition {
+	self.transitions = append(self.transitions, t)
+	return self
+}
+
+// Validate checks that the initial states, parents and transition targets
+// name defined states, and that every Initial is a child of its state. Call
+// it after building the definition; machines with an invalid definition
+// log an error and stay in their current state instead of entering an
+// undefined one.
+func (self *StateMachineDefinition) Validate() error {
+	var errs []error
+	if self.states[self.Initial] == nil {
+		errs = append(errs, fmt.Errorf("katsu2d: initial state %q is not defined", self.Initial))
+	}
+	names := slices.Sorted(maps.Keys(self.states))
+	for _, name := range names {
+		s := self.states[name]
+		if s.Parent != "" && self.states[s.Parent] == nil {
+			errs = append(errs, fmt.Errorf("katsu2d: state %q has undefined parent %q", name, s.Parent))
+		}
+		if s.Initial != "" && !slices.Contains(self.children[name], s.Initial) {
+			errs = append(errs, fmt.Errorf("katsu2d: initial state %q of %q is not one of its children", s.Initial, name))
+		}
+	}
+	for _, t := range self.transitions {
+		if self.states[t.From] == nil {
+			errs = append(errs, fmt.Errorf("katsu2d: transition from undefined state %q", t.From))
+		}
+		if self.states[t.To] == nil {
+			errs = append(errs, fmt.Errorf("katsu2d: transition to undefined state %q", t.To))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// path returns the chain of states from the root to name.
+func (self *StateMachineDefinition) path(name string) []string {
+	var res []string
+	for s := self.states[name]; s != nil && len(res) < 32; s = self.states[s.Parent] {
+		res = append(res, s.Name)
+	}
+	slices.Reverse(res)
+	return res
+}
+
+// StateMachine is a running instance of a StateMachineDefinition.
+type StateMachine struct {
+	Definition  *StateMachineDefinition
+	active      []string
+	events      []string
+	next        string
+	timeInState float64
+	started     bool
+}
+
+// NewStateMachine creates a machine that enters its initial state on its first update.
+func NewStateMachine(def *StateMachineDefinition) *StateMachine {
+	return &StateMachine{Definition: def}
+}
+
+// Current returns the innermost active state.
+func (self *StateMachine) Current() string {
+	if len(self.active) == 0 {
+		return ""
+	}
+	return self.active[len(self.active)-1]
+}
+
+// IsInState reports whether name is the current state or one of its parents.
+func (self *StateMachine) IsInState(name string) bool {
+	return slices.Contains(self.active, name)
+}
+
+// TimeInState returns the seconds spent in the current state.
+func (self *StateMachine) TimeInState() float64 {
+	return self.timeInState
+}
+
+// Trigger queues an event for transitions checked on the next update.
+func (self *StateMachine) Trigger(event string) {
+	self.events = append(self.events, event)
+}
+
+// ChangeState forces a transition on the next update.
+func (self *StateMachine) ChangeState(name string) {
+	self.next = name
+}
+
+// Update evaluates transitions and ticks every active state from the
+// outermost to the innermost.
+func (self *StateMachine) Update(ctx *StateContext, dt float64) {
+	ctx.Machine = self
+	if !self.started {
+		self.started = true
+		self.transition(ctx, self.Definition.Initial)
+	}
+	if self.next != "" {
+		target := self.next
+		self.next = ""
+		self.transition(ctx, target)
+	} else if t := self.findTransition(ctx); t != nil {
+		self.transition(ctx, t.To)
+	}
+	self.events = self.events[:0]
+
+	self.timeInState += dt
+	for _, name := range self.active {
+		if s := self.Definition.states[name]; s != nil && s.OnUpdate != nil {
+			s.OnUpdate(ctx, dt)
+		}
+	}
+}
+
+func (self *StateMachine) findTransition(ctx *StateContext) *StateTransition {
+	for _, name := range self.active {
+		for i := range self.Definition.transitions {
+			t := &self.Definition.transitions[i]
+			if t.From != name {
+				continue
+			}
+			if t.Event != "" && !slices.Contains(self.events, t.Event) {
+				continue
+			}
+			if t.Condition != "" && (ctx.Vars == nil || !ctx.Vars.MustEvaluate(t.Condition)) {
+				continue
+			}
+			if t.Guard != nil && !t.Guard(ctx) {
+				continue
+			}
+			return t
+		}
+	}
+	return nil
+}
+
+func (self *StateMachine) transition(ctx *StateContext, to string) {
+	target := self.Definition.path(to)
+	if len(target) == 0 {
+		logger.GetLogger().Error("state machine: state %q is not defined", to)
+		return
+	}
+	// Drill into initial substates.
+	for {
+		leaf := target[len(target)-1]
+		children := self.Definition.children[leaf]
+		if len(children) == 0 {
+			break
+		}
+		initial := self.Definition.states[leaf].Initial
+		if initial == "" {
+			initial = children[0]
+		}
+		if !slices.Contains(children, initial) || self.Definition.states[initial] == nil {
+			logger.GetLogger().Error("state machine: initial state %q of %q is not defined", initial, leaf)
+			return
+		}
+		target = append(target, initial)
+	}
+
+	from := self.Current()
+	shared := 0
+	for shared < len(self.active) && shared < len(target) && self.active[shared] == target[shared] {
+		shared++
+	}
+	// Re-entering the current state exits and enters it again.
+	if shared == len(self.active) && shared == len(target) {
+		shared--
+	}
+
+	for i := len(self.active) - 1; i >= shared; i-- {
+		if s := self.Definition.states[self.active[i]]; s.OnExit != nil {
+			s.OnExit(ctx, 0)
+		}
+	}
+	self.active = append(self.active[:shared], target[shared:]...)
+	self.timeInState = 0
+	for _, name := range target[shared:] {
+		if s := self.Definition.states[name]; s.OnEnter != nil {
+			s.OnEnter(ctx, 0)
+		}
+	}
+	if ctx.World != nil {
+		Publish(ctx.World, StateChangedEvent{Entity: ctx.Entity, From: from, To: self.Current()})
+	}
+}
@@ -0,0 +1,174 @@
+package katsu2d
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// SystemAccess lists the components a system reads and writes.
+type SystemAccess struct {
+	Reads, Writes []reflect.Type
+}
+
+// AccessDeclarer is implemented by systems that declare their component
+// access, e.g.
+//
+//	func (self *MoveSystem) Access() katsu2d.SystemAccess {
+//		return katsu2d.SystemAccess{
+//			Reads:  []reflect.Type{reflect.TypeFor[VelocityComponent]()},
+//			Writes: []reflect.Type{reflect.TypeFor[katsu2d.TransformComponent]()},
+//		}
+//	}
+type AccessDeclarer interface {
+	Access() SystemAccess
+}
+
+// SystemNode is one registered system in a SystemGraph.
+type SystemNode struct {
+	Name string
+	// TimeGroup is the group the system pauses and scales with.
+	TimeGroup TimeGroup
+	// Access is empty unless the system implements AccessDeclarer.
+	Access SystemAccess
+}
+
+// SystemStage is a list of systems that run one after another. Stages run
+// in the order of SystemGraph.Stages.
+type SystemStage struct {
+	Name    string
+	Systems []SystemNode
+}
+
+// SystemGraph describes the systems of an engine and its current scene in
+// the order they run within a frame.
+type SystemGraph struct {
+	Stages []SystemStage
+}
+
+// SystemGraph returns the engine's systems followed by the current
+// scene's, stage by stage in frame order.
+func (self *Engine) SystemGraph() SystemGraph {
+	var graph SystemGraph
+	scene := self.scm.current
+	if scene == nil {
+		scene = &Scene{}
+	}
+	graph.addUpdate("engine simulation", self.updateSystems[SimulationGroup], self.timeControl)
+	graph.addUpdate("scene simulation", scene.UpdateSystems, self.timeControl)
+	graph.addUpdate("engine late update", self.updateSystems[LateUpdateGroup], self.timeControl)
+	graph.addUpdate("scene late update", scene.LateUpdateSystems, self.timeControl)
+	graph.addUpdate("engine presentation", self.updateSystems[PresentationGroup], self.timeControl)
+	graph.addUpdate("scene presentation", scene.PresentationSystems, self.timeControl)
+	graph.addDraw("background draw", self.backgroundDrawSystems)
+	graph.addDraw("scene draw", scene.DrawSystems)
+	graph.addDraw("overlay draw", self.overlayDrawSystems)
+	return graph
+}
+
+// SystemGraph returns the scene's systems stage by stage in frame order.
+func (self *Scene) SystemGraph() SystemGraph {
+	var graph SystemGraph
+	tc := GetTimeControl(self.world)
+	graph.addUpdate("simulation", self.UpdateSystems, tc)
+	graph.addUpdate("late update", self.LateUpdateSystems, tc)
+	graph.addUpdate("presentation", self.PresentationSystems, tc)
+	graph.addDraw("draw", self.DrawSystems)
+	return graph
+}
+
+func (self *SystemGraph) addUpdate(name string, systems []UpdateSystem, tc *TimeControl) {
+	stage := SystemStage{Name: name}
+	for _, us := range systems {
+		node := newSystemNode(us)
+		if tc != nil {
+			node.TimeGroup = tc.GroupOf(us)
+		}
+		stage.Systems = append(stage.Systems, node)
+	}
+	self.add(stage)
+}
+
+func (self *SystemGraph) addDraw(name string, systems []DrawSystem) {
+	stage := SystemStage{Name: name}
+	for _, ds := range systems {
+		stage.Systems = append(stage.Systems, newSystemNode(ds))
+	}
+	self.add(stage)
+}
+
+func (self *SystemGraph) add(stage SystemStage) {
+	if len(stage.Systems) > 0 {
+		self.Stages = append(self.Stages, stage)
+	}
+}
+
+func newSystemNode(sys any) SystemNode {
+	node := SystemNode{Name: strings.TrimPrefix(reflect.TypeOf(sys).String(), "*")}
+	if ad, ok := sys.(AccessDeclarer); ok {
+		node.Access = ad.Access()
+	}
+	return node
+}
+
+// WriteDOT writes the graph in Graphviz DOT format: a cluster per stage
+// with its systems chained in run order, and declared component access as
+// dashed read edges into systems and bold write edges out of them.
+func (self SystemGraph) WriteDOT(w io.Writer) error {
+	b := bufio.NewWriter(w)
+	fmt.Fprintln(b, "digraph systems {")
+	fmt.Fprintln(b, "\trankdir=LR;")
+	fmt.Fprintln(b, "\tnode [shape=box, fontname=\"sans-serif\"];")
+	var components []string
+	prev := ""
+	for i, stage := range self.Stages {
+		fmt.Fprintf(b, "\tsubgraph cluster_%d {\n\t\tlabel=%q;\n", i, stage.Name)
+		for j, node := range stage.Systems {
+			label := node.Name
+			if node.TimeGroup != DefaultTimeGroup {
+				label += "\\n(" + string(node.TimeGroup) + ")"
+			}
+			fmt.Fprintf(b, "\t\ts%d_%d [label=\"%s\"];\n", i, j, label)
+		}
+		fmt.Fprintln(b, "\t}")
+		for j, node := range stage.Systems {
+			id := fmt.Sprintf("s%d_%d", i, j)
+			if prev != "" {
+				fmt.Fprintf(b, "\t%s -> %s;\n", prev, id)
+			}
+			prev = id
+			for _, t := range node.Access.Reads {
+				components = append(components, t.String())
+				fmt.Fprintf(b, "\t%q -> %s [style=dashed, color=gray40];\n", t.String(), id)
+			}
+			for _, t := range node.Access.Writes {
+				components = append(components, t.String())
+				fmt.Fprintf(b, "\t%s -> %q [style=bold, color=firebrick];\n", id, t.String())
+			}
+		}
+	}
+	slices.Sort(components)
+	for _, name := range slices.Compact(components) {
+		fmt.Fprintf(b, "\t%q [shape=ellipse, style=filled, fillcolor=lightyellow];\n", name)
+	}
+	fmt.Fprintln(b, "}")
+	return b.Flush()
+}
+
+// DumpSystemGraph writes the engine's SystemGraph as a DOT file, to render
+// with e.g. `dot -Tsvg systems.dot -o systems.svg`.
+func (self *Engine) DumpSystemGraph(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := self.SystemGraph().WriteDOT(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
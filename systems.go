@@ -13,3 +13,43 @@ type DrawSystem interface {
 	Initialize(*teishoku.World)
 	Draw(*teishoku.World, *BatchRenderer)
 }
+
+// SystemGroup defines when an update system runs within a frame.
+type SystemGroup int
+
+const (
+	// SimulationGroup runs once per engine tick with a fixed timestep.
+	SimulationGroup SystemGroup = iota
+	// LateUpdateGroup runs after every simulation step has finished,
+	// which is the right place for camera follow and similar systems
+	// that must observe the final positions of the tick.
+	LateUpdateGroup
+	// PresentationGroup runs once per rendered frame, right before drawing,
+	// with the real elapsed frame time.
+	PresentationGroup
+	systemGroupCount
+)
+
+// systemGroups stores update systems bucketed by their SystemGroup.
+type systemGroups [systemGroupCount][]UpdateSystem
+
+func (self *systemGroups) add(group SystemGroup, sys UpdateSystem) {
+	if group < 0 || group >= systemGroupCount {
+		group = SimulationGroup
+	}
+	self[group] = append(self[group], sys)
+}
+
+func (self *systemGroups) initialize(w *teishoku.World) {
+	for _, systems := range self {
+		for _, us := range systems {
+			us.Initialize(w)
+		}
+	}
+}
+
+func (self *systemGroups) update(group SystemGroup, w *teishoku.World, dt float64) {
+	for _, us := range self[group] {
+		runUpdateSystem(w, us, dt)
+	}
+}
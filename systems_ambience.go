@@ -0,0 +1,169 @@
+package katsu2d
+
+import "github.com/edwinsyarief/teishoku"
+
+// AmbientEvent is a random event run by the AmbienceSystem, such as distant
+// thunder, a flock of birds flying by or a gust of wind.
+type AmbientEvent struct {
+	Name string
+	// Weight is the relative chance of being picked among the events that
+	// are due at the same time. Zero counts as 1.
+	Weight float64
+	// MinInterval and MaxInterval bound the random number of seconds
+	// before the event may run again. The first run is also delayed by it.
+	MinInterval, MaxInterval float64
+	// Condition, when set, must hold for the event to run, e.g. only at
+	// night or only outdoors.
+	Condition func(w *teishoku.World) bool
+	// Action runs the event.
+	Action func(w *teishoku.World)
+
+	cooldown float64
+}
+
+// AmbientEventFiredEvent is published when the AmbienceSystem runs an event.
+type AmbientEventFiredEvent struct {
+	Name string
+}
+
+// AmbientWhen returns a condition holding while the blackboard bool key is
+// set, e.g. AmbientWhen("night").
+func AmbientWhen(key string) func(w *teishoku.World) bool {
+	return func(w *teishoku.World) bool {
+		return GetVars(w).GetBool(key)
+	}
+}
+
+// AmbientUnless returns a condition holding while the blackboard bool key
+// is not set, e.g. AmbientUnless("indoors").
+func AmbientUnless(key string) func(w *teishoku.World) bool {
+	return func(w *teishoku.World) bool {
+		return !GetVars(w).GetBool(key)
+	}
+}
+
+// AmbienceSystem runs weighted random AmbientEvents on their own intervals.
+// At most one event runs per update, picked by weight among the due ones.
+// MinGap and MaxGap additionally space out any two events, so ambience
+// never piles up; with no gap, the other due events run on the following
+// updates.
+type AmbienceSystem struct {
+	MinGap, MaxGap float64
+	Enabled        bool
+
+	events []*AmbientEvent
+	gap    float64
+	due    []*AmbientEvent
+	rand   *Rand
+}
+
+// NewAmbienceSystem creates a new AmbienceSystem running events.
+func NewAmbienceSystem(events ...AmbientEvent) *AmbienceSystem {
+	self := &AmbienceSystem{Enabled: true, rand: Random()}
+	for _, ev := range events {
+		self.Add(ev)
+	}
+	return self
+}
+
+// SetSeed makes the event timing reproducible. The cooldowns of the
+// registered events and the gap are drawn again from the new seed.
+func (self *AmbienceSystem) SetSeed(seed int64) {
+	self.rand.SetSeed(seed)
+	self.gap = 0
+	for _, ev := range self.events {
+		ev.cooldown = self.interval(ev.MinInterval, ev.MaxInterval)
+	}
+}
+
+// Add registers ev, replacing an event with the same name.
+func (self *AmbienceSystem) Add(ev AmbientEvent) {
+	ev.cooldown = self.interval(ev.MinInterval, ev.MaxInterval)
+	for i, existing := range self.events {
+		if existing.Name == ev.Name {
+			self.events[i] = &ev
+			return
+		}
+	}
+	self.events = append(self.events, &ev)
+}
+
+// Remove unregisters the event called name.
+func (self *AmbienceSystem) Remove(name string) {
+	for i, ev := range self.events {
+		if ev.Name == name {
+			self.events = append(self.events[:i], self.events[i+1:]...)
+			return
+		}
+	}
+}
+
+// Trigger runs the event called name now, ignoring its interval and
+// condition. It returns false if there is no such event.
+func (self *AmbienceSystem) Trigger(w *teishoku.World, name string) bool {
+	for _, ev := range self.events {
+		if ev.Name == name {
+			self.fire(w, ev)
+			return true
+		}
+	}
+	return false
+}
+
+func (self *AmbienceSystem) Initialize(w *teishoku.World) {}
+
+func (self *AmbienceSystem) Update(w *teishoku.World, dt float64) {
+	if !self.Enabled || dt <= 0 {
+		return
+	}
+	self.gap -= dt
+	self.due = self.due[:0]
+	total := 0.0
+	for _, ev := range self.events {
+		ev.cooldown -= dt
+		if ev.cooldown > 0 || self.gap > 0 {
+			continue
+		}
+		if ev.Condition != nil && !ev.Condition(w) {
+			continue
+		}
+		self.due = append(self.due, ev)
+		total += ambientWeight(ev)
+	}
+	if len(self.due) == 0 {
+		return
+	}
+	// Pick one due event by weight; the others wait for the next gap.
+	pick := self.rand.Float64() * total
+	for i, ev := range self.due {
+		pick -= ambientWeight(ev)
+		if pick < 0 || i == len(self.due)-1 {
+			self.fire(w, ev)
+			break
+		}
+	}
+	clear(self.due)
+}
+
+func (self *AmbienceSystem) fire(w *teishoku.World, ev *AmbientEvent) {
+	ev.cooldown = self.interval(ev.MinInterval, ev.MaxInterval)
+	self.gap = self.interval(self.MinGap, self.MaxGap)
+	if ev.Action != nil {
+		ev.Action(w)
+	}
+	Publish(w, AmbientEventFiredEvent{Name: ev.Name})
+}
+
+func (self *AmbienceSystem) interval(min, max float64) float64 {
+	if max <= min {
+		return min
+	}
+	return self.rand.FloatRange(min, max)
+}
+
+func ambientWeight(ev *AmbientEvent) float64 {
+	if ev.Weight <= 0 {
+		return 1
+	}
+	return ev.Weight
+}
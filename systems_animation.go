@@ -2,7 +2,7 @@ package katsu2d
 
 import "github.com/edwinsyarief/teishoku"
 
-// AnimationSystem updates animations.
+// AnimationSystem updates animations and publishes their frame events.
 type AnimationSystem struct {
 	filter *teishoku.Filter2[AnimationComponent, SpriteComponent]
 }
@@ -23,9 +23,10 @@ func (self *AnimationSystem) Update(w *teishoku.World, dt float64) {
 		if !anim.Active || len(anim.Frames) == 0 {
 			continue
 		}
-		anim.Elapsed += dt
+		anim.Elapsed += ScaledDelta(w, self.filter.Entity(), dt)
 		if anim.Elapsed >= anim.Speed {
 			anim.Elapsed -= anim.Speed
+			previous := anim.Current
 			nf := len(anim.Frames)
 			switch anim.Mode {
 			case AnimOnce:
@@ -60,6 +61,12 @@ func (self *AnimationSystem) Update(w *teishoku.World, dt float64) {
 			}
 			frame := anim.Frames[anim.Current]
 			spr.Bound = frame
+			spr.SyncSizeToBound()
+			if anim.Current != previous {
+				for _, name := range anim.Events[anim.Current] {
+					Publish(w, AnimationFrameEvent{Entity: self.filter.Entity(), Name: name, Frame: anim.Current})
+				}
+			}
 		}
 	}
 
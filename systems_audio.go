@@ -0,0 +1,83 @@
+package katsu2d
+
+import (
+	"log"
+
+	"github.com/edwinsyarief/teishoku"
+)
+
+// SpatialAudioSystem moves the audio listener to the AudioListenerComponent
+// entity, or the camera, and plays AudioEmitterComponent entities at their
+// positions. Emitters stop when their entity is removed.
+type SpatialAudioSystem struct {
+	emitters  *teishoku.Filter2[TransformComponent, AudioEmitterComponent]
+	listeners *teishoku.Filter2[TransformComponent, AudioListenerComponent]
+	playing   map[teishoku.Entity]PlaybackID
+	seen      map[teishoku.Entity]bool
+}
+
+// NewSpatialAudioSystem creates a new SpatialAudioSystem.
+func NewSpatialAudioSystem() *SpatialAudioSystem {
+	return &SpatialAudioSystem{
+		playing: make(map[teishoku.Entity]PlaybackID),
+		seen:    make(map[teishoku.Entity]bool),
+	}
+}
+
+func (self *SpatialAudioSystem) Initialize(w *teishoku.World) {
+	self.emitters = self.emitters.New(w)
+	self.listeners = self.listeners.New(w)
+}
+
+func (self *SpatialAudioSystem) Update(w *teishoku.World, dt float64) {
+	am := GetAudioManager(w)
+	if am == nil {
+		return
+	}
+	self.listeners.Reset()
+	if self.listeners.Next() {
+		t, _ := self.listeners.Get()
+		am.SetListener(Vector(t.Position), t.Rotation)
+	} else if cam := GetCamera(w); cam != nil {
+		am.SetListener(cam.Position, cam.Rotation)
+	}
+
+	clear(self.seen)
+	self.emitters.Reset()
+	for self.emitters.Next() {
+		t, emitter := self.emitters.Get()
+		e := self.emitters.Entity()
+		self.seen[e] = true
+		if emitter.playing && !am.IsPlaying(emitter.playback) && !am.IsPaused(emitter.playback) {
+			emitter.playing = false
+			delete(self.playing, e)
+			if !emitter.Loop {
+				emitter.Active = false
+			}
+		}
+		switch {
+		case emitter.Active && !emitter.playing:
+			stack := &StackingConfig{Enabled: true, MaxStack: max(emitter.MaxInstances, 2)}
+			id, err := am.PlaySpatial(emitter.Track, Vector(t.Position), emitter.Rolloff, emitter.Loop, stack, emitter.Bus)
+			if err != nil {
+				log.Printf("error playing audio emitter: %v\n", err)
+				emitter.Active = false
+				continue
+			}
+			emitter.playback, emitter.playing = id, true
+			self.playing[e] = id
+		case !emitter.Active && emitter.playing:
+			_ = am.Stop(emitter.playback)
+			emitter.playing = false
+			delete(self.playing, e)
+		case emitter.playing:
+			_ = am.SetSpatialPosition(emitter.playback, Vector(t.Position))
+		}
+	}
+	for e, id := range self.playing {
+		if !self.seen[e] {
+			_ = am.Stop(id)
+			delete(self.playing, e)
+		}
+	}
+}
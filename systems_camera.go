@@ -0,0 +1,76 @@
+package katsu2d
+
+import (
+	"math"
+
+	"github.com/edwinsyarief/teishoku"
+)
+
+// CameraSystem moves the world's cameras towards their follow targets and
+// advances their shake. Add it to the LateUpdateGroup so it sees the final
+// positions of the tick.
+type CameraSystem struct {
+	filter  *teishoku.Filter[CameraComponent]
+	cameras []*Camera
+}
+
+// NewCameraSystem creates a new CameraSystem.
+func NewCameraSystem() *CameraSystem {
+	return &CameraSystem{}
+}
+
+func (self *CameraSystem) Initialize(w *teishoku.World) {
+	self.filter = self.filter.New(w)
+}
+
+func (self *CameraSystem) Update(w *teishoku.World, dt float64) {
+	self.cameras = appendCameras(w, self.filter, self.cameras[:0])
+	for _, cam := range self.cameras {
+		self.update(w, cam, dt)
+	}
+	clear(self.cameras)
+}
+
+func (self *CameraSystem) update(w *teishoku.World, cam *Camera, dt float64) {
+	cam.updateShake(dt)
+	if len(cam.Bounds) == 0 {
+		cam.bounded = false
+		self.follow(w, cam, dt)
+		return
+	}
+	// Follow from the unconfined position unless the camera was moved by
+	// hand since the last update.
+	if cam.bounded && cam.Position == cam.boundedPosition {
+		cam.Position = cam.freePosition
+	}
+	focus := self.follow(w, cam, dt)
+	cam.freePosition = cam.Position
+	cam.Position = cam.confine(focus, dt)
+	cam.boundedPosition, cam.bounded = cam.Position, true
+}
+
+// follow moves the camera towards its target and returns the point used to
+// pick the bounds region.
+func (self *CameraSystem) follow(w *teishoku.World, cam *Camera, dt float64) Vector {
+	if !cam.Following || !CheckEntity(w, cam.Target) {
+		return cam.Position
+	}
+	t := teishoku.GetComponent[TransformComponent](w, cam.Target)
+	if t == nil {
+		return cam.Position
+	}
+	if cam.Target != cam.lastTarget {
+		// A new target has no velocity history yet.
+		cam.lastTarget, cam.targetTracked = cam.Target, false
+		cam.targetVelocity = Vector{}
+	}
+	cam.updateLookAhead(Vector(t.Position), dt)
+	goal := Vector(t.Position).Add(cam.FollowOffset, cam.lookAhead)
+	if cam.FollowSpeed <= 0 {
+		cam.Position = goal
+	} else {
+		// Frame-rate independent exponential smoothing.
+		cam.Position = cam.Position.Add(goal.Sub(cam.Position).ScaleF(1 - math.Exp(-cam.FollowSpeed*dt)))
+	}
+	return Vector(t.Position)
+}
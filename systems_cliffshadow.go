@@ -0,0 +1,156 @@
+package katsu2d
+
+import (
+	"hash/maphash"
+	"image/color"
+
+	"github.com/edwinsyarief/teishoku"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+type cliffShadowChunk struct {
+	hash     uint64
+	vertices []ebiten.Vertex
+	indices  []uint16
+}
+
+// CliffShadowSystem darkens the edges of elevation map cells that border
+// higher cells. Meshes are baked per chunk and only rebuilt when the cells of
+// a chunk change. Add it after the tile layers and before sprites.
+type CliffShadowSystem struct {
+	// ChunkSize is the chunk width and height in cells.
+	ChunkSize int
+	// Width is how far the shadow reaches into a cell, as a fraction of it.
+	Width    float64
+	Color    color.RGBA
+	Strength float64
+
+	chunks   map[[2]int]*cliffShadowChunk
+	revision int
+	seed     maphash.Seed
+	baked    bool
+}
+
+// NewCliffShadowSystem creates a cliff shadow system with soft black shadows.
+func NewCliffShadowSystem() *CliffShadowSystem {
+	return &CliffShadowSystem{
+		ChunkSize: 16,
+		Width:     0.5,
+		Color:     color.RGBA{A: 255},
+		Strength:  0.45,
+		chunks:    make(map[[2]int]*cliffShadowChunk),
+		seed:      maphash.MakeSeed(),
+	}
+}
+
+func (self *CliffShadowSystem) Initialize(w *teishoku.World) {}
+
+// Invalidate forces every chunk to be rebuilt, e.g. after changing Color.
+func (self *CliffShadowSystem) Invalidate() {
+	clear(self.chunks)
+	self.baked = false
+}
+
+func (self *CliffShadowSystem) Draw(w *teishoku.World, rdr *BatchRenderer) {
+	m := GetElevationMap(w)
+	if m == nil || m.CellSize <= 0 {
+		return
+	}
+	if !self.baked || m.Revision() != self.revision {
+		self.bake(m)
+	}
+	img := GetTextureManager(w).Get(0)
+	for _, chunk := range self.chunks {
+		if len(chunk.indices) > 0 {
+			rdr.AddCustomMeshes(chunk.vertices, chunk.indices, img)
+		}
+	}
+}
+
+// bake rebuilds the chunks whose cells, or the cells bordering them, changed.
+func (self *CliffShadowSystem) bake(m *ElevationMap) {
+	self.revision = m.Revision()
+	self.baked = true
+	// Keep chunk meshes within 16-bit indices.
+	size := Clamp(self.ChunkSize, 1, 63)
+	cols, rows := (m.Width+size-1)/size, (m.Height+size-1)/size
+	for key := range self.chunks {
+		if key[0] >= cols || key[1] >= rows {
+			delete(self.chunks, key)
+		}
+	}
+	for cy := range rows {
+		for cx := range cols {
+			key := [2]int{cx, cy}
+			hash := self.chunkHash(m, cx, cy, size)
+			chunk := self.chunks[key]
+			if chunk != nil && chunk.hash == hash {
+				continue
+			}
+			if chunk == nil {
+				chunk = &cliffShadowChunk{}
+				self.chunks[key] = chunk
+			}
+			chunk.hash = hash
+			self.buildChunk(m, chunk, cx, cy, size)
+		}
+	}
+}
+
+func (self *CliffShadowSystem) chunkHash(m *ElevationMap, cx, cy, size int) uint64 {
+	var h maphash.Hash
+	h.SetSeed(self.seed)
+	var buf [8]byte
+	for y := cy*size - 1; y <= (cy+1)*size; y++ {
+		for x := cx*size - 1; x <= (cx+1)*size; x++ {
+			v := uint64(m.At(x, y))
+			for i := range buf {
+				buf[i] = byte(v >> (8 * i))
+			}
+			h.Write(buf[:])
+		}
+	}
+	return h.Sum64()
+}
+
+func (self *CliffShadowSystem) buildChunk(m *ElevationMap, chunk *cliffShadowChunk, cx, cy, size int) {
+	chunk.vertices = chunk.vertices[:0]
+	chunk.indices = chunk.indices[:0]
+	a := float32(self.Color.A) / 255 * float32(self.Strength)
+	r, g, b := float32(self.Color.R)/255*a, float32(self.Color.G)/255*a, float32(self.Color.B)/255*a
+	cell := m.CellSize
+	depth := cell * Clamp(self.Width, 0, 1)
+
+	for y := cy * size; y < min((cy+1)*size, m.Height); y++ {
+		for x := cx * size; x < min((cx+1)*size, m.Width); x++ {
+			level := m.At(x, y)
+			x0, y0 := m.Origin.X+float64(x)*cell, m.Origin.Y+float64(y)*cell
+			x1, y1 := x0+cell, y0+cell
+			// Each edge fades from the border with the higher neighbor inwards.
+			if m.InBounds(x, y-1) && m.At(x, y-1) > level {
+				self.appendFade(chunk, V(x0, y0), V(x1, y0), V(x1, y0+depth), V(x0, y0+depth), r, g, b, a)
+			}
+			if m.InBounds(x, y+1) && m.At(x, y+1) > level {
+				self.appendFade(chunk, V(x1, y1), V(x0, y1), V(x0, y1-depth), V(x1, y1-depth), r, g, b, a)
+			}
+			if m.InBounds(x-1, y) && m.At(x-1, y) > level {
+				self.appendFade(chunk, V(x0, y1), V(x0, y0), V(x0+depth, y0), V(x0+depth, y1), r, g, b, a)
+			}
+			if m.InBounds(x+1, y) && m.At(x+1, y) > level {
+				self.appendFade(chunk, V(x1, y0), V(x1, y1), V(x1-depth, y1), V(x1-depth, y0), r, g, b, a)
+			}
+		}
+	}
+}
+
+// appendFade adds a quad that is dark along p0-p1 and transparent along p2-p3.
+func (self *CliffShadowSystem) appendFade(chunk *cliffShadowChunk, p0, p1, p2, p3 Vector, r, g, b, a float32) {
+	base := uint16(len(chunk.vertices))
+	chunk.vertices = append(chunk.vertices,
+		ebiten.Vertex{DstX: float32(p0.X), DstY: float32(p0.Y), ColorR: r, ColorG: g, ColorB: b, ColorA: a},
+		ebiten.Vertex{DstX: float32(p1.X), DstY: float32(p1.Y), ColorR: r, ColorG: g, ColorB: b, ColorA: a},
+		ebiten.Vertex{DstX: float32(p2.X), DstY: float32(p2.Y)},
+		ebiten.Vertex{DstX: float32(p3.X), DstY: float32(p3.Y)},
+	)
+	chunk.indices = append(chunk.indices, base, base+1, base+2, base, base+2, base+3)
+}
@@ -0,0 +1,396 @@
+package katsu2d
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/edwinsyarief/teishoku"
+)
+
+// collisionPair identifies two colliding entities, lowest ID first.
+type collisionPair struct {
+	A, B teishoku.Entity
+}
+
+func newCollisionPair(a, b teishoku.Entity) collisionPair {
+	if b.ID < a.ID {
+		a, b = b, a
+	}
+	return collisionPair{A: a, B: b}
+}
+
+// collisionBody is a collider resolved to world space for one update.
+type collisionBody struct {
+	entity   teishoku.Entity
+	shape    ColliderShape
+	bounds   Rectangle
+	center   Vector
+	radius   float64
+	capsule  Capsule
+	layer    CollisionLayer
+	sensor   bool
+	oneWay   Vector
+	material string
+}
+
+// CollisionSystem finds overlapping ColliderComponent entities, using a
+// quadtree as broadphase, and publishes CollisionEnterEvent,
+// CollisionStayEvent and CollisionExitEvent. Only entities on the same
+// elevation collide, and only on layers the Matrix lets collide. Enter also
+// calls the entities' script OnCollision hooks.
+type CollisionSystem struct {
+	// Matrix decides which collider layers collide. It becomes the world's
+	// CollisionMatrix on Initialize; nil uses the world's.
+	Matrix *CollisionMatrix
+	// Resolve pushes RigidBodyComponent entities out of the non-sensor
+	// colliders they overlap, bouncing and sliding them by the combined
+	// PhysicsMaterials of the pair, and sets their Grounded flag when they
+	// rest on something below.
+	Resolve bool
+
+	filter   *teishoku.Filter2[TransformComponent, ColliderComponent]
+	tree     *Quadtree
+	bodies   []collisionBody
+	index    map[teishoku.Entity]int
+	active   map[collisionPair]Contact
+	previous map[collisionPair]Contact
+	enters   []collisionPair
+	events   []collisionPair
+	nearby   []teishoku.Entity
+	reach    Vector
+	contacts []collisionPair
+}
+
+// NewCollisionSystem creates a new CollisionSystem.
+func NewCollisionSystem() *CollisionSystem {
+	return &CollisionSystem{
+		index:    make(map[teishoku.Entity]int),
+		active:   make(map[collisionPair]Contact),
+		previous: make(map[collisionPair]Contact),
+	}
+}
+
+func (self *CollisionSystem) Initialize(w *teishoku.World) {
+	self.filter = self.filter.New(w)
+	self.tree = NewQuadtree(w, Rectangle{})
+	if self.Matrix != nil {
+		SetResource(w, self.Matrix)
+	} else {
+		self.Matrix = GetCollisionMatrix(w)
+	}
+}
+
+// Colliding reports whether a and b overlapped in the last update.
+func (self *CollisionSystem) Colliding(a, b teishoku.Entity) bool {
+	_, ok := self.active[newCollisionPair(a, b)]
+	return ok
+}
+
+func (self *CollisionSystem) Update(w *teishoku.World, dt float64) {
+	// Resolve the colliders and the extent the broadphase must cover.
+	self.bodies = self.bodies[:0]
+	clear(self.index)
+	var world Rectangle
+	reachX, reachY := 0.0, 0.0
+	self.filter.Reset()
+	for self.filter.Next() {
+		t, c := self.filter.Get()
+		if c.Disabled {
+			continue
+		}
+		body := resolveCollider(Vector(t.Position), t.Rotation, c)
+		body.entity = self.filter.Entity()
+		body.layer, body.sensor, body.oneWay, body.material = c.Layer, c.Sensor, c.OneWay, c.Material
+		if self.Resolve {
+			if rb := teishoku.GetComponent[RigidBodyComponent](w, body.entity); rb != nil {
+				rb.Grounded = false
+			}
+		}
+		self.index[body.entity] = len(self.bodies)
+		self.bodies = append(self.bodies, body)
+		if len(self.bodies) == 1 {
+			world = body.bounds
+		} else {
+			world = unionRectangle(world, body.bounds)
+		}
+		pos := Vector(t.Position)
+		reachX = math.Max(reachX, math.Max(pos.X-body.bounds.Min.X, body.bounds.Max.X-pos.X))
+		reachY = math.Max(reachY, math.Max(pos.Y-body.bounds.Min.Y, body.bounds.Max.Y-pos.Y))
+	}
+
+	self.previous, self.active = self.active, self.previous
+	clear(self.active)
+	self.enters = self.enters[:0]
+	self.contacts = self.contacts[:0]
+	self.reach = V(reachX, reachY)
+	// The tree is kept after the update for the queries.
+	self.tree.Reset(NewRectangle(world.Min.X-1, world.Min.Y-1, world.Max.X+1, world.Max.Y+1))
+	for _, body := range self.bodies {
+		self.tree.Insert(body.entity)
+	}
+	if len(self.bodies) > 1 {
+		// The tree stores positions, so queries grow by the largest reach.
+		for i := range self.bodies {
+			a := &self.bodies[i]
+			query := NewRectangle(a.bounds.Min.X-reachX, a.bounds.Min.Y-reachY, a.bounds.Max.X+reachX, a.bounds.Max.Y+reachY)
+			self.nearby = self.tree.QueryInto(self.nearby[:0], query)
+			for _, other := range self.nearby {
+				j, ok := self.index[other]
+				if !ok || j <= i {
+					continue
+				}
+				b := &self.bodies[j]
+				if !self.Matrix.Collides(a.layer, b.layer) || !a.bounds.Intersects(b.bounds) || !SameElevation(w, a.entity, b.entity) {
+					continue
+				}
+				contact, hit := collide(a, b)
+				if !hit {
+					continue
+				}
+				pair := newCollisionPair(a.entity, b.entity)
+				_, touching := self.previous[pair]
+				if !touching && (!oneWayAllows(w, a, b, contact.Normal) || !oneWayAllows(w, b, a, contact.Normal.Negate())) {
+					continue
+				}
+				if pair.A != a.entity {
+					contact.Normal = contact.Normal.Negate()
+				}
+				self.active[pair] = contact
+				if !touching {
+					self.enters = append(self.enters, pair)
+				}
+				if self.Resolve && !a.sensor && !b.sensor {
+					self.contacts = append(self.contacts, pair)
+				}
+			}
+		}
+	}
+
+	for _, pair := range self.contacts {
+		self.resolve(w, pair, self.active[pair])
+	}
+
+	// Publish after the scan so handlers may change the world freely. Exits
+	// and stays come from maps, so they are sorted to keep the order stable.
+	self.events = self.events[:0]
+	for pair := range self.previous {
+		if _, ok := self.active[pair]; !ok {
+			self.events = append(self.events, pair)
+		}
+	}
+	sortCollisionPairs(self.events)
+	for _, pair := range self.events {
+		Publish(w, CollisionExitEvent{A: pair.A, B: pair.B})
+	}
+	self.events = self.events[:0]
+	for pair := range self.active {
+		if _, ok := self.previous[pair]; ok {
+			self.events = append(self.events, pair)
+		}
+	}
+	sortCollisionPairs(self.events)
+	for _, pair := range self.events {
+		Publish(w, CollisionStayEvent{A: pair.A, B: pair.B, Contact: self.active[pair], Sensor: self.sensor(pair)})
+	}
+	for _, pair := range self.enters {
+		Publish(w, CollisionEnterEvent{A: pair.A, B: pair.B, Contact: self.active[pair], Sensor: self.sensor(pair)})
+		SendScriptCollision(w, pair.A, pair.B)
+		SendScriptCollision(w, pair.B, pair.A)
+	}
+}
+
+// sortCollisionPairs orders pairs by the IDs of their entities.
+func sortCollisionPairs(pairs []collisionPair) {
+	slices.SortFunc(pairs, func(a, b collisionPair) int {
+		return cmp.Or(cmp.Compare(a.A.ID, b.A.ID), cmp.Compare(a.B.ID, b.B.ID))
+	})
+}
+
+// oneWayAlignment is how closely the contact normal must match a one-way
+// collider's direction for the other body to count as on its solid side.
+const oneWayAlignment = 0.5
+
+// oneWayAllows reports whether b may collide with the one-way collider a,
+// normal pointing from a to b. Pairs already touching keep colliding.
+func oneWayAllows(w *teishoku.World, a, b *collisionBody, normal Vector) bool {
+	if a.oneWay == (Vector{}) {
+		return true
+	}
+	dir := a.oneWay.Normalize()
+	if normal.Dot(dir) < oneWayAlignment {
+		return false
+	}
+	rb := teishoku.GetComponent[RigidBodyComponent](w, b.entity)
+	return rb == nil || rb.Velocity.Dot(dir) <= 0
+}
+
+// sensor reports whether either collider of pair is a sensor.
+func (self *CollisionSystem) sensor(pair collisionPair) bool {
+	i, iok := self.index[pair.A]
+	j, jok := self.index[pair.B]
+	return (iok && self.bodies[i].sensor) || (jok && self.bodies[j].sensor)
+}
+
+// groundNormal is how far up a contact normal must point for the body on
+// top to count as grounded.
+const groundNormal = 0.7
+
+// resolve separates the rigid bodies of pair along the contact and applies
+// the bounce and friction of their materials.
+func (self *CollisionSystem) resolve(w *teishoku.World, pair collisionPair, contact Contact) {
+	ra := teishoku.GetComponent[RigidBodyComponent](w, pair.A)
+	rb := teishoku.GetComponent[RigidBodyComponent](w, pair.B)
+	ia, ib := 0.0, 0.0
+	var va, vb Vector
+	if ra != nil {
+		ia, va = ra.inverseMass(), ra.Velocity
+	}
+	if rb != nil {
+		ib, vb = rb.inverseMass(), rb.Velocity
+	}
+	if ia+ib == 0 {
+		return
+	}
+	n := contact.Normal
+	correction := n.ScaleF(contact.Depth / (ia + ib))
+	if ra != nil {
+		t := teishoku.GetComponent[TransformComponent](w, pair.A)
+		t.Position = Point(Vector(t.Position).Sub(correction.ScaleF(ia)))
+	}
+	if rb != nil {
+		t := teishoku.GetComponent[TransformComponent](w, pair.B)
+		t.Position = Point(Vector(t.Position).Add(correction.ScaleF(ib)))
+	}
+
+	rel := vb.Sub(va)
+	if vn := rel.Dot(n); vn < 0 {
+		material := GetPhysicsMaterials(w).Combine(self.bodies[self.index[pair.A]].material, self.bodies[self.index[pair.B]].material)
+		j := -(1 + material.Restitution) * vn / (ia + ib)
+		impulse := n.ScaleF(j)
+		if tangent := rel.Sub(n.ScaleF(vn)); tangent != (Vector{}) {
+			tangent = tangent.Normalize()
+			jt := Clamp(-rel.Dot(tangent)/(ia+ib), -material.Friction*j, material.Friction*j)
+			impulse = impulse.Add(tangent.ScaleF(jt))
+		}
+		if ra != nil {
+			ra.Velocity = ra.Velocity.Sub(impulse.ScaleF(ia))
+		}
+		if rb != nil {
+			rb.Velocity = rb.Velocity.Add(impulse.ScaleF(ib))
+		}
+	}
+	// Up is negative Y.
+	if rb != nil && n.Y < -groundNormal {
+		rb.Grounded = true
+	}
+	if ra != nil && n.Y > groundNormal {
+		ra.Grounded = true
+	}
+}
+
+// QueryRectangle appends to dst the colliders from the last update that
+// overlap rect and collide with layer.
+func (self *CollisionSystem) QueryRectangle(dst []teishoku.Entity, rect Rectangle, layer CollisionLayer) []teishoku.Entity {
+	query := collisionBody{shape: ColliderAABB, bounds: rect, center: rect.Min.Add(rect.Max).ScaleF(0.5)}
+	return self.query(dst, &query, layer)
+}
+
+// QueryCircle appends to dst the colliders from the last update that
+// overlap the circle and collide with layer.
+func (self *CollisionSystem) QueryCircle(dst []teishoku.Entity, center Vector, radius float64, layer CollisionLayer) []teishoku.Entity {
+	query := collisionBody{shape: ColliderCircle, center: center, radius: radius,
+		bounds: NewRectangle(center.X-radius, center.Y-radius, center.X+radius, center.Y+radius)}
+	return self.query(dst, &query, layer)
+}
+
+// QueryPoint appends to dst the colliders from the last update containing
+// p that collide with layer.
+func (self *CollisionSystem) QueryPoint(dst []teishoku.Entity, p Vector, layer CollisionLayer) []teishoku.Entity {
+	return self.QueryCircle(dst, p, 0, layer)
+}
+
+func (self *CollisionSystem) query(dst []teishoku.Entity, query *collisionBody, layer CollisionLayer) []teishoku.Entity {
+	if len(self.bodies) == 0 {
+		return dst
+	}
+	b := query.bounds
+	self.nearby = self.tree.QueryInto(self.nearby[:0],
+		NewRectangle(b.Min.X-self.reach.X, b.Min.Y-self.reach.Y, b.Max.X+self.reach.X, b.Max.Y+self.reach.Y))
+	for _, e := range self.nearby {
+		i, ok := self.index[e]
+		if !ok {
+			continue
+		}
+		body := &self.bodies[i]
+		if !self.Matrix.Collides(layer, body.layer) || !b.Intersects(body.bounds) {
+			continue
+		}
+		if _, hit := collide(query, body); hit {
+			dst = append(dst, e)
+		}
+	}
+	return dst
+}
+
+// resolveCollider places a collider in world space.
+func resolveCollider(pos Vector, rotation float64, c *ColliderComponent) collisionBody {
+	center := pos.Add(c.Offset)
+	body := collisionBody{shape: c.Shape, center: center, radius: c.Radius}
+	switch c.Shape {
+	case ColliderCircle:
+		body.bounds = NewRectangle(center.X-c.Radius, center.Y-c.Radius, center.X+c.Radius, center.Y+c.Radius)
+	case ColliderCapsule:
+		body.capsule = NewCapsule(center.Add(c.A.Rotate(rotation)), center.Add(c.B.Rotate(rotation)), c.Radius)
+		body.bounds = body.capsule.Bounds()
+	default:
+		hw, hh := c.Width/2, c.Height/2
+		body.bounds = NewRectangle(center.X-hw, center.Y-hh, center.X+hw, center.Y+hh)
+	}
+	return body
+}
+
+// collide runs the narrowphase test for a pair of bodies.
+func collide(a, b *collisionBody) (Contact, bool) {
+	switch a.shape {
+	case ColliderCircle:
+		switch b.shape {
+		case ColliderCircle:
+			return CircleVsCircle(a.center, a.radius, b.center, b.radius)
+		case ColliderCapsule:
+			return flipContact(b.capsule.IntersectsCircle(a.center, a.radius))
+		default:
+			return flipContact(RectangleVsCircle(b.bounds, a.center, a.radius))
+		}
+	case ColliderCapsule:
+		switch b.shape {
+		case ColliderCircle:
+			return a.capsule.IntersectsCircle(b.center, b.radius)
+		case ColliderCapsule:
+			return a.capsule.IntersectsCapsule(b.capsule)
+		default:
+			return flipContact(RectangleVsCapsule(b.bounds, a.capsule))
+		}
+	default:
+		switch b.shape {
+		case ColliderCircle:
+			return RectangleVsCircle(a.bounds, b.center, b.radius)
+		case ColliderCapsule:
+			return RectangleVsCapsule(a.bounds, b.capsule)
+		default:
+			return RectangleVsRectangle(a.bounds, b.bounds)
+		}
+	}
+}
+
+func flipContact(contact Contact, hit bool) (Contact, bool) {
+	contact.Normal = contact.Normal.Negate()
+	return contact, hit
+}
+
+func unionRectangle(a, b Rectangle) Rectangle {
+	return NewRectangle(
+		math.Min(a.Min.X, b.Min.X), math.Min(a.Min.Y, b.Min.Y),
+		math.Max(a.Max.X, b.Max.X), math.Max(a.Max.Y, b.Max.Y),
+	)
+}
@@ -0,0 +1,96 @@
+package katsu2d
+
+import (
+	"math"
+
+	"github.com/edwinsyarief/teishoku"
+)
+
+// ConstraintSystem solves orbit, distance and look-at constraints. Add it
+// after movement systems so constraints see the final positions.
+type ConstraintSystem struct {
+	orbits    *teishoku.Filter2[TransformComponent, OrbitComponent]
+	distances *teishoku.Filter2[TransformComponent, DistanceConstraintComponent]
+	lookAts   *teishoku.Filter2[TransformComponent, LookAtComponent]
+}
+
+// NewConstraintSystem creates a new ConstraintSystem.
+func NewConstraintSystem() *ConstraintSystem {
+	return &ConstraintSystem{}
+}
+
+func (self *ConstraintSystem) Initialize(w *teishoku.World) {
+	self.orbits = self.orbits.New(w)
+	self.distances = self.distances.New(w)
+	self.lookAts = self.lookAts.New(w)
+}
+
+func (self *ConstraintSystem) Update(w *teishoku.World, dt float64) {
+	self.orbits.Reset()
+	for self.orbits.Next() {
+		t, orbit := self.orbits.Get()
+		center, ok := constraintTarget(w, orbit.Center)
+		if !ok {
+			continue
+		}
+		orbit.Angle += orbit.AngularSpeed * ScaledDelta(w, self.orbits.Entity(), dt)
+		t.Position = Point(center.Add(AngleToVector(orbit.Angle, orbit.Radius)))
+	}
+
+	self.distances.Reset()
+	for self.distances.Next() {
+		t, c := self.distances.Get()
+		target, ok := constraintTarget(w, c.Target)
+		if !ok {
+			continue
+		}
+		pos := Vector(t.Position)
+		delta := pos.Sub(target)
+		dist := delta.Length()
+		clamped := max(dist, c.Min)
+		if c.Max > 0 {
+			clamped = min(clamped, c.Max)
+		}
+		if clamped == dist {
+			continue
+		}
+		dir := delta.Normalize()
+		if dist == 0 {
+			dir = V(1, 0)
+		}
+		t.Position = Point(target.Add(dir.ScaleF(clamped)))
+	}
+
+	self.lookAts.Reset()
+	for self.lookAts.Next() {
+		t, look := self.lookAts.Get()
+		target, ok := constraintTarget(w, look.Target)
+		if !ok {
+			continue
+		}
+		pos := Vector(t.Position)
+		if pos.Equals(target) {
+			continue
+		}
+		desired := pos.AngleToPoint(target) + look.RotationOffset
+		if look.TurnSpeed <= 0 {
+			t.Rotation = desired
+			continue
+		}
+		diff := math.Remainder(desired-t.Rotation, 2*math.Pi)
+		step := look.TurnSpeed * ScaledDelta(w, self.lookAts.Entity(), dt)
+		t.Rotation += Clamp(diff, -step, step)
+	}
+}
+
+// constraintTarget returns the position of a live target entity.
+func constraintTarget(w *teishoku.World, e teishoku.Entity) (Vector, bool) {
+	if !CheckEntity(w, e) {
+		return Vector{}, false
+	}
+	t := teishoku.GetComponent[TransformComponent](w, e)
+	if t == nil {
+		return Vector{}, false
+	}
+	return Vector(t.Position), true
+}
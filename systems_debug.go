@@ -0,0 +1,288 @@
+package katsu2d
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/edwinsyarief/katsu2d/logger"
+	"github.com/edwinsyarief/teishoku"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// DebugOverlaySystem is a debug mode for a scene: it shows the names of
+// entities with a DebugLabelComponent and, on click, selects the entity
+// under the cursor and logs its components. Only components registered
+// with RegisterSnapshotComponent are listed. It can also graph the recent
+// frame times with GC pauses and the heap size, and outline colliders by
+// layer. Add it to a scene as both an update and a draw system, after the
+// systems drawing the entities.
+type DebugOverlaySystem struct {
+	// Enabled turns debug mode on; ToggleKey flips it.
+	Enabled   bool
+	ToggleKey ebiten.Key
+	// PickButton selects the entity under the cursor in debug mode.
+	PickButton ebiten.MouseButton
+	// PickRadius is the size picked around entities without a sprite or
+	// collider.
+	PickRadius float64
+	// OnSelect, when set, receives the selected entity and the description
+	// also written to the log, e.g. to show it in an inspector.
+	OnSelect func(w *teishoku.World, e teishoku.Entity, description string)
+	Color    color.RGBA
+	// ShowPerformance graphs the frame times at GraphPosition in debug mode:
+	// update time below draw time per frame, with GC markers on top.
+	ShowPerformance bool
+	GraphPosition   Vector
+	// GraphBudget is the frame time at the top of the graph.
+	GraphBudget time.Duration
+	// ShowColliders outlines colliders in debug mode, colored by their
+	// CollisionMatrix layer.
+	ShowColliders bool
+
+	selected    teishoku.Entity
+	hasSelected bool
+	transform   *Transform
+}
+
+// NewDebugOverlaySystem creates a DebugOverlaySystem toggled with F3.
+func NewDebugOverlaySystem() *DebugOverlaySystem {
+	return &DebugOverlaySystem{
+		ToggleKey:       ebiten.KeyF3,
+		PickButton:      ebiten.MouseButtonLeft,
+		PickRadius:      8,
+		Color:           color.RGBA{255, 255, 0, 255},
+		ShowPerformance: true,
+		GraphPosition:   V(8, 8),
+		GraphBudget:     time.Second / 30,
+		transform:       T(),
+	}
+}
+
+// Selected returns the entity picked last, if it is still alive.
+func (self *DebugOverlaySystem) Selected(w *teishoku.World) (teishoku.Entity, bool) {
+	if !self.hasSelected || !IsAlive(w, self.selected) {
+		return teishoku.Entity{}, false
+	}
+	return self.selected, true
+}
+
+// Select selects e as if it was clicked.
+func (self *DebugOverlaySystem) Select(w *teishoku.World, e teishoku.Entity) {
+	self.selected, self.hasSelected = e, true
+	description := DescribeEntity(w, e)
+	logger.GetLogger().Info("%s", description)
+	if self.OnSelect != nil {
+		self.OnSelect(w, e, description)
+	}
+}
+
+func (self *DebugOverlaySystem) Initialize(w *teishoku.World) {}
+
+func (self *DebugOverlaySystem) Update(w *teishoku.World, dt float64) {
+	if keyJustPressed(self.ToggleKey) {
+		self.Enabled = !self.Enabled
+	}
+	if !self.Enabled || !mouseButtonJustPressed(self.PickButton) {
+		return
+	}
+	cx, cy := ebiten.CursorPosition()
+	p := V(float64(cx), float64(cy))
+	if cam := GetCamera(w); cam != nil {
+		p = cam.ScreenToWorld(p)
+	}
+	if e, ok := self.pick(w, p); ok {
+		self.Select(w, e)
+	}
+}
+
+// pick returns the entity under p, preferring the smallest bounds so small
+// entities on top of large ones can be picked.
+func (self *DebugOverlaySystem) pick(w *teishoku.World, p Vector) (teishoku.Entity, bool) {
+	var best teishoku.Entity
+	bestArea, found := 0.0, false
+	filter := teishoku.NewFilter[TransformComponent](w)
+	for filter.Next() {
+		e := filter.Entity()
+		bounds := self.bounds(w, e, filter.Get())
+		if !bounds.Contains(p) {
+			continue
+		}
+		if area := bounds.Width() * bounds.Height(); !found || area < bestArea {
+			best, bestArea, found = e, area, true
+		}
+	}
+	return best, found
+}
+
+// bounds returns the world rectangle of e's sprite or collider, or a square
+// of PickRadius around its position.
+func (self *DebugOverlaySystem) bounds(w *teishoku.World, e teishoku.Entity, t *TransformComponent) Rectangle {
+	if s := teishoku.GetComponent[SpriteComponent](w, e); s != nil {
+		if img := GetTextureManager(w).Get(s.TextureID); img != nil {
+			sprite := *s
+			quad := spriteQuad(self.transform, t, &sprite, img)
+			bounds := Rectangle{Min: V(float64(quad[0].DstX), float64(quad[0].DstY)), Max: V(float64(quad[0].DstX), float64(quad[0].DstY))}
+			for _, v := range quad[1:] {
+				bounds.Min = V(min(bounds.Min.X, float64(v.DstX)), min(bounds.Min.Y, float64(v.DstY)))
+				bounds.Max = V(max(bounds.Max.X, float64(v.DstX)), max(bounds.Max.Y, float64(v.DstY)))
+			}
+			return bounds
+		}
+	}
+	if c := teishoku.GetComponent[ColliderComponent](w, e); c != nil && !c.Disabled {
+		center := Vector(t.Position).Add(c.Offset)
+		switch c.Shape {
+		case ColliderAABB:
+			half := V(c.Width/2, c.Height/2)
+			return Rectangle{Min: center.Sub(half), Max: center.Add(half)}
+		case ColliderCircle:
+			return Rectangle{Min: center.SubF(c.Radius), Max: center.AddF(c.Radius)}
+		}
+	}
+	pos := Vector(t.Position)
+	return Rectangle{Min: pos.SubF(self.PickRadius), Max: pos.AddF(self.PickRadius)}
+}
+
+func (self *DebugOverlaySystem) Draw(w *teishoku.World, rdr *BatchRenderer) {
+	if !self.Enabled {
+		return
+	}
+	rdr.Flush()
+	screen := rdr.GetScreen()
+	view, _ := rdr.View()
+	if rdr.ScreenSpace() {
+		view = Matrix{}
+	}
+	if self.ShowColliders {
+		self.drawColliders(w, screen, view)
+	}
+	if e, ok := self.Selected(w); ok {
+		if t := teishoku.GetComponent[TransformComponent](w, e); t != nil {
+			b := self.bounds(w, e, t)
+			x0, y0 := view.Apply(b.Min.X, b.Min.Y)
+			x1, y1 := view.Apply(b.Max.X, b.Max.Y)
+			vector.StrokeRect(screen, float32(min(x0, x1)), float32(min(y0, y1)),
+				float32(max(x0, x1)-min(x0, x1)), float32(max(y0, y1)-min(y0, y1)), 1, self.Color, false)
+		}
+	}
+	filter := teishoku.NewFilter2[TransformComponent, DebugLabelComponent](w)
+	for filter.Next() {
+		t, label := filter.Get()
+		b := self.bounds(w, filter.Entity(), t)
+		x, y := view.Apply((b.Min.X+b.Max.X)/2, b.Min.Y)
+		// The debug font is 6 pixels wide per glyph and 16 pixels high.
+		ebitenutil.DebugPrintAt(screen, label.Name, int(x)-len(label.Name)*3, int(y)-16)
+	}
+	if stats := GetFrameStats(w); self.ShowPerformance && stats != nil {
+		self.drawGraph(screen, stats)
+	}
+}
+
+// drawColliders outlines every enabled collider in its layer's color.
+func (self *DebugOverlaySystem) drawColliders(w *teishoku.World, screen *ebiten.Image, view Matrix) {
+	matrix := GetCollisionMatrix(w)
+	filter := teishoku.NewFilter2[TransformComponent, ColliderComponent](w)
+	for filter.Next() {
+		t, c := filter.Get()
+		if c.Disabled {
+			continue
+		}
+		col := matrix.Color(c.Layer)
+		body := resolveCollider(Vector(t.Position), t.Rotation, c)
+		if c.Shape == ColliderCircle {
+			x, y := view.Apply(body.center.X, body.center.Y)
+			ex, ey := view.Apply(body.center.X+body.radius, body.center.Y)
+			vector.StrokeCircle(screen, float32(x), float32(y), float32(math.Hypot(ex-x, ey-y)), 1, col, false)
+			continue
+		}
+		x0, y0 := view.Apply(body.bounds.Min.X, body.bounds.Min.Y)
+		x1, y1 := view.Apply(body.bounds.Max.X, body.bounds.Max.Y)
+		vector.StrokeRect(screen, float32(min(x0, x1)), float32(min(y0, y1)),
+			float32(max(x0, x1)-min(x0, x1)), float32(max(y0, y1)-min(y0, y1)), 1, col, false)
+	}
+}
+
+// Colors of the frame time graph.
+var (
+	debugGraphBackground = color.RGBA{0, 0, 0, 160}
+	debugGraphUpdate     = color.RGBA{80, 200, 120, 255}
+	debugGraphDraw       = color.RGBA{80, 140, 255, 255}
+	debugGraphGC         = color.RGBA{255, 60, 60, 255}
+	debugGraphTarget     = color.RGBA{255, 255, 255, 96}
+)
+
+// debugGraphHeight is the height of the frame time graph in pixels; each
+// frame is one pixel wide.
+const debugGraphHeight = 64
+
+func (self *DebugOverlaySystem) drawGraph(screen *ebiten.Image, stats *FrameStats) {
+	x0, y0 := float32(self.GraphPosition.X), float32(self.GraphPosition.Y)
+	bottom := y0 + debugGraphHeight
+	vector.DrawFilledRect(screen, x0, y0, frameStatsHistory, debugGraphHeight, debugGraphBackground, false)
+	scale := debugGraphHeight / float32(self.GraphBudget.Seconds())
+	// The 60 FPS budget line.
+	target := bottom - float32(time.Second.Seconds()/60)*scale
+	vector.StrokeLine(screen, x0, target, x0+frameStatsHistory, target, 1, debugGraphTarget, false)
+	offset := frameStatsHistory - stats.Len()
+	for i := range stats.Len() {
+		f := stats.Frame(i)
+		x := x0 + float32(offset+i)
+		update := min(float32(f.Update.Seconds())*scale, debugGraphHeight)
+		draw := min(float32(f.Draw.Seconds())*scale, debugGraphHeight-update)
+		vector.DrawFilledRect(screen, x, bottom-update, 1, update, debugGraphUpdate, false)
+		vector.DrawFilledRect(screen, x, bottom-update-draw, 1, draw, debugGraphDraw, false)
+		if f.GC {
+			vector.DrawFilledRect(screen, x, y0, 1, 4, debugGraphGC, false)
+		}
+	}
+	avg := stats.Average()
+	var pause time.Duration
+	for i := stats.Len() - 1; i >= 0; i-- {
+		if f := stats.Frame(i); f.GC {
+			pause = f.GCPause
+			break
+		}
+	}
+	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("update %.2fms  draw %.2fms\nheap %.1fMB  gc %d  pause %.2fms",
+		avg.Update.Seconds()*1000, avg.Draw.Seconds()*1000, float64(avg.Heap)/(1<<20),
+		stats.NumGC(), pause.Seconds()*1000), int(x0), int(bottom)+2)
+}
+
+// DescribeEntity formats the components of e registered with
+// RegisterSnapshotComponent, one field per line.
+func DescribeEntity(w *teishoku.World, e teishoku.Entity) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "entity %s", entityString(e))
+	if label := teishoku.GetComponent[DebugLabelComponent](w, e); label != nil {
+		fmt.Fprintf(&sb, " %q", label.Name)
+	}
+	entities := make(map[teishoku.Entity]map[string]componentFields)
+	for _, c := range snapshotComponents {
+		c.capture(w, entities)
+	}
+	components := entities[e]
+	names := make([]string, 0, len(components))
+	for name := range components {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		fmt.Fprintf(&sb, "\n  %s", name)
+		fields := components[name]
+		keys := make([]string, 0, len(fields))
+		for key := range fields {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			fmt.Fprintf(&sb, "\n    %s: %s", key, fields[key])
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,243 @@
+package katsu2d
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/color"
+	"math"
+	"os"
+	"slices"
+
+	"github.com/edwinsyarief/katsu2d/logger"
+	"github.com/edwinsyarief/teishoku"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// PolygonToolSystem is a debug tool for authoring trigger zones against the
+// running game. While enabled, left clicks place vertices in world space,
+// right click or Backspace removes the last one, and Enter or a click on
+// the first vertex closes the polygon into a new TriggerZoneComponent
+// entity. Escape drops the polygon in progress and Ctrl+S adds the created
+// zones to the scene file at Path. Add it to a scene as both an update and
+// a draw system.
+type PolygonToolSystem struct {
+	// Enabled turns the tool on; ToggleKey flips it.
+	Enabled   bool
+	ToggleKey ebiten.Key
+	// Grid snaps vertices to multiples of its size; 0 disables snapping.
+	Grid float64
+	// Path is the scene file Export adds the zones to.
+	Path string
+	// NamePrefix names created zones, followed by a number.
+	NamePrefix string
+	// OnCreate, when set, runs for each created zone, e.g. to add more
+	// components to it.
+	OnCreate func(w *teishoku.World, e teishoku.Entity)
+	Color    color.RGBA
+
+	points  []Vector
+	cursor  Vector
+	created int
+	zones   []teishoku.Entity
+	// exported holds the entities written to Path by the previous Export,
+	// which the next one replaces.
+	exported map[teishoku.Entity]struct{}
+}
+
+// NewPolygonToolSystem creates a PolygonToolSystem toggled with F4 that
+// exports to path.
+func NewPolygonToolSystem(path string) *PolygonToolSystem {
+	return &PolygonToolSystem{
+		ToggleKey:  ebiten.KeyF4,
+		Path:       path,
+		NamePrefix: "trigger",
+		Color:      color.RGBA{0, 255, 200, 255},
+	}
+}
+
+// Points returns the vertices of the polygon in progress.
+func (self *PolygonToolSystem) Points() []Vector {
+	return self.points
+}
+
+// polygonCloseRadius is how near the first vertex, in screen pixels, a
+// click closes the polygon.
+const polygonCloseRadius = 6
+
+func (self *PolygonToolSystem) Initialize(w *teishoku.World) {}
+
+func (self *PolygonToolSystem) Update(w *teishoku.World, dt float64) {
+	if keyJustPressed(self.ToggleKey) {
+		self.Enabled = !self.Enabled
+	}
+	if !self.Enabled {
+		return
+	}
+	cx, cy := ebiten.CursorPosition()
+	self.cursor = V(float64(cx), float64(cy))
+	closeRadius := float64(polygonCloseRadius)
+	if cam := GetCamera(w); cam != nil {
+		self.cursor = cam.ScreenToWorld(self.cursor)
+		closeRadius /= cam.RenderZoom()
+	}
+	if self.Grid > 0 {
+		self.cursor = V(math.Round(self.cursor.X/self.Grid)*self.Grid, math.Round(self.cursor.Y/self.Grid)*self.Grid)
+	}
+
+	ctrl := ebiten.IsKeyPressed(ebiten.KeyControl) || ebiten.IsKeyPressed(ebiten.KeyMeta)
+	switch {
+	case ctrl && keyJustPressed(ebiten.KeyS):
+		if err := self.Export(w); err != nil {
+			logger.GetLogger().Error("failed to export trigger zones: %v", err)
+		}
+	case keyJustPressed(ebiten.KeyEscape):
+		self.points = self.points[:0]
+	case keyJustPressed(ebiten.KeyEnter):
+		self.Close(w)
+	case keyJustPressed(ebiten.KeyBackspace), mouseButtonJustPressed(ebiten.MouseButtonRight):
+		if len(self.points) > 0 {
+			self.points = self.points[:len(self.points)-1]
+		}
+	case mouseButtonJustPressed(ebiten.MouseButtonLeft):
+		if len(self.points) >= 3 && self.cursor.DistanceTo(self.points[0]) <= closeRadius {
+			self.Close(w)
+			return
+		}
+		self.points = append(self.points, self.cursor)
+	}
+}
+
+// Close turns the polygon in progress into a trigger zone entity placed at
+// its center, and returns it. It does nothing with fewer than three
+// vertices.
+func (self *PolygonToolSystem) Close(w *teishoku.World) (teishoku.Entity, bool) {
+	if len(self.points) < 3 {
+		return teishoku.Entity{}, false
+	}
+	center := Vector{}
+	for _, p := range self.points {
+		center = center.Add(p)
+	}
+	center = center.DivF(float64(len(self.points)))
+	points := make([]Vector, len(self.points))
+	for i, p := range self.points {
+		points[i] = p.Sub(center)
+	}
+	self.points = self.points[:0]
+	self.created++
+	name := fmt.Sprintf("%s%d", self.NamePrefix, self.created)
+
+	e := TrackEntity(w, w.CreateEntity())
+	teishoku.SetComponent2(w, e,
+		TransformComponent{Position: Point(center), Scale: Point(V(1, 1))},
+		NewTriggerZoneComponent(name, points...))
+	logger.GetLogger().Info("created trigger zone %q at %.1f,%.1f with %d points", name, center.X, center.Y, len(points))
+	if self.OnCreate != nil {
+		self.OnCreate(w, e)
+	}
+	self.zones = append(self.zones, e)
+	return e, true
+}
+
+// Export adds the zones created so far to the scene file at Path, creating
+// it if needed. The rest of the file is kept as authored, and zones written
+// by an earlier Export are replaced. Entity references held by the zones'
+// components are not rewritten.
+func (self *PolygonToolSystem) Export(w *teishoku.World) error {
+	if self.Path == "" {
+		return fmt.Errorf("katsu2d: polygon tool has no export path")
+	}
+	doc := WorldDocument{Version: worldFormatVersion, VersionStamp: CurrentVersion()}
+	data, err := os.ReadFile(self.Path)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("katsu2d: decoding %s: %w", self.Path, err)
+		}
+		if doc.Version != worldFormatVersion {
+			return fmt.Errorf("katsu2d: unsupported world format version %d in %s", doc.Version, self.Path)
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return err
+	}
+
+	self.zones = slices.DeleteFunc(self.zones, func(e teishoku.Entity) bool { return !IsAlive(w, e) })
+	zones, err := serializeEntities(w, self.zones)
+	if err != nil {
+		return err
+	}
+	doc.Entities = slices.DeleteFunc(doc.Entities, func(ed EntityDocument) bool {
+		_, ok := self.exported[ed.Entity]
+		return ok
+	})
+	// Saved handles only need to be unique within the file.
+	next := uint32(0)
+	for _, ed := range doc.Entities {
+		next = max(next, ed.Entity.ID+1)
+	}
+	if self.exported == nil {
+		self.exported = make(map[teishoku.Entity]struct{})
+	}
+	clear(self.exported)
+	for i := range zones {
+		zones[i].Entity = teishoku.Entity{ID: next + uint32(i), Version: 1}
+		self.exported[zones[i].Entity] = struct{}{}
+	}
+	doc.Entities = append(doc.Entities, zones...)
+
+	if data, err = json.Marshal(doc); err != nil {
+		return err
+	}
+	if err := os.WriteFile(self.Path, data, 0o644); err != nil {
+		return err
+	}
+	logger.GetLogger().Info("exported %d trigger zones to %s", len(zones), self.Path)
+	return nil
+}
+
+func (self *PolygonToolSystem) Draw(w *teishoku.World, rdr *BatchRenderer) {
+	if !self.Enabled {
+		return
+	}
+	rdr.Flush()
+	screen := rdr.GetScreen()
+	view, _ := rdr.View()
+	if rdr.ScreenSpace() {
+		view = Matrix{}
+	}
+	toScreen := func(p Vector) (float32, float32) {
+		x, y := view.Apply(p.X, p.Y)
+		return float32(x), float32(y)
+	}
+
+	filter := teishoku.NewFilter2[TransformComponent, TriggerZoneComponent](w)
+	for filter.Next() {
+		t, zone := filter.Get()
+		position := Vector(t.Position)
+		for i := range zone.Points {
+			x0, y0 := toScreen(position.Add(zone.Points[i]))
+			x1, y1 := toScreen(position.Add(zone.Points[(i+1)%len(zone.Points)]))
+			vector.StrokeLine(screen, x0, y0, x1, y1, 1, self.Color, false)
+		}
+		x, y := toScreen(position)
+		ebitenutil.DebugPrintAt(screen, zone.Name, int(x)-len(zone.Name)*3, int(y)-8)
+	}
+
+	for i, p := range self.points {
+		x0, y0 := toScreen(p)
+		next := self.cursor
+		if i+1 < len(self.points) {
+			next = self.points[i+1]
+		}
+		x1, y1 := toScreen(next)
+		vector.StrokeLine(screen, x0, y0, x1, y1, 1, self.Color, false)
+		vector.DrawFilledRect(screen, x0-2, y0-2, 4, 4, self.Color, false)
+	}
+	x, y := toScreen(self.cursor)
+	vector.StrokeCircle(screen, x, y, 3, 1, self.Color, false)
+	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("%.0f,%.0f  click: add  right: undo  enter: close  ctrl+s: export", self.cursor.X, self.cursor.Y),
+		8, screen.Bounds().Dy()-16)
+}
@@ -0,0 +1,108 @@
+package katsu2d
+
+import "github.com/edwinsyarief/teishoku"
+
+// ElevationSystem moves entities between levels as they cross ramps and,
+// with an ElevationMap, keeps them on their level: an entity stepping onto
+// a cell of another level outside a ramp is moved back, sliding along the
+// cliff edge where it can. Add it after the systems moving entities and
+// before the CollisionSystem.
+type ElevationSystem struct {
+	entities *teishoku.Filter2[TransformComponent, ElevationComponent]
+	ramps    *teishoku.Filter[RampComponent]
+	regions  []RampComponent
+	// last holds the last position of each entity that was on its level.
+	last map[teishoku.Entity]Vector
+}
+
+// NewElevationSystem creates a new ElevationSystem.
+func NewElevationSystem() *ElevationSystem {
+	return &ElevationSystem{last: make(map[teishoku.Entity]Vector)}
+}
+
+func (self *ElevationSystem) Initialize(w *teishoku.World) {
+	self.entities = self.entities.New(w)
+	self.ramps = self.ramps.New(w)
+}
+
+func (self *ElevationSystem) Update(w *teishoku.World, dt float64) {
+	self.regions = self.regions[:0]
+	self.ramps.Reset()
+	for self.ramps.Next() {
+		self.regions = append(self.regions, *self.ramps.Get())
+	}
+	levels := GetElevationMap(w)
+	if len(self.regions) == 0 && levels == nil {
+		return
+	}
+
+	count := 0
+	self.entities.Reset()
+	for self.entities.Next() {
+		count++
+		e := self.entities.Entity()
+		t, el := self.entities.Get()
+		pos := Vector(t.Position)
+		onRamp := false
+		for _, ramp := range self.regions {
+			if !ramp.Region.Contains(pos) || (el.Level != ramp.From && el.Level != ramp.To) {
+				continue
+			}
+			onRamp = true
+			level := ramp.From
+			if rampProgress(ramp, pos) >= 0.5 {
+				level = ramp.To
+			}
+			if level != el.Level {
+				old := el.Level
+				el.Level = level
+				Publish(w, ElevationChangedEvent{Entity: e, From: old, To: level})
+			}
+			break
+		}
+		if levels == nil || onRamp || levels.IsWalkable(pos, el.Level) {
+			self.last[e] = pos
+			continue
+		}
+		if last, ok := self.last[e]; ok {
+			t.Position = Point(keepOnLevel(levels, el.Level, last, pos))
+		}
+	}
+	// Forget entities that were removed or lost their elevation.
+	if len(self.last) > count {
+		for e := range self.last {
+			if !IsAlive(w, e) || teishoku.GetComponent[ElevationComponent](w, e) == nil {
+				delete(self.last, e)
+			}
+		}
+	}
+}
+
+// keepOnLevel returns where an entity moving from last to pos may stand on
+// level: pos with one axis of the move undone if that is walkable, so it
+// slides along the cliff edge, or last otherwise.
+func keepOnLevel(levels *ElevationMap, level int, last, pos Vector) Vector {
+	if p := V(pos.X, last.Y); levels.IsWalkable(p, level) {
+		return p
+	}
+	if p := V(last.X, pos.Y); levels.IsWalkable(p, level) {
+		return p
+	}
+	return last
+}
+
+// rampProgress returns how far pos is across the ramp along its direction,
+// from 0 on the From side to 1 on the To side.
+func rampProgress(ramp RampComponent, pos Vector) float64 {
+	dir := ramp.Direction.Normalize()
+	if dir.IsZero() {
+		dir = V(0, -1)
+	}
+	center := ramp.Region.Center()
+	half := V(ramp.Region.Width()/2, ramp.Region.Height()/2)
+	extent := Abs(dir.X)*half.X + Abs(dir.Y)*half.Y
+	if extent == 0 {
+		return 0
+	}
+	return Clamp((pos.Sub(center).Dot(dir)/extent+1)/2, 0, 1)
+}
@@ -1,74 +1,209 @@
 package katsu2d
 
 import (
+	"math"
+
 	"github.com/edwinsyarief/teishoku"
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
+// InputSystem resolves bindings into action states. Action maps pushed onto
+// it are evaluated from the top of the stack down, before each component's
+// own bindings; an input claimed by a higher map is consumed and no longer
+// seen by the maps below. Entities whose controls are locked, see
+// ControlLocked, read no input until the lock ends.
 type InputSystem struct {
-	filter *teishoku.Filter[InputComponent]
+	filter   *teishoku.Filter[InputComponent]
+	maps     []*ActionMap
+	consumed map[InputCode]bool
+	claimed  []InputCode
+
+	cursor, lastCursor Vector
+	cursorTracked      bool
 }
 
 func NewInputSystem() *InputSystem {
-	return &InputSystem{}
+	return &InputSystem{
+		consumed: make(map[InputCode]bool),
+	}
 }
 
 func (self *InputSystem) Initialize(w *teishoku.World) {
 	self.filter = self.filter.New(w)
 }
 
+// PushMap makes an action map the top-most input context.
+func (self *InputSystem) PushMap(m *ActionMap) {
+	self.maps = append(self.maps, m)
+}
+
+// PopMap removes and returns the top-most action map, or nil if none.
+func (self *InputSystem) PopMap() *ActionMap {
+	if len(self.maps) == 0 {
+		return nil
+	}
+	m := self.maps[len(self.maps)-1]
+	self.maps[len(self.maps)-1] = nil
+	self.maps = self.maps[:len(self.maps)-1]
+	return m
+}
+
+// TopMap returns the top-most action map, or nil if none.
+func (self *InputSystem) TopMap() *ActionMap {
+	if len(self.maps) == 0 {
+		return nil
+	}
+	return self.maps[len(self.maps)-1]
+}
+
+// Maps returns the action map stack, bottom first.
+func (self *InputSystem) Maps() []*ActionMap {
+	return self.maps
+}
+
 func (self *InputSystem) Update(w *teishoku.World, dt float64) {
+	touchInput.update(dt)
 	// Get mouse wheel deltas once per frame
 	wx, wy := ebiten.Wheel()
+	// Mouse motion axes read the cursor movement since the last update.
+	cx, cy := ebiten.CursorPosition()
+	self.lastCursor, self.cursor = self.cursor, V(float64(cx), float64(cy))
+	if !self.cursorTracked {
+		self.lastCursor, self.cursorTracked = self.cursor, true
+	}
 
 	self.filter.Reset()
 	for self.filter.Next() {
 		inp := self.filter.Get()
 
-		// Reset states for the current frame
-		for action := range inp.Bindings {
-			inp.JustPressed[action] = false
-			inp.JustReleased[action] = false
+		// Reset states for the current frame. Actions of popped maps must
+		// not linger, so the whole state is cleared.
+		clear(inp.JustPressed)
+		clear(inp.Pressed)
+		clear(inp.JustReleased)
+		if inp.activeCodes == nil {
+			inp.activeCodes = make(map[Action][]InputCode)
+		}
+		clear(inp.activeCodes)
+		if inp.axisValues == nil {
+			inp.axisValues = make(map[Action]float64)
+		}
+		clear(inp.axisValues)
+		clear(self.consumed)
+		if ControlLocked(w, self.filter.Entity()) {
+			inp.MouseWheelX, inp.MouseWheelY = 0, 0
+			continue
 		}
 
 		// set the mouse wheel deltas
 		inp.MouseWheelX = wx
 		inp.MouseWheelY = wy
 
-		// Now, check for the current state for all bindings.
-		for action := range inp.Bindings {
-			// A single action can be triggered by multiple bindings (e.g., keyboard and gamepad)
-			// We use a logical OR to ensure that if any binding is met, the action is triggered.
-			isAnyJustPressed := false
-			isAnyPressed := false
-			isAnyJustReleased := false
-
-			for _, binding := range inp.Bindings[action] {
-				modsDown := true
-				for _, mod := range binding.Modifiers {
-					if !isPressed(inp.ID, mod) {
-						modsDown = false
-						break
-					}
-				}
+		blocked := false
+		for i := len(self.maps) - 1; i >= 0; i-- {
+			self.resolve(inp, self.maps[i].Bindings, self.maps[i].Axes)
+			if self.maps[i].Blocking {
+				blocked = true
+				break
+			}
+		}
+		if !blocked {
+			self.resolve(inp, inp.Bindings, inp.Axes)
+		}
+	}
+}
+
+// resolve evaluates one layer of bindings, skipping consumed inputs, then
+// consumes the inputs this layer used.
+func (self *InputSystem) resolve(inp *InputComponent, bindings map[Action][]KeyConfig, axes map[Action][]AxisBinding) {
+	self.claimed = self.claimed[:0]
+	for action, configs := range bindings {
+		// A single action can be triggered by multiple bindings (e.g., keyboard and gamepad)
+		// We use a logical OR to ensure that if any binding is met, the action is triggered.
+		isAnyJustPressed := inp.JustPressed[action]
+		isAnyPressed := inp.Pressed[action]
+		isAnyJustReleased := inp.JustReleased[action]
 
-				if modsDown {
-					if isJustPressed(inp.ID, binding.Primary) {
-						isAnyJustPressed = true
-					}
-					if isPressed(inp.ID, binding.Primary) {
-						isAnyPressed = true
-					}
-					if isJustReleased(inp.ID, binding.Primary) {
-						isAnyJustReleased = true
-					}
+		for _, binding := range configs {
+			if self.consumed[binding.Primary] {
+				continue
+			}
+			modsDown := true
+			for _, mod := range binding.Modifiers {
+				if !isPressed(inp.ID, mod) {
+					modsDown = false
+					break
 				}
 			}
+			if !modsDown {
+				continue
+			}
+			justPressed := isJustPressed(inp.ID, binding.Primary)
+			pressed := isPressed(inp.ID, binding.Primary)
+			justReleased := isJustReleased(inp.ID, binding.Primary)
+			if justPressed || pressed || justReleased {
+				inp.activeCodes[action] = append(inp.activeCodes[action], binding.Primary)
+				self.claimed = append(self.claimed, binding.Primary)
+			}
+			isAnyJustPressed = isAnyJustPressed || justPressed
+			isAnyPressed = isAnyPressed || pressed
+			isAnyJustReleased = isAnyJustReleased || justReleased
+		}
+
+		// Update the component's state based on the calculated values
+		inp.JustPressed[action] = isAnyJustPressed
+		inp.JustReleased[action] = isAnyJustReleased
+		inp.Pressed[action] = isAnyPressed
+	}
+	self.resolveAxes(inp, axes)
+	for _, code := range self.claimed {
+		self.consumed[code] = true
+	}
+}
+
+// resolveAxes evaluates one layer of axis bindings. The binding with the
+// largest magnitude wins, so a stick and keys can drive the same action.
+func (self *InputSystem) resolveAxes(inp *InputComponent, axes map[Action][]AxisBinding) {
+	delta := self.cursor.Sub(self.lastCursor)
+	for action, bindings := range axes {
+		value := inp.axisValues[action]
+		for _, binding := range bindings {
+			if self.consumed[binding.Positive] || (binding.Virtual && self.consumed[binding.Negative]) {
+				continue
+			}
+			v := self.axisValue(inp, binding, delta)
+			if v == 0 {
+				continue
+			}
+			inp.activeCodes[action] = append(inp.activeCodes[action], binding.Positive)
+			self.claimed = append(self.claimed, binding.Positive)
+			if binding.Virtual {
+				inp.activeCodes[action] = append(inp.activeCodes[action], binding.Negative)
+				self.claimed = append(self.claimed, binding.Negative)
+			}
+			if math.Abs(v) > math.Abs(value) {
+				value = v
+			}
+		}
+		inp.axisValues[action] = value
+	}
+}
 
-			// Update the component's state based on the calculated values
-			inp.JustPressed[action] = isAnyJustPressed
-			inp.JustReleased[action] = isAnyJustReleased
-			inp.Pressed[action] = isAnyPressed
+func (self *InputSystem) axisValue(inp *InputComponent, binding AxisBinding, mouseDelta Vector) float64 {
+	v := inputValue(inp.ID, binding.Positive, mouseDelta)
+	if binding.Virtual {
+		v -= inputValue(inp.ID, binding.Negative, mouseDelta)
+	}
+	switch binding.Positive.Type {
+	case InputTypeAnalog, InputTypeGamepad:
+		deadZone := binding.DeadZone
+		if deadZone == 0 {
+			deadZone = inp.AxisDeadZone
 		}
+		v = applyDeadZone(v, deadZone)
+	}
+	if binding.Scale != 0 {
+		v *= binding.Scale
 	}
+	return v
 }
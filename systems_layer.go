@@ -36,6 +36,15 @@ func PixelPerfect(pixelPerfect bool) LayerOption {
 	}
 }
 
+// UseCamera renders the layer through cam, sized to the layer's virtual
+// resolution. With the layer's PixelPerfect option the camera should use
+// PixelPerfect too, so positions snap to whole layer pixels.
+func UseCamera(cam *Camera) LayerOption {
+	return func(ls *LayerSytem) {
+		ls.camera = cam
+	}
+}
+
 // LayerSytem manages the rendering of multiple drawing systems
 // onto a single buffer layer. It handles scaling and positioning of the final output.
 type LayerSytem struct {
@@ -44,17 +53,23 @@ type LayerSytem struct {
 	canvas                               *canvas
 	drawSystems                          []DrawSystem   // Collection of drawing systems to be executed
 	updateSystems                        []UpdateSystem // Collection of update systems to be executed
+	drawCommands                         DrawCommandList
+	camera                               *Camera
+	scrollBuffer                         *ebiten.Image // Buffer with a one pixel margin for subpixel scrolling
+	width, height                        int
 	stretched, pixelPerfect, initialized bool
 }
 
 // NewLayerSystem creates a new layer renderer with specified dimensions
 // and optional configuration through LayerRendererOptions
 func NewLayerSystem(width, height int, opts ...LayerOption) *LayerSytem {
-	buffer := ebiten.NewImage(width, height)
+	buffer := newRenderTarget(width, height, "layer")
 	ls := &LayerSytem{
 		batchRenderer: NewBatchRenderer(),
 		buffer:        buffer,
 		drawSystems:   make([]DrawSystem, 0),
+		width:         width,
+		height:        height,
 	}
 
 	// Apply all provided options
@@ -97,20 +112,52 @@ func (self *LayerSytem) Update(w *teishoku.World, dt float64) {
 // Draw executes all registered render systems and handles scaling of the final output.
 // It maintains aspect ratio while scaling and centers the result on the screen.
 func (self *LayerSytem) Draw(w *teishoku.World, rdr *BatchRenderer) {
+	buffer := self.buffer
+	subpixel := self.camera != nil && self.camera.PixelPerfect && self.camera.SubpixelScroll
+	if subpixel {
+		if self.scrollBuffer == nil {
+			self.scrollBuffer = newRenderTarget(self.width+2, self.height+2, "layer scroll")
+		}
+		buffer = self.scrollBuffer
+	}
+
 	// Clear the buffer with transparency
-	self.buffer.Fill(color.Transparent)
+	buffer.Fill(color.Transparent)
 
 	// Begin batch rendering to the buffer
-	self.batchRenderer.Begin(self.buffer)
-
-	// Execute all registered drawing systems
-	for _, ds := range self.drawSystems {
-		ds.Draw(w, self.batchRenderer)
+	self.batchRenderer.Begin(buffer)
+	if self.camera != nil {
+		self.camera.Width, self.camera.Height = self.width, self.height
+		view := self.camera.Matrix()
+		if subpixel {
+			view.Translate(1, 1)
+		}
+		self.batchRenderer.SetView(view)
 	}
 
+	// Execute all registered drawing systems, interleaving z-keyed ones
+	drawSystemsInOrder(w, self.batchRenderer, self.drawSystems, &self.drawCommands)
+
 	// Ensure all batched operations are executed
 	self.batchRenderer.Flush()
+	self.batchRenderer.ResetView()
 	rdr.Flush()
 
-	self.canvas.Draw(self.buffer, rdr.screen)
+	if subpixel {
+		// Shift the upscaled image by the fraction the camera snapped away.
+		min := V(1, 1).Add(self.camera.Subpixel())
+		self.canvas.DrawRegion(buffer, rdr.screen, min, min.Add(V(float64(self.width), float64(self.height))))
+		return
+	}
+	self.canvas.Draw(buffer, rdr.screen)
+}
+
+// CursorWorldPosition returns the cursor position in the layer's world
+// space, accounting for scaling and the layer camera.
+func (self *LayerSytem) CursorWorldPosition() Vector {
+	p := self.canvas.ScaleCursorPosition()
+	if self.camera == nil {
+		return p
+	}
+	return self.camera.ScreenToWorld(p)
 }
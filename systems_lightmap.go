@@ -0,0 +1,45 @@
+package katsu2d
+
+import (
+	"github.com/edwinsyarief/teishoku"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// multiplyBlend multiplies the destination by the source color.
+var multiplyBlend = ebiten.Blend{
+	BlendFactorSourceRGB:        ebiten.BlendFactorZero,
+	BlendFactorSourceAlpha:      ebiten.BlendFactorZero,
+	BlendFactorDestinationRGB:   ebiten.BlendFactorSourceColor,
+	BlendFactorDestinationAlpha: ebiten.BlendFactorOne,
+	BlendOperationRGB:           ebiten.BlendOperationAdd,
+	BlendOperationAlpha:         ebiten.BlendOperationAdd,
+}
+
+// LightmapSystem multiplies the world's baked Lightmap over everything drawn
+// before it. Add it after the map and sprite systems; it costs one draw call
+// per frame.
+type LightmapSystem struct {
+	op ebiten.DrawImageOptions
+}
+
+// NewLightmapSystem creates a new LightmapSystem.
+func NewLightmapSystem() *LightmapSystem {
+	return &LightmapSystem{}
+}
+
+func (self *LightmapSystem) Initialize(w *teishoku.World) {}
+
+func (self *LightmapSystem) Draw(w *teishoku.World, rdr *BatchRenderer) {
+	lm := GetLightmap(w)
+	if lm == nil || lm.Image() == nil {
+		return
+	}
+	rdr.Flush()
+	self.op = ebiten.DrawImageOptions{Filter: ebiten.FilterLinear, Blend: multiplyBlend}
+	self.op.GeoM.Scale(lm.CellSize, lm.CellSize)
+	self.op.GeoM.Translate(lm.Origin.X, lm.Origin.Y)
+	if view, ok := rdr.View(); ok {
+		self.op.GeoM.Concat(view)
+	}
+	rdr.GetScreen().DrawImage(lm.Image(), &self.op)
+}
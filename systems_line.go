@@ -0,0 +1,98 @@
+package katsu2d
+
+import (
+	"sort"
+
+	"github.com/edwinsyarief/teishoku"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// LineRenderSystem renders line components through the batch renderer,
+// ordered by their transform Z.
+type LineRenderSystem struct {
+	transform   *Transform
+	filter      *teishoku.Filter2[TransformComponent, LineComponent]
+	entities    []teishoku.Entity
+	vertices    []ebiten.Vertex
+	initialized bool
+}
+
+// NewLineRenderSystem creates a new LineRenderSystem.
+func NewLineRenderSystem() *LineRenderSystem {
+	return &LineRenderSystem{
+		transform: T(),
+		entities:  make([]teishoku.Entity, 0),
+	}
+}
+
+func (self *LineRenderSystem) Initialize(w *teishoku.World) {
+	if self.initialized {
+		return
+	}
+
+	self.filter = self.filter.New(w)
+	self.initialized = true
+}
+
+// Draw renders all line components in the world.
+func (self *LineRenderSystem) Draw(w *teishoku.World, rdr *BatchRenderer) {
+	self.entities = self.entities[:0]
+	for self.filter.Next() {
+		self.entities = append(self.entities, self.filter.Entity())
+	}
+	self.filter.Reset()
+	sort.SliceStable(self.entities, func(i, j int) bool {
+		t1 := teishoku.GetComponent[TransformComponent](w, self.entities[i])
+		t2 := teishoku.GetComponent[TransformComponent](w, self.entities[j])
+		return t1.Z < t2.Z
+	})
+
+	for _, e := range self.entities {
+		entityRenderSpace(w, e, rdr)
+		self.DrawEntity(w, e, rdr)
+	}
+	rdr.SetScreenSpace(false)
+}
+
+// SubmitDraws queues every line as a z-keyed draw command.
+func (self *LineRenderSystem) SubmitDraws(w *teishoku.World, list *DrawCommandList) {
+	for self.filter.Next() {
+		t, _ := self.filter.Get()
+		list.Submit(t.Z, self, self.filter.Entity())
+	}
+	self.filter.Reset()
+}
+
+// DrawEntity draws a single line entity.
+func (self *LineRenderSystem) DrawEntity(w *teishoku.World, e teishoku.Entity, rdr *BatchRenderer) {
+	t, l := teishoku.GetComponent2[TransformComponent, LineComponent](w, e)
+	if t == nil || l == nil || l.Line == nil {
+		return
+	}
+	l.Line.BuildMesh()
+	vertices, indices := l.Line.GetMesh()
+	if len(vertices) == 0 {
+		return
+	}
+
+	img := l.Line.Texture()
+	textured := img != nil
+	if !textured {
+		img = GetTextureManager(w).Get(0)
+	}
+
+	setRenderTransform(w, e, self.transform, t)
+	matrix := self.transform.Matrix()
+	self.vertices = self.vertices[:0]
+	for _, v := range vertices {
+		vx, vy := matrix.Apply(float64(v.DstX), float64(v.DstY))
+		v.DstX = float32(vx)
+		v.DstY = float32(vy)
+		if !textured {
+			v.SrcX = 0
+			v.SrcY = 0
+		}
+		self.vertices = append(self.vertices, v)
+	}
+	rdr.AddCustomMeshes(self.vertices, indices, img)
+}
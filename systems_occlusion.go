@@ -0,0 +1,30 @@
+package katsu2d
+
+import "github.com/edwinsyarief/teishoku"
+
+// OcclusionSystem selects the region of the world's OcclusionSet that holds
+// the main camera, for game logic that reads Region. Add it to the
+// LateUpdateGroup after the CameraSystem. Drawing does not depend on it:
+// the engine selects the region of each camera before drawing through it,
+// so split-screen views cull for their own camera.
+type OcclusionSystem struct{}
+
+// NewOcclusionSystem creates a new OcclusionSystem.
+func NewOcclusionSystem() *OcclusionSystem {
+	return &OcclusionSystem{}
+}
+
+func (self *OcclusionSystem) Initialize(w *teishoku.World) {}
+
+func (self *OcclusionSystem) Update(w *teishoku.World, dt float64) {
+	if cam := GetCamera(w); cam != nil {
+		selectOcclusionRegion(w, cam)
+	}
+}
+
+// selectOcclusionRegion selects the region of w's OcclusionSet holding cam.
+func selectOcclusionRegion(w *teishoku.World, cam *Camera) {
+	if set := GetOcclusionSet(w); set != nil {
+		set.SetRegion(set.RegionAt(cam.Position))
+	}
+}
@@ -0,0 +1,43 @@
+package katsu2d
+
+import "github.com/edwinsyarief/teishoku"
+
+// OrderableSystem recomputes the index of dynamic OrderableComponents and
+// publishes OrderChangedEvent when any of them moved, so the ordered sprite
+// system re-sorts. Add it after movement systems.
+type OrderableSystem struct {
+	filter *teishoku.Filter2[TransformComponent, OrderableComponent]
+}
+
+// NewOrderableSystem creates a new OrderableSystem.
+func NewOrderableSystem() *OrderableSystem {
+	return &OrderableSystem{}
+}
+
+func (self *OrderableSystem) Initialize(w *teishoku.World) {
+	self.filter = self.filter.New(w)
+}
+
+func (self *OrderableSystem) Update(w *teishoku.World, dt float64) {
+	changed := false
+	self.filter.Reset()
+	for self.filter.Next() {
+		t, o := self.filter.Get()
+		if !o.Dynamic() {
+			continue
+		}
+		var index float64
+		if o.Provider != nil {
+			index = o.Provider(w, self.filter.Entity())
+		} else {
+			index = t.Position.Y + o.YOffset
+		}
+		if index != o.Index {
+			o.Index = index
+			changed = true
+		}
+	}
+	if changed {
+		Publish(w, OrderChangedEvent{})
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"sort"
 
 	"github.com/edwinsyarief/teishoku"
-	"github.com/hajimehoshi/ebiten/v2"
 )
 
 type OrderedSpriteSystem struct {
@@ -12,6 +11,7 @@ type OrderedSpriteSystem struct {
 	filter                   *teishoku.Filter3[TransformComponent, SpriteComponent, OrderableComponent]
 	lastFrameEntities        map[teishoku.Entity]struct{}
 	entities                 []teishoku.Entity
+	current                  []teishoku.Entity
 	zSortNeeded, initialized bool
 }
 
@@ -28,14 +28,21 @@ func (self *OrderedSpriteSystem) Initialize(w *teishoku.World) {
 	}
 
 	self.filter = self.filter.New(w)
+	Subscribe(w, func(ElevationChangedEvent) { self.zSortNeeded = true })
+	Subscribe(w, func(OrderChangedEvent) { self.zSortNeeded = true })
 	self.initialized = true
 }
 func (self *OrderedSpriteSystem) Update(w *teishoku.World, dt float64) {
-	currentEntities := make([]teishoku.Entity, 0)
+	currentEntities := self.current[:0]
 	self.filter.Reset()
 	for self.filter.Next() {
-		currentEntities = append(currentEntities, self.filter.Entity())
+		entity := self.filter.Entity()
+		if teishoku.GetComponent[StaticComponent](w, entity) != nil {
+			continue
+		}
+		currentEntities = append(currentEntities, entity)
 	}
+	self.current = currentEntities
 
 	zSortNeeded := self.zSortNeeded || len(currentEntities) != len(self.lastFrameEntities)
 	if !zSortNeeded && len(currentEntities) > 0 {
@@ -48,7 +55,7 @@ func (self *OrderedSpriteSystem) Update(w *teishoku.World, dt float64) {
 	}
 
 	if zSortNeeded {
-		self.entities = currentEntities
+		self.entities = append(self.entities[:0], currentEntities...)
 		sort.SliceStable(self.entities, func(i, j int) bool {
 			t1 := teishoku.GetComponent[TransformComponent](w, self.entities[i])
 			t2 := teishoku.GetComponent[TransformComponent](w, self.entities[j])
@@ -56,6 +63,11 @@ func (self *OrderedSpriteSystem) Update(w *teishoku.World, dt float64) {
 				return t1.Z < t2.Z
 			}
 
+			// Higher elevation levels draw above lower ones.
+			if l1, l2 := ElevationOf(w, self.entities[i]), ElevationOf(w, self.entities[j]); l1 != l2 {
+				return l1 < l2
+			}
+
 			o1 := teishoku.GetComponent[OrderableComponent](w, self.entities[i])
 			o2 := teishoku.GetComponent[OrderableComponent](w, self.entities[j])
 			index1 := o1.Index
@@ -69,21 +81,40 @@ func (self *OrderedSpriteSystem) Update(w *teishoku.World, dt float64) {
 		self.zSortNeeded = false
 	}
 
-	self.lastFrameEntities = make(map[teishoku.Entity]struct{}, len(currentEntities))
+	clear(self.lastFrameEntities)
 	for _, entity := range currentEntities {
 		self.lastFrameEntities[entity] = struct{}{}
 	}
 }
 func (self *OrderedSpriteSystem) Draw(w *teishoku.World, rdr *BatchRenderer) {
 	tm := GetTextureManager(w)
+	occlusion := GetOcclusionSet(w)
 	for _, e := range self.entities {
+		if !occlusion.EntityVisible(w, e) {
+			continue
+		}
+		entityRenderSpace(w, e, rdr)
 		t, s := teishoku.GetComponent2[TransformComponent, SpriteComponent](w, e)
-		self.transform.SetFromComponent(t)
+		setRenderTransform(w, e, self.transform, t)
+		if lift := elevationLift(w, e); lift != 0 {
+			self.transform.SetPosition(self.transform.Position().Sub(V(0, lift)))
+		}
 		img := tm.Get(s.TextureID)
 		if img == nil {
 			continue
 		}
 
+		if s.Origin != OriginNone {
+			if s.Width == 0 && s.Height == 0 {
+				if IsBoundEmpty(s.Bound) {
+					s.Width, s.Height = img.Bounds().Dx(), img.Bounds().Dy()
+				} else {
+					s.SyncSizeToBound()
+				}
+			}
+			self.transform.SetOffset(self.transform.Offset().Add(s.OriginOffset()))
+		}
+
 		if IsBoundEmpty(s.Bound) {
 			s.Bound = Bound{
 				Min: Point{X: 0, Y: 0},
@@ -94,7 +125,7 @@ func (self *OrderedSpriteSystem) Draw(w *teishoku.World, rdr *BatchRenderer) {
 		if m := teishoku.GetComponent[MeshComponent](w, e); m != nil {
 			GenerateMesh(m, s)
 
-			worldVertices := make([]ebiten.Vertex, len(m.Vertices))
+			worldVertices := rdr.Scratch(len(m.Vertices))
 			matrix := self.transform.Matrix()
 			for i, v := range m.Vertices {
 				v.ColorR = float32(s.Color.R) / 255
@@ -107,6 +138,13 @@ func (self *OrderedSpriteSystem) Draw(w *teishoku.World, rdr *BatchRenderer) {
 				worldVertices[i] = v
 			}
 			rdr.AddCustomMeshes(worldVertices, m.Indices, img)
+		} else if t.HasSkewOrPivot() {
+			col := s.Color
+			col.A = uint8((float64(col.A) / 255.0) * s.Opacity)
+			rdr.AddQuadMatrix(self.transform.Matrix(), img, col,
+				float32(s.Bound.Min.X), float32(s.Bound.Min.Y),
+				float32(s.Bound.Max.X), float32(s.Bound.Max.Y),
+				float64(s.Width), float64(s.Height))
 		} else {
 			col := s.Color
 			col.A = uint8((float64(col.A) / 255.0) * s.Opacity)
@@ -120,4 +158,5 @@ func (self *OrderedSpriteSystem) Draw(w *teishoku.World, rdr *BatchRenderer) {
 				float64(s.Width), float64(s.Height))
 		}
 	}
+	rdr.SetScreenSpace(false)
 }
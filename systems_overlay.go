@@ -47,7 +47,7 @@ func (self *FadeOverlaySystem) Update(w *teishoku.World, dt float64) {
 		fade.CurrentFade, fade.Finished = tween.Current, tween.Finished
 	}
 	for _, e := range self.toRemove {
-		w.RemoveEntity(e)
+		DestroyEntity(w, e)
 	}
 }
 
@@ -126,7 +126,7 @@ func (self *CinematicOverlaySystem) Initialize(w *teishoku.World) {
 }
 
 func (self *CinematicOverlaySystem) onEngineLayoutChanged(data EngineLayoutChangedEvent) {
-	self.target = ebiten.NewImage(data.Width, data.Height)
+	self.target, _ = resizeRenderTarget(self.target, data.Width, data.Height, "cinematic overlay")
 }
 
 func (self *CinematicOverlaySystem) onTimerFinished(obj TimerFinishedEvent) {
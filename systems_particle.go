@@ -0,0 +1,334 @@
+package katsu2d
+
+import (
+	"math"
+
+	"github.com/edwinsyarief/teishoku"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// ParticleSystem emits, simulates and draws particles. Particles are entities
+// carrying a TransformComponent and a ParticleComponent. It draws world space
+// particles only; register ScreenLayer to draw the screen space ones.
+type ParticleSystem struct {
+	world     *teishoku.World
+	screen    *ScreenParticleLayer
+	emitters  *teishoku.Filter2[TransformComponent, ParticleEmitterComponent]
+	particles *teishoku.Filter2[TransformComponent, ParticleComponent]
+	builder   *teishoku.Builder2[TransformComponent, ParticleComponent]
+	rng       *Rand
+	transform *Transform
+	counts    map[teishoku.Entity]int
+	meshes    map[teishoku.Entity]*particleMesh
+	dead      []teishoku.Entity
+	spawns    []particleSpawn
+}
+
+type particleSpawn struct {
+	transform TransformComponent
+	particle  ParticleComponent
+}
+
+// NewParticleSystem creates a new ParticleSystem.
+func NewParticleSystem() *ParticleSystem {
+	return &ParticleSystem{
+		rng:       Random(),
+		counts:    make(map[teishoku.Entity]int),
+		meshes:    make(map[teishoku.Entity]*particleMesh),
+		transform: T(),
+	}
+}
+
+func (self *ParticleSystem) Initialize(w *teishoku.World) {
+	self.world = w
+	self.emitters = self.emitters.New(w)
+	self.particles = self.particles.New(w)
+	self.builder = self.builder.New(w)
+}
+
+func (self *ParticleSystem) Update(w *teishoku.World, dt float64) {
+	counts := self.counts
+	clear(counts)
+	self.prepareMeshes(w)
+	self.particles.Reset()
+	for self.particles.Next() {
+		t, p := self.particles.Get()
+		e := self.particles.Entity()
+		pdt := ScaledDelta(w, e, dt)
+		p.Age += pdt
+		if p.Age >= p.Lifetime {
+			self.dead = append(self.dead, e)
+			continue
+		}
+		counts[p.Emitter]++
+		p.Velocity = p.Velocity.Add(p.Gravity.ScaleF(pdt))
+		if p.Damping > 0 {
+			p.Velocity = p.Velocity.ScaleF(math.Max(0, 1-p.Damping*pdt))
+		}
+		if p.Local {
+			if et := teishoku.GetComponent[TransformComponent](w, p.Emitter); et != nil {
+				p.LocalPosition = p.LocalPosition.Add(p.Velocity.ScaleF(pdt))
+				t.Position = Point(Vector(et.Position).Add(p.LocalPosition.Rotate(et.Rotation)))
+			} else {
+				// The emitter is gone; carry on in world space.
+				p.Local = false
+			}
+		}
+		if !p.Local {
+			t.Position = Point(Vector(t.Position).Add(p.Velocity.ScaleF(pdt)))
+		}
+		t.Rotation += p.RotationSpeed * pdt
+		progress := p.Progress()
+		scale := Lerp(p.InitialScale, p.TargetScale, progress)
+		t.Scale = Point{X: scale, Y: scale}
+		p.Color = p.ColorAt(progress)
+		if p.batched {
+			if mesh := self.meshes[p.Emitter]; mesh != nil {
+				mesh.add(t, p)
+			} else {
+				// The emitter is gone or no longer batched.
+				p.batched = false
+			}
+		}
+	}
+
+	self.emitters.Reset()
+	for self.emitters.Next() {
+		t, emitter := self.emitters.Get()
+		e := self.emitters.Entity()
+		emitter.alive = counts[e]
+		edt := ScaledDelta(w, e, dt)
+		position := Vector(t.Position)
+		previous := emitter.lastPosition
+		if emitter.tracked && edt > 0 {
+			emitter.velocity = position.Sub(previous).DivF(edt)
+		}
+		moved := emitter.tracked
+		emitter.lastPosition, emitter.tracked = position, true
+		if !emitter.Active {
+			emitter.emitAccumulator = 0
+			emitter.distAccumulator = 0
+			continue
+		}
+		var outline Rectangle
+		if emitter.Shape == EmitterSpriteEdge {
+			outline = spriteOutline(w, e, t, emitter)
+		}
+		count := emitter.BurstCount
+		emitter.BurstCount = 0
+		emitter.emitAccumulator += emitter.EmitRate * edt
+		count += int(emitter.emitAccumulator)
+		emitter.emitAccumulator -= math.Floor(emitter.emitAccumulator)
+		// Distance emission spaces particles along the path moved this
+		// update and ages them by the time since they passed each point.
+		var trail int
+		var travel, carry float64
+		if emitter.EmitPerDistance > 0 && moved {
+			travel = position.DistanceTo(previous) * emitter.EmitPerDistance
+			carry = emitter.distAccumulator
+			total := carry + travel
+			trail = int(total)
+			emitter.distAccumulator = total - math.Floor(total)
+		}
+		if emitter.MaxParticles > 0 {
+			room := max(emitter.MaxParticles-emitter.alive, 0)
+			count = min(count, room)
+			trail = min(trail, room-count)
+		}
+		for range count {
+			self.spawns = append(self.spawns, self.newParticle(e, t, emitter, position, 0, outline))
+		}
+		for k := 1; k <= trail; k++ {
+			f := Clamp((float64(k)-carry)/travel, 0, 1)
+			self.spawns = append(self.spawns, self.newParticle(e, t, emitter, previous.Lerp(position, f), (1-f)*edt, outline))
+		}
+		emitter.alive += max(count, 0) + trail
+	}
+
+	for _, e := range self.dead {
+		if w.IsValid(e) {
+			w.RemoveEntity(e)
+		}
+	}
+	self.dead = self.dead[:0]
+	for i := range self.spawns {
+		spawn := &self.spawns[i]
+		self.builder.Set(self.builder.NewEntity(), spawn.transform, spawn.particle)
+		if spawn.particle.batched {
+			self.meshes[spawn.particle.Emitter].add(&spawn.transform, &spawn.particle)
+		}
+	}
+	clear(self.spawns)
+	self.spawns = self.spawns[:0]
+}
+
+// newParticle spawns a particle at origin offset by the emitter shape,
+// already aged by age seconds.
+func (self *ParticleSystem) newParticle(e teishoku.Entity, t *TransformComponent, emitter *ParticleEmitterComponent, origin Vector, age float64, outline Rectangle) particleSpawn {
+	angle := emitter.Direction + t.Rotation + (self.rng.Float64()-0.5)*emitter.Spread
+	speed := self.rng.FloatRange(emitter.SpeedMin, emitter.SpeedMax)
+	scale := self.rng.FloatRange(emitter.ScaleMin, emitter.ScaleMax)
+	p := ParticleComponent{
+		Emitter:       e,
+		Velocity:      V(math.Cos(angle), math.Sin(angle)).ScaleF(speed),
+		Gravity:       emitter.Gravity,
+		Damping:       emitter.Damping,
+		Age:           age,
+		Lifetime:      self.rng.FloatRange(emitter.LifetimeMin, emitter.LifetimeMax),
+		RotationSpeed: self.rng.FloatRange(emitter.RotationSpeedMin, emitter.RotationSpeedMax),
+		InitialScale:  scale,
+		TargetScale:   scale * emitter.TargetScale,
+		InitialColor:  emitter.InitialColor,
+		TargetColor:   emitter.TargetColor,
+		Gradient:      emitter.ColorOverLifetime,
+		TextureID:     emitter.TextureID,
+		Bound:         emitter.Bound,
+		Width:         emitter.Width,
+		Height:        emitter.Height,
+		ScreenSpace:   emitter.ScreenSpace,
+		batched:       emitter.Batched,
+	}
+	p.Color = p.ColorAt(p.Progress())
+	offset := emitter.sampleOffset(self.rng, outline)
+	if emitter.Space == ParticleSpaceLocal {
+		// Local velocities are expressed in the emitter's unrotated frame.
+		p.Local = true
+		p.Velocity = p.Velocity.Rotate(-t.Rotation)
+		p.LocalPosition = origin.Sub(Vector(t.Position)).Rotate(-t.Rotation).Add(offset).Add(p.Velocity.ScaleF(age))
+	} else {
+		p.Velocity = p.Velocity.Add(emitter.velocity.ScaleF(emitter.InheritVelocity))
+	}
+	position := origin.Add(offset.Rotate(t.Rotation)).Add(p.Velocity.ScaleF(age))
+	if p.Local {
+		position = Vector(t.Position).Add(p.LocalPosition.Rotate(t.Rotation))
+	}
+	return particleSpawn{
+		transform: TransformComponent{
+			Position: Point(position),
+			Scale:    Point{X: scale, Y: scale},
+			Z:        emitter.Z,
+		},
+		particle: p,
+	}
+}
+
+// spriteOutline returns the entity's sprite rectangle in its unrotated
+// frame, or the emitter Size centered on it without a sprite.
+func spriteOutline(w *teishoku.World, e teishoku.Entity, t *TransformComponent, emitter *ParticleEmitterComponent) Rectangle {
+	s := teishoku.GetComponent[SpriteComponent](w, e)
+	if s == nil || (s.Width == 0 && s.Height == 0) {
+		return NewRectangle(-emitter.Size.X/2, -emitter.Size.Y/2, emitter.Size.X/2, emitter.Size.Y/2)
+	}
+	topLeft := Vector(t.Offset).Add(s.OriginOffset()).Negate().Scale(Vector(t.Scale))
+	size := V(float64(s.Width), float64(s.Height)).Scale(Vector(t.Scale))
+	return NewRectangle(topLeft.X, topLeft.Y, topLeft.X+size.X, topLeft.Y+size.Y)
+}
+
+func (self *ParticleSystem) Draw(w *teishoku.World, rdr *BatchRenderer) {
+	self.drawParticles(w, rdr, false)
+}
+
+// ScreenLayer returns a draw system for this system's screen space particles.
+// Add it last to the scene, or as an engine overlay to draw above post
+// processing; either way it ignores the camera.
+func (self *ParticleSystem) ScreenLayer() *ScreenParticleLayer {
+	if self.screen == nil {
+		self.screen = &ScreenParticleLayer{system: self}
+	}
+	return self.screen
+}
+
+func (self *ParticleSystem) drawParticles(w *teishoku.World, rdr *BatchRenderer, screenSpace bool) {
+	tm := GetTextureManager(w)
+	self.particles.Reset()
+	for self.particles.Next() {
+		t, p := self.particles.Get()
+		if p.Color.A == 0 || p.ScreenSpace != screenSpace || p.batched {
+			continue
+		}
+		drawParticle(t, p, tm.Get(p.TextureID), rdr)
+	}
+	self.drawMeshes(rdr, screenSpace)
+}
+
+// SubmitDraws queues every visible world space particle as a z-keyed draw
+// command, so particles batch with sprites sharing their texture. Batched
+// emitters submit a single command for their whole mesh.
+func (self *ParticleSystem) SubmitDraws(w *teishoku.World, list *DrawCommandList) {
+	tm := GetTextureManager(w)
+	self.particles.Reset()
+	for self.particles.Next() {
+		t, p := self.particles.Get()
+		if p.Color.A == 0 || p.ScreenSpace || p.batched {
+			continue
+		}
+		list.SubmitTextured(t.Z, tm.Page(p.TextureID), self, self.particles.Entity())
+	}
+	self.emitters.Reset()
+	for self.emitters.Next() {
+		_, emitter := self.emitters.Get()
+		if emitter.mesh != nil && !emitter.ScreenSpace {
+			list.SubmitTextured(emitter.Z, emitter.mesh.page, self, self.emitters.Entity())
+		}
+	}
+}
+
+// DrawEntity draws a single particle entity, or the mesh of a batched
+// emitter.
+func (self *ParticleSystem) DrawEntity(w *teishoku.World, e teishoku.Entity, rdr *BatchRenderer) {
+	if emitter := teishoku.GetComponent[ParticleEmitterComponent](w, e); emitter != nil && emitter.mesh != nil {
+		emitter.mesh.draw(rdr, emitter.Blend)
+		return
+	}
+	t, p := teishoku.GetComponent2[TransformComponent, ParticleComponent](w, e)
+	if t == nil || p == nil {
+		return
+	}
+	drawParticle(t, p, GetTextureManager(w).Get(p.TextureID), rdr)
+}
+
+func drawParticle(t *TransformComponent, p *ParticleComponent, img *ebiten.Image, rdr *BatchRenderer) {
+	if img == nil {
+		return
+	}
+	rdr.addQuadVertices(particleQuad(t, p, img), img)
+}
+
+// particleQuad computes the vertices of a particle drawn with img.
+func particleQuad(t *TransformComponent, p *ParticleComponent, img *ebiten.Image) [4]ebiten.Vertex {
+	bound := p.Bound
+	if IsBoundEmpty(bound) {
+		bound = Bound{Max: Point{X: float64(img.Bounds().Dx()), Y: float64(img.Bounds().Dy())}}
+	}
+	width, height := float64(p.Width), float64(p.Height)
+	if width == 0 && height == 0 {
+		width, height = bound.Max.X-bound.Min.X, bound.Max.Y-bound.Min.Y
+	}
+	var m Matrix
+	m.Translate(-width/2, -height/2)
+	m.Scale(t.Scale.X, t.Scale.Y)
+	m.Rotate(t.Rotation)
+	m.Translate(t.Position.X, t.Position.Y)
+	return QuadVerticesMatrix(m, p.Color,
+		float32(bound.Min.X), float32(bound.Min.Y),
+		float32(bound.Max.X), float32(bound.Max.Y),
+		width, height)
+}
+
+// ScreenParticleLayer draws the screen space particles of a ParticleSystem.
+type ScreenParticleLayer struct {
+	system *ParticleSystem
+}
+
+func (self *ScreenParticleLayer) Initialize(w *teishoku.World) {}
+
+// Draw draws the particles of the world the ParticleSystem was initialized
+// with, so the layer also works as an engine overlay.
+func (self *ScreenParticleLayer) Draw(w *teishoku.World, rdr *BatchRenderer) {
+	if self.system.world == nil {
+		return
+	}
+	rdr.SetScreenSpace(true)
+	self.system.drawParticles(self.system.world, rdr, true)
+	rdr.SetScreenSpace(false)
+}
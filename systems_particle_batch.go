@@ -0,0 +1,73 @@
+package katsu2d
+
+import (
+	"github.com/edwinsyarief/teishoku"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// particleMesh is the persistent vertex and index buffer of a batched
+// emitter. The particle system rewrites its vertices every update.
+type particleMesh struct {
+	vertices []ebiten.Vertex
+	indices  []uint32
+	img      *ebiten.Image
+	page     *ebiten.Image // atlas page of img, for grouping draw commands
+}
+
+func (self *particleMesh) reset(img, page *ebiten.Image) {
+	self.vertices = self.vertices[:0]
+	self.img, self.page = img, page
+}
+
+// add appends the quad of a particle, growing the shared quad indices as
+// needed.
+func (self *particleMesh) add(t *TransformComponent, p *ParticleComponent) {
+	if self.img == nil || p.Color.A == 0 {
+		return
+	}
+	quad := particleQuad(t, p, self.img)
+	self.vertices = append(self.vertices, quad[:]...)
+	if quads := len(self.vertices) / 4; len(self.indices) < quads*6 {
+		i := uint32(quads-1) * 4
+		self.indices = append(self.indices, i, i+1, i+2, i, i+2, i+3)
+	}
+}
+
+func (self *particleMesh) draw(rdr *BatchRenderer, blend ebiten.Blend) {
+	rdr.DrawMesh(self.vertices, self.indices[:len(self.vertices)/4*6], self.img, blend)
+}
+
+// prepareMeshes clears the meshes of batched emitters for this update and
+// indexes them by emitter entity.
+func (self *ParticleSystem) prepareMeshes(w *teishoku.World) {
+	clear(self.meshes)
+	tm := GetTextureManager(w)
+	self.emitters.Reset()
+	for self.emitters.Next() {
+		_, emitter := self.emitters.Get()
+		if !emitter.Batched {
+			emitter.mesh = nil
+			continue
+		}
+		if emitter.mesh == nil {
+			emitter.mesh = &particleMesh{}
+		}
+		var img, page *ebiten.Image
+		if tm != nil {
+			img, page = tm.Get(emitter.TextureID), tm.Page(emitter.TextureID)
+		}
+		emitter.mesh.reset(img, page)
+		self.meshes[self.emitters.Entity()] = emitter.mesh
+	}
+}
+
+// drawMeshes draws the meshes of batched emitters in the given space.
+func (self *ParticleSystem) drawMeshes(rdr *BatchRenderer, screenSpace bool) {
+	self.emitters.Reset()
+	for self.emitters.Next() {
+		_, emitter := self.emitters.Get()
+		if emitter.mesh != nil && emitter.ScreenSpace == screenSpace {
+			emitter.mesh.draw(rdr, emitter.Blend)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package katsu2d
+
+import (
+	"math"
+
+	"github.com/edwinsyarief/teishoku"
+)
+
+// PathFollowSystem moves entities along their paths.
+type PathFollowSystem struct {
+	filter *teishoku.Filter3[TransformComponent, PathComponent, PathFollowerComponent]
+}
+
+// NewPathFollowSystem creates a new PathFollowSystem.
+func NewPathFollowSystem() *PathFollowSystem {
+	return &PathFollowSystem{}
+}
+
+func (self *PathFollowSystem) Initialize(w *teishoku.World) {
+	self.filter = self.filter.New(w)
+}
+
+// Update advances every active follower by the given delta time.
+func (self *PathFollowSystem) Update(w *teishoku.World, dt float64) {
+	self.filter.Reset()
+	for self.filter.Next() {
+		t, path, follower := self.filter.Get()
+		if !follower.Active || follower.Speed <= 0 {
+			continue
+		}
+		length := path.Length()
+		if length == 0 {
+			continue
+		}
+
+		duration := length / follower.Speed
+		follower.Elapsed += ScaledDelta(w, self.filter.Entity(), dt)
+		progress := follower.Elapsed / duration
+		backwards := false
+		switch follower.Mode {
+		case PathOnce:
+			if progress >= 1 {
+				progress = 1
+				// Rewind so reactivating the follower runs the path again.
+				follower.Active = false
+				follower.Elapsed = 0
+				Publish(w, PathFinishedEvent{Entity: self.filter.Entity()})
+			}
+		case PathLoop:
+			progress = math.Mod(progress, 1)
+			follower.Elapsed = progress * duration
+		case PathPingPong:
+			progress = math.Mod(progress, 2)
+			follower.Elapsed = progress * duration
+			if progress > 1 {
+				progress = 2 - progress
+				backwards = true
+			}
+		}
+
+		eased := EaseTypes[float64](follower.EaseType)(progress, 0, 1, 1)
+		follower.Distance = eased * length
+		closed := path.Closed && path.Curve == PathLinear
+		pos, tangent := PointAtDistance(path.Polyline(), closed, follower.Distance)
+		t.Position = Point(pos)
+		if follower.OrientToTangent && !tangent.IsZero() {
+			angle := tangent.Angle()
+			if backwards {
+				angle += math.Pi
+			}
+			t.Rotation = angle + follower.RotationOffset
+		}
+		t.IsDirty = true
+	}
+}
@@ -0,0 +1,69 @@
+package katsu2d
+
+import "github.com/edwinsyarief/teishoku"
+
+// QuestSystem tracks quest progress from QuestProgressEvents, actor positions
+// and blackboard conditions.
+type QuestSystem struct {
+	actors      *teishoku.Filter2[TransformComponent, QuestActorComponent]
+	positions   []Vector
+	initialized bool
+}
+
+// NewQuestSystem creates a new QuestSystem.
+func NewQuestSystem() *QuestSystem {
+	return &QuestSystem{}
+}
+
+func (self *QuestSystem) Initialize(w *teishoku.World) {
+	self.actors = self.actors.New(w)
+	if self.initialized {
+		return
+	}
+	self.initialized = true
+	Subscribe(w, func(ev QuestProgressEvent) {
+		amount := ev.Amount
+		if amount == 0 {
+			amount = 1
+		}
+		GetQuests(w).Report(ev.Tag, amount)
+	})
+}
+
+// Update completes area and condition objectives of active quests.
+func (self *QuestSystem) Update(w *teishoku.World, dt float64) {
+	self.positions = self.positions[:0]
+	self.actors.Reset()
+	for self.actors.Next() {
+		t, _ := self.actors.Get()
+		self.positions = append(self.positions, Vector(t.Position))
+	}
+
+	log := GetQuests(w)
+	vars := GetVars(w)
+	for _, state := range log.Quests(QuestActive) {
+		stage := state.CurrentStage()
+		if stage == nil {
+			continue
+		}
+		id, current := state.Definition.ID, state.Stage
+		for i, obj := range stage.Objectives {
+			if state.Status != QuestActive || state.Stage != current || state.Progress[i].Done {
+				continue
+			}
+			switch obj.Kind {
+			case ObjectiveReachArea:
+				for _, pos := range self.positions {
+					if obj.Area.Contains(pos) {
+						log.CompleteObjective(id, i)
+						break
+					}
+				}
+			case ObjectiveCondition:
+				if obj.Condition != "" && vars.MustEvaluate(obj.Condition) {
+					log.CompleteObjective(id, i)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package katsu2d
+
+import (
+	"math"
+
+	"github.com/edwinsyarief/teishoku"
+)
+
+// RigidBodySystem integrates RigidBodyComponent velocities into positions,
+// applying gravity and friction, and counts down control locks. Add it
+// before the CollisionSystem so contacts see the new positions.
+type RigidBodySystem struct {
+	filter *teishoku.Filter2[TransformComponent, RigidBodyComponent]
+}
+
+// NewRigidBodySystem creates a new RigidBodySystem.
+func NewRigidBodySystem() *RigidBodySystem {
+	return &RigidBodySystem{}
+}
+
+func (self *RigidBodySystem) Initialize(w *teishoku.World) {
+	self.filter = self.filter.New(w)
+}
+
+func (self *RigidBodySystem) Update(w *teishoku.World, dt float64) {
+	self.filter.Reset()
+	for self.filter.Next() {
+		t, rb := self.filter.Get()
+		edt := ScaledDelta(w, self.filter.Entity(), dt)
+		if edt == 0 {
+			continue
+		}
+		if !rb.Grounded {
+			rb.Velocity = rb.Velocity.Add(rb.Gravity.ScaleF(edt))
+		}
+		friction := rb.AirFriction
+		if rb.Grounded {
+			friction = rb.GroundFriction
+		}
+		if friction > 0 {
+			rb.Velocity = rb.Velocity.ScaleF(math.Max(0, 1-friction*edt))
+		}
+		if rb.Velocity != (Vector{}) {
+			t.Position = Point(Vector(t.Position).Add(rb.Velocity.ScaleF(edt)))
+			t.IsDirty = true
+		}
+		rb.ControlLock = max(rb.ControlLock-edt, 0)
+	}
+}
@@ -0,0 +1,93 @@
+package katsu2d
+
+import "github.com/edwinsyarief/teishoku"
+
+// roofZoneVisit is a revealer standing inside a named trigger zone.
+type roofZoneVisit struct {
+	zone, revealer teishoku.Entity
+	name           string
+}
+
+// RoofFadeSystem fades roof layers in and out as revealers enter and leave
+// their trigger regions or zones. Zones need a TriggerZoneSystem and a
+// ColliderComponent on the revealers.
+type RoofFadeSystem struct {
+	roofs     *teishoku.Filter[RoofComponent]
+	revealers *teishoku.Filter2[TransformComponent, RoofRevealerComponent]
+	entities  []teishoku.Entity
+	positions []Vector
+	radii     []float64
+	visits    map[roofZoneVisit]struct{}
+}
+
+// NewRoofFadeSystem creates a new RoofFadeSystem.
+func NewRoofFadeSystem() *RoofFadeSystem {
+	return &RoofFadeSystem{visits: make(map[roofZoneVisit]struct{})}
+}
+
+func (self *RoofFadeSystem) Initialize(w *teishoku.World) {
+	self.roofs = self.roofs.New(w)
+	self.revealers = self.revealers.New(w)
+	Subscribe(w, func(ev TriggerEnterEvent) {
+		if teishoku.GetComponent[RoofRevealerComponent](w, ev.Entity) != nil {
+			self.visits[roofZoneVisit{zone: ev.Zone, revealer: ev.Entity, name: ev.Name}] = struct{}{}
+		}
+	})
+	Subscribe(w, func(ev TriggerExitEvent) {
+		delete(self.visits, roofZoneVisit{zone: ev.Zone, revealer: ev.Entity, name: ev.Name})
+	})
+}
+
+// Update recomputes which roofs are revealed and advances their fades.
+func (self *RoofFadeSystem) Update(w *teishoku.World, dt float64) {
+	self.entities = self.entities[:0]
+	self.positions = self.positions[:0]
+	self.radii = self.radii[:0]
+	self.revealers.Reset()
+	for self.revealers.Next() {
+		t, r := self.revealers.Get()
+		self.entities = append(self.entities, self.revealers.Entity())
+		self.positions = append(self.positions, Vector(t.Position))
+		self.radii = append(self.radii, r.Radius)
+	}
+
+	self.roofs.Reset()
+	for self.roofs.Next() {
+		roof, e := self.roofs.Get(), self.roofs.Entity()
+		revealed := false
+		for i, pos := range self.positions {
+			if (roof.Region.Contains(pos) || (self.radii[i] > 0 && roof.Region.IntersectsCircle(pos, self.radii[i]))) &&
+				SameElevation(w, e, self.entities[i]) {
+				revealed = true
+				break
+			}
+		}
+		if !revealed && roof.Zone != "" {
+			for visit := range self.visits {
+				if visit.name == roof.Zone && SameElevation(w, e, visit.revealer) {
+					revealed = true
+					break
+				}
+			}
+		}
+		if revealed != roof.Revealed {
+			roof.Revealed = revealed
+			Publish(w, RoofRevealChangedEvent{Entity: e, Revealed: revealed})
+		}
+
+		target := 1.0
+		if revealed {
+			target = roof.HiddenAlpha
+		}
+		if roof.FadeSpeed <= 0 {
+			roof.Alpha = target
+		} else if roof.Alpha < target {
+			roof.Alpha = min(roof.Alpha+roof.FadeSpeed*dt, target)
+		} else {
+			roof.Alpha = max(roof.Alpha-roof.FadeSpeed*dt, target)
+		}
+		if s := teishoku.GetComponent[SpriteComponent](w, e); s != nil {
+			s.Opacity = roof.Alpha
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package katsu2d
+
+import "github.com/edwinsyarief/teishoku"
+
+// ScriptSystem runs ScriptComponent callbacks. Register it both as an update
+// and a draw system to use Draw callbacks.
+type ScriptSystem struct {
+	filter   *teishoku.Filter[ScriptComponent]
+	entities []teishoku.Entity
+	ctx      ScriptContext
+}
+
+// NewScriptSystem creates a new ScriptSystem.
+func NewScriptSystem() *ScriptSystem {
+	return &ScriptSystem{}
+}
+
+func (self *ScriptSystem) Initialize(w *teishoku.World) {
+	self.filter = self.filter.New(w)
+}
+
+// Update calls OnStart once and then Update for every enabled script.
+// Entities are collected first so scripts may create or remove entities.
+func (self *ScriptSystem) Update(w *teishoku.World, dt float64) {
+	self.collect()
+	self.ctx.World = w
+	for _, e := range self.entities {
+		s := teishoku.GetComponent[ScriptComponent](w, e)
+		if s == nil || s.Disabled {
+			continue
+		}
+		self.ctx.Entity = e
+		if !s.started {
+			s.started = true
+			if s.OnStart != nil {
+				s.OnStart(&self.ctx)
+				if s = teishoku.GetComponent[ScriptComponent](w, e); s == nil {
+					continue
+				}
+			}
+		}
+		if s.Update != nil {
+			s.Update(&self.ctx, ScaledDelta(w, e, dt))
+		}
+	}
+}
+
+func (self *ScriptSystem) Draw(w *teishoku.World, rdr *BatchRenderer) {
+	self.collect()
+	self.ctx.World = w
+	for _, e := range self.entities {
+		s := teishoku.GetComponent[ScriptComponent](w, e)
+		if s == nil || s.Disabled || s.Draw == nil {
+			continue
+		}
+		self.ctx.Entity = e
+		s.Draw(&self.ctx, rdr)
+	}
+}
+
+func (self *ScriptSystem) collect() {
+	self.entities = self.entities[:0]
+	self.filter.Reset()
+	for self.filter.Next() {
+		self.entities = append(self.entities, self.filter.Entity())
+	}
+}
@@ -0,0 +1,162 @@
+package katsu2d
+
+import (
+	"math"
+
+	"github.com/edwinsyarief/teishoku"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const shadowSegments = 24
+
+// ShadowSystem draws blob shadows. Add it before the sprite systems so the
+// shadows sit below every y-sorted sprite.
+type ShadowSystem struct {
+	filter   *teishoku.Filter2[TransformComponent, ShadowComponent]
+	vertices []ebiten.Vertex
+	indices  []uint16
+}
+
+// NewShadowSystem creates a new ShadowSystem.
+func NewShadowSystem() *ShadowSystem {
+	return &ShadowSystem{}
+}
+
+func (self *ShadowSystem) Initialize(w *teishoku.World) {
+	self.filter = self.filter.New(w)
+}
+
+func (self *ShadowSystem) Draw(w *teishoku.World, rdr *BatchRenderer) {
+	tm := GetTextureManager(w)
+	self.filter.Reset()
+	for self.filter.Next() {
+		t, shadow := self.filter.Get()
+		if shadow.Opacity <= 0 {
+			continue
+		}
+		sprite := teishoku.GetComponent[SpriteComponent](w, self.filter.Entity())
+		anchor := GroundAnchor(t, sprite).Add(Vector(shadow.Offset))
+
+		if shadow.Mode == ShadowSilhouette && sprite != nil {
+			self.drawSilhouette(t, sprite, shadow, anchor, tm, rdr)
+			continue
+		}
+		if shadow.Mode == ShadowSkewed && sprite != nil {
+			if light := GetShadowLight(w); light != nil {
+				self.drawSkewed(t, sprite, shadow, light, anchor, tm, rdr)
+			}
+			continue
+		}
+		self.drawEllipse(shadow, anchor, tm.Get(0), rdr)
+	}
+}
+
+// GroundAnchor returns the point an entity stands on: the bottom center of
+// its sprite, or its position if sprite is nil. Rotation is ignored.
+func GroundAnchor(t *TransformComponent, sprite *SpriteComponent) Vector {
+	pos := Vector(t.Position)
+	if sprite == nil {
+		return pos
+	}
+	offset := Vector(t.Offset)
+	if sprite.Origin != OriginNone {
+		offset = offset.Add(sprite.OriginOffset())
+	}
+	scale := Vector(t.Scale)
+	return pos.Add(V(float64(sprite.Width)/2-offset.X, float64(sprite.Height)-offset.Y).Scale(scale))
+}
+
+func (self *ShadowSystem) drawEllipse(shadow *ShadowComponent, center Vector, img *ebiten.Image, rdr *BatchRenderer) {
+	a := float32(shadow.Color.A) / 255 * float32(shadow.Opacity)
+	r, g, b := float32(shadow.Color.R)/255*a, float32(shadow.Color.G)/255*a, float32(shadow.Color.B)/255*a
+	inner := 1 - Clamp(shadow.Softness, 0, 1)
+
+	self.vertices = append(self.vertices[:0], ebiten.Vertex{
+		DstX: float32(center.X), DstY: float32(center.Y),
+		ColorR: r, ColorG: g, ColorB: b, ColorA: a,
+	})
+	self.indices = self.indices[:0]
+	for i := range shadowSegments {
+		angle := 2 * math.Pi * float64(i) / shadowSegments
+		cos, sin := math.Cos(angle), math.Sin(angle)
+		self.vertices = append(self.vertices,
+			ebiten.Vertex{
+				DstX: float32(center.X + cos*shadow.Size.X*inner), DstY: float32(center.Y + sin*shadow.Size.Y*inner),
+				ColorR: r, ColorG: g, ColorB: b, ColorA: a,
+			},
+			ebiten.Vertex{
+				DstX: float32(center.X + cos*shadow.Size.X), DstY: float32(center.Y + sin*shadow.Size.Y),
+			})
+		in, out := uint16(1+i*2), uint16(2+i*2)
+		nextIn, nextOut := uint16(1+(i+1)%shadowSegments*2), uint16(2+(i+1)%shadowSegments*2)
+		self.indices = append(self.indices,
+			0, in, nextIn,
+			in, out, nextOut,
+			in, nextOut, nextIn)
+	}
+	rdr.AddCustomMeshes(self.vertices, self.indices, img)
+}
+
+func (self *ShadowSystem) drawSilhouette(t *TransformComponent, sprite *SpriteComponent, shadow *ShadowComponent, anchor Vector, tm *TextureManager, rdr *BatchRenderer) {
+	img := tm.Get(sprite.TextureID)
+	if img == nil || sprite.Width == 0 || sprite.Height == 0 {
+		return
+	}
+	bound := sprite.Bound
+	if IsBoundEmpty(bound) {
+		bound = Bound{Max: Point{X: float64(sprite.Width), Y: float64(sprite.Height)}}
+	}
+	width, height := float64(sprite.Width), float64(sprite.Height)
+
+	var m Matrix
+	m.Translate(-width/2, -height)
+	m.Scale(t.Scale.X*shadow.Size.X, t.Scale.Y*shadow.Size.Y)
+	m.Translate(anchor.X, anchor.Y)
+
+	clr := shadow.Color
+	clr.A = uint8(float64(clr.A) * shadow.Opacity)
+	rdr.AddQuadMatrix(m, img, clr,
+		float32(bound.Min.X), float32(bound.Min.Y),
+		float32(bound.Max.X), float32(bound.Max.Y),
+		width, height)
+}
+
+// drawSkewed shears the silhouette so its top lands Height*Length away from
+// the anchor along the light direction, flattening it onto the ground.
+func (self *ShadowSystem) drawSkewed(t *TransformComponent, sprite *SpriteComponent, shadow *ShadowComponent, light *ShadowLight, anchor Vector, tm *TextureManager, rdr *BatchRenderer) {
+	img := tm.Get(sprite.TextureID)
+	if img == nil || sprite.Width == 0 || sprite.Height == 0 {
+		return
+	}
+	bound := sprite.Bound
+	if IsBoundEmpty(bound) {
+		bound = Bound{Max: Point{X: float64(sprite.Width), Y: float64(sprite.Height)}}
+	}
+	width, height := float64(sprite.Width), float64(sprite.Height)
+	scaled := height * t.Scale.Y * shadow.Size.Y
+	if scaled == 0 {
+		return
+	}
+	casterHeight := shadow.Height
+	if casterHeight == 0 {
+		casterHeight = scaled
+	}
+	tip := light.Direction.Normalize().ScaleF(casterHeight * light.Length)
+
+	var m Matrix
+	m.Translate(-width/2, -height)
+	m.Scale(t.Scale.X*shadow.Size.X, t.Scale.Y*shadow.Size.Y)
+	// Local y runs from -scaled at the top to 0 at the anchor.
+	var shear Matrix
+	shear.SetElement(0, 1, -tip.X/scaled)
+	shear.SetElement(1, 1, -tip.Y/scaled)
+	m.Concat(shear)
+	m.Translate(anchor.X, anchor.Y)
+
+	clr := shadow.Color
+	clr.A = uint8(float64(clr.A) * Clamp(shadow.Opacity*light.Opacity, 0, 1))
+	rdr.AddQuadMatrix(m, img, clr,
+		float32(bound.Min.X), float32(bound.Min.Y),
+		float32(bound.Max.X), float32(bound.Max.Y),
+		width, height)
+}
@@ -1,14 +1,12 @@
 package katsu2d
 
-import (
-	"github.com/edwinsyarief/teishoku"
-	"github.com/hajimehoshi/ebiten/v2"
-)
+import "github.com/edwinsyarief/teishoku"
 
 // ShapeRenderSystem renders shape components.
 type ShapeRenderSystem struct {
 	transform   *Transform
 	filter      *teishoku.Filter2[TransformComponent, ShapeComponent]
+	ghosts      []Vector
 	initialized bool
 }
 
@@ -31,19 +29,58 @@ func (self *ShapeRenderSystem) Initialize(w *teishoku.World) {
 // Draw renders all shape components in the world.
 func (self *ShapeRenderSystem) Draw(w *teishoku.World, rdr *BatchRenderer) {
 	tm := GetTextureManager(w)
+	occlusion := GetOcclusionSet(w)
 	for self.filter.Next() {
+		if !occlusion.EntityVisible(w, self.filter.Entity()) {
+			continue
+		}
 		transform, shape := self.filter.Get()
-		shape.Shape.Rebuild()
-		vertices := shape.Shape.GetVertices()
-		indices := shape.Shape.GetIndices()
+		entityRenderSpace(w, self.filter.Entity(), rdr)
+		self.drawShape(w, self.filter.Entity(), transform, shape, tm, rdr)
+	}
+	self.filter.Reset()
+	rdr.SetScreenSpace(false)
+}
 
-		if len(vertices) == 0 {
+// SubmitDraws queues every shape as a z-keyed draw command.
+func (self *ShapeRenderSystem) SubmitDraws(w *teishoku.World, list *DrawCommandList) {
+	img := GetTextureManager(w).Page(0)
+	occlusion := GetOcclusionSet(w)
+	for self.filter.Next() {
+		if !occlusion.EntityVisible(w, self.filter.Entity()) {
 			continue
 		}
+		transform, _ := self.filter.Get()
+		list.SubmitTextured(transform.Z, img, self, self.filter.Entity())
+	}
+	self.filter.Reset()
+}
 
-		self.transform.SetFromComponent(transform)
+// DrawEntity draws a single shape entity.
+func (self *ShapeRenderSystem) DrawEntity(w *teishoku.World, e teishoku.Entity, rdr *BatchRenderer) {
+	transform, shape := teishoku.GetComponent2[TransformComponent, ShapeComponent](w, e)
+	if transform == nil || shape == nil {
+		return
+	}
+	self.drawShape(w, e, transform, shape, GetTextureManager(w), rdr)
+}
 
-		worldVertices := make([]ebiten.Vertex, len(vertices))
+func (self *ShapeRenderSystem) drawShape(w *teishoku.World, e teishoku.Entity, transform *TransformComponent, shape *ShapeComponent, tm *TextureManager, rdr *BatchRenderer) {
+	shape.Shape.Rebuild()
+	vertices := shape.Shape.GetVertices()
+	indices := shape.Shape.GetIndices()
+
+	if len(vertices) == 0 {
+		return
+	}
+
+	setRenderTransform(w, e, self.transform, transform)
+	img := tm.Get(0)
+
+	self.ghosts = wrapGhostOffsets(w, e, Vector(transform.Position), self.ghosts)
+	for _, offset := range self.ghosts {
+		self.transform.SetPosition(Vector(transform.Position).Add(offset))
+		worldVertices := rdr.Scratch(len(vertices))
 		transformMatrix := self.transform.Matrix()
 		for i, v := range vertices {
 			vx, vy := transformMatrix.Apply(float64(v.DstX), float64(v.DstY))
@@ -53,8 +90,6 @@ func (self *ShapeRenderSystem) Draw(w *teishoku.World, rdr *BatchRenderer) {
 			v.SrcY = 0
 			worldVertices[i] = v
 		}
-		img := tm.Get(0)
 		rdr.AddCustomMeshes(worldVertices, indices, img)
 	}
-	self.filter.Reset()
 }
@@ -0,0 +1,171 @@
+package katsu2d
+
+import "github.com/edwinsyarief/teishoku"
+
+type pendingSpawn struct {
+	spawner teishoku.Entity
+	pos     Vector
+}
+
+// SpawnerSystem runs spawner schedules and tracks spawned entities.
+type SpawnerSystem struct {
+	spawners *teishoku.Filter2[TransformComponent, SpawnerComponent]
+	triggers *teishoku.Filter2[TransformComponent, SpawnTriggerComponent]
+	rand     *Rand
+	targets  []Vector
+	pending  []pendingSpawn
+	finished []teishoku.Entity
+}
+
+// NewSpawnerSystem creates a new SpawnerSystem.
+func NewSpawnerSystem() *SpawnerSystem {
+	return &SpawnerSystem{rand: Random()}
+}
+
+func (self *SpawnerSystem) Initialize(w *teishoku.World) {
+	self.spawners = self.spawners.New(w)
+	self.triggers = self.triggers.New(w)
+}
+
+// Update advances spawners. Entities are created after iteration so that
+// spawn funcs are free to add components.
+func (self *SpawnerSystem) Update(w *teishoku.World, dt float64) {
+	self.targets = self.targets[:0]
+	self.triggers.Reset()
+	for self.triggers.Next() {
+		t, _ := self.triggers.Get()
+		self.targets = append(self.targets, Vector(t.Position))
+	}
+
+	var vars *Blackboard
+	self.pending = self.pending[:0]
+	self.spawners.Reset()
+	for self.spawners.Next() {
+		t, s := self.spawners.Get()
+		e := self.spawners.Entity()
+
+		alive := s.alive[:0]
+		for _, a := range s.alive {
+			if w.IsValid(a) {
+				alive = append(alive, a)
+			}
+		}
+		s.alive = alive
+
+		if !s.Active || s.Finished || s.Spawn == nil {
+			continue
+		}
+		origin := Vector(t.Position)
+		if !self.inRange(origin, s.ActivationRadius) {
+			continue
+		}
+		if s.Condition != "" {
+			if vars == nil {
+				vars = GetVars(w)
+			}
+			if !vars.MustEvaluate(s.Condition) {
+				continue
+			}
+		}
+
+		step := ScaledDelta(w, e, dt)
+		s.Elapsed += step
+		queued := len(s.alive)
+		canSpawn := func() bool {
+			return s.MaxAlive <= 0 || queued < s.MaxAlive
+		}
+		queue := func() {
+			self.pending = append(self.pending, pendingSpawn{spawner: e, pos: self.spawnPoint(origin, s)})
+			queued++
+			s.Spawned++
+		}
+
+		switch s.Mode {
+		case SpawnWaves:
+			s.waveTimer += step
+			for s.Wave < len(s.Waves) {
+				wave := s.Waves[s.Wave]
+				if s.waveSpawned == 0 && (s.waveTimer < wave.Delay || (wave.WaitForClear && queued > 0)) {
+					break
+				}
+				if s.waveSpawned < wave.Count {
+					elapsed := s.waveTimer - wave.Delay
+					if !canSpawn() || elapsed < float64(s.waveSpawned)*wave.Interval {
+						break
+					}
+					if s.waveSpawned == 0 {
+						Publish(w, SpawnWaveStartedEvent{Entity: e, Wave: s.Wave})
+					}
+					queue()
+					s.waveSpawned++
+					continue
+				}
+				Publish(w, SpawnWaveCompletedEvent{Entity: e, Wave: s.Wave})
+				s.Wave++
+				s.waveSpawned = 0
+				s.waveTimer = 0
+			}
+			if s.Wave >= len(s.Waves) {
+				s.Finished = true
+			}
+		case SpawnContinuous:
+			if s.Rate != nil {
+				s.accumulator += s.Rate.Evaluate(s.Elapsed) * step
+			}
+			for s.accumulator >= 1 && canSpawn() {
+				s.accumulator--
+				queue()
+			}
+			if s.accumulator > 1 {
+				s.accumulator = 1
+			}
+			if s.Duration > 0 && s.Elapsed >= s.Duration {
+				s.Finished = true
+			}
+		}
+		if s.Finished {
+			self.finished = append(self.finished, e)
+		}
+	}
+
+	for _, p := range self.pending {
+		s := teishoku.GetComponent[SpawnerComponent](w, p.spawner)
+		if s == nil {
+			continue
+		}
+		spawned := s.Spawn(w, p.pos)
+		// Spawning may have moved the spawner's storage.
+		if s := teishoku.GetComponent[SpawnerComponent](w, p.spawner); s != nil && w.IsValid(spawned) {
+			s.alive = append(s.alive, spawned)
+		}
+		Publish(w, EntitySpawnedEvent{Spawner: p.spawner, Entity: spawned})
+	}
+	for _, e := range self.finished {
+		Publish(w, SpawnerFinishedEvent{Entity: e})
+	}
+	self.finished = self.finished[:0]
+}
+
+func (self *SpawnerSystem) inRange(origin Vector, radius float64) bool {
+	if radius <= 0 {
+		return true
+	}
+	for _, pos := range self.targets {
+		if pos.DistanceSquaredTo(origin) <= radius*radius {
+			return true
+		}
+	}
+	return false
+}
+
+func (self *SpawnerSystem) spawnPoint(origin Vector, s *SpawnerComponent) Vector {
+	if !s.Area.IsEmpty() {
+		return origin.Add(self.rand.VectorRange(s.Area.Min, s.Area.Max))
+	}
+	if len(s.Points) == 0 {
+		return origin
+	}
+	p := s.Points[s.nextPoint%len(s.Points)]
+	s.nextPoint++
+	return origin.Add(Vector(p))
+}
@@ -1,22 +1,38 @@
 package katsu2d
 
 import (
-	"sort"
+	"cmp"
+	"image/color"
+	"slices"
 
 	"github.com/edwinsyarief/teishoku"
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
 type SpriteSystem struct {
+	// StrictOrder keeps sprites with equal Z in submission order instead of
+	// grouping them by atlas page to reduce batch flushes.
+	StrictOrder bool
+	// Ambient is the light of sprites with a normal map where no
+	// PointLightComponent reaches.
+	Ambient color.RGBA
+
 	transform                *Transform
 	filter                   *teishoku.Filter2[TransformComponent, SpriteComponent]
 	lastFrameEntities        map[teishoku.Entity]struct{}
 	entities                 []teishoku.Entity
+	ghosts                   []Vector
+	shaderOptions            ebiten.DrawTrianglesShaderOptions
+	quadIndices              []uint16
+	lighting                 spriteLighting
+	pages                    map[*ebiten.Image]int
+	flushesSaved             int
 	zSortNeeded, initialized bool
 }
 
 func NewSpriteSystem() *SpriteSystem {
 	return &SpriteSystem{
+		Ambient:           color.RGBA{96, 96, 96, 255},
 		transform:         T(),
 		lastFrameEntities: make(map[teishoku.Entity]struct{}),
 		entities:          make([]teishoku.Entity, 0),
@@ -31,10 +47,16 @@ func (self *SpriteSystem) Initialize(w *teishoku.World) {
 	self.initialized = true
 }
 func (self *SpriteSystem) Update(w *teishoku.World, dt float64) {
-	currentEntities := make([]teishoku.Entity, 0)
+	// The query results and sort keys only live for this update, so they
+	// come from the frame arena.
+	currentEntities := ArenaBuffer[teishoku.Entity](w, len(self.lastFrameEntities))
 	self.filter.Reset()
 	for self.filter.Next() {
-		currentEntities = append(currentEntities, self.filter.Entity())
+		entity := self.filter.Entity()
+		if teishoku.GetComponent[StaticComponent](w, entity) != nil {
+			continue
+		}
+		currentEntities = append(currentEntities, entity)
 	}
 
 	zSortNeeded := self.zSortNeeded || len(currentEntities) != len(self.lastFrameEntities)
@@ -48,64 +70,253 @@ func (self *SpriteSystem) Update(w *teishoku.World, dt float64) {
 	}
 
 	if zSortNeeded {
-		self.entities = currentEntities
-		sort.SliceStable(self.entities, func(i, j int) bool {
-			t1 := teishoku.GetComponent[TransformComponent](w, self.entities[i])
-			t2 := teishoku.GetComponent[TransformComponent](w, self.entities[j])
-			return t1.Z < t2.Z
+		keys := ArenaBuffer[spriteSortKey](w, len(currentEntities))
+		tm := GetTextureManager(w)
+		if self.pages == nil {
+			self.pages = make(map[*ebiten.Image]int)
+		}
+		clear(self.pages)
+		for _, e := range currentEntities {
+			t, s := teishoku.GetComponent2[TransformComponent, SpriteComponent](w, e)
+			keys = append(keys, spriteSortKey{z: t.Z, page: self.pageIndex(tm, s.TextureID), entity: e})
+		}
+		slices.SortStableFunc(keys, func(a, b spriteSortKey) int {
+			return cmp.Compare(a.z, b.z)
 		})
+		self.flushesSaved = 0
+		if !self.StrictOrder {
+			before := textureSwitches(keys)
+			groupByPage(keys)
+			self.flushesSaved = before - textureSwitches(keys)
+		}
+		self.entities = self.entities[:0]
+		for _, key := range keys {
+			self.entities = append(self.entities, key.entity)
+		}
 		self.zSortNeeded = false
 	}
 
-	self.lastFrameEntities = make(map[teishoku.Entity]struct{}, len(currentEntities))
+	clear(self.lastFrameEntities)
 	for _, entity := range currentEntities {
 		self.lastFrameEntities[entity] = struct{}{}
 	}
 }
 func (self *SpriteSystem) Draw(w *teishoku.World, rdr *BatchRenderer) {
-	tm := GetTextureManager(w)
+	rdr.AddFlushSavings(self.flushesSaved)
+	self.lighting.gather(w)
+	occlusion := GetOcclusionSet(w)
 	for _, e := range self.entities {
-		t, s := teishoku.GetComponent2[TransformComponent, SpriteComponent](w, e)
-		self.transform.SetFromComponent(t)
+		if !occlusion.EntityVisible(w, e) {
+			continue
+		}
+		entityRenderSpace(w, e, rdr)
+		self.DrawEntity(w, e, rdr)
+	}
+	rdr.SetScreenSpace(false)
+}
 
-		img := tm.Get(s.TextureID)
-		if img == nil {
+// SubmitDraws queues every sprite as a z-keyed draw command.
+func (self *SpriteSystem) SubmitDraws(w *teishoku.World, list *DrawCommandList) {
+	if self.flushesSaved > 0 {
+		list.SubmitFunc(0, self.reportFlushSavings)
+	}
+	tm := GetTextureManager(w)
+	occlusion := GetOcclusionSet(w)
+	self.lighting.gather(w)
+	for _, e := range self.entities {
+		if !occlusion.EntityVisible(w, e) {
 			continue
 		}
+		t, s := teishoku.GetComponent2[TransformComponent, SpriteComponent](w, e)
+		list.SubmitTextured(t.Z, tm.Page(s.TextureID), self, e)
+	}
+}
+
+// DrawEntity draws a single sprite entity.
+func (self *SpriteSystem) DrawEntity(w *teishoku.World, e teishoku.Entity, rdr *BatchRenderer) {
+	tm := GetTextureManager(w)
+	t, s := teishoku.GetComponent2[TransformComponent, SpriteComponent](w, e)
+	setRenderTransform(w, e, self.transform, t)
+
+	img := tm.Get(s.TextureID)
+	if img == nil {
+		return
+	}
 
-		if IsBoundEmpty(s.Bound) {
-			s.Bound = Bound{
-				Min: Point{X: 0, Y: 0},
-				Max: Point{X: float64(s.Width), Y: float64(s.Height)},
+	if s.Origin != OriginNone {
+		if s.Width == 0 && s.Height == 0 {
+			if IsBoundEmpty(s.Bound) {
+				s.Width, s.Height = img.Bounds().Dx(), img.Bounds().Dy()
+			} else {
+				s.SyncSizeToBound()
 			}
 		}
+		self.transform.SetOffset(self.transform.Offset().Add(s.OriginOffset()))
+	}
+
+	if IsBoundEmpty(s.Bound) {
+		s.Bound = Bound{
+			Min: Point{X: 0, Y: 0},
+			Max: Point{X: float64(s.Width), Y: float64(s.Height)},
+		}
+	}
+
+	m := teishoku.GetComponent[MeshComponent](w, e)
+	sh := teishoku.GetComponent[ShaderComponent](w, e)
+	if sh != nil && (sh.Disabled || sh.Shader == nil) {
+		sh = nil
+	}
+	if sh == nil && s.NormalMapID != 0 {
+		sh = self.lighting.shade(Vector(t.Position), self.transform.Scale(), self.transform.Rotation(),
+			self.Ambient, tm.Get(s.NormalMapID), rdr)
+	}
+	self.ghosts = wrapGhostOffsets(w, e, Vector(t.Position), self.ghosts)
+	position := self.transform.Position().Sub(V(0, elevationLift(w, e)))
+	for _, offset := range self.ghosts {
+		self.transform.SetPosition(position.Add(offset))
+		if sh != nil {
+			self.drawShaded(t, s, m, sh, img, rdr)
+			continue
+		}
+		self.drawSprite(t, s, m, img, rdr)
+	}
+}
 
+// drawShaded draws a sprite with its ShaderComponent in its own draw call.
+func (self *SpriteSystem) drawShaded(t *TransformComponent, s *SpriteComponent, m *MeshComponent, sh *ShaderComponent, img *ebiten.Image, rdr *BatchRenderer) {
+	col := s.Color
+	col.A = uint8(float64(col.A) * s.Opacity)
+	var verts []ebiten.Vertex
+	var inds []uint16
+	switch {
+	case m != nil:
+		GenerateMesh(m, s)
 		matrix := self.transform.Matrix()
-		if m := teishoku.GetComponent[MeshComponent](w, e); m != nil {
-			GenerateMesh(m, s)
-			worldVertices := make([]ebiten.Vertex, len(m.Vertices))
-			for i, v := range m.Vertices {
-				v.ColorR = float32(s.Color.R) / 255
-				v.ColorG = float32(s.Color.G) / 255
-				v.ColorB = float32(s.Color.B) / 255
-				v.ColorA = (float32(s.Color.A) / 255) * float32(s.Opacity)
-				vx, vy := (&matrix).Apply(float64(v.DstX), float64(v.DstY))
-				v.DstX = float32(vx)
-				v.DstY = float32(vy)
-				worldVertices[i] = v
-			}
-			rdr.AddCustomMeshes(worldVertices, m.Indices, img)
+		verts = rdr.Scratch(len(m.Vertices))
+		cr, cg, cb, ca := float32(col.R)/255, float32(col.G)/255, float32(col.B)/255, float32(col.A)/255
+		for i, v := range m.Vertices {
+			v.ColorR, v.ColorG, v.ColorB, v.ColorA = cr, cg, cb, ca
+			vx, vy := matrix.Apply(float64(v.DstX), float64(v.DstY))
+			v.DstX, v.DstY = float32(vx), float32(vy)
+			verts[i] = v
+		}
+		inds = m.Indices
+	default:
+		var quad [4]ebiten.Vertex
+		if t.HasSkewOrPivot() {
+			quad = QuadVerticesMatrix(self.transform.Matrix(), col,
+				float32(s.Bound.Min.X), float32(s.Bound.Min.Y),
+				float32(s.Bound.Max.X), float32(s.Bound.Max.Y),
+				float64(s.Width), float64(s.Height))
 		} else {
-			col := s.Color
-			col.A = uint8(float64(col.A) * s.Opacity)
-			rdr.AddQuad(self.transform.Position(),
-				self.transform.Offset(),
-				self.transform.Origin(),
-				self.transform.Scale(), self.transform.Rotation(),
-				img, col,
+			quad = QuadVertices(self.transform.Position(), self.transform.Offset(), self.transform.Origin(),
+				self.transform.Scale(), self.transform.Rotation(), col,
 				float32(s.Bound.Min.X), float32(s.Bound.Min.Y),
 				float32(s.Bound.Max.X), float32(s.Bound.Max.Y),
 				float64(s.Width), float64(s.Height))
 		}
+		verts = rdr.Scratch(4)
+		copy(verts, quad[:])
+		if self.quadIndices == nil {
+			self.quadIndices = []uint16{0, 1, 2, 0, 2, 3}
+		}
+		inds = self.quadIndices
+	}
+	self.shaderOptions.Uniforms = sh.Uniforms
+	self.shaderOptions.Images = [4]*ebiten.Image{img, sh.Images[0], sh.Images[1], sh.Images[2]}
+	self.shaderOptions.Blend = sh.Blend
+	rdr.DrawShader(verts, inds, sh.Shader, &self.shaderOptions)
+}
+
+func (self *SpriteSystem) drawSprite(t *TransformComponent, s *SpriteComponent, m *MeshComponent, img *ebiten.Image, rdr *BatchRenderer) {
+	matrix := self.transform.Matrix()
+	if m != nil {
+		GenerateMesh(m, s)
+		worldVertices := rdr.Scratch(len(m.Vertices))
+		for i, v := range m.Vertices {
+			v.ColorR = float32(s.Color.R) / 255
+			v.ColorG = float32(s.Color.G) / 255
+			v.ColorB = float32(s.Color.B) / 255
+			v.ColorA = (float32(s.Color.A) / 255) * float32(s.Opacity)
+			vx, vy := (&matrix).Apply(float64(v.DstX), float64(v.DstY))
+			v.DstX = float32(vx)
+			v.DstY = float32(vy)
+			worldVertices[i] = v
+		}
+		rdr.AddCustomMeshes(worldVertices, m.Indices, img)
+	} else if t.HasSkewOrPivot() {
+		col := s.Color
+		col.A = uint8(float64(col.A) * s.Opacity)
+		rdr.AddQuadMatrix(matrix, img, col,
+			float32(s.Bound.Min.X), float32(s.Bound.Min.Y),
+			float32(s.Bound.Max.X), float32(s.Bound.Max.Y),
+			float64(s.Width), float64(s.Height))
+	} else {
+		col := s.Color
+		col.A = uint8(float64(col.A) * s.Opacity)
+		rdr.AddQuad(self.transform.Position(),
+			self.transform.Offset(),
+			self.transform.Origin(),
+			self.transform.Scale(), self.transform.Rotation(),
+			img, col,
+			float32(s.Bound.Min.X), float32(s.Bound.Min.Y),
+			float32(s.Bound.Max.X), float32(s.Bound.Max.Y),
+			float64(s.Width), float64(s.Height))
+	}
+}
+
+func (self *SpriteSystem) reportFlushSavings(rdr *BatchRenderer) {
+	rdr.AddFlushSavings(self.flushesSaved)
+}
+
+// pageIndex numbers the atlas page of texture id in order of first use, as
+// sprites on different textures of one page still share a draw call.
+func (self *SpriteSystem) pageIndex(tm *TextureManager, id int) int {
+	if tm == nil {
+		return id
+	}
+	page := tm.Page(id)
+	index, ok := self.pages[page]
+	if !ok {
+		index = len(self.pages)
+		self.pages[page] = index
+	}
+	return index
+}
+
+// spriteSortKey caches the sort fields of a sprite, so sorting does not look
+// up components in every comparison.
+type spriteSortKey struct {
+	z      float64
+	page   int
+	entity teishoku.Entity
+}
+
+// groupByPage reorders each run of equal Z in the Z-sorted keys by atlas
+// page, keeping the submission order within a page.
+func groupByPage(keys []spriteSortKey) {
+	for start := 0; start < len(keys); {
+		end := start + 1
+		for end < len(keys) && keys[end].z == keys[start].z {
+			end++
+		}
+		if end-start > 1 {
+			slices.SortStableFunc(keys[start:end], func(a, b spriteSortKey) int {
+				return cmp.Compare(a.page, b.page)
+			})
+		}
+		start = end
+	}
+}
+
+// textureSwitches counts how often consecutive sprites change atlas page,
+// each of which forces a batch flush.
+func textureSwitches(keys []spriteSortKey) int {
+	switches := 0
+	for i := 1; i < len(keys); i++ {
+		if keys[i].page != keys[i-1].page {
+			switches++
+		}
 	}
+	return switches
 }
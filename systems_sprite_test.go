@@ -0,0 +1,36 @@
+package katsu2d
+
+import (
+	"testing"
+
+	"github.com/edwinsyarief/teishoku"
+)
+
+// TestSpriteSystemUpdateNoAllocs guards the steady-state update of the
+// sprite systems against per-frame allocations.
+func TestSpriteSystemUpdateNoAllocs(t *testing.T) {
+	world := teishoku.NewWorld(testInitialCap)
+	for i := 0; i < 500; i++ {
+		ent := world.CreateEntity()
+		teishoku.SetComponent(world, ent, TransformComponent{Position: Point{X: float64(i)}, Z: float64(i % 7)})
+		teishoku.SetComponent(world, ent, SpriteComponent{TextureID: i % 3})
+		teishoku.SetComponent(world, ent, OrderableComponent{Index: float64(i)})
+	}
+	sprites := NewSpriteSystem()
+	sprites.Initialize(world)
+	ordered := NewOrderedSpriteSystem()
+	ordered.Initialize(world)
+	// The first update sorts and sizes the buffers.
+	sprites.Update(world, 1.0/60.0)
+	ordered.Update(world, 1.0/60.0)
+	allocs := testing.AllocsPerRun(50, func() {
+		sprites.Update(world, 1.0/60.0)
+		ordered.Update(world, 1.0/60.0)
+	})
+	if allocs != 0 {
+		t.Errorf("Expected no allocations, got %.1f per run", allocs)
+	}
+	if len(sprites.entities) != 500 || len(ordered.entities) != 500 {
+		t.Errorf("Expected 500 entities, got %d and %d", len(sprites.entities), len(ordered.entities))
+	}
+}
@@ -0,0 +1,33 @@
+package katsu2d
+
+import "github.com/edwinsyarief/teishoku"
+
+// StateMachineSystem ticks the state machines of all entities.
+type StateMachineSystem struct {
+	filter *teishoku.Filter[StateMachineComponent]
+	ctx    StateContext
+}
+
+// NewStateMachineSystem creates a new StateMachineSystem.
+func NewStateMachineSystem() *StateMachineSystem {
+	return &StateMachineSystem{}
+}
+
+func (self *StateMachineSystem) Initialize(w *teishoku.World) {
+	self.filter = self.filter.New(w)
+}
+
+func (self *StateMachineSystem) Update(w *teishoku.World, dt float64) {
+	self.ctx.World = w
+	self.ctx.Vars = GetVars(w)
+	self.filter.Reset()
+	for self.filter.Next() {
+		sm := self.filter.Get()
+		if sm.Machine == nil || sm.Machine.Definition == nil {
+			continue
+		}
+		e := self.filter.Entity()
+		self.ctx.Entity = e
+		sm.Machine.Update(&self.ctx, ScaledDelta(w, e, dt))
+	}
+}
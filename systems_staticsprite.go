@@ -0,0 +1,164 @@
+package katsu2d
+
+import (
+	"sort"
+
+	"github.com/edwinsyarief/teishoku"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// StaticSpriteSystem draws sprites tagged with StaticComponent from cached
+// geometry, grouped by texture. Static sprites are drawn in texture order
+// rather than Z order, so they suit background decoration. Batches are split
+// by OcclusionGroupComponent so hidden groups are skipped as a whole.
+type StaticSpriteSystem struct {
+	transform   *Transform
+	filter      *teishoku.Filter3[TransformComponent, SpriteComponent, StaticComponent]
+	batches     map[staticBatchKey][]ebiten.Vertex
+	keys        []staticBatchKey
+	count       int
+	dirty       bool
+	initialized bool
+}
+
+// staticBatchKey identifies the batch of one texture in one occlusion group.
+type staticBatchKey struct {
+	textureID, group int
+}
+
+// noOcclusionGroup batches static sprites outside any occlusion group.
+const noOcclusionGroup = -1
+
+// NewStaticSpriteSystem creates a new StaticSpriteSystem.
+func NewStaticSpriteSystem() *StaticSpriteSystem {
+	return &StaticSpriteSystem{
+		transform: T(),
+		batches:   make(map[staticBatchKey][]ebiten.Vertex),
+		dirty:     true,
+	}
+}
+
+func (self *StaticSpriteSystem) Initialize(w *teishoku.World) {
+	if self.initialized {
+		return
+	}
+
+	self.filter = self.filter.New(w)
+	self.initialized = true
+}
+
+// Update re-transforms only the static sprites whose components changed.
+func (self *StaticSpriteSystem) Update(w *teishoku.World, dt float64) {
+	tm := GetTextureManager(w)
+	count := 0
+	self.filter.Reset()
+	for self.filter.Next() {
+		t, s, st := self.filter.Get()
+		count++
+		group := noOcclusionGroup
+		if g := teishoku.GetComponent[OcclusionGroupComponent](w, self.filter.Entity()); g != nil {
+			group = g.Group
+		}
+		if st.group != group {
+			st.group = group
+			self.dirty = true
+		}
+		if st.cached && st.transform == *t && st.sprite == *s {
+			continue
+		}
+		img := tm.Get(s.TextureID)
+		if img == nil {
+			continue
+		}
+		st.quad = spriteQuad(self.transform, t, s, img)
+		st.transform = *t
+		st.sprite = *s
+		st.cached = true
+		self.dirty = true
+	}
+	if count != self.count {
+		self.count = count
+		self.dirty = true
+	}
+	if self.dirty {
+		self.rebuild()
+	}
+}
+
+// rebuild regroups the cached quads into per-texture and per-group vertex
+// batches.
+func (self *StaticSpriteSystem) rebuild() {
+	for key, verts := range self.batches {
+		self.batches[key] = verts[:0]
+	}
+	self.filter.Reset()
+	for self.filter.Next() {
+		_, s, st := self.filter.Get()
+		if !st.cached {
+			continue
+		}
+		key := staticBatchKey{textureID: s.TextureID, group: st.group}
+		self.batches[key] = append(self.batches[key], st.quad[:]...)
+	}
+	self.keys = self.keys[:0]
+	for key, verts := range self.batches {
+		if len(verts) == 0 {
+			delete(self.batches, key)
+			continue
+		}
+		self.keys = append(self.keys, key)
+	}
+	sort.Slice(self.keys, func(i, j int) bool {
+		a, b := self.keys[i], self.keys[j]
+		return a.textureID < b.textureID || (a.textureID == b.textureID && a.group < b.group)
+	})
+	self.dirty = false
+}
+
+// Draw submits the cached batches of visible groups, one run per texture.
+func (self *StaticSpriteSystem) Draw(w *teishoku.World, rdr *BatchRenderer) {
+	tm := GetTextureManager(w)
+	occlusion := GetOcclusionSet(w)
+	for _, key := range self.keys {
+		if key.group != noOcclusionGroup && !occlusion.GroupVisible(key.group) {
+			continue
+		}
+		img := tm.Get(key.textureID)
+		if img == nil {
+			continue
+		}
+		rdr.AddQuads(self.batches[key], img)
+	}
+}
+
+// spriteQuad computes the world-space quad of a plain (non-mesh) sprite.
+func spriteQuad(transform *Transform, t *TransformComponent, s *SpriteComponent, img *ebiten.Image) [4]ebiten.Vertex {
+	transform.SetFromComponent(t)
+	if s.Origin != OriginNone {
+		if s.Width == 0 && s.Height == 0 {
+			if IsBoundEmpty(s.Bound) {
+				s.Width, s.Height = img.Bounds().Dx(), img.Bounds().Dy()
+			} else {
+				s.SyncSizeToBound()
+			}
+		}
+		transform.SetOffset(transform.Offset().Add(s.OriginOffset()))
+	}
+	bound := s.Bound
+	if IsBoundEmpty(bound) {
+		bound = Bound{Max: Point{X: float64(s.Width), Y: float64(s.Height)}}
+	}
+	col := s.Color
+	col.A = uint8(float64(col.A) * s.Opacity)
+	if t.HasSkewOrPivot() {
+		return QuadVerticesMatrix(transform.Matrix(), col,
+			float32(bound.Min.X), float32(bound.Min.Y),
+			float32(bound.Max.X), float32(bound.Max.Y),
+			float64(s.Width), float64(s.Height))
+	}
+	return QuadVertices(transform.Position(), transform.Offset(), transform.Origin(),
+		transform.Scale(), transform.Rotation(), col,
+		float32(bound.Min.X), float32(bound.Min.Y),
+		float32(bound.Max.X), float32(bound.Max.Y),
+		float64(s.Width), float64(s.Height))
+}
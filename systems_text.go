@@ -2,6 +2,7 @@ package katsu2d
 
 import (
 	"github.com/edwinsyarief/teishoku"
+	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
 	"golang.org/x/text/language"
 )
@@ -24,14 +25,20 @@ type TextSystem struct {
 	drawOpts    *text.DrawOptions
 	transform   *Transform
 	fontFaceMap map[teishoku.Entity]*text.GoTextFace
+	rich        map[teishoku.Entity]*richText
+	glyphs      []text.Glyph
+	glyphOpts   ebiten.DrawImageOptions
+	time        float64
 	entities    []teishoku.Entity
 	initialized bool
 }
 
 func NewTextSystem() *TextSystem {
 	return &TextSystem{
-		drawOpts:  &text.DrawOptions{},
-		transform: T(),
+		drawOpts:    &text.DrawOptions{},
+		transform:   T(),
+		fontFaceMap: make(map[teishoku.Entity]*text.GoTextFace),
+		rich:        make(map[teishoku.Entity]*richText),
 	}
 }
 func (self *TextSystem) Initialize(w *teishoku.World) {
@@ -44,36 +51,85 @@ func (self *TextSystem) Initialize(w *teishoku.World) {
 	self.initialized = true
 }
 func (self *TextSystem) Update(w *teishoku.World, dt float64) {
-	self.entities = make([]teishoku.Entity, 0)
-	self.fontFaceMap = make(map[teishoku.Entity]*text.GoTextFace)
+	self.time += dt
+	self.entities = self.entities[:0]
 	self.filter.Reset()
 	for self.filter.Next() {
-		self.entities = append(self.entities, self.filter.Entity())
+		e := self.filter.Entity()
+		self.entities = append(self.entities, e)
 		_, txt := self.filter.Get()
 		f := self.getFontFace(txt.FontID, txt.Size)
+		if txt.RichText {
+			self.updateRichCache(w, e, txt, f)
+			continue
+		}
+		if _, ok := self.rich[e]; ok {
+			// Rich text was turned off; measure the plain caption again.
+			delete(self.rich, e)
+			txt.CachedText = ""
+		}
 		self.updateCache(txt, f)
 	}
+	// Forget the faces of entities that no longer have text.
+	if len(self.fontFaceMap) > len(self.entities) {
+		for e := range self.fontFaceMap {
+			if !w.IsValid(e) || teishoku.GetComponent[TextComponent](w, e) == nil {
+				delete(self.fontFaceMap, e)
+				delete(self.rich, e)
+			}
+		}
+	}
 }
 func (self *TextSystem) Draw(w *teishoku.World, rdr *BatchRenderer) {
-	rdr.Flush()
+	occlusion := GetOcclusionSet(w)
 	for _, e := range self.entities {
-		t, txt := teishoku.GetComponent2[TransformComponent, TextComponent](w, e)
-		self.drawOpts.LineSpacing = txt.LineSpacing
-		switch txt.Alignment {
-		case TextAlignmentTopRight, TextAlignmentMiddleRight, TextAlignmentBottomRight:
-			self.drawOpts.PrimaryAlign = text.AlignStart
-		case TextAlignmentTopCenter, TextAlignmentMiddleCenter, TextAlignmentBottomCenter:
-			self.drawOpts.PrimaryAlign = text.AlignCenter
-		default:
-			self.drawOpts.PrimaryAlign = text.AlignEnd
+		if !occlusion.EntityVisible(w, e) {
+			continue
 		}
-		offsetX, offsetY := AlignmentOffsets[txt.Alignment](txt.CachedWidth, txt.CachedHeight)
-		t.Offset = Point(V(offsetX, offsetY))
-		self.transform.SetFromComponent(t)
-		self.drawOpts.GeoM = self.transform.Matrix()
-		self.drawOpts.ColorScale = RGBAToColorScale(txt.Color)
-		text.Draw(rdr.screen, txt.Caption, self.fontFaceMap[e], self.drawOpts)
+		entityRenderSpace(w, e, rdr)
+		self.DrawEntity(w, e, rdr)
+	}
+	rdr.SetScreenSpace(false)
+}
+
+// SubmitDraws queues every text as a z-keyed draw command.
+func (self *TextSystem) SubmitDraws(w *teishoku.World, list *DrawCommandList) {
+	occlusion := GetOcclusionSet(w)
+	for _, e := range self.entities {
+		if !occlusion.EntityVisible(w, e) {
+			continue
+		}
+		t := teishoku.GetComponent[TransformComponent](w, e)
+		list.Submit(t.Z, self, e)
+	}
+}
+
+// DrawEntity draws a single text entity.
+func (self *TextSystem) DrawEntity(w *teishoku.World, e teishoku.Entity, rdr *BatchRenderer) {
+	rdr.Flush()
+	t, txt := teishoku.GetComponent2[TransformComponent, TextComponent](w, e)
+	self.drawOpts.LineSpacing = txt.LineSpacing
+	switch txt.Alignment {
+	case TextAlignmentTopRight, TextAlignmentMiddleRight, TextAlignmentBottomRight:
+		self.drawOpts.PrimaryAlign = text.AlignStart
+	case TextAlignmentTopCenter, TextAlignmentMiddleCenter, TextAlignmentBottomCenter:
+		self.drawOpts.PrimaryAlign = text.AlignCenter
+	default:
+		self.drawOpts.PrimaryAlign = text.AlignEnd
+	}
+	offsetX, offsetY := AlignmentOffsets[txt.Alignment](txt.CachedWidth, txt.CachedHeight)
+	t.Offset = Point(V(offsetX, offsetY))
+	setRenderTransform(w, e, self.transform, t)
+	self.drawOpts.GeoM = self.transform.Matrix()
+	if view, ok := rdr.View(); ok && !rdr.ScreenSpace() {
+		self.drawOpts.GeoM.Concat(view)
+	}
+	if txt.RichText {
+		self.drawRich(e, txt, self.drawOpts.GeoM, rdr.screen)
+		return
 	}
+	self.drawOpts.ColorScale = RGBAToColorScale(txt.Color)
+	text.Draw(rdr.screen, txt.Caption, self.fontFaceMap[e], self.drawOpts)
 }
 func (self *TextSystem) updateCache(txt *TextComponent, fontFace *text.GoTextFace) {
 	if txt.CachedText != txt.Caption {
@@ -82,9 +138,9 @@ func (self *TextSystem) updateCache(txt *TextComponent, fontFace *text.GoTextFac
 	}
 }
 func (self *TextSystem) getFontFace(fontID int, size float64) *text.GoTextFace {
+	source := self.fm.Get(fontID)
 	fontFace, ok := self.fontFaceMap[self.filter.Entity()]
-	if !ok {
-		source := self.fm.Get(fontID)
+	if !ok || fontFace.Source != source || fontFace.Size != size {
 		fontFace = &text.GoTextFace{
 			Source:    source,
 			Direction: text.DirectionLeftToRight,
@@ -0,0 +1,204 @@
+package katsu2d
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/edwinsyarief/teishoku"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+const (
+	// textWaveSpeed and textWavePhase shape the wave effect; its height is
+	// textWaveAmplitude of the font size.
+	textWaveSpeed     = 6.0
+	textWavePhase     = 0.6
+	textWaveAmplitude = 0.12
+	// textShakeRate is how often shaken glyphs jump per second, by up to
+	// textShakeAmplitude of the font size.
+	textShakeRate      = 30.0
+	textShakeAmplitude = 0.08
+)
+
+// richText caches the parsed and measured markup of a text entity.
+type richText struct {
+	caption string
+	face    *text.GoTextFace
+	lines   [][]richSpan
+	widths  []float64
+}
+
+type richSpan struct {
+	TextSpan
+	icon  *ebiten.Image
+	width float64
+}
+
+// updateRichCache reparses and measures a rich text when its caption or
+// face changed.
+func (self *TextSystem) updateRichCache(w *teishoku.World, e teishoku.Entity, txt *TextComponent, face *text.GoTextFace) {
+	rt := self.rich[e]
+	if rt != nil && rt.caption == txt.Caption && rt.face == face && txt.CachedText == txt.Caption {
+		return
+	}
+	if rt == nil {
+		rt = &richText{}
+		self.rich[e] = rt
+	}
+	rt.caption, rt.face = txt.Caption, face
+	rt.lines, rt.widths = rt.lines[:0], rt.widths[:0]
+	m := face.Metrics()
+	lineHeight := m.HAscent + m.HDescent
+	var line []richSpan
+	endLine := func() {
+		width := 0.0
+		for _, s := range line {
+			width += s.width
+		}
+		rt.lines = append(rt.lines, line)
+		rt.widths = append(rt.widths, width)
+		line = nil
+	}
+	for _, span := range ParseRichText(txt.Caption) {
+		if span.Icon != "" {
+			img, width := textIconImage(w, span.Icon, lineHeight)
+			line = append(line, richSpan{TextSpan: span, icon: img, width: width})
+			continue
+		}
+		for {
+			part, rest, found := cutLine(span.Text)
+			if part != "" {
+				s := span
+				s.Text = part
+				line = append(line, richSpan{TextSpan: s, width: text.Advance(part, face)})
+			}
+			if !found {
+				break
+			}
+			endLine()
+			span.Text = rest
+		}
+	}
+	endLine()
+
+	txt.CachedWidth = 0
+	for _, width := range rt.widths {
+		txt.CachedWidth = max(txt.CachedWidth, width)
+	}
+	txt.CachedHeight = float64(len(rt.lines)-1)*txt.LineSpacing + lineHeight
+	txt.CachedText = txt.Caption
+}
+
+func cutLine(s string) (string, string, bool) {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\n' {
+			return s[:i], s[i+1:], true
+		}
+	}
+	return s, "", false
+}
+
+// textIconImage returns the image of a registered icon and its width when
+// scaled to the line height.
+func textIconImage(w *teishoku.World, name string, lineHeight float64) (*ebiten.Image, float64) {
+	icon, ok := GetTextIcon(w, name)
+	tm := GetTextureManager(w)
+	if !ok || tm == nil {
+		return nil, 0
+	}
+	img := tm.Get(icon.TextureID)
+	if img == nil {
+		return nil, 0
+	}
+	if !IsBoundEmpty(icon.Bound) {
+		img = img.SubImage(image.Rect(int(icon.Bound.Min.X), int(icon.Bound.Min.Y),
+			int(icon.Bound.Max.X), int(icon.Bound.Max.Y))).(*ebiten.Image)
+	}
+	b := img.Bounds()
+	if b.Dy() == 0 {
+		return nil, 0
+	}
+	return img, float64(b.Dx()) * lineHeight / float64(b.Dy())
+}
+
+// drawRich draws a rich text glyph by glyph, with base placing the text's
+// origin on the screen.
+func (self *TextSystem) drawRich(e teishoku.Entity, txt *TextComponent, base ebiten.GeoM, screen *ebiten.Image) {
+	rt := self.rich[e]
+	if rt == nil {
+		return
+	}
+	face := rt.face
+	m := face.Metrics()
+	lineHeight := m.HAscent + m.HDescent
+	index := 0
+	for i, line := range rt.lines {
+		x := 0.0
+		switch self.drawOpts.PrimaryAlign {
+		case text.AlignCenter:
+			x = -rt.widths[i] / 2
+		case text.AlignEnd:
+			x = -rt.widths[i]
+		}
+		y := float64(i) * txt.LineSpacing
+		for _, s := range line {
+			col := txt.Color
+			if s.HasColor {
+				col = s.Color
+				col.A = uint8(int(col.A) * int(txt.Color.A) / 255)
+			}
+			if s.Icon != "" {
+				if s.icon != nil {
+					dx, dy := self.glyphEffect(s.Effects, index, face.Size)
+					b := s.icon.Bounds()
+					scale := lineHeight / float64(b.Dy())
+					self.glyphOpts.GeoM.Reset()
+					self.glyphOpts.GeoM.Scale(scale, scale)
+					self.glyphOpts.GeoM.Translate(x+dx, y+dy)
+					self.glyphOpts.GeoM.Concat(base)
+					self.glyphOpts.ColorScale = RGBAToColorScale(color.RGBA{R: 255, G: 255, B: 255, A: txt.Color.A})
+					screen.DrawImage(s.icon, &self.glyphOpts)
+				}
+				x += s.width
+				index++
+				continue
+			}
+			self.glyphOpts.ColorScale = RGBAToColorScale(col)
+			self.glyphs = text.AppendGlyphs(self.glyphs[:0], s.Text, face, nil)
+			for _, g := range self.glyphs {
+				if g.Image != nil {
+					dx, dy := self.glyphEffect(s.Effects, index, face.Size)
+					self.glyphOpts.GeoM.Reset()
+					self.glyphOpts.GeoM.Translate(x+g.X+dx, y+g.Y+dy)
+					self.glyphOpts.GeoM.Concat(base)
+					screen.DrawImage(g.Image, &self.glyphOpts)
+				}
+				index++
+			}
+			x += s.width
+		}
+	}
+	clear(self.glyphs)
+}
+
+// glyphEffect returns the offset of the glyph at index for effects.
+func (self *TextSystem) glyphEffect(effects TextEffect, index int, size float64) (float64, float64) {
+	var dx, dy float64
+	if effects&TextEffectWave != 0 {
+		dy += math.Sin(self.time*textWaveSpeed+float64(index)*textWavePhase) * size * textWaveAmplitude
+	}
+	if effects&TextEffectShake != 0 {
+		step := math.Floor(self.time * textShakeRate)
+		dx += (shakeNoise(float64(index), step) - 0.5) * 2 * size * textShakeAmplitude
+		dy += (shakeNoise(float64(index)+17, step) - 0.5) * 2 * size * textShakeAmplitude
+	}
+	return dx, dy
+}
+
+// shakeNoise is a cheap hash of (a, b) into [0, 1).
+func shakeNoise(a, b float64) float64 {
+	v := math.Sin(a*12.9898+b*78.233) * 43758.5453
+	return v - math.Floor(v)
+}
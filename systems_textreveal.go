@@ -0,0 +1,84 @@
+package katsu2d
+
+import "github.com/edwinsyarief/teishoku"
+
+// TextRevealSystem advances TextRevealComponents and writes the revealed
+// text into their TextComponent. Add it before the TextSystem.
+type TextRevealSystem struct {
+	filter *teishoku.Filter2[TextComponent, TextRevealComponent]
+}
+
+// NewTextRevealSystem creates a new TextRevealSystem.
+func NewTextRevealSystem() *TextRevealSystem {
+	return &TextRevealSystem{}
+}
+
+func (self *TextRevealSystem) Initialize(w *teishoku.World) {
+	self.filter = self.filter.New(w)
+}
+
+func (self *TextRevealSystem) Update(w *teishoku.World, dt float64) {
+	self.filter.Reset()
+	for self.filter.Next() {
+		txt, reveal := self.filter.Get()
+		e := self.filter.Entity()
+		reveal.tokenize(txt.RichText)
+		if reveal.done {
+			continue
+		}
+		before := reveal.revealed
+		if reveal.skip || reveal.Speed <= 0 {
+			reveal.revealed = len(reveal.tokens)
+		} else {
+			self.advance(w, e, reveal, ScaledDelta(w, e, dt))
+		}
+		if reveal.revealed != before || !reveal.written {
+			txt.Caption = reveal.caption()
+			reveal.written = true
+		}
+		if reveal.revealed == len(reveal.tokens) {
+			reveal.done, reveal.skip = true, false
+			Publish(w, TextRevealFinishedEvent{Entity: e})
+		}
+	}
+}
+
+// advance reveals as many characters as dt allows, stopping at pauses.
+func (self *TextRevealSystem) advance(w *teishoku.World, e teishoku.Entity, reveal *TextRevealComponent, dt float64) {
+	if reveal.pause > 0 {
+		reveal.pause -= dt
+		if reveal.pause > 0 {
+			return
+		}
+		// Carry the time left over from the pause into the reveal.
+		dt = -reveal.pause
+		reveal.pause = 0
+	}
+	reveal.elapsed += dt * reveal.Speed
+	for reveal.revealed < len(reveal.tokens) {
+		t := reveal.tokens[reveal.revealed]
+		switch {
+		case t.pause > 0:
+			reveal.revealed++
+			reveal.pause = t.pause - reveal.elapsed/reveal.Speed
+			reveal.elapsed = 0
+			if reveal.pause > 0 {
+				return
+			}
+			reveal.elapsed, reveal.pause = -reveal.pause*reveal.Speed, 0
+		case t.char == 0:
+			// Markup tags and zero pauses cost no time.
+			reveal.revealed++
+		default:
+			if reveal.elapsed < 1 {
+				return
+			}
+			reveal.elapsed--
+			reveal.revealed++
+			if reveal.OnChar != nil {
+				reveal.OnChar(w, e, t.char)
+			}
+		}
+	}
+	reveal.elapsed = 0
+}
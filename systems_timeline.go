@@ -0,0 +1,47 @@
+package katsu2d
+
+import "github.com/edwinsyarief/teishoku"
+
+type runningTimeline struct {
+	entity   teishoku.Entity
+	id       string
+	timeline *Timeline
+}
+
+// TimelineSystem plays the timelines of TimelineComponent entities with
+// their ScaledDelta.
+type TimelineSystem struct {
+	filter  *teishoku.Filter[TimelineComponent]
+	running []runningTimeline
+}
+
+// NewTimelineSystem creates a new TimelineSystem.
+func NewTimelineSystem() *TimelineSystem {
+	return &TimelineSystem{}
+}
+
+func (self *TimelineSystem) Initialize(w *teishoku.World) {
+	self.filter = self.filter.New(w)
+}
+
+func (self *TimelineSystem) Update(w *teishoku.World, dt float64) {
+	// Timelines run after the pass, as their actions may add components.
+	self.running = self.running[:0]
+	self.filter.Reset()
+	for self.filter.Next() {
+		tc := self.filter.Get()
+		if tc.Timeline == nil || tc.Timeline.Done() || tc.Timeline.Paused() {
+			continue
+		}
+		self.running = append(self.running, runningTimeline{entity: self.filter.Entity(), id: tc.ID, timeline: tc.Timeline})
+	}
+	for _, r := range self.running {
+		if !IsAlive(w, r.entity) {
+			continue
+		}
+		if r.timeline.Update(w, ScaledDelta(w, r.entity, dt)) {
+			Publish(w, TimelineFinishedEvent{Entity: r.entity, ID: r.id})
+		}
+	}
+	clear(self.running)
+}
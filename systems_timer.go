@@ -21,7 +21,7 @@ func (self *TimerSystem) Update(w *teishoku.World, dt float64) {
 	for self.filter.Next() {
 		t := self.filter.Get()
 		if t.State == TimerStateActive {
-			t.Time -= dt
+			t.Time -= ScaledDelta(w, self.filter.Entity(), dt)
 
 			if t.Time <= 0 {
 				t.Time = 0
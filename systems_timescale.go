@@ -0,0 +1,66 @@
+package katsu2d
+
+import "github.com/edwinsyarief/teishoku"
+
+// TimeScaleRegionSystem computes the effective local time scale of every
+// entity with a LocalTimeComponent. Register it before the systems that
+// read ScaledDelta so they see this tick's values.
+type TimeScaleRegionSystem struct {
+	regions  *teishoku.Filter2[TransformComponent, TimeScaleRegionComponent]
+	entities *teishoku.Filter2[TransformComponent, LocalTimeComponent]
+	centers  []Vector
+	shapes   []TimeScaleRegionComponent
+}
+
+// NewTimeScaleRegionSystem creates a new TimeScaleRegionSystem.
+func NewTimeScaleRegionSystem() *TimeScaleRegionSystem {
+	return &TimeScaleRegionSystem{}
+}
+
+func (self *TimeScaleRegionSystem) Initialize(w *teishoku.World) {
+	self.regions = self.regions.New(w)
+	self.entities = self.entities.New(w)
+}
+
+// Update multiplies each entity's base scale by every region containing it.
+func (self *TimeScaleRegionSystem) Update(w *teishoku.World, dt float64) {
+	self.centers = self.centers[:0]
+	self.shapes = self.shapes[:0]
+	self.regions.Reset()
+	for self.regions.Next() {
+		t, r := self.regions.Get()
+		self.centers = append(self.centers, Vector(t.Position))
+		self.shapes = append(self.shapes, *r)
+	}
+
+	self.entities.Reset()
+	for self.entities.Next() {
+		t, lt := self.entities.Get()
+		scale := lt.Base
+		if scale == 0 {
+			scale = 1
+		}
+		pos := Vector(t.Position)
+		for i := range self.shapes {
+			if self.shapes[i].Contains(self.centers[i], pos) {
+				scale *= self.shapes[i].Scale
+			}
+		}
+		lt.Scale = scale
+		lt.computed = true
+	}
+}
+
+// ScaledDelta returns dt scaled by the entity's local time, or dt unchanged
+// if the entity has not opted in with a LocalTimeComponent. It is zero while
+// the entity is held by a hit-stop.
+func ScaledDelta(w *teishoku.World, e teishoku.Entity, dt float64) float64 {
+	if hs := GetResource[HitStops](w); hs != nil && hs.Frozen(e) {
+		return 0
+	}
+	lt := teishoku.GetComponent[LocalTimeComponent](w, e)
+	if lt == nil {
+		return dt
+	}
+	return dt * lt.Effective()
+}
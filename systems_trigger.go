@@ -0,0 +1,81 @@
+package katsu2d
+
+import "github.com/edwinsyarief/teishoku"
+
+type triggerPair struct {
+	zone, entity teishoku.Entity
+}
+
+// TriggerZoneSystem tests the centers of enabled colliders against every
+// TriggerZoneComponent and publishes the enter and exit events. Zones only
+// see colliders on their own elevation level. Add it after the systems
+// moving the colliders.
+type TriggerZoneSystem struct {
+	zones     *teishoku.Filter2[TransformComponent, TriggerZoneComponent]
+	colliders *teishoku.Filter2[TransformComponent, ColliderComponent]
+	inside    map[triggerPair]bool
+	centers   []Vector
+	entities  []teishoku.Entity
+}
+
+// NewTriggerZoneSystem creates a new TriggerZoneSystem.
+func NewTriggerZoneSystem() *TriggerZoneSystem {
+	return &TriggerZoneSystem{inside: make(map[triggerPair]bool)}
+}
+
+func (self *TriggerZoneSystem) Initialize(w *teishoku.World) {
+	self.zones = self.zones.New(w)
+	self.colliders = self.colliders.New(w)
+}
+
+func (self *TriggerZoneSystem) Update(w *teishoku.World, dt float64) {
+	self.centers = self.centers[:0]
+	self.entities = self.entities[:0]
+	self.colliders.Reset()
+	for self.colliders.Next() {
+		t, c := self.colliders.Get()
+		if c.Disabled {
+			continue
+		}
+		self.centers = append(self.centers, Vector(t.Position).Add(c.Offset))
+		self.entities = append(self.entities, self.colliders.Entity())
+	}
+
+	// Pairs still marked false after the pass have left their zone.
+	for pair := range self.inside {
+		self.inside[pair] = false
+	}
+	self.zones.Reset()
+	for self.zones.Next() {
+		t, zone := self.zones.Get()
+		if zone.Disabled {
+			continue
+		}
+		ze, position := self.zones.Entity(), Vector(t.Position)
+		bounds := zone.Bounds(position)
+		for i, center := range self.centers {
+			if self.entities[i] == ze || !bounds.Contains(center) || !zone.Contains(position, center) ||
+				!SameElevation(w, ze, self.entities[i]) {
+				continue
+			}
+			pair := triggerPair{zone: ze, entity: self.entities[i]}
+			if _, ok := self.inside[pair]; !ok {
+				Publish(w, TriggerEnterEvent{Zone: ze, Entity: pair.entity, Name: zone.Name})
+			}
+			self.inside[pair] = true
+		}
+	}
+	for pair, inside := range self.inside {
+		if inside {
+			continue
+		}
+		delete(self.inside, pair)
+		name := ""
+		if IsAlive(w, pair.zone) {
+			if zone := teishoku.GetComponent[TriggerZoneComponent](w, pair.zone); zone != nil {
+				name = zone.Name
+			}
+		}
+		Publish(w, TriggerExitEvent{Zone: pair.zone, Entity: pair.entity, Name: name})
+	}
+}
@@ -5,7 +5,10 @@ import (
 )
 
 type TweenSystem struct {
-	filter *teishoku.Filter[TweenComponent]
+	filter      *teishoku.Filter[TweenComponent]
+	juiceFilter *teishoku.Filter[JuiceComponent]
+	sequences   *teishoku.Filter[TweenSequenceComponent]
+	finished    []TweenFinishedEvent
 }
 
 func NewTweenSystem() *TweenSystem {
@@ -14,6 +17,8 @@ func NewTweenSystem() *TweenSystem {
 
 func (self *TweenSystem) Initialize(w *teishoku.World) {
 	self.filter = self.filter.New(w)
+	self.juiceFilter = self.juiceFilter.New(w)
+	self.sequences = self.sequences.New(w)
 }
 
 func (self *TweenSystem) Update(w *teishoku.World, dt float64) {
@@ -25,7 +30,7 @@ func (self *TweenSystem) Update(w *teishoku.World, dt float64) {
 		}
 
 		tw.Current = tw.Start
-		tw.Time += dt
+		tw.Time += ScaledDelta(w, self.filter.Entity(), dt)
 
 		if tw.Time < tw.Delay {
 			continue
@@ -45,4 +50,30 @@ func (self *TweenSystem) Update(w *teishoku.World, dt float64) {
 
 		tw.Current = EaseTypes[float64](tw.EaseType)(tw.Time-tw.Delay, tw.Start, tw.End-tw.Start, tw.Duration)
 	}
+
+	self.juiceFilter.Reset()
+	for self.juiceFilter.Next() {
+		j := self.juiceFilter.Get()
+		if j.Active() {
+			j.advance(ScaledDelta(w, self.juiceFilter.Entity(), dt))
+		}
+	}
+
+	// Events are published after the pass, as handlers may change the
+	// entities being iterated.
+	self.finished = self.finished[:0]
+	self.sequences.Reset()
+	for self.sequences.Next() {
+		ts := self.sequences.Get()
+		if ts.Sequence == nil || ts.Sequence.Done() {
+			continue
+		}
+		e := self.sequences.Entity()
+		if _, done := ts.Sequence.Update(ScaledDelta(w, e, dt)); done {
+			self.finished = append(self.finished, TweenFinishedEvent{Entity: e, ID: ts.ID})
+		}
+	}
+	for _, event := range self.finished {
+		Publish(w, event)
+	}
 }
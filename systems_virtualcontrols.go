@@ -0,0 +1,219 @@
+package katsu2d
+
+import (
+	"image/color"
+
+	"github.com/edwinsyarief/teishoku"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// VirtualStick is an on-screen joystick. Its axes are bound with
+// NewAxisBinding(VirtualStickX(n)) and NewAxisBinding(VirtualStickY(n)),
+// where n is its index in the VirtualControlsSystem.
+type VirtualStick struct {
+	// Center is the resting position of the stick in screen pixels.
+	Center Vector
+	// Radius is how far the knob travels for a full deflection.
+	Radius float64
+	// Area, when not empty, is where a touch grabs the stick; the stick
+	// then floats to where the touch started. Otherwise the touch must
+	// start within Radius of Center.
+	Area Rectangle
+	// DeadZone is the fraction of Radius ignored around the center.
+	DeadZone float64
+
+	id     int
+	active bool
+	origin Vector
+	value  Vector
+}
+
+// Value returns the stick deflection, with a length of at most 1.
+func (self *VirtualStick) Value() Vector {
+	return self.value
+}
+
+// VirtualButtonControl is an on-screen button driving Button while a touch
+// is inside it.
+type VirtualButtonControl struct {
+	Button VirtualButton
+	Center Vector
+	Radius float64
+
+	pressed bool
+}
+
+// VirtualControlsSystem shows on-screen joysticks and buttons for touch
+// screens and feeds them to the input bindings. Add it before the
+// InputSystem, and as an overlay so it draws on top. Touches grabbed by a
+// control are claimed and no longer count as touch gestures.
+type VirtualControlsSystem struct {
+	Sticks  []*VirtualStick
+	Buttons []*VirtualButtonControl
+	// Visible draws the controls; they work either way.
+	Visible bool
+	// OnlyWhenTouched hides the controls until the first touch, so they
+	// stay out of the way with a mouse and keyboard.
+	OnlyWhenTouched bool
+	Color           color.RGBA
+
+	touched bool
+	base    *CircleShape
+	knob    *CircleShape
+}
+
+// virtualControlRadius is the radius the control shapes are built at before
+// scaling, large enough for a smooth outline.
+const virtualControlRadius = 64
+
+// NewVirtualControlsSystem creates a new VirtualControlsSystem.
+func NewVirtualControlsSystem() *VirtualControlsSystem {
+	return &VirtualControlsSystem{
+		Visible:         true,
+		OnlyWhenTouched: true,
+		Color:           color.RGBA{255, 255, 255, 96},
+		base:            NewCircleShape(virtualControlRadius, color.RGBA{255, 255, 255, 255}),
+		knob:            NewCircleShape(virtualControlRadius, color.RGBA{255, 255, 255, 255}),
+	}
+}
+
+// AddStick adds a joystick and returns its index for VirtualStickX and
+// VirtualStickY.
+func (self *VirtualControlsSystem) AddStick(stick *VirtualStick) int {
+	self.Sticks = append(self.Sticks, stick)
+	return len(self.Sticks) - 1
+}
+
+// AddButton adds an on-screen button.
+func (self *VirtualControlsSystem) AddButton(button *VirtualButtonControl) {
+	self.Buttons = append(self.Buttons, button)
+}
+
+func (self *VirtualControlsSystem) Initialize(w *teishoku.World) {}
+
+func (self *VirtualControlsSystem) Update(w *teishoku.World, dt float64) {
+	touchInput.update(dt)
+	clear(virtualInput.prevButtons)
+	for button, down := range virtualInput.buttons {
+		virtualInput.prevButtons[button] = down
+	}
+	clear(virtualInput.buttons)
+	touches := touchInput.touches
+	if len(touches) > 0 {
+		self.touched = true
+	}
+
+	for i, stick := range self.Sticks {
+		self.updateStick(stick, touches)
+		virtualInput.axes[VirtualStickX(i)] = stick.value.X
+		virtualInput.axes[VirtualStickY(i)] = stick.value.Y
+	}
+	for _, button := range self.Buttons {
+		button.pressed = false
+		for j := range touches {
+			t := &touches[j]
+			if t.JustReleased || t.Position.DistanceTo(button.Center) > button.Radius {
+				continue
+			}
+			t.Claimed = true
+			button.pressed = true
+		}
+		if button.pressed {
+			virtualInput.buttons[button.Button] = true
+		}
+	}
+}
+
+func (self *VirtualControlsSystem) updateStick(stick *VirtualStick, touches []Touch) {
+	// Keep the touch that grabbed the stick until it is released.
+	if stick.active {
+		stick.active = false
+		for j := range touches {
+			if int(touches[j].ID) == stick.id && !touches[j].JustReleased {
+				touches[j].Claimed = true
+				stick.active = true
+				stick.value = stickValue(stick, touches[j].Position)
+				break
+			}
+		}
+		if !stick.active {
+			stick.value = Vector{}
+		}
+		return
+	}
+	for j := range touches {
+		t := &touches[j]
+		if !t.JustPressed || t.Claimed || !stick.grabs(t.Start) {
+			continue
+		}
+		t.Claimed = true
+		stick.active, stick.id = true, int(t.ID)
+		stick.origin = stick.Center
+		if !stick.Area.IsEmpty() {
+			stick.origin = t.Start
+		}
+		stick.value = stickValue(stick, t.Position)
+		return
+	}
+}
+
+func (self *VirtualStick) grabs(p Vector) bool {
+	if !self.Area.IsEmpty() {
+		return self.Area.Contains(p)
+	}
+	return p.DistanceTo(self.Center) <= self.Radius
+}
+
+// stickValue maps a touch position to a deflection with a dead zone.
+func stickValue(stick *VirtualStick, p Vector) Vector {
+	if stick.Radius <= 0 {
+		return Vector{}
+	}
+	v := p.Sub(stick.origin).DivF(stick.Radius).ClampLength(1)
+	length := v.Length()
+	if length <= stick.DeadZone {
+		return Vector{}
+	}
+	return v.ScaleF((length - stick.DeadZone) / (1 - stick.DeadZone) / length)
+}
+
+func (self *VirtualControlsSystem) Draw(w *teishoku.World, rdr *BatchRenderer) {
+	if !self.Visible || (self.OnlyWhenTouched && !self.touched) {
+		return
+	}
+	img := GetTextureManager(w).Get(0)
+	screenSpace := rdr.ScreenSpace()
+	rdr.SetScreenSpace(true)
+	for _, stick := range self.Sticks {
+		center := stick.Center
+		if stick.active {
+			center = stick.origin
+		}
+		self.drawCircle(rdr, self.base, center, stick.Radius, self.Color, img)
+		self.drawCircle(rdr, self.knob, center.Add(stick.value.ScaleF(stick.Radius)), stick.Radius*0.4, self.Color, img)
+	}
+	for _, button := range self.Buttons {
+		col := self.Color
+		if button.pressed {
+			col.A = uint8(min(int(col.A)*2, 255))
+		}
+		self.drawCircle(rdr, self.base, button.Center, button.Radius, col, img)
+	}
+	rdr.SetScreenSpace(screenSpace)
+}
+
+// drawCircle draws a control shape scaled to radius at center.
+func (self *VirtualControlsSystem) drawCircle(rdr *BatchRenderer, shape *CircleShape, center Vector, radius float64, col color.RGBA, img *ebiten.Image) {
+	vertices := shape.GetVertices()
+	out := rdr.Scratch(len(vertices))
+	r, g, b, a := float32(col.R)/255, float32(col.G)/255, float32(col.B)/255, float32(col.A)/255
+	scale := float32(radius / virtualControlRadius)
+	for i, v := range vertices {
+		// Shapes span 0 to twice their radius.
+		v.DstX = float32(center.X) + (v.DstX-virtualControlRadius)*scale
+		v.DstY = float32(center.Y) + (v.DstY-virtualControlRadius)*scale
+		v.ColorR, v.ColorG, v.ColorB, v.ColorA = v.ColorR*r, v.ColorG*g, v.ColorB*b, v.ColorA*a
+		out[i] = v
+	}
+	rdr.AddCustomMeshes(out, shape.GetIndices(), img)
+}
@@ -0,0 +1,46 @@
+package katsu2d
+
+import "github.com/edwinsyarief/teishoku"
+
+// WorldWrapSystem wraps the positions of WorldWrapComponent entities at the
+// world's wrap bounds. Add it after movement systems.
+type WorldWrapSystem struct {
+	filter *teishoku.Filter2[TransformComponent, WorldWrapComponent]
+}
+
+// NewWorldWrapSystem creates a new WorldWrapSystem.
+func NewWorldWrapSystem() *WorldWrapSystem {
+	return &WorldWrapSystem{}
+}
+
+func (self *WorldWrapSystem) Initialize(w *teishoku.World) {
+	self.filter = self.filter.New(w)
+}
+
+func (self *WorldWrapSystem) Update(w *teishoku.World, dt float64) {
+	ww := GetWorldWrap(w)
+	if ww == nil {
+		return
+	}
+	self.filter.Reset()
+	for self.filter.Next() {
+		t, _ := self.filter.Get()
+		t.Position = Point(ww.Wrap(Vector(t.Position)))
+	}
+}
+
+// wrapGhostOffsets returns where e should be drawn relative to its position,
+// including seam duplicates when the world wraps.
+func wrapGhostOffsets(w *teishoku.World, e teishoku.Entity, pos Vector, dst []Vector) []Vector {
+	dst = dst[:0]
+	ww := GetWorldWrap(w)
+	wc := teishoku.GetComponent[WorldWrapComponent](w, e)
+	if ww == nil || wc == nil {
+		return append(dst, Vector{})
+	}
+	radius := wc.Radius
+	if radius <= 0 {
+		radius = ww.Margin
+	}
+	return ww.GhostOffsets(dst, pos, radius)
+}
@@ -0,0 +1,188 @@
+package katsu2d
+
+import "github.com/edwinsyarief/teishoku"
+
+// Attitude describes how one team treats another.
+type Attitude int
+
+const (
+	AttitudeNeutral Attitude = iota
+	AttitudeFriendly
+	AttitudeHostile
+)
+
+// maxOwnerDepth guards owner chains against cycles.
+const maxOwnerDepth = 16
+
+// TeamRelations stores attitudes between teams. By default a team is friendly
+// to itself and hostile to every other non-neutral team.
+type TeamRelations struct {
+	overrides map[[2]Team]Attitude
+}
+
+// NewTeamRelations creates relations with the default attitudes.
+func NewTeamRelations() *TeamRelations {
+	return &TeamRelations{overrides: make(map[[2]Team]Attitude)}
+}
+
+// GetTeamRelations returns the world's team relations, creating them on first use.
+func GetTeamRelations(w *teishoku.World) *TeamRelations {
+	return EnsureResource(w, NewTeamRelations)
+}
+
+// SetAttitude sets the attitude between two teams in both directions.
+func (self *TeamRelations) SetAttitude(a, b Team, attitude Attitude) {
+	self.overrides[teamPair(a, b)] = attitude
+}
+
+// Attitude returns how team a treats team b.
+func (self *TeamRelations) Attitude(a, b Team) Attitude {
+	if att, ok := self.overrides[teamPair(a, b)]; ok {
+		return att
+	}
+	switch {
+	case a == TeamNeutral || b == TeamNeutral:
+		return AttitudeNeutral
+	case a == b:
+		return AttitudeFriendly
+	}
+	return AttitudeHostile
+}
+
+func teamPair(a, b Team) [2]Team {
+	if a > b {
+		a, b = b, a
+	}
+	return [2]Team{a, b}
+}
+
+// OwnerOf returns the owner of e if it is set and still alive.
+func OwnerOf(w *teishoku.World, e teishoku.Entity) (teishoku.Entity, bool) {
+	owner := teishoku.GetComponent[OwnerComponent](w, e)
+	if owner == nil || !w.IsValid(owner.Owner) {
+		return teishoku.Entity{}, false
+	}
+	return owner.Owner, true
+}
+
+// RootOwner follows the owner chain of e and returns the top-most live owner,
+// or e itself if it has none.
+func RootOwner(w *teishoku.World, e teishoku.Entity) teishoku.Entity {
+	for range maxOwnerDepth {
+		owner, ok := OwnerOf(w, e)
+		if !ok {
+			break
+		}
+		e = owner
+	}
+	return e
+}
+
+// IsOwnedBy reports whether owner appears in e's owner chain.
+func IsOwnedBy(w *teishoku.World, e, owner teishoku.Entity) bool {
+	for range maxOwnerDepth {
+		next, ok := OwnerOf(w, e)
+		if !ok {
+			return false
+		}
+		if next == owner {
+			return true
+		}
+		e = next
+	}
+	return false
+}
+
+// TeamOf returns the team of e, falling back to its owners' team.
+func TeamOf(w *teishoku.World, e teishoku.Entity) Team {
+	for range maxOwnerDepth {
+		if team := teishoku.GetComponent[TeamComponent](w, e); team != nil {
+			return team.Team
+		}
+		owner, ok := OwnerOf(w, e)
+		if !ok {
+			break
+		}
+		e = owner
+	}
+	return TeamNeutral
+}
+
+// AttitudeBetween returns how entity a treats entity b. An entity is always
+// friendly to itself and to anything it owns.
+func AttitudeBetween(w *teishoku.World, a, b teishoku.Entity) Attitude {
+	if a == b || RootOwner(w, a) == RootOwner(w, b) {
+		return AttitudeFriendly
+	}
+	return GetTeamRelations(w).Attitude(TeamOf(w, a), TeamOf(w, b))
+}
+
+// IsFriend reports whether a and b are friendly.
+func IsFriend(w *teishoku.World, a, b teishoku.Entity) bool {
+	return AttitudeBetween(w, a, b) == AttitudeFriendly
+}
+
+// IsFoe reports whether a and b are hostile.
+func IsFoe(w *teishoku.World, a, b teishoku.Entity) bool {
+	return AttitudeBetween(w, a, b) == AttitudeHostile
+}
+
+// TeamFilter iterates entities whose TeamComponent matches a predicate.
+type TeamFilter struct {
+	filter *teishoku.Filter[TeamComponent]
+	match  func(Team) bool
+}
+
+// NewTeamFilter creates a filter over entities whose team satisfies match.
+func NewTeamFilter(w *teishoku.World, match func(Team) bool) *TeamFilter {
+	var f *teishoku.Filter[TeamComponent]
+	return &TeamFilter{filter: f.New(w), match: match}
+}
+
+// InTeam matches a single team.
+func InTeam(team Team) func(Team) bool {
+	return func(t Team) bool { return t == team }
+}
+
+// FriendsOf matches teams friendly to team.
+func FriendsOf(w *teishoku.World, team Team) func(Team) bool {
+	relations := GetTeamRelations(w)
+	return func(t Team) bool { return relations.Attitude(team, t) == AttitudeFriendly }
+}
+
+// FoesOf matches teams hostile to team.
+func FoesOf(w *teishoku.World, team Team) func(Team) bool {
+	relations := GetTeamRelations(w)
+	return func(t Team) bool { return relations.Attitude(team, t) == AttitudeHostile }
+}
+
+func (self *TeamFilter) Reset() {
+	self.filter.Reset()
+}
+
+// Next advances to the next matching entity.
+func (self *TeamFilter) Next() bool {
+	for self.filter.Next() {
+		if self.match == nil || self.match(self.filter.Get().Team) {
+			return true
+		}
+	}
+	return false
+}
+
+func (self *TeamFilter) Entity() teishoku.Entity {
+	return self.filter.Entity()
+}
+
+func (self *TeamFilter) Get() *TeamComponent {
+	return self.filter.Get()
+}
+
+// Entities appends all matching entities to dst.
+func (self *TeamFilter) Entities(dst []teishoku.Entity) []teishoku.Entity {
+	self.Reset()
+	for self.Next() {
+		dst = append(dst, self.Entity())
+	}
+	return dst
+}
@@ -0,0 +1,163 @@
+package katsu2d
+
+import (
+	"image/color"
+	"strings"
+
+	"github.com/edwinsyarief/katsu2d/colors"
+	"github.com/edwinsyarief/teishoku"
+)
+
+// TextEffect animates the glyphs of a rich text span.
+type TextEffect uint8
+
+const (
+	// TextEffectWave bobs glyphs up and down in a travelling wave.
+	TextEffectWave TextEffect = 1 << iota
+	// TextEffectShake jitters glyphs randomly.
+	TextEffectShake
+)
+
+// TextSpan is a run of rich text sharing one style, or a single inline
+// icon when Icon is set.
+type TextSpan struct {
+	Text     string
+	Icon     string
+	Color    color.RGBA
+	HasColor bool
+	Effects  TextEffect
+}
+
+// ParseRichText splits markup into styled spans. It understands
+// [color=#rrggbb]…[/color], [icon=name], [wave]…[/wave] and
+// [shake]…[/shake]; "[[" is a literal bracket and unknown tags are kept as
+// text.
+func ParseRichText(markup string) []TextSpan {
+	var spans []TextSpan
+	var colorStack []color.RGBA
+	var wave, shake int
+	var sb strings.Builder
+	style := func() TextSpan {
+		var s TextSpan
+		if len(colorStack) > 0 {
+			s.Color, s.HasColor = colorStack[len(colorStack)-1], true
+		}
+		if wave > 0 {
+			s.Effects |= TextEffectWave
+		}
+		if shake > 0 {
+			s.Effects |= TextEffectShake
+		}
+		return s
+	}
+	flush := func() {
+		if sb.Len() == 0 {
+			return
+		}
+		s := style()
+		s.Text = sb.String()
+		sb.Reset()
+		if n := len(spans); n > 0 && spans[n-1].Icon == "" && sameTextStyle(spans[n-1], s) {
+			spans[n-1].Text += s.Text
+			return
+		}
+		spans = append(spans, s)
+	}
+	for i := 0; i < len(markup); i++ {
+		c := markup[i]
+		if c != '[' {
+			sb.WriteByte(c)
+			continue
+		}
+		if i+1 < len(markup) && markup[i+1] == '[' {
+			sb.WriteByte('[')
+			i++
+			continue
+		}
+		end := strings.IndexByte(markup[i:], ']')
+		if end < 0 {
+			sb.WriteString(markup[i:])
+			break
+		}
+		tag := markup[i+1 : i+end]
+		handled := true
+		flush()
+		switch {
+		case strings.HasPrefix(tag, "color="):
+			col, err := colors.Hex(tag[len("color="):])
+			if err != nil {
+				handled = false
+				break
+			}
+			colorStack = append(colorStack, col)
+		case tag == "/color":
+			if len(colorStack) > 0 {
+				colorStack = colorStack[:len(colorStack)-1]
+			}
+		case strings.HasPrefix(tag, "icon="):
+			s := style()
+			s.Icon = tag[len("icon="):]
+			spans = append(spans, s)
+		case tag == "wave":
+			wave++
+		case tag == "/wave":
+			wave = max(wave-1, 0)
+		case tag == "shake":
+			shake++
+		case tag == "/shake":
+			shake = max(shake-1, 0)
+		default:
+			handled = false
+		}
+		if !handled {
+			sb.WriteString(markup[i : i+end+1])
+		}
+		i += end
+	}
+	flush()
+	return spans
+}
+
+func sameTextStyle(a, b TextSpan) bool {
+	return a.Color == b.Color && a.HasColor == b.HasColor && a.Effects == b.Effects
+}
+
+// StripRichText returns the text of markup without tags or icons.
+func StripRichText(markup string) string {
+	var sb strings.Builder
+	for _, s := range ParseRichText(markup) {
+		sb.WriteString(s.Text)
+	}
+	return sb.String()
+}
+
+// TextIcon is an image that rich text can inline with [icon=name].
+type TextIcon struct {
+	TextureID int
+	// Bound is the icon's source rectangle; empty uses the whole texture.
+	Bound Bound
+}
+
+// textIcons is the world resource holding the registered icons.
+type textIcons struct {
+	icons map[string]TextIcon
+}
+
+// RegisterTextIcon makes a texture region available to rich text as
+// [icon=name]. Icons are scaled to the line height.
+func RegisterTextIcon(w *teishoku.World, name string, textureID int, bound Bound) {
+	icons := EnsureResource(w, func() *textIcons {
+		return &textIcons{icons: make(map[string]TextIcon)}
+	})
+	icons.icons[name] = TextIcon{TextureID: textureID, Bound: bound}
+}
+
+// GetTextIcon returns the icon registered under name.
+func GetTextIcon(w *teishoku.World, name string) (TextIcon, bool) {
+	icons := GetResource[textIcons](w)
+	if icons == nil {
+		return TextIcon{}, false
+	}
+	icon, ok := icons.icons[name]
+	return icon, ok
+}
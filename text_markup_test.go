@@ -0,0 +1,63 @@
+package katsu2d
+
+import (
+	"image/color"
+	"reflect"
+	"testing"
+)
+
+// TestParseRichText verifies nesting, unclosed and unknown tags, and
+// escapes in rich text markup.
+func TestParseRichText(t *testing.T) {
+	red := color.RGBA{R: 255, A: 255}
+	blue := color.RGBA{B: 255, A: 255}
+	cases := []struct {
+		name   string
+		markup string
+		want   []TextSpan
+	}{
+		{"plain", "hello", []TextSpan{{Text: "hello"}}},
+		{"color", "a[color=#ff0000]b[/color]c", []TextSpan{
+			{Text: "a"},
+			{Text: "b", Color: red, HasColor: true},
+			{Text: "c"},
+		}},
+		{"nested colors", "[color=#f00]a[color=#00f]b[/color]c[/color]", []TextSpan{
+			{Text: "a", Color: red, HasColor: true},
+			{Text: "b", Color: blue, HasColor: true},
+			{Text: "c", Color: red, HasColor: true},
+		}},
+		{"nested effects", "[wave]a[shake]b[/wave]c[/shake]", []TextSpan{
+			{Text: "a", Effects: TextEffectWave},
+			{Text: "b", Effects: TextEffectWave | TextEffectShake},
+			{Text: "c", Effects: TextEffectShake},
+		}},
+		{"icon takes the style", "[color=#f00]x[icon=coin]y", []TextSpan{
+			{Text: "x", Color: red, HasColor: true},
+			{Icon: "coin", Color: red, HasColor: true},
+			{Text: "y", Color: red, HasColor: true},
+		}},
+		{"unclosed tag runs to the end", "[wave]abc", []TextSpan{
+			{Text: "abc", Effects: TextEffectWave},
+		}},
+		{"stray close tags", "a[/color][/wave]b", []TextSpan{{Text: "ab"}}},
+		{"unterminated bracket", "a[color=#f00", []TextSpan{{Text: "a[color=#f00"}}},
+		{"unknown tag", "a[b]c[/b]", []TextSpan{{Text: "a[b]c[/b]"}}},
+		{"bad color", "[color=nope]a", []TextSpan{{Text: "[color=nope]a"}}},
+		{"escape", "[[wave]] [[", []TextSpan{{Text: "[wave]] ["}}},
+		{"escape inside tag", "[wave][[x][/wave]", []TextSpan{{Text: "[x]", Effects: TextEffectWave}}},
+		{"empty", "", nil},
+	}
+	for _, c := range cases {
+		if got := ParseRichText(c.markup); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: ParseRichText(%q) = %+v, want %+v", c.name, c.markup, got, c.want)
+		}
+	}
+}
+
+// TestStripRichText verifies that stripping keeps only the text.
+func TestStripRichText(t *testing.T) {
+	if got := StripRichText("[color=#fff]Gold[/color] x[icon=coin]3 [[ok]"); got != "Gold x3 [ok]" {
+		t.Fatalf("StripRichText = %q", got)
+	}
+}
@@ -0,0 +1,165 @@
+package katsu2d
+
+import (
+	"cmp"
+	"runtime"
+	"slices"
+	"sync"
+	"weak"
+
+	"github.com/edwinsyarief/katsu2d/logger"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// bytesPerPixel is the GPU size of an RGBA texel.
+const bytesPerPixel = 4
+
+// RenderTargetInfo describes one tracked render target.
+type RenderTargetInfo struct {
+	Label         string
+	Width, Height int
+	Bytes         int64
+}
+
+// TextureMemoryStats estimates the GPU memory held by textures.
+type TextureMemoryStats struct {
+	// Textures is held by the TextureManager: loaded and derived textures,
+	// or its atlas pages when atlases are on.
+	Textures int64
+	// RenderTargets is held by offscreen buffers of the engine, layers,
+	// lightmaps, post-processing and images passed to TrackRenderTarget.
+	RenderTargets int64
+	// Budget is the configured limit, 0 when none is set.
+	Budget int64
+}
+
+// Total returns the estimated bytes of all textures.
+func (self TextureMemoryStats) Total() int64 {
+	return self.Textures + self.RenderTargets
+}
+
+// renderTargets counts the offscreen images in use. Like Ebitengine's images
+// they are shared by the whole game. Images are held weakly, so a dropped
+// scene's buffers can still be garbage collected; they are untracked when
+// that happens.
+var renderTargets = struct {
+	mu     sync.Mutex
+	images map[weak.Pointer[ebiten.Image]]RenderTargetInfo
+	bytes  int64
+}{images: make(map[weak.Pointer[ebiten.Image]]RenderTargetInfo)}
+
+// TrackRenderTarget counts img as a render target named label in the
+// texture memory stats until UntrackRenderTarget or until img is garbage
+// collected.
+func TrackRenderTarget(img *ebiten.Image, label string) {
+	size := img.Bounds().Size()
+	info := RenderTargetInfo{Label: label, Width: size.X, Height: size.Y, Bytes: imageBytes(img)}
+	key := weak.Make(img)
+	renderTargets.mu.Lock()
+	defer renderTargets.mu.Unlock()
+	old, tracked := renderTargets.images[key]
+	renderTargets.bytes += info.Bytes - old.Bytes
+	renderTargets.images[key] = info
+	if !tracked {
+		runtime.AddCleanup(img, untrackRenderTarget, key)
+	}
+}
+
+// UntrackRenderTarget stops counting img.
+func UntrackRenderTarget(img *ebiten.Image) {
+	untrackRenderTarget(weak.Make(img))
+}
+
+func untrackRenderTarget(key weak.Pointer[ebiten.Image]) {
+	renderTargets.mu.Lock()
+	defer renderTargets.mu.Unlock()
+	renderTargets.bytes -= renderTargets.images[key].Bytes
+	delete(renderTargets.images, key)
+}
+
+// RenderTargets returns the tracked render targets, largest first.
+func RenderTargets() []RenderTargetInfo {
+	renderTargets.mu.Lock()
+	defer renderTargets.mu.Unlock()
+	infos := make([]RenderTargetInfo, 0, len(renderTargets.images))
+	for _, info := range renderTargets.images {
+		infos = append(infos, info)
+	}
+	slices.SortFunc(infos, func(a, b RenderTargetInfo) int {
+		return cmp.Or(cmp.Compare(b.Bytes, a.Bytes), cmp.Compare(a.Label, b.Label))
+	})
+	return infos
+}
+
+func renderTargetBytes() int64 {
+	renderTargets.mu.Lock()
+	defer renderTargets.mu.Unlock()
+	return renderTargets.bytes
+}
+
+// newRenderTarget creates a tracked render target.
+func newRenderTarget(width, height int, label string) *ebiten.Image {
+	img := ebiten.NewImage(width, height)
+	TrackRenderTarget(img, label)
+	return img
+}
+
+// releaseRenderTarget deallocates a tracked render target.
+func releaseRenderTarget(img *ebiten.Image) {
+	UntrackRenderTarget(img)
+	img.Deallocate()
+}
+
+// resizeRenderTarget returns img when it is width x height, or releases it
+// and returns a new render target. The bool reports a new image.
+func resizeRenderTarget(img *ebiten.Image, width, height int, label string) (*ebiten.Image, bool) {
+	if img != nil && img.Bounds().Dx() == width && img.Bounds().Dy() == height {
+		return img, false
+	}
+	if img != nil {
+		releaseRenderTarget(img)
+	}
+	return newRenderTarget(width, height, label), true
+}
+
+// WithTextureBudget logs a warning whenever the estimated texture memory
+// grows past bytes, e.g. the VRAM of the weakest target device.
+func WithTextureBudget(bytes int64) Option {
+	return func(e *Engine) {
+		e.textureBudget = bytes
+	}
+}
+
+// SetTextureBudget changes the texture memory budget; 0 disables it.
+func (self *Engine) SetTextureBudget(bytes int64) {
+	self.textureBudget = bytes
+	self.overTextureBudget = false
+}
+
+// TextureMemory returns the estimated texture memory in use.
+func (self *Engine) TextureMemory() TextureMemoryStats {
+	return TextureMemoryStats{
+		Textures:      self.tm.MemoryUsage(),
+		RenderTargets: renderTargetBytes(),
+		Budget:        self.textureBudget,
+	}
+}
+
+// checkTextureBudget logs once each time the texture memory crosses the
+// budget.
+func (self *Engine) checkTextureBudget() {
+	if self.textureBudget <= 0 {
+		return
+	}
+	stats := self.TextureMemory()
+	over := stats.Total() > stats.Budget
+	if over && !self.overTextureBudget {
+		logger.GetLogger().Warn("texture memory %.1f MB exceeds the budget of %.1f MB (textures %.1f MB, render targets %.1f MB)",
+			megabytes(stats.Total()), megabytes(stats.Budget), megabytes(stats.Textures), megabytes(stats.RenderTargets))
+	}
+	self.overTextureBudget = over
+}
+
+func megabytes(bytes int64) float64 {
+	return float64(bytes) / (1 << 20)
+}
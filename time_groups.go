@@ -0,0 +1,145 @@
+package katsu2d
+
+import (
+	"reflect"
+
+	"github.com/edwinsyarief/katsu2d/logger"
+	"github.com/edwinsyarief/teishoku"
+)
+
+// TimeGroup names a set of update systems that pause and scale time
+// together, e.g. gameplay while a "ui" group keeps running. Systems that
+// were never assigned belong to DefaultTimeGroup.
+type TimeGroup string
+
+// DefaultTimeGroup holds every update system without an explicit group.
+const DefaultTimeGroup TimeGroup = ""
+
+// TimeControl pauses and scales update systems by TimeGroup. The engine
+// shares one with its world and every scene added to it.
+type TimeControl struct {
+	systems map[UpdateSystem]TimeGroup
+	groups  map[TimeGroup]*timeGroupState
+}
+
+type timeGroupState struct {
+	paused bool
+	scale  float64
+}
+
+// NewTimeControl creates a time control with every group running at
+// normal speed.
+func NewTimeControl() *TimeControl {
+	return &TimeControl{
+		systems: make(map[UpdateSystem]TimeGroup),
+		groups:  make(map[TimeGroup]*timeGroupState),
+	}
+}
+
+// GetTimeControl returns the world's time control, or nil if none is set.
+func GetTimeControl(w *teishoku.World) *TimeControl {
+	return GetResource[TimeControl](w)
+}
+
+// Assign moves systems into group. Systems whose type cannot be a map key,
+// such as structs holding slices passed by value, always stay in
+// DefaultTimeGroup; pass them by pointer to group them.
+func (self *TimeControl) Assign(group TimeGroup, systems ...UpdateSystem) {
+	for _, sys := range systems {
+		if !comparableSystem(sys) {
+			logger.GetLogger().Warn("TimeControl: system %T is not comparable and cannot be grouped", sys)
+			continue
+		}
+		if group == DefaultTimeGroup {
+			delete(self.systems, sys)
+			continue
+		}
+		self.systems[sys] = group
+	}
+}
+
+// GroupOf returns the group sys belongs to.
+func (self *TimeControl) GroupOf(sys UpdateSystem) TimeGroup {
+	if !comparableSystem(sys) {
+		return DefaultTimeGroup
+	}
+	return self.systems[sys]
+}
+
+// SetPaused pauses or resumes group. Paused systems are not updated.
+func (self *TimeControl) SetPaused(group TimeGroup, paused bool) {
+	self.group(group).paused = paused
+}
+
+// Paused reports whether group is paused.
+func (self *TimeControl) Paused(group TimeGroup) bool {
+	if g := self.groups[group]; g != nil {
+		return g.paused
+	}
+	return false
+}
+
+// SetScale sets the dt multiplier of group, on top of the engine time
+// scale.
+func (self *TimeControl) SetScale(group TimeGroup, scale float64) {
+	self.group(group).scale = scale
+}
+
+// Scale returns the dt multiplier of group.
+func (self *TimeControl) Scale(group TimeGroup) float64 {
+	if g := self.groups[group]; g != nil {
+		return g.scale
+	}
+	return 1
+}
+
+// Delta returns dt as seen by group: zero when paused, scaled otherwise.
+func (self *TimeControl) Delta(group TimeGroup, dt float64) float64 {
+	g := self.groups[group]
+	if g == nil {
+		return dt
+	}
+	if g.paused {
+		return 0
+	}
+	return dt * g.scale
+}
+
+func (self *TimeControl) group(group TimeGroup) *timeGroupState {
+	g := self.groups[group]
+	if g == nil {
+		g = &timeGroupState{scale: 1}
+		self.groups[group] = g
+	}
+	return g
+}
+
+// systemDelta returns the dt for sys, and false when its group is paused.
+func (self *TimeControl) systemDelta(sys UpdateSystem, dt float64) (float64, bool) {
+	g := self.groups[self.GroupOf(sys)]
+	if g == nil {
+		return dt, true
+	}
+	return dt * g.scale, !g.paused
+}
+
+// comparableSystem reports whether sys can be used as a map key. Looking up
+// a value whose dynamic type holds a slice or map panics.
+func comparableSystem(sys any) bool {
+	return sys != nil && reflect.TypeOf(sys).Comparable()
+}
+
+// TimeControl returns the engine's time control.
+func (self *Engine) TimeControl() *TimeControl {
+	return self.timeControl
+}
+
+// SetGroupPaused pauses or resumes the update systems of group.
+func (self *Engine) SetGroupPaused(group TimeGroup, paused bool) {
+	self.timeControl.SetPaused(group, paused)
+}
+
+// SetGroupTimeScale sets the dt multiplier of group.
+func (self *Engine) SetGroupTimeScale(group TimeGroup, scale float64) {
+	self.timeControl.SetScale(group, scale)
+}
@@ -0,0 +1,343 @@
+package katsu2d
+
+import (
+	"image/color"
+
+	"github.com/edwinsyarief/teishoku"
+)
+
+// Tweener is a step of a Sequence. Update advances it by dt and reports
+// when it finishes, with the part of dt it did not use.
+type Tweener interface {
+	Update(dt float64) (leftover float64, done bool)
+	Reset()
+}
+
+// TweenValue is a type Tween can interpolate.
+type TweenValue interface {
+	float64 | Vector | Point | color.RGBA
+}
+
+// Tween animates a value of type T towards To. The start value is read from
+// the target when the tween first starts, unless From sets it, and kept
+// across Reset so looping sequences replay the same animation; Restart
+// reads it again.
+type Tween[T TweenValue] struct {
+	target   func() *T
+	from, to T
+	fixed    bool
+	duration float64
+	delay    float64
+	ease     EaseType
+	elapsed  float64
+	captured bool
+	onUpdate func(T)
+}
+
+// NewTween creates a tween writing to the pointer returned by target, which
+// is called every update so it may look the value up afresh.
+func NewTween[T TweenValue](target func() *T, to T, duration float64) *Tween[T] {
+	return &Tween[T]{target: target, to: to, duration: duration, ease: QuadOut}
+}
+
+// TweenFloatField tweens *target to to over duration seconds.
+func TweenFloatField(target *float64, to, duration float64) *Tween[float64] {
+	return NewTween(func() *float64 { return target }, to, duration)
+}
+
+// TweenVec2 tweens *target to to over duration seconds.
+func TweenVec2(target *Vector, to Vector, duration float64) *Tween[Vector] {
+	return NewTween(func() *Vector { return target }, to, duration)
+}
+
+// TweenColor tweens *target to to over duration seconds.
+func TweenColor(target *color.RGBA, to color.RGBA, duration float64) *Tween[color.RGBA] {
+	return NewTween(func() *color.RGBA { return target }, to, duration)
+}
+
+// TweenEntityField tweens a field of e's component C, e.g.
+//
+//	TweenEntityField(w, e, func(s *SpriteComponent) *float64 { return &s.Opacity }, 0, 0.5)
+//
+// The field is looked up every update, so unlike pointers taken once it
+// stays valid when components are added to or removed from e. The tween
+// stops when e or its component is gone.
+func TweenEntityField[C any, T TweenValue](w *teishoku.World, e teishoku.Entity, field func(*C) *T, to T, duration float64) *Tween[T] {
+	return NewTween(func() *T {
+		if !IsAlive(w, e) {
+			return nil
+		}
+		if c := teishoku.GetComponent[C](w, e); c != nil {
+			return field(c)
+		}
+		return nil
+	}, to, duration)
+}
+
+// From fixes the start value instead of reading it from the target.
+func (self *Tween[T]) From(from T) *Tween[T] {
+	self.from, self.fixed = from, true
+	return self
+}
+
+// Ease sets the easing curve; the default is QuadOut.
+func (self *Tween[T]) Ease(ease EaseType) *Tween[T] {
+	self.ease = ease
+	return self
+}
+
+// Delay waits seconds before starting.
+func (self *Tween[T]) Delay(seconds float64) *Tween[T] {
+	self.delay = seconds
+	return self
+}
+
+// OnUpdate calls fn with every new value.
+func (self *Tween[T]) OnUpdate(fn func(T)) *Tween[T] {
+	self.onUpdate = fn
+	return self
+}
+
+func (self *Tween[T]) Update(dt float64) (float64, bool) {
+	self.elapsed += dt
+	if self.elapsed < self.delay {
+		return 0, false
+	}
+	target := self.target()
+	if target == nil {
+		return 0, true
+	}
+	if !self.captured {
+		self.captured = true
+		if !self.fixed {
+			self.from = *target
+		}
+	}
+	t := 1.0
+	if self.duration > 0 {
+		t = min((self.elapsed-self.delay)/self.duration, 1)
+	}
+	*target = lerpTweenValue(self.from, self.to, EaseTypes[float64](self.ease)(t, 0, 1, 1))
+	if self.onUpdate != nil {
+		self.onUpdate(*target)
+	}
+	if leftover := self.elapsed - self.delay - self.duration; leftover >= 0 {
+		return leftover, true
+	}
+	return 0, false
+}
+
+// Reset rewinds the tween to its start value.
+func (self *Tween[T]) Reset() {
+	self.elapsed = 0
+}
+
+// Restart rewinds the tween and reads the start value from the target
+// again when it next starts.
+func (self *Tween[T]) Restart() {
+	self.elapsed, self.captured = 0, false
+}
+
+// lerpTweenValue blends a towards b by t; colors blend per channel.
+func lerpTweenValue[T TweenValue](a, b T, t float64) T {
+	switch a := any(a).(type) {
+	case float64:
+		return any(a + (any(b).(float64)-a)*t).(T)
+	case Vector:
+		return any(a.Lerp(any(b).(Vector), t)).(T)
+	case Point:
+		return any(Point(Vector(a).Lerp(Vector(any(b).(Point)), t))).(T)
+	case color.RGBA:
+		c := any(b).(color.RGBA)
+		mix := func(x, y uint8) uint8 { return uint8(Clamp(float64(x)+(float64(y)-float64(x))*t+0.5, 0, 255)) }
+		return any(color.RGBA{mix(a.R, c.R), mix(a.G, c.G), mix(a.B, c.B), mix(a.A, c.A)}).(T)
+	}
+	panic("unreachable")
+}
+
+// parallel runs tweeners together, finishing with the last of them.
+type parallel struct {
+	items []Tweener
+	done  []bool
+}
+
+func (self *parallel) Update(dt float64) (float64, bool) {
+	leftover, all := dt, true
+	for i, item := range self.items {
+		if self.done[i] {
+			continue
+		}
+		left, done := item.Update(dt)
+		if !done {
+			all = false
+			continue
+		}
+		self.done[i] = true
+		leftover = min(leftover, left)
+	}
+	if !all {
+		return 0, false
+	}
+	return leftover, true
+}
+
+func (self *parallel) Reset() {
+	for i, item := range self.items {
+		item.Reset()
+		self.done[i] = false
+	}
+}
+
+// wait is an idle Tweener.
+type wait struct {
+	duration, elapsed float64
+}
+
+func (self *wait) Update(dt float64) (float64, bool) {
+	self.elapsed += dt
+	if leftover := self.elapsed - self.duration; leftover >= 0 {
+		return leftover, true
+	}
+	return 0, false
+}
+
+func (self *wait) Reset() {
+	self.elapsed = 0
+}
+
+// callback is a Tweener calling a function once.
+type callback struct {
+	fn     func()
+	called bool
+}
+
+func (self *callback) Update(dt float64) (float64, bool) {
+	if !self.called {
+		self.called = true
+		self.fn()
+	}
+	return dt, true
+}
+
+func (self *callback) Reset() {
+	self.called = false
+}
+
+// Sequence chains tweeners into steps that run one after another; the
+// tweeners of one step run in parallel. Build it with Then, Join, Wait and
+// Call. A Sequence is itself a Tweener, so sequences nest.
+type Sequence struct {
+	steps      []*parallel
+	index      int
+	loops      int
+	loop       int
+	onComplete func()
+	done       bool
+}
+
+// NewSequence creates an empty sequence playing once.
+func NewSequence() *Sequence {
+	return &Sequence{loops: 1}
+}
+
+// Then appends a step running tweeners together after the previous step.
+func (self *Sequence) Then(tweeners ...Tweener) *Sequence {
+	self.steps = append(self.steps, &parallel{items: tweeners, done: make([]bool, len(tweeners))})
+	return self
+}
+
+// Join adds tweeners to the last step, running alongside it.
+func (self *Sequence) Join(tweeners ...Tweener) *Sequence {
+	if len(self.steps) == 0 {
+		return self.Then(tweeners...)
+	}
+	last := self.steps[len(self.steps)-1]
+	last.items = append(last.items, tweeners...)
+	last.done = append(last.done, make([]bool, len(tweeners))...)
+	return self
+}
+
+// Wait appends a pause of seconds.
+func (self *Sequence) Wait(seconds float64) *Sequence {
+	return self.Then(&wait{duration: seconds})
+}
+
+// Call appends a step calling fn.
+func (self *Sequence) Call(fn func()) *Sequence {
+	return self.Then(&callback{fn: fn})
+}
+
+// Loop plays the sequence count times; 0 or less loops forever.
+func (self *Sequence) Loop(count int) *Sequence {
+	self.loops = count
+	return self
+}
+
+// OnComplete calls fn when the last loop finishes.
+func (self *Sequence) OnComplete(fn func()) *Sequence {
+	self.onComplete = fn
+	return self
+}
+
+// Done reports whether the sequence has finished.
+func (self *Sequence) Done() bool {
+	return self.done
+}
+
+func (self *Sequence) Update(dt float64) (float64, bool) {
+	if self.done {
+		return dt, true
+	}
+	for {
+		passStart := dt
+		for self.index < len(self.steps) {
+			left, done := self.steps[self.index].Update(dt)
+			if !done {
+				return 0, false
+			}
+			self.index++
+			dt = left
+		}
+		self.loop++
+		if self.loops > 0 && self.loop >= self.loops {
+			break
+		}
+		self.resetSteps()
+		// A loop taking no time would spin forever; go on next update.
+		if dt >= passStart {
+			return 0, false
+		}
+	}
+	self.done = true
+	if self.onComplete != nil {
+		self.onComplete()
+	}
+	return dt, true
+}
+
+func (self *Sequence) Reset() {
+	self.resetSteps()
+	self.loop, self.done = 0, false
+}
+
+func (self *Sequence) resetSteps() {
+	self.index = 0
+	for _, step := range self.steps {
+		step.Reset()
+	}
+}
+
+// TweenSequenceComponent plays a Sequence on an entity. The TweenSystem
+// advances it with the entity's ScaledDelta and publishes a
+// TweenFinishedEvent with ID when it completes.
+type TweenSequenceComponent struct {
+	ID       string
+	Sequence *Sequence
+}
+
+// PlaySequence plays seq on e under id, replacing the sequence e plays.
+// Field pointers into e's components should be taken after this call or
+// through TweenEntityField, as adding the component moves them.
+func PlaySequence(w *teishoku.World, e teishoku.Entity, id string, seq *Sequence) {
+	teishoku.SetComponent(w, e, TweenSequenceComponent{ID: id, Sequence: seq})
+}
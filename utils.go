@@ -5,10 +5,10 @@ import (
 	"image"
 	"image/color"
 	"math"
-
 	"reflect"
 	"time"
 
+	"github.com/edwinsyarief/katsu2d/colors"
 	"github.com/edwinsyarief/katsu2d/opensimplex"
 	"github.com/hajimehoshi/ebiten/v2"
 )
@@ -93,42 +93,11 @@ func InitializeEmbedded[T any](obj T) T {
 	return v.Interface().(T)
 }
 
+// LerpPremultipliedRGBA interpolates two colors with premultiplied alpha.
+//
+// Deprecated: use colors.Lerp.
 func LerpPremultipliedRGBA(color1, color2 color.RGBA, t float64) color.RGBA {
-	// Clamp interpolation factor (t) to 0-1 range
-	t = Clamp(t, 0.0, 1.0)
-
-	// Convert RGBA to float64 (0-1 range) with premultiplied alpha handling
-	r1 := float64(color1.R) / 255.0
-	g1 := float64(color1.G) / 255.0
-	b1 := float64(color1.B) / 255.0
-	a1 := float64(color1.A) / 255.0
-	if a1 > 0.0 {
-		r1 /= a1
-		g1 /= a1
-		b1 /= a1
-	}
-
-	r2 := float64(color2.R) / 255.0
-	g2 := float64(color2.G) / 255.0
-	b2 := float64(color2.B) / 255.0
-	a2 := float64(color2.A) / 255.0
-	if a2 > 0.0 {
-		r2 /= a2
-		g2 /= a2
-		b2 /= a2
-	}
-
-	// Lerp with premultiplied alpha
-	r := (r1*a1 + t*(r2*a2-r1*a1)) / (a1 + t*(a2-a1))
-	g := (g1*a1 + t*(g2*a2-g1*a1)) / (a1 + t*(a2-a1))
-	b := (b1*a1 + t*(b2*a2-b1*a1)) / (a1 + t*(a2-a1))
-	a := a1 + t*(a2-a1)
-
-	// Convert back to uint8 and clamp to 0-255 range
-	return color.RGBA{R: uint8(Clamp(r*255.0, 0.0, 255.0)),
-		G: uint8(Clamp(g*255.0, 0.0, 255.0)),
-		B: uint8(Clamp(b*255.0, 0.0, 255.0)),
-		A: uint8(Clamp(a*255.0, 0.0, 255.0))}
+	return colors.Lerp(color1, color2, t)
 }
 
 func PremultiplyRGBA(r, g, b, a float64) color.RGBA {
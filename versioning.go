@@ -0,0 +1,249 @@
+package katsu2d
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/edwinsyarief/teishoku"
+)
+
+// EngineVersion is the katsu2d version stamped into saves and serialized
+// worlds.
+const EngineVersion = "0.1.0"
+
+// contentVersion is the game's schema version, see SetContentVersion.
+var contentVersion int
+
+// SetContentVersion sets the game's content version, stamped into saves and
+// serialized worlds. Bump it whenever a component or resource changes shape
+// and register a migration from the previous version.
+func SetContentVersion(version int) {
+	contentVersion = version
+}
+
+// ContentVersion returns the version set with SetContentVersion.
+func ContentVersion() int {
+	return contentVersion
+}
+
+// VersionStamp records which engine and content version wrote some data.
+// Saves, serialized worlds and asset packs carry one; embed it in other game
+// files to check them with Check.
+type VersionStamp struct {
+	Engine  string `json:"engine_version,omitempty"`
+	Content int    `json:"content_version"`
+}
+
+// CurrentVersion returns the stamp for data written now.
+func CurrentVersion() VersionStamp {
+	return VersionStamp{Engine: EngineVersion, Content: contentVersion}
+}
+
+// Check reports whether data with this stamp can be loaded: it must not
+// come from a newer content version, and migrations must lead from its
+// version to the current one.
+func (self VersionStamp) Check() error {
+	if self.Content > contentVersion {
+		return fmt.Errorf("katsu2d: content version %d is newer than %d", self.Content, contentVersion)
+	}
+	_, err := migrationPath(self.Content, contentVersion)
+	return err
+}
+
+// WorldDocument is the layout of a serialized world, handed to migrations.
+// Components and resources are raw JSON keyed by codec name.
+type WorldDocument struct {
+	Version int `json:"version"`
+	VersionStamp
+	Entities  []EntityDocument           `json:"entities"`
+	Resources map[string]json.RawMessage `json:"resources,omitempty"`
+}
+
+// EntityDocument is one serialized entity of a WorldDocument.
+type EntityDocument struct {
+	Entity     teishoku.Entity            `json:"entity"`
+	Components map[string]json.RawMessage `json:"components"`
+}
+
+// RenameComponent renames every component saved as from to to.
+func (self *WorldDocument) RenameComponent(from, to string) {
+	for _, ed := range self.Entities {
+		if raw, ok := ed.Components[from]; ok {
+			delete(ed.Components, from)
+			ed.Components[to] = raw
+		}
+	}
+}
+
+// RemoveComponent drops every component saved as name.
+func (self *WorldDocument) RemoveComponent(name string) {
+	for _, ed := range self.Entities {
+		delete(ed.Components, name)
+	}
+}
+
+// UpdateComponent rewrites every component saved as name through fn, which
+// edits its decoded JSON fields.
+func (self *WorldDocument) UpdateComponent(name string, fn func(fields map[string]any) error) error {
+	for _, ed := range self.Entities {
+		raw, ok := ed.Components[name]
+		if !ok {
+			continue
+		}
+		updated, err := updateJSONObject(raw, fn)
+		if err != nil {
+			return fmt.Errorf("katsu2d: migrating component %s: %w", name, err)
+		}
+		ed.Components[name] = updated
+	}
+	return nil
+}
+
+// UpdateResource rewrites the resource saved as name through fn.
+func (self *WorldDocument) UpdateResource(name string, fn func(fields map[string]any) error) error {
+	raw, ok := self.Resources[name]
+	if !ok {
+		return nil
+	}
+	updated, err := updateJSONObject(raw, fn)
+	if err != nil {
+		return fmt.Errorf("katsu2d: migrating resource %s: %w", name, err)
+	}
+	self.Resources[name] = updated
+	return nil
+}
+
+func updateJSONObject(raw json.RawMessage, fn func(fields map[string]any) error) (json.RawMessage, error) {
+	var fields map[string]any
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	if err := fn(fields); err != nil {
+		return nil, err
+	}
+	return json.Marshal(fields)
+}
+
+// Migration upgrades a serialized world from one content version to a
+// later one.
+type Migration func(doc *WorldDocument) error
+
+type migration struct {
+	from, to int
+	fn       Migration
+}
+
+var migrations []migration
+
+// RegisterMigration registers fn to upgrade worlds of content version from
+// to version to. DeserializeWorld runs the chain of migrations from a
+// world's version up to ContentVersion before loading it. Registering the
+// same versions again replaces the migration.
+func RegisterMigration(from, to int, fn Migration) {
+	if to <= from {
+		panic(fmt.Sprintf("katsu2d: migration must go forward, got %d to %d", from, to))
+	}
+	m := migration{from: from, to: to, fn: fn}
+	if i := slices.IndexFunc(migrations, func(m migration) bool { return m.from == from && m.to == to }); i >= 0 {
+		migrations[i] = m
+		return
+	}
+	migrations = append(migrations, m)
+}
+
+// migrationPath returns the shortest chain of migrations leading from one
+// version to another. It searches breadth first, so a dead end reached by
+// a large step does not hide a longer chain that arrives.
+func migrationPath(from, to int) ([]migration, error) {
+	if from >= to {
+		return nil, nil
+	}
+	// via holds the migration that first reached each version. Migrations
+	// only go forward, so from itself is never reached again.
+	via := make(map[int]int)
+	queue := []int{from}
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		if v == to {
+			break
+		}
+		for i, m := range migrations {
+			if _, seen := via[m.to]; m.from != v || m.to > to || seen {
+				continue
+			}
+			via[m.to] = i
+			queue = append(queue, m.to)
+		}
+	}
+	if _, ok := via[to]; !ok {
+		return nil, fmt.Errorf("katsu2d: no migration path from content version %d to %d", from, to)
+	}
+	var path []migration
+	for v := to; v != from; {
+		m := migrations[via[v]]
+		path = append(path, m)
+		v = m.from
+	}
+	slices.Reverse(path)
+	return path, nil
+}
+
+// migrate runs the migrations from the document's content version up to
+// the current one.
+func (self *WorldDocument) migrate() error {
+	if self.Content == contentVersion {
+		return nil
+	}
+	if err := self.Check(); err != nil {
+		return err
+	}
+	path, _ := migrationPath(self.Content, contentVersion)
+	for _, m := range path {
+		if err := m.fn(self); err != nil {
+			return fmt.Errorf("katsu2d: migrating content version %d to %d: %w", m.from, m.to, err)
+		}
+		self.Content = m.to
+	}
+	self.Engine = EngineVersion
+	return nil
+}
+
+// MigrateWorld upgrades serialized world data to the current content
+// version, e.g. to rewrite scene files on disk. Data already at the current
+// version is returned unchanged.
+func MigrateWorld(data []byte) ([]byte, error) {
+	var doc WorldDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("katsu2d: decoding world: %w", err)
+	}
+	if doc.Content == contentVersion {
+		return data, nil
+	}
+	if err := doc.migrate(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(doc)
+}
+
+// AssetPackVersionFile is the asset pack entry holding the pack's
+// VersionStamp as JSON. Write it with the other assets when building a pack.
+const AssetPackVersionFile = "version.json"
+
+// AssetPackVersion returns the stamp of the loaded asset pack, and false
+// when no pack is loaded or it carries no stamp.
+func AssetPackVersion() (VersionStamp, bool) {
+	if assets.reader == nil {
+		return VersionStamp{}, false
+	}
+	b, err := assets.reader.GetAsset(AssetPackVersionFile)
+	if err != nil {
+		return VersionStamp{}, false
+	}
+	var stamp VersionStamp
+	if err := json.Unmarshal(b.Content, &stamp); err != nil {
+		return VersionStamp{}, false
+	}
+	return stamp, true
+}
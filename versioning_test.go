@@ -0,0 +1,92 @@
+package katsu2d
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+// withMigrations runs the test against only the given migrations and
+// content version, restoring the registered ones afterwards.
+func withMigrations(t *testing.T, version int) {
+	t.Helper()
+	saved, savedVersion := migrations, contentVersion
+	migrations, contentVersion = nil, version
+	t.Cleanup(func() { migrations, contentVersion = saved, savedVersion })
+}
+
+func noMigration(*WorldDocument) error { return nil }
+
+// pathSteps returns the versions a migration path passes through.
+func pathSteps(path []migration) []int {
+	var steps []int
+	for _, m := range path {
+		steps = append(steps, m.from, m.to)
+	}
+	return steps
+}
+
+// TestMigrationPath verifies that paths are found past dead ends and take
+// the fewest steps.
+func TestMigrationPath(t *testing.T) {
+	withMigrations(t, 3)
+	RegisterMigration(0, 2, noMigration)
+	RegisterMigration(0, 1, noMigration)
+	RegisterMigration(1, 3, noMigration)
+
+	path, err := migrationPath(0, 3)
+	if err != nil {
+		t.Fatalf("migrationPath(0, 3): %v", err)
+	}
+	if got := pathSteps(path); !slices.Equal(got, []int{0, 1, 1, 3}) {
+		t.Fatalf("migrationPath(0, 3) = %v, want 0-1, 1-3", got)
+	}
+	if path, err := migrationPath(3, 3); err != nil || len(path) != 0 {
+		t.Fatalf("migrationPath(3, 3) = %v, %v, want an empty path", path, err)
+	}
+	if _, err := migrationPath(2, 3); err == nil {
+		t.Fatal("migrationPath(2, 3) found a path without a migration from 2")
+	}
+
+	RegisterMigration(0, 3, noMigration)
+	path, err = migrationPath(0, 3)
+	if err != nil || !slices.Equal(pathSteps(path), []int{0, 3}) {
+		t.Fatalf("migrationPath(0, 3) = %v, %v, want the direct migration", pathSteps(path), err)
+	}
+}
+
+// TestMigrateWorld verifies that migrations run in order on the document
+// and stamp it with the current version.
+func TestMigrateWorld(t *testing.T) {
+	withMigrations(t, 2)
+	RegisterMigration(0, 1, func(doc *WorldDocument) error {
+		doc.RenameComponent("Health", "HealthComponent")
+		return nil
+	})
+	RegisterMigration(1, 2, func(doc *WorldDocument) error {
+		return doc.UpdateComponent("HealthComponent", func(fields map[string]any) error {
+			fields["Max"] = fields["Value"]
+			return nil
+		})
+	})
+
+	data := []byte(`{"version":1,"content_version":0,"entities":[{"entity":{"ID":1,"Version":1},"components":{"Health":{"Value":5}}}]}`)
+	migrated, err := MigrateWorld(data)
+	if err != nil {
+		t.Fatalf("MigrateWorld: %v", err)
+	}
+	var doc WorldDocument
+	if err := json.Unmarshal(migrated, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Content != 2 || doc.Engine != EngineVersion {
+		t.Fatalf("stamp = %+v, want content 2 and engine %s", doc.VersionStamp, EngineVersion)
+	}
+	if got := string(doc.Entities[0].Components["HealthComponent"]); got != `{"Max":5,"Value":5}` {
+		t.Fatalf("HealthComponent = %s", got)
+	}
+
+	if _, err := MigrateWorld([]byte(`{"version":1,"content_version":3}`)); err == nil {
+		t.Fatal("MigrateWorld accepted data from a newer content version")
+	}
+}
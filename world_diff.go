@@ -0,0 +1,221 @@
+package katsu2d
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/edwinsyarief/teishoku"
+)
+
+// snapshotComponent captures one registered component type.
+type snapshotComponent struct {
+	name    string
+	capture func(w *teishoku.World, dst map[teishoku.Entity]map[string]componentFields)
+}
+
+// componentFields maps a flattened field path such as "Position.X" to its
+// formatted value.
+type componentFields map[string]string
+
+var snapshotComponents []snapshotComponent
+
+func init() {
+	RegisterSnapshotComponent[TransformComponent]()
+	RegisterSnapshotComponent[SpriteComponent]()
+	RegisterSnapshotComponent[AnimationComponent]()
+	RegisterSnapshotComponent[TextComponent]()
+	RegisterSnapshotComponent[ElevationComponent]()
+	RegisterSnapshotComponent[ColliderComponent]()
+	RegisterSnapshotComponent[TeamComponent]()
+	RegisterSnapshotComponent[OwnerComponent]()
+	RegisterSnapshotComponent[TimerComponent]()
+	RegisterSnapshotComponent[PathFollowerComponent]()
+	RegisterSnapshotComponent[LocalTimeComponent]()
+	RegisterSnapshotComponent[RigidBodyComponent]()
+	RegisterSnapshotComponent[TriggerZoneComponent]()
+	RegisterSnapshotComponent[DebugLabelComponent]()
+}
+
+// RegisterSnapshotComponent includes components of type T in world
+// snapshots. Entities are only seen through registered components, so
+// register every gameplay component that should take part in diffs.
+func RegisterSnapshotComponent[T any]() {
+	name := reflect.TypeFor[T]().Name()
+	for _, c := range snapshotComponents {
+		if c.name == name {
+			return
+		}
+	}
+	snapshotComponents = append(snapshotComponents, snapshotComponent{
+		name: name,
+		capture: func(w *teishoku.World, dst map[teishoku.Entity]map[string]componentFields) {
+			filter := teishoku.NewFilter[T](w)
+			for filter.Next() {
+				e := filter.Entity()
+				if dst[e] == nil {
+					dst[e] = make(map[string]componentFields)
+				}
+				fields := make(componentFields)
+				flattenFields(reflect.ValueOf(filter.Get()).Elem(), "", fields, 0)
+				dst[e][name] = fields
+			}
+		},
+	})
+}
+
+// WorldSnapshot is a formatted copy of the registered components of every
+// entity, taken at one moment. Later changes to the world do not affect it.
+type WorldSnapshot struct {
+	entities map[teishoku.Entity]map[string]componentFields
+}
+
+// TakeSnapshot captures the registered components of w.
+func TakeSnapshot(w *teishoku.World) *WorldSnapshot {
+	snap := &WorldSnapshot{entities: make(map[teishoku.Entity]map[string]componentFields)}
+	for _, c := range snapshotComponents {
+		c.capture(w, snap.entities)
+	}
+	return snap
+}
+
+// Len returns the number of entities in the snapshot.
+func (self *WorldSnapshot) Len() int {
+	return len(self.entities)
+}
+
+// DiffKind classifies a WorldChange.
+type DiffKind uint8
+
+const (
+	DiffEntityAdded DiffKind = iota
+	DiffEntityRemoved
+	DiffComponentAdded
+	DiffComponentRemoved
+	DiffFieldChanged
+)
+
+// WorldChange is one difference between two snapshots. Component, Field,
+// Old and New are set as far as they apply to the kind.
+type WorldChange struct {
+	Kind      DiffKind
+	Entity    teishoku.Entity
+	Component string
+	Field     string
+	Old, New  string
+}
+
+func (self WorldChange) String() string {
+	id := fmt.Sprintf("entity %d:%d", self.Entity.ID, self.Entity.Version)
+	switch self.Kind {
+	case DiffEntityAdded:
+		return "+ " + id
+	case DiffEntityRemoved:
+		return "- " + id
+	case DiffComponentAdded:
+		return fmt.Sprintf("+ %s %s", id, self.Component)
+	case DiffComponentRemoved:
+		return fmt.Sprintf("- %s %s", id, self.Component)
+	default:
+		return fmt.Sprintf("~ %s %s.%s: %s -> %s", id, self.Component, self.Field, self.Old, self.New)
+	}
+}
+
+// WorldDiff lists the changes from one snapshot to another, ordered by
+// entity, component and field.
+type WorldDiff []WorldChange
+
+// String formats the diff one change per line.
+func (self WorldDiff) String() string {
+	var sb strings.Builder
+	for _, c := range self {
+		sb.WriteString(c.String())
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// DiffWorlds compares two snapshots, e.g. of a replay and the original run,
+// or of a world before saving and after loading.
+func DiffWorlds(from, to *WorldSnapshot) WorldDiff {
+	var diff WorldDiff
+	for e, comps := range from.entities {
+		if _, ok := to.entities[e]; !ok {
+			diff = append(diff, WorldChange{Kind: DiffEntityRemoved, Entity: e})
+		} else {
+			diff = diffComponents(diff, e, comps, to.entities[e])
+		}
+	}
+	for e := range to.entities {
+		if _, ok := from.entities[e]; !ok {
+			diff = append(diff, WorldChange{Kind: DiffEntityAdded, Entity: e})
+		}
+	}
+	slices.SortFunc(diff, func(a, b WorldChange) int {
+		return cmp.Or(
+			cmp.Compare(a.Entity.ID, b.Entity.ID),
+			cmp.Compare(a.Entity.Version, b.Entity.Version),
+			cmp.Compare(a.Component, b.Component),
+			cmp.Compare(a.Field, b.Field),
+		)
+	})
+	return diff
+}
+
+func diffComponents(diff WorldDiff, e teishoku.Entity, from, to map[string]componentFields) WorldDiff {
+	for name, fields := range from {
+		other, ok := to[name]
+		if !ok {
+			diff = append(diff, WorldChange{Kind: DiffComponentRemoved, Entity: e, Component: name})
+			continue
+		}
+		for field, old := range fields {
+			if now := other[field]; now != old {
+				diff = append(diff, WorldChange{Kind: DiffFieldChanged, Entity: e, Component: name, Field: field, Old: old, New: now})
+			}
+		}
+	}
+	for name := range to {
+		if _, ok := from[name]; !ok {
+			diff = append(diff, WorldChange{Kind: DiffComponentAdded, Entity: e, Component: name})
+		}
+	}
+	return diff
+}
+
+// flattenFields formats the exported fields of v, descending into nested
+// structs. Functions are skipped since they cannot be compared.
+func flattenFields(v reflect.Value, prefix string, dst componentFields, depth int) {
+	if v.Kind() == reflect.Pointer && !v.IsNil() && v.Elem().Kind() == reflect.Struct {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct || depth > 4 || !hasExportedFields(v.Type()) {
+		if v.Kind() != reflect.Func {
+			dst[prefix] = fmt.Sprint(v.Interface())
+		}
+		return
+	}
+	t := v.Type()
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Name
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+		flattenFields(v.Field(i), name, dst, depth+1)
+	}
+}
+
+func hasExportedFields(t reflect.Type) bool {
+	for i := range t.NumField() {
+		if t.Field(i).IsExported() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,309 @@
+package katsu2d
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/edwinsyarief/teishoku"
+)
+
+// worldFormatVersion is written into serialized worlds and checked on load.
+const worldFormatVersion = 1
+
+// Codec converts a component or resource of type T to and from JSON for
+// world serialization. Marshal and Unmarshal default to encoding/json, which
+// only keeps exported fields. Remap, when set, rewrites the entity handles
+// the value holds after loading, since entities get new IDs.
+type Codec[T any] struct {
+	Marshal   func(*T) ([]byte, error)
+	Unmarshal func([]byte, *T) error
+	Remap     func(*T, EntityMapper)
+}
+
+// EntityMapper returns the loaded entity for a saved one, or the zero
+// entity when it was not part of the save.
+type EntityMapper func(teishoku.Entity) teishoku.Entity
+
+func (self Codec[T]) marshal(v *T) (json.RawMessage, error) {
+	if self.Marshal == nil {
+		return json.Marshal(v)
+	}
+	data, err := self.Marshal(v)
+	if err == nil && !json.Valid(data) {
+		err = fmt.Errorf("codec produced invalid JSON")
+	}
+	return data, err
+}
+
+func (self Codec[T]) unmarshal(data []byte, v *T) error {
+	if self.Unmarshal == nil {
+		return json.Unmarshal(data, v)
+	}
+	return self.Unmarshal(data, v)
+}
+
+type componentCodec struct {
+	name         string
+	encode       func(w *teishoku.World, dst map[teishoku.Entity]map[string]json.RawMessage) error
+	encodeEntity func(w *teishoku.World, e teishoku.Entity) (json.RawMessage, bool, error)
+	decode       func(w *teishoku.World, e teishoku.Entity, data []byte) error
+	remap        func(w *teishoku.World, e teishoku.Entity, mapper EntityMapper)
+}
+
+type resourceCodec struct {
+	name   string
+	encode func(w *teishoku.World) (json.RawMessage, bool, error)
+	decode func(w *teishoku.World, data []byte) error
+	remap  func(w *teishoku.World, mapper EntityMapper)
+}
+
+var (
+	componentCodecs []componentCodec
+	resourceCodecs  []resourceCodec
+)
+
+func init() {
+	RegisterSerializableComponent[TransformComponent]()
+	RegisterSerializableComponent[SpriteComponent]()
+	RegisterSerializableComponent[AnimationComponent]()
+	RegisterSerializableComponent[TextComponent]()
+	RegisterSerializableComponent[ElevationComponent]()
+	RegisterSerializableComponent[ColliderComponent]()
+	RegisterSerializableComponent[TeamComponent]()
+	RegisterComponentCodec(typeName[OwnerComponent](), Codec[OwnerComponent]{
+		Remap: func(c *OwnerComponent, mapper EntityMapper) { c.Owner = mapper(c.Owner) },
+	})
+	RegisterSerializableComponent[TimerComponent]()
+	RegisterSerializableComponent[PathFollowerComponent]()
+	RegisterSerializableComponent[LocalTimeComponent]()
+	RegisterSerializableComponent[RigidBodyComponent]()
+	RegisterSerializableComponent[TriggerZoneComponent]()
+	RegisterResourceCodec(typeName[Camera](), Codec[Camera]{
+		Remap: func(c *Camera, mapper EntityMapper) { c.Target = mapper(c.Target) },
+	})
+}
+
+func typeName[T any]() string {
+	return reflect.TypeFor[T]().Name()
+}
+
+// RegisterSerializableComponent saves components of type T as JSON under
+// their type name.
+func RegisterSerializableComponent[T any]() {
+	RegisterComponentCodec(typeName[T](), Codec[T]{})
+}
+
+// RegisterComponentCodec saves components of type T under name using codec.
+// Entities are found through registered components only, so an entity with
+// none of them is not saved. Registering a name again replaces its codec.
+func RegisterComponentCodec[T any](name string, codec Codec[T]) {
+	c := componentCodec{
+		name: name,
+		encode: func(w *teishoku.World, dst map[teishoku.Entity]map[string]json.RawMessage) error {
+			filter := teishoku.NewFilter[T](w)
+			for filter.Next() {
+				data, err := codec.marshal(filter.Get())
+				if err != nil {
+					return fmt.Errorf("katsu2d: encoding %s: %w", name, err)
+				}
+				e := filter.Entity()
+				if dst[e] == nil {
+					dst[e] = make(map[string]json.RawMessage)
+				}
+				dst[e][name] = data
+			}
+			return nil
+		},
+		encodeEntity: func(w *teishoku.World, e teishoku.Entity) (json.RawMessage, bool, error) {
+			v := teishoku.GetComponent[T](w, e)
+			if v == nil {
+				return nil, false, nil
+			}
+			data, err := codec.marshal(v)
+			if err != nil {
+				return nil, false, fmt.Errorf("katsu2d: encoding %s: %w", name, err)
+			}
+			return data, true, nil
+		},
+		decode: func(w *teishoku.World, e teishoku.Entity, data []byte) error {
+			var v T
+			if err := codec.unmarshal(data, &v); err != nil {
+				return fmt.Errorf("katsu2d: decoding %s: %w", name, err)
+			}
+			teishoku.SetComponent(w, e, v)
+			return nil
+		},
+	}
+	if codec.Remap != nil {
+		c.remap = func(w *teishoku.World, e teishoku.Entity, mapper EntityMapper) {
+			if v := teishoku.GetComponent[T](w, e); v != nil {
+				codec.Remap(v, mapper)
+			}
+		}
+	}
+	if i := slices.IndexFunc(componentCodecs, func(c componentCodec) bool { return c.name == name }); i >= 0 {
+		componentCodecs[i] = c
+		return
+	}
+	componentCodecs = append(componentCodecs, c)
+}
+
+// RegisterSerializableResource saves the world resource of type T as JSON
+// under its type name.
+func RegisterSerializableResource[T any]() {
+	RegisterResourceCodec(typeName[T](), Codec[T]{})
+}
+
+// RegisterResourceCodec saves the world resource of type T under name using
+// codec. Loading overwrites an existing resource in place, or adds it.
+func RegisterResourceCodec[T any](name string, codec Codec[T]) {
+	c := resourceCodec{
+		name: name,
+		encode: func(w *teishoku.World) (json.RawMessage, bool, error) {
+			res := GetResource[T](w)
+			if res == nil {
+				return nil, false, nil
+			}
+			data, err := codec.marshal(res)
+			if err != nil {
+				return nil, false, fmt.Errorf("katsu2d: encoding resource %s: %w", name, err)
+			}
+			return data, true, nil
+		},
+		decode: func(w *teishoku.World, data []byte) error {
+			res := EnsureResource(w, func() *T { return new(T) })
+			if err := codec.unmarshal(data, res); err != nil {
+				return fmt.Errorf("katsu2d: decoding resource %s: %w", name, err)
+			}
+			return nil
+		},
+	}
+	if codec.Remap != nil {
+		c.remap = func(w *teishoku.World, mapper EntityMapper) {
+			if res := GetResource[T](w); res != nil {
+				codec.Remap(res, mapper)
+			}
+		}
+	}
+	if i := slices.IndexFunc(resourceCodecs, func(c resourceCodec) bool { return c.name == name }); i >= 0 {
+		resourceCodecs[i] = c
+		return
+	}
+	resourceCodecs = append(resourceCodecs, c)
+}
+
+// SerializeWorld encodes the registered components of every entity and the
+// registered resources of w as JSON, e.g. for a SaveSlotManager save.
+func SerializeWorld(w *teishoku.World) ([]byte, error) {
+	entities := make(map[teishoku.Entity]map[string]json.RawMessage)
+	for _, c := range componentCodecs {
+		if err := c.encode(w, entities); err != nil {
+			return nil, err
+		}
+	}
+	doc := WorldDocument{Version: worldFormatVersion, VersionStamp: CurrentVersion(), Entities: make([]EntityDocument, 0, len(entities))}
+	for e, components := range entities {
+		doc.Entities = append(doc.Entities, EntityDocument{Entity: e, Components: components})
+	}
+	slices.SortFunc(doc.Entities, func(a, b EntityDocument) int { return int(a.Entity.ID) - int(b.Entity.ID) })
+	for _, c := range resourceCodecs {
+		data, ok, err := c.encode(w)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			if doc.Resources == nil {
+				doc.Resources = make(map[string]json.RawMessage)
+			}
+			doc.Resources[c.name] = data
+		}
+	}
+	return json.Marshal(doc)
+}
+
+// serializeEntities encodes the registered components of the given entities
+// only, skipping entities with none of them.
+func serializeEntities(w *teishoku.World, entities []teishoku.Entity) ([]EntityDocument, error) {
+	var docs []EntityDocument
+	for _, e := range entities {
+		components := make(map[string]json.RawMessage)
+		for _, c := range componentCodecs {
+			data, ok, err := c.encodeEntity(w, e)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				components[c.name] = data
+			}
+		}
+		if len(components) > 0 {
+			docs = append(docs, EntityDocument{Entity: e, Components: components})
+		}
+	}
+	return docs, nil
+}
+
+// DeserializeWorld recreates the entities and resources saved by
+// SerializeWorld in w. Existing entities are kept, so load into a fresh or
+// cleared world. Data from an older content version is first upgraded with
+// the registered migrations. The returned map gives the new entity for each saved one;
+// entity references in components and resources with a Remap codec are
+// already rewritten through it.
+func DeserializeWorld(w *teishoku.World, data []byte) (map[teishoku.Entity]teishoku.Entity, error) {
+	var doc WorldDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("katsu2d: decoding world: %w", err)
+	}
+	if doc.Version != worldFormatVersion {
+		return nil, fmt.Errorf("katsu2d: unsupported world format version %d", doc.Version)
+	}
+	if err := doc.migrate(); err != nil {
+		return nil, err
+	}
+	codecs := make(map[string]*componentCodec, len(componentCodecs))
+	for i := range componentCodecs {
+		codecs[componentCodecs[i].name] = &componentCodecs[i]
+	}
+	for _, ed := range doc.Entities {
+		for name := range ed.Components {
+			if codecs[name] == nil {
+				return nil, fmt.Errorf("katsu2d: no codec registered for component %q", name)
+			}
+		}
+	}
+
+	entities := make(map[teishoku.Entity]teishoku.Entity, len(doc.Entities))
+	for _, ed := range doc.Entities {
+		e := TrackEntity(w, w.CreateEntity())
+		entities[ed.Entity] = e
+		for name, raw := range ed.Components {
+			if err := codecs[name].decode(w, e, raw); err != nil {
+				return entities, err
+			}
+		}
+	}
+	for _, c := range resourceCodecs {
+		if raw, ok := doc.Resources[c.name]; ok {
+			if err := c.decode(w, raw); err != nil {
+				return entities, err
+			}
+		}
+	}
+
+	mapper := func(e teishoku.Entity) teishoku.Entity { return entities[e] }
+	for _, ed := range doc.Entities {
+		for name := range ed.Components {
+			if c := codecs[name]; c.remap != nil {
+				c.remap(w, entities[ed.Entity], mapper)
+			}
+		}
+	}
+	for _, c := range resourceCodecs {
+		if _, ok := doc.Resources[c.name]; ok && c.remap != nil {
+			c.remap(w, mapper)
+		}
+	}
+	return entities, nil
+}
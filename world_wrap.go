@@ -0,0 +1,117 @@
+package katsu2d
+
+import (
+	"math"
+
+	"github.com/edwinsyarief/teishoku"
+)
+
+// WorldWrap describes toroidal world space: positions leaving Bounds on a
+// wrapping axis re-enter from the opposite side.
+type WorldWrap struct {
+	Bounds       Rectangle
+	WrapX, WrapY bool
+	// Margin is the default distance from an edge at which wrapped entities
+	// are drawn a second time on the opposite side.
+	Margin float64
+}
+
+// SetWorldWrap enables wrapping on both axes within bounds.
+func SetWorldWrap(w *teishoku.World, bounds Rectangle, margin float64) *WorldWrap {
+	ww := GetWorldWrap(w)
+	if ww == nil {
+		ww = SetResource(w, &WorldWrap{})
+	}
+	ww.Bounds, ww.WrapX, ww.WrapY, ww.Margin = bounds, true, true, margin
+	return ww
+}
+
+// GetWorldWrap returns the world's wrap settings, or nil if wrapping is off.
+func GetWorldWrap(w *teishoku.World) *WorldWrap {
+	return GetResource[WorldWrap](w)
+}
+
+// Wrap maps p back into the bounds on wrapping axes.
+func (self *WorldWrap) Wrap(p Vector) Vector {
+	if self.WrapX && self.Bounds.Width() > 0 {
+		p.X = self.Bounds.Min.X + wrapFloat(p.X-self.Bounds.Min.X, self.Bounds.Width())
+	}
+	if self.WrapY && self.Bounds.Height() > 0 {
+		p.Y = self.Bounds.Min.Y + wrapFloat(p.Y-self.Bounds.Min.Y, self.Bounds.Height())
+	}
+	return p
+}
+
+// Delta returns the shortest vector from a to b, crossing seams if shorter.
+func (self *WorldWrap) Delta(a, b Vector) Vector {
+	d := b.Sub(a)
+	if self.WrapX {
+		d.X = shortestWrapped(d.X, self.Bounds.Width())
+	}
+	if self.WrapY {
+		d.Y = shortestWrapped(d.Y, self.Bounds.Height())
+	}
+	return d
+}
+
+// Distance returns the shortest distance between a and b.
+func (self *WorldWrap) Distance(a, b Vector) float64 {
+	return self.Delta(a, b).Length()
+}
+
+// Direction returns the normalized shortest direction from a to b.
+func (self *WorldWrap) Direction(a, b Vector) Vector {
+	return self.Delta(a, b).Normalize()
+}
+
+// GhostOffsets appends the offsets at which an object of the given radius at
+// p must be drawn again so it stays continuous across seams. The zero offset
+// for the object itself is always first.
+func (self *WorldWrap) GhostOffsets(dst []Vector, p Vector, radius float64) []Vector {
+	dst = append(dst, Vector{})
+	var xs, ys [2]float64
+	nx, ny := 1, 1
+	width, height := self.Bounds.Width(), self.Bounds.Height()
+	if self.WrapX && width > 0 {
+		if p.X-radius < self.Bounds.Min.X {
+			xs[1], nx = width, 2
+		} else if p.X+radius > self.Bounds.Max.X {
+			xs[1], nx = -width, 2
+		}
+	}
+	if self.WrapY && height > 0 {
+		if p.Y-radius < self.Bounds.Min.Y {
+			ys[1], ny = height, 2
+		} else if p.Y+radius > self.Bounds.Max.Y {
+			ys[1], ny = -height, 2
+		}
+	}
+	for i := range nx {
+		for j := range ny {
+			if i == 0 && j == 0 {
+				continue
+			}
+			dst = append(dst, V(xs[i], ys[j]))
+		}
+	}
+	return dst
+}
+
+func wrapFloat(v, size float64) float64 {
+	v = math.Mod(v, size)
+	if v < 0 {
+		v += size
+	}
+	return v
+}
+
+func shortestWrapped(d, size float64) float64 {
+	if size <= 0 {
+		return d
+	}
+	d = wrapFloat(d, size)
+	if d > size/2 {
+		d -= size
+	}
+	return d
+}